@@ -0,0 +1,324 @@
+// Package admission protects the server from overload: per-client token-bucket rate
+// limits, and a cap on the number of records validated at once, overall and per model
+package admission
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"goplayground-data-validator/metrics"
+)
+
+// ErrOverloaded is returned when a request cannot be admitted; errors.As it to
+// *RejectedError for the suggested retry delay
+var ErrOverloaded = errors.New("server overloaded")
+
+// RejectedError reports why a request was turned away and when to retry
+type RejectedError struct {
+	Reason     string        // "rate_limit" or "overload"
+	Message    string        // Human-readable cause
+	RetryAfter time.Duration // Suggested delay before retrying, at least one second
+}
+
+func (e *RejectedError) Error() string { return e.Message }
+
+func (e *RejectedError) Unwrap() error { return ErrOverloaded }
+
+// RetryAfterSeconds is the Retry-After header value for e
+func (e *RejectedError) RetryAfterSeconds() int {
+	return max(1, int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// Config bounds the records validated concurrently
+type Config struct {
+	MaxConcurrentRecords int64            // Across all models; 0 means unlimited
+	QueueTimeout         time.Duration    // How long a request waits for capacity; 0 rejects at once
+	MaxQueued            int              // Requests allowed to wait at once; 0 means unlimited
+	ModelLimits          map[string]int64 // Per-model caps on concurrent records
+}
+
+// Validate rejects negative limits
+func (c Config) Validate() error {
+	if c.MaxConcurrentRecords < 0 || c.QueueTimeout < 0 || c.MaxQueued < 0 {
+		return fmt.Errorf("admission limits must not be negative")
+	}
+	for model, limit := range c.ModelLimits {
+		if limit < 0 {
+			return fmt.Errorf("record limit for model %s must not be negative", model)
+		}
+	}
+	return nil
+}
+
+// Controller admits validations while their records fit under the global and
+// per-model caps. Requests that do not fit wait in FIFO order for up to
+// QueueTimeout. Every limit can be changed while the server runs.
+type Controller struct {
+	mu           sync.Mutex
+	queueTimeout time.Duration
+	maxQueued    int
+	queued       int
+	global       *pool
+	models       map[string]*pool
+}
+
+var (
+	defaultController     *Controller
+	defaultControllerOnce sync.Once
+)
+
+// Default returns the controller shared by the HTTP handlers; it admits
+// everything until configured
+func Default() *Controller {
+	defaultControllerOnce.Do(func() {
+		defaultController = NewController(Config{})
+		metrics.Default().NewGaugeFunc("validator_records_in_flight",
+			"Records currently admitted for validation.",
+			func() (float64, error) { return float64(defaultController.InFlight()), nil })
+	})
+	return defaultController
+}
+
+// NewController creates a controller with cfg
+func NewController(cfg Config) *Controller {
+	c := &Controller{global: &pool{}, models: make(map[string]*pool)}
+	c.Configure(cfg)
+	return c
+}
+
+// Configure replaces every limit. Admitted requests keep their records; waiting
+// requests are admitted as soon as they fit under the new limits.
+func (c *Controller) Configure(cfg Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queueTimeout = cfg.QueueTimeout
+	c.maxQueued = cfg.MaxQueued
+	c.global.setCapacity(cfg.MaxConcurrentRecords, &c.queued)
+	for model, p := range c.models {
+		if _, kept := cfg.ModelLimits[model]; !kept {
+			p.setCapacity(0, &c.queued)
+		}
+	}
+	for model, limit := range cfg.ModelLimits {
+		c.modelPool(model).setCapacity(limit, &c.queued)
+	}
+}
+
+// SetModelLimit changes the concurrent record cap of one model; 0 removes it
+func (c *Controller) SetModelLimit(model string, maxRecords int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.modelPool(model).setCapacity(maxRecords, &c.queued)
+}
+
+// modelPool returns the pool of model, creating an unlimited one; c.mu must be held
+func (c *Controller) modelPool(model string) *pool {
+	p, ok := c.models[model]
+	if !ok {
+		p = &pool{}
+		c.models[model] = p
+	}
+	return p
+}
+
+// Acquire admits records of model, waiting for capacity if needed. A request
+// larger than a cap is admitted alone once everything else has finished. The
+// returned function must be called when validation ends.
+func (c *Controller) Acquire(ctx context.Context, model string, records int) (func(), error) {
+	if records <= 0 {
+		return func() {}, nil
+	}
+
+	c.mu.Lock()
+	modelPool := c.modelPool(model)
+	deadline := time.Now().Add(c.queueTimeout)
+	c.mu.Unlock()
+
+	modelHeld, err := c.acquire(ctx, modelPool, int64(records), "model "+model, deadline)
+	if err != nil {
+		return nil, err
+	}
+	globalHeld, err := c.acquire(ctx, c.global, int64(records), "server", deadline)
+	if err != nil {
+		c.release(modelPool, modelHeld)
+		return nil, err
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.release(c.global, globalHeld)
+			c.release(modelPool, modelHeld)
+		})
+	}, nil
+}
+
+// acquire takes n records from p, waiting until deadline, and returns the amount
+// actually held, which is n capped to the pool's capacity
+func (c *Controller) acquire(ctx context.Context, p *pool, n int64, scope string, deadline time.Time) (int64, error) {
+	c.mu.Lock()
+	n = p.clamp(n)
+	if p.waiters.Len() == 0 && p.fits(n) {
+		p.used += n
+		c.mu.Unlock()
+		return n, nil
+	}
+	wait := time.Until(deadline)
+	if wait <= 0 || (c.maxQueued > 0 && c.queued >= c.maxQueued) {
+		c.mu.Unlock()
+		return 0, overloaded(scope, c.queueTimeout)
+	}
+	w := &waiter{n: n, ready: make(chan struct{})}
+	elem := p.waiters.PushBack(w)
+	c.queued++
+	retryAfter := c.queueTimeout
+	c.mu.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	var waitErr error
+	select {
+	case <-w.ready:
+		return w.n, nil
+	case <-timer.C:
+		waitErr = overloaded(scope, retryAfter)
+	case <-ctx.Done():
+		waitErr = ctx.Err()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-w.ready:
+		// Admitted while timing out; keep it
+		return w.n, nil
+	default:
+	}
+	p.waiters.Remove(elem)
+	c.queued--
+	p.admitWaiters(&c.queued)
+	return 0, waitErr
+}
+
+// release returns n records to p and admits the waiters that now fit
+func (c *Controller) release(p *pool, n int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p.used -= n
+	p.admitWaiters(&c.queued)
+}
+
+// overloaded builds the error for a request that did not fit under scope's cap
+func overloaded(scope string, retryAfter time.Duration) error {
+	rejectedRequests.Inc("overload")
+	return &RejectedError{
+		Reason:     "overload",
+		Message:    fmt.Sprintf("too many records being validated (%s limit); retry later", scope),
+		RetryAfter: max(retryAfter, time.Second),
+	}
+}
+
+// InFlight returns the number of records currently admitted
+func (c *Controller) InFlight() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.global.used
+}
+
+// ModelStatus is the limit and usage of one model
+type ModelStatus struct {
+	MaxConcurrentRecords int64 `json:"max_concurrent_records"`
+	InFlight             int64 `json:"in_flight"`
+	Queued               int   `json:"queued"`
+}
+
+// Status describes the current limits and usage
+type Status struct {
+	MaxConcurrentRecords int64                  `json:"max_concurrent_records"`
+	QueueTimeout         string                 `json:"queue_timeout"`
+	MaxQueued            int                    `json:"max_queued"`
+	InFlight             int64                  `json:"in_flight"`
+	Queued               int                    `json:"queued"`
+	Models               map[string]ModelStatus `json:"models"`
+}
+
+// Status returns the current limits and usage; models without a limit or
+// records in flight are left out
+func (c *Controller) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := Status{
+		MaxConcurrentRecords: c.global.capacity,
+		QueueTimeout:         c.queueTimeout.String(),
+		MaxQueued:            c.maxQueued,
+		InFlight:             c.global.used,
+		Queued:               c.queued,
+		Models:               make(map[string]ModelStatus),
+	}
+	for model, p := range c.models {
+		if p.capacity == 0 && p.used == 0 && p.waiters.Len() == 0 {
+			continue
+		}
+		status.Models[model] = ModelStatus{MaxConcurrentRecords: p.capacity, InFlight: p.used, Queued: p.waiters.Len()}
+	}
+	return status
+}
+
+// pool is a resizable FIFO counting semaphore; it is guarded by the controller's mutex
+type pool struct {
+	capacity int64 // 0 means unlimited
+	used     int64
+	waiters  list.List // of *waiter
+}
+
+type waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// clamp caps n to the capacity so an oversized request can still run alone
+func (p *pool) clamp(n int64) int64 {
+	if p.capacity > 0 && n > p.capacity {
+		return p.capacity
+	}
+	return n
+}
+
+func (p *pool) fits(n int64) bool {
+	return p.capacity == 0 || p.used+n <= p.capacity
+}
+
+// setCapacity resizes the pool and admits the waiters that now fit
+func (p *pool) setCapacity(capacity int64, queued *int) {
+	p.capacity = capacity
+	for elem := p.waiters.Front(); elem != nil; elem = elem.Next() {
+		w := elem.Value.(*waiter)
+		w.n = p.clamp(w.n)
+	}
+	p.admitWaiters(queued)
+}
+
+// admitWaiters admits waiters in arrival order until the first that does not fit
+func (p *pool) admitWaiters(queued *int) {
+	for elem := p.waiters.Front(); elem != nil; elem = p.waiters.Front() {
+		w := elem.Value.(*waiter)
+		if !p.fits(w.n) {
+			return
+		}
+		p.used += w.n
+		p.waiters.Remove(elem)
+		*queued--
+		close(w.ready)
+	}
+}
+
+// rejectedRequests counts requests turned away by rate limits or admission control
+var rejectedRequests = metrics.Default().NewCounterVec(
+	"validator_rejected_requests_total",
+	"Requests rejected with 429, by reason (rate_limit, overload).",
+	"reason")
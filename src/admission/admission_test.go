@@ -0,0 +1,272 @@
+package admission
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"goplayground-data-validator/auth"
+)
+
+// acquireAsync starts an Acquire and returns a channel with its outcome
+func acquireAsync(c *Controller, model string, records int) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		release, err := c.Acquire(context.Background(), model, records)
+		if err == nil {
+			defer release()
+		}
+		done <- err
+	}()
+	return done
+}
+
+// waitQueued waits until n requests are queued
+func waitQueued(t *testing.T, c *Controller, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for c.Status().Queued != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d queued requests, got %d", n, c.Status().Queued)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestController_GlobalCap(t *testing.T) {
+	c := NewController(Config{MaxConcurrentRecords: 100, QueueTimeout: time.Second})
+
+	release, err := c.Acquire(context.Background(), "incident", 80)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if c.InFlight() != 80 {
+		t.Errorf("Expected 80 records in flight, got %d", c.InFlight())
+	}
+
+	// 30 more records do not fit and wait until the first request is done
+	done := acquireAsync(c, "github", 30)
+	waitQueued(t, c, 1)
+	release()
+	if err := <-done; err != nil {
+		t.Errorf("Expected queued request to be admitted, got %v", err)
+	}
+	release() // A second call must not free capacity twice
+	if c.InFlight() != 0 {
+		t.Errorf("Expected nothing in flight, got %d", c.InFlight())
+	}
+}
+
+func TestController_QueueTimeout(t *testing.T) {
+	c := NewController(Config{MaxConcurrentRecords: 10, QueueTimeout: 20 * time.Millisecond})
+	release, err := c.Acquire(context.Background(), "incident", 10)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer release()
+
+	_, err = c.Acquire(context.Background(), "incident", 1)
+	var rejection *RejectedError
+	if !errors.As(err, &rejection) || !errors.Is(err, ErrOverloaded) {
+		t.Fatalf("Expected a RejectedError, got %v", err)
+	}
+	if rejection.Reason != "overload" || rejection.RetryAfterSeconds() != 1 {
+		t.Errorf("Unexpected rejection: %+v", rejection)
+	}
+	if c.Status().Queued != 0 {
+		t.Errorf("Expected the timed-out request to leave the queue")
+	}
+}
+
+func TestController_NoQueue(t *testing.T) {
+	c := NewController(Config{MaxConcurrentRecords: 10, MaxQueued: 1, QueueTimeout: time.Second})
+	release, _ := c.Acquire(context.Background(), "incident", 10)
+	defer release()
+
+	first := acquireAsync(c, "incident", 1)
+	waitQueued(t, c, 1)
+	if _, err := c.Acquire(context.Background(), "incident", 1); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("Expected immediate rejection with a full queue, got %v", err)
+	}
+	release()
+	if err := <-first; err != nil {
+		t.Errorf("Expected queued request to be admitted, got %v", err)
+	}
+}
+
+func TestController_OversizedRequestRunsAlone(t *testing.T) {
+	c := NewController(Config{MaxConcurrentRecords: 100, QueueTimeout: time.Second})
+	release, err := c.Acquire(context.Background(), "incident", 5000)
+	if err != nil {
+		t.Fatalf("Expected oversized request to be admitted on an idle server, got %v", err)
+	}
+	if c.InFlight() != 100 {
+		t.Errorf("Expected the request to hold the whole cap, got %d", c.InFlight())
+	}
+	release()
+}
+
+func TestController_ModelLimits(t *testing.T) {
+	c := NewController(Config{QueueTimeout: time.Second, ModelLimits: map[string]int64{"incident": 10}})
+	release, err := c.Acquire(context.Background(), "incident", 10)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer release()
+
+	// Other models are not held back by the incident cap
+	other, err := c.Acquire(context.Background(), "github", 1000)
+	if err != nil {
+		t.Fatalf("Expected other model to be admitted, got %v", err)
+	}
+	other()
+
+	// Raising the cap at runtime admits the waiting request
+	done := acquireAsync(c, "incident", 5)
+	waitQueued(t, c, 1)
+	c.SetModelLimit("incident", 20)
+	if err := <-done; err != nil {
+		t.Errorf("Expected request to be admitted after raising the limit, got %v", err)
+	}
+
+	status := c.Status()
+	if status.Models["incident"].MaxConcurrentRecords != 20 || status.Models["incident"].InFlight != 10 {
+		t.Errorf("Unexpected status: %+v", status.Models["incident"])
+	}
+	if _, listed := status.Models["github"]; listed {
+		t.Error("Expected idle models without a limit to be left out of the status")
+	}
+}
+
+func TestController_ContextCancelled(t *testing.T) {
+	c := NewController(Config{MaxConcurrentRecords: 1, QueueTimeout: time.Minute})
+	release, _ := c.Acquire(context.Background(), "incident", 1)
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		waitQueued(t, c, 1)
+		cancel()
+	}()
+	if _, err := c.Acquire(ctx, "incident", 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+	l := NewRateLimiter(RateConfig{RequestsPerSecond: 2, Burst: 3})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if allowed, _ := l.Allow("client"); !allowed {
+			t.Fatalf("Expected request %d of the burst to be allowed", i+1)
+		}
+	}
+	allowed, wait := l.Allow("client")
+	if allowed || wait != 500*time.Millisecond {
+		t.Errorf("Expected rejection with a 500ms wait, got %v and %s", allowed, wait)
+	}
+	if allowed, _ := l.Allow("other"); !allowed {
+		t.Error("Expected another client to have its own bucket")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if allowed, _ := l.Allow("client"); !allowed {
+		t.Error("Expected a token after 500ms")
+	}
+
+	// Idle clients whose buckets refilled are dropped on the next sweep
+	now = now.Add(2 * rateSweepInterval)
+	l.Allow("client")
+	if len(l.buckets) != 1 {
+		t.Errorf("Expected only the active bucket after a sweep, got %d", len(l.buckets))
+	}
+}
+
+func TestRateLimiter_Middleware(t *testing.T) {
+	digest := sha256.Sum256([]byte("key"))
+	authenticator, err := auth.New(auth.Config{APIKeys: []auth.APIKey{{ID: "ingest", SHA256: hex.EncodeToString(digest[:])}}})
+	if err != nil {
+		t.Fatalf("auth.New failed: %v", err)
+	}
+	limiter := NewRateLimiter(RateConfig{RequestsPerSecond: 1})
+	handler := authenticator.Middleware(limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	send := func(path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(auth.APIKeyHeader, "key")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send("/validate/incident", "10.0.0.1:1234"); w.Code != http.StatusOK {
+		t.Fatalf("Expected first request to pass, got %d", w.Code)
+	}
+	// Same API key from another address shares the bucket
+	w := send("/validate/incident", "10.0.0.2:1234")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected 429 with Retry-After 1, got %d and %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := send("/health", "10.0.0.1:1234"); w.Code != http.StatusOK {
+		t.Errorf("Expected health checks to be exempt, got %d", w.Code)
+	}
+}
+
+func TestRateLimiter_ExemptPaths(t *testing.T) {
+	limiter := NewRateLimiter(RateConfig{RequestsPerSecond: 1, ExemptPaths: []string{"/status/", "/ping"}})
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	send := func(path string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := send("/validate/incident"); code != http.StatusOK {
+		t.Fatalf("Expected first request to pass, got %d", code)
+	}
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/ping", http.StatusOK},
+		{"/status/ready", http.StatusOK},
+		{"/status", http.StatusTooManyRequests},
+		{"/health", http.StatusTooManyRequests}, // Default public paths are replaced, not added to
+		{"/validate/incident", http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		if code := send(tt.path); code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.path, tt.want, code)
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/validate", nil)
+	w := httptest.NewRecorder()
+	WriteError(w, req, &RejectedError{Reason: "overload", Message: "busy", RetryAfter: 2500 * time.Millisecond})
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "3" {
+		t.Errorf("Expected 429 with Retry-After 3, got %d and %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"code":"overload"`) || !strings.Contains(body, `"detail":"busy"`) {
+		t.Errorf("Expected an overload problem, got %s", body)
+	}
+
+	w = httptest.NewRecorder()
+	WriteError(w, req, context.Canceled)
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "" {
+		t.Errorf("Expected 503 without Retry-After, got %d", w.Code)
+	}
+}
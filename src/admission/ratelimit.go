@@ -0,0 +1,155 @@
+package admission
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"goplayground-data-validator/auth"
+	"goplayground-data-validator/problem"
+)
+
+// RateConfig sets the token bucket every client gets
+type RateConfig struct {
+	RequestsPerSecond float64  // Sustained rate per client; 0 disables rate limiting
+	Burst             int      // Requests a client may send at once; 0 means ceil(RequestsPerSecond)
+	ExemptPaths       []string // Never limited, matched like auth public paths; nil means auth.DefaultPublicPaths
+}
+
+// rateSweepInterval is how often buckets of idle clients are dropped
+const rateSweepInterval = time.Minute
+
+// RateLimiter keeps a token bucket per client, keyed by the authenticated
+// principal or, without one, by the client IP
+type RateLimiter struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	exemptPaths []string
+	buckets     map[string]*bucket
+	lastSweep   time.Time
+	now         func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewRateLimiter creates a rate limiter with cfg
+func NewRateLimiter(cfg RateConfig) *RateLimiter {
+	l := &RateLimiter{buckets: make(map[string]*bucket), now: time.Now}
+	l.Configure(cfg)
+	return l
+}
+
+// Configure replaces the rate and burst; clients keep their current tokens
+func (l *RateLimiter) Configure(cfg RateConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = cfg.RequestsPerSecond
+	l.burst = float64(cfg.Burst)
+	if l.burst <= 0 {
+		l.burst = math.Max(1, math.Ceil(l.rate))
+	}
+	l.exemptPaths = cfg.ExemptPaths
+	if l.exemptPaths == nil {
+		l.exemptPaths = auth.DefaultPublicPaths
+	}
+}
+
+// Allow takes a token from key's bucket. When none is left it returns false and
+// how long until the next token.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return true, 0
+	}
+
+	now := l.now()
+	if now.Sub(l.lastSweep) > rateSweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+	b.updated = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops buckets that have refilled completely, which behave like new ones; l.mu must be held
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Middleware answers 429 with Retry-After once a client has used up its bucket.
+// It must run after authentication so API key and token holders are limited by
+// identity rather than by address.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.isExempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		allowed, wait := l.Allow(clientKey(r))
+		if !allowed {
+			rejectedRequests.Inc("rate_limit")
+			WriteError(w, r, &RejectedError{
+				Reason:     "rate_limit",
+				Message:    "rate limit exceeded; retry later",
+				RetryAfter: wait,
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *RateLimiter) isExempt(urlPath string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return auth.MatchPath(l.exemptPaths, urlPath)
+}
+
+// clientKey identifies the caller: the principal when authenticated, else the remote IP
+func clientKey(r *http.Request) string {
+	if principal, ok := auth.PrincipalFrom(r.Context()); ok {
+		return "principal:" + principal.Method + ":" + principal.ID
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// WriteError answers a request that was not admitted with a problem: 429
+// Too Many Requests with a Retry-After header for a RejectedError, coded by
+// its reason, and 503 for a request whose context ended while it waited
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	var rejection *RejectedError
+	if errors.As(err, &rejection) {
+		w.Header().Set("Retry-After", strconv.Itoa(rejection.RetryAfterSeconds()))
+		problem.Write(w, r, http.StatusTooManyRequests, rejection.Reason, rejection.Message)
+		return
+	}
+	problem.Write(w, r, http.StatusServiceUnavailable, "", "request cancelled while waiting for capacity: "+err.Error())
+}
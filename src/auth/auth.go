@@ -0,0 +1,372 @@
+// Package auth authenticates HTTP requests with API keys, HMAC request signatures,
+// JWT bearer tokens or TLS client certificates, and restricts each caller to a set of models and operations
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"goplayground-data-validator/logging"
+	"goplayground-data-validator/problem"
+)
+
+// Operations a caller can be allowed to perform
+const (
+	OpValidate = "validate" // POST /validate and POST /validate/{model}[/stream]
+	OpBatch    = "batch"    // Batch sessions: /validate/batch/* and X-Batch-* chunks on POST /validate
+	OpRead     = "read"     // Everything else: models, schemas, detailed health
+	OpAdmin    = "admin"    // Runtime settings under /limits
+)
+
+// Authentication methods, as reported in Principal.Method
+const (
+	MethodAPIKey     = "api_key"
+	MethodHMAC       = "hmac"
+	MethodJWT        = "jwt"
+	MethodClientCert = "client_cert"
+)
+
+// APIKeyHeader carries a static API key
+const APIKeyHeader = "X-API-Key"
+
+// DefaultPublicPaths are served without credentials unless the config says otherwise
+var DefaultPublicPaths = []string{"/health", "/health/live", "/health/ready", "/metrics"}
+
+// ErrForbidden is returned when an authenticated caller may not use a model or operation
+var ErrForbidden = errors.New("forbidden")
+
+// Grant lists the models and operations a credential may use. An empty list
+// allows everything; model entries may be patterns such as "api.*".
+type Grant struct {
+	Models     []string `json:"models,omitempty"`
+	Operations []string `json:"operations,omitempty"`
+}
+
+// APIKey is a static key, stored as the hex SHA-256 of the key
+type APIKey struct {
+	ID     string `json:"id"`
+	SHA256 string `json:"sha256"`
+	Grant
+}
+
+// HMACKey is a shared secret for signed requests, given inline or as the name
+// of an environment variable holding it
+type HMACKey struct {
+	ID        string `json:"id"`
+	Secret    string `json:"secret,omitempty"`
+	SecretEnv string `json:"secret_env,omitempty"`
+	Grant
+}
+
+// ClientCert grants access to TLS clients whose verified certificate has the
+// given subject, matched against its common name or its full distinguished
+// name such as "CN=deploy-bot,O=Example"
+type ClientCert struct {
+	Subject string `json:"subject"`
+	Grant
+}
+
+// Config is the JSON auth configuration file
+type Config struct {
+	PublicPaths      []string     `json:"public_paths"`                  // Exact paths, or prefixes ending in "/"; nil means DefaultPublicPaths
+	APIKeys          []APIKey     `json:"api_keys,omitempty"`            // Accepted in the X-API-Key header
+	HMACKeys         []HMACKey    `json:"hmac_keys,omitempty"`           // Accepted as X-Auth-* signature headers
+	HMACMaxBodyBytes int64        `json:"hmac_max_body_bytes,omitempty"` // Largest body a signed request may have (default 10 MiB)
+	HMACMaxNonces    int          `json:"hmac_max_nonces,omitempty"`     // Signed requests remembered to refuse replays (default 100000)
+	JWT              *JWTConfig   `json:"jwt,omitempty"`                 // Accepted as Authorization: Bearer tokens
+	ClientCerts      []ClientCert `json:"client_certs,omitempty"`        // Accepted from TLS connections when no other credential is sent
+}
+
+// LoadConfig reads a JSON auth configuration file
+func LoadConfig(filename string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read auth config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid auth config %s: %w", filename, err)
+	}
+	return cfg, nil
+}
+
+// Principal is an authenticated caller
+type Principal struct {
+	ID     string // Key ID, the JWT subject, or the client certificate subject
+	Method string // One of the Method constants
+	Grant
+}
+
+// Allows reports whether the principal may perform operation, on model when it is not empty
+func (p *Principal) Allows(operation, model string) bool {
+	if !matchesAny(p.Operations, operation, false) {
+		return false
+	}
+	return model == "" || matchesAny(p.Models, model, true)
+}
+
+// matchesAny reports whether value is in list, or list is empty
+func matchesAny(list []string, value string, patterns bool) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, entry := range list {
+		if entry == value || entry == "*" {
+			return true
+		}
+		if patterns {
+			if ok, _ := path.Match(entry, value); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying principal, for servers that
+// authenticate without Middleware
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the caller authenticated by Authenticator.Middleware
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
+
+// AllowModel checks that the caller in ctx may perform operation on model. It is
+// for handlers that learn the model from the body; requests without a principal,
+// such as public paths or a server without auth, are allowed.
+func AllowModel(ctx context.Context, operation, model string) error {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok || principal.Allows(operation, model) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s may not %s model '%s'", ErrForbidden, principal.ID, operation, model)
+}
+
+// Authenticator checks the credentials of every request that is not on a public path
+type Authenticator struct {
+	publicPaths []string
+	apiKeys     []APIKey
+	hmac        *hmacVerifier
+	jwt         *jwtVerifier
+	clientCerts []ClientCert
+}
+
+// New validates cfg and builds an Authenticator; JWKS and HMAC secrets are loaded now
+func New(cfg Config) (*Authenticator, error) {
+	a := &Authenticator{publicPaths: cfg.PublicPaths}
+	if a.publicPaths == nil {
+		a.publicPaths = DefaultPublicPaths
+	}
+
+	for _, key := range cfg.APIKeys {
+		if key.ID == "" {
+			return nil, fmt.Errorf("api key without an id")
+		}
+		if digest, err := hex.DecodeString(key.SHA256); err != nil || len(digest) != sha256.Size {
+			return nil, fmt.Errorf("api key %s: sha256 must be 64 hex characters", key.ID)
+		}
+		if err := checkGrant(key.Grant); err != nil {
+			return nil, fmt.Errorf("api key %s: %w", key.ID, err)
+		}
+		key.SHA256 = strings.ToLower(key.SHA256)
+		a.apiKeys = append(a.apiKeys, key)
+	}
+
+	if len(cfg.HMACKeys) > 0 {
+		verifier, err := newHMACVerifier(cfg.HMACKeys, cfg.HMACMaxBodyBytes, cfg.HMACMaxNonces)
+		if err != nil {
+			return nil, err
+		}
+		a.hmac = verifier
+	}
+
+	if cfg.JWT != nil {
+		verifier, err := newJWTVerifier(*cfg.JWT)
+		if err != nil {
+			return nil, err
+		}
+		a.jwt = verifier
+	}
+
+	for _, clientCert := range cfg.ClientCerts {
+		if clientCert.Subject == "" {
+			return nil, fmt.Errorf("client cert without a subject")
+		}
+		if err := checkGrant(clientCert.Grant); err != nil {
+			return nil, fmt.Errorf("client cert %s: %w", clientCert.Subject, err)
+		}
+		a.clientCerts = append(a.clientCerts, clientCert)
+	}
+
+	if len(a.apiKeys) == 0 && a.hmac == nil && a.jwt == nil && len(a.clientCerts) == 0 {
+		return nil, fmt.Errorf("auth config has no api_keys, hmac_keys, jwt or client_certs")
+	}
+	return a, nil
+}
+
+// checkGrant rejects unknown operations and malformed model patterns
+func checkGrant(grant Grant) error {
+	for _, operation := range grant.Operations {
+		switch operation {
+		case OpValidate, OpBatch, OpRead, OpAdmin, "*":
+		default:
+			return fmt.Errorf("unknown operation %q (want validate, batch, read or admin)", operation)
+		}
+	}
+	for _, model := range grant.Models {
+		if _, err := path.Match(model, ""); err != nil {
+			return fmt.Errorf("invalid model pattern %q", model)
+		}
+	}
+	return nil
+}
+
+// Middleware rejects requests without valid credentials with 401, and requests for
+// an operation or path model the caller is not granted with 403. The principal is
+// stored in the request context and added to the access log.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.isPublic(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, status, err := a.authenticate(r)
+		if err != nil {
+			slog.InfoContext(r.Context(), "Authentication failed", "error", err)
+			if status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="validator"`)
+			}
+			problem.Write(w, r, status, "", err.Error())
+			return
+		}
+		logging.AddFields(r.Context(), slog.String("principal", principal.ID), slog.String("auth_method", principal.Method))
+
+		operation, model := classify(r)
+		if !principal.Allows(operation, model) {
+			message := fmt.Sprintf("%s may not perform %s", principal.ID, operation)
+			if model != "" {
+				message = fmt.Sprintf("%s may not %s model '%s'", principal.ID, operation, model)
+			}
+			denied := problem.New(http.StatusForbidden, "", message)
+			denied.ModelType = model
+			denied.Write(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), principal)))
+	})
+}
+
+// isPublic reports whether urlPath is served without credentials
+func (a *Authenticator) isPublic(urlPath string) bool {
+	return MatchPath(a.publicPaths, urlPath)
+}
+
+// MatchPath reports whether urlPath is one of paths, or starts with one of
+// them that ends in "/"
+func MatchPath(paths []string, urlPath string) bool {
+	for _, path := range paths {
+		if urlPath == path || (strings.HasSuffix(path, "/") && strings.HasPrefix(urlPath, path)) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate tries each credential the request carries. The status is 401 for
+// missing or bad credentials, 413 for a signed body that is too large and 503
+// when too many signed requests are remembered to refuse replays.
+func (a *Authenticator) authenticate(r *http.Request) (*Principal, int, error) {
+	if r.Header.Get(APIKeyHeader) == "" && r.Header.Get(HMACSignatureHeader) != "" {
+		if a.hmac == nil {
+			return nil, http.StatusUnauthorized, errors.New("signed requests are not accepted")
+		}
+		return a.hmac.verify(r)
+	}
+	principal, err := a.Authenticate(r.Header, r.TLS)
+	return principal, http.StatusUnauthorized, err
+}
+
+// Authenticate checks the credentials that do not depend on a request body: an
+// API key or bearer token in header, or the verified client certificate of the
+// connection state. It lets other transports, such as gRPC with its metadata,
+// share the HTTP configuration; signed requests are only accepted over HTTP.
+func (a *Authenticator) Authenticate(header http.Header, state *tls.ConnectionState) (*Principal, error) {
+	switch {
+	case header.Get(APIKeyHeader) != "":
+		return a.checkAPIKey(header.Get(APIKeyHeader))
+	case header.Get(HMACSignatureHeader) != "":
+		return nil, errors.New("signed requests are only accepted by the HTTP API")
+	case strings.HasPrefix(header.Get("Authorization"), "Bearer "):
+		if a.jwt == nil {
+			return nil, errors.New("bearer tokens are not accepted")
+		}
+		return a.jwt.verify(strings.TrimPrefix(header.Get("Authorization"), "Bearer "))
+	case state != nil && len(state.VerifiedChains) > 0 && len(a.clientCerts) > 0:
+		return a.checkClientCert(state.VerifiedChains[0][0])
+	}
+	return nil, errors.New("authentication required")
+}
+
+// checkAPIKey finds the configured key whose hash matches key
+func (a *Authenticator) checkAPIKey(key string) (*Principal, error) {
+	digest := sha256.Sum256([]byte(key))
+	hexDigest := []byte(hex.EncodeToString(digest[:]))
+	for _, apiKey := range a.apiKeys {
+		if subtle.ConstantTimeCompare(hexDigest, []byte(apiKey.SHA256)) == 1 {
+			return &Principal{ID: apiKey.ID, Method: MethodAPIKey, Grant: apiKey.Grant}, nil
+		}
+	}
+	return nil, errors.New("invalid API key")
+}
+
+// checkClientCert finds the configured client cert whose subject matches cert,
+// a leaf the TLS handshake has already verified against the client CA bundle
+func (a *Authenticator) checkClientCert(cert *x509.Certificate) (*Principal, error) {
+	for _, clientCert := range a.clientCerts {
+		if clientCert.Subject == cert.Subject.CommonName || clientCert.Subject == cert.Subject.String() {
+			return &Principal{ID: clientCert.Subject, Method: MethodClientCert, Grant: clientCert.Grant}, nil
+		}
+	}
+	return nil, fmt.Errorf("client certificate %q is not allowed", cert.Subject.String())
+}
+
+// classify maps a request to its operation and, for model endpoints, the model in its path
+func classify(r *http.Request) (operation, model string) {
+	urlPath := r.URL.Path
+	switch {
+	case urlPath == "/limits" || strings.HasPrefix(urlPath, "/limits/"):
+		return OpAdmin, ""
+	case strings.HasPrefix(urlPath, "/validate/batch/"):
+		return OpBatch, ""
+	case urlPath == "/validate":
+		if r.Header.Get("X-Batch-ID") != "" || r.Header.Get("X-Batch-Complete") != "" {
+			return OpBatch, ""
+		}
+		return OpValidate, ""
+	case r.Method == http.MethodPost && strings.HasPrefix(urlPath, "/validate/"):
+		// /validate/{family}[/{variant}][/stream] serves model "family[.variant]"
+		endpoint := strings.TrimSuffix(strings.TrimPrefix(urlPath, "/validate/"), "/stream")
+		return OpValidate, strings.ReplaceAll(endpoint, "/", ".")
+	}
+	return OpRead, ""
+}
@@ -0,0 +1,455 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// hashKey returns the config form of an API key
+func hashKey(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(digest[:])
+}
+
+// serve runs req through the middleware and returns the response and the principal the handler saw
+func serve(t *testing.T, a *Authenticator, req *http.Request) (*httptest.ResponseRecorder, *Principal, []byte) {
+	t.Helper()
+	var principal *Principal
+	var body []byte
+	handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = PrincipalFrom(r.Context())
+		body, _ = io.ReadAll(r.Body)
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w, principal, body
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"no credentials", Config{}},
+		{"bad hash", Config{APIKeys: []APIKey{{ID: "k", SHA256: "abc"}}}},
+		{"unknown operation", Config{APIKeys: []APIKey{{ID: "k", SHA256: hashKey("x"), Grant: Grant{Operations: []string{"delete"}}}}}},
+		{"short hmac secret", Config{HMACKeys: []HMACKey{{ID: "h", Secret: "short"}}}},
+		{"missing jwks", Config{JWT: &JWTConfig{JWKSFile: "/nonexistent/jwks.json"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestMiddleware_APIKey(t *testing.T) {
+	a, err := New(Config{APIKeys: []APIKey{
+		{ID: "ingest", SHA256: hashKey("secret-key"), Grant: Grant{Models: []string{"incident", "api.*"}, Operations: []string{OpValidate}}},
+	}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		key      string
+		expected int
+	}{
+		{"public health", "GET", "/health", "", http.StatusOK},
+		{"public metrics", "GET", "/metrics", "", http.StatusOK},
+		{"missing key", "POST", "/validate/incident", "", http.StatusUnauthorized},
+		{"wrong key", "POST", "/validate/incident", "other", http.StatusUnauthorized},
+		{"granted model", "POST", "/validate/incident", "secret-key", http.StatusOK},
+		{"granted stream", "POST", "/validate/incident/stream", "secret-key", http.StatusOK},
+		{"granted pattern", "POST", "/validate/api/response", "secret-key", http.StatusOK},
+		{"other model", "POST", "/validate/github", "secret-key", http.StatusForbidden},
+		{"batch operation", "POST", "/validate/batch/start", "secret-key", http.StatusForbidden},
+		{"read operation", "GET", "/models", "secret-key", http.StatusForbidden},
+		{"admin operation", "PUT", "/limits/models/incident", "secret-key", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			w, principal, _ := serve(t, a, req)
+			if w.Code != tt.expected {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expected, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected WWW-Authenticate on 401")
+			}
+			if tt.key == "secret-key" && w.Code == http.StatusOK && (principal == nil || principal.ID != "ingest") {
+				t.Errorf("Expected principal ingest, got %+v", principal)
+			}
+		})
+	}
+}
+
+func TestMiddleware_PublicPathsConfigurable(t *testing.T) {
+	a, err := New(Config{PublicPaths: []string{"/swagger/"}, APIKeys: []APIKey{{ID: "k", SHA256: hashKey("k")}}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if w, _, _ := serve(t, a, httptest.NewRequest("GET", "/swagger/index.html", nil)); w.Code != http.StatusOK {
+		t.Errorf("Expected prefix path to be public, got %d", w.Code)
+	}
+	if w, _, _ := serve(t, a, httptest.NewRequest("GET", "/health", nil)); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected /health to need credentials when not listed, got %d", w.Code)
+	}
+}
+
+func TestMiddleware_HMAC(t *testing.T) {
+	a, err := New(Config{HMACKeys: []HMACKey{{ID: "partner", Secret: testSecret}}, HMACMaxBodyBytes: 64})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	now := time.Unix(1760000000, 0)
+	a.hmac.now = func() time.Time { return now }
+
+	nonces := 0
+	signed := func(body, timestamp string) *http.Request {
+		nonces++
+		req := httptest.NewRequest("POST", "/validate?fail_fast=true", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Batch-ID", "batch-1")
+		req.Header.Set("X-Batch-Chunk-ID", "chunk-1")
+		req.Header.Set(HMACKeyIDHeader, "partner")
+		req.Header.Set(HMACTimestampHeader, timestamp)
+		req.Header.Set(HMACNonceHeader, "nonce-"+strconv.Itoa(nonces))
+		req.Header.Set(HMACSignatureHeader, Sign([]byte(testSecret), "POST", "/validate?fail_fast=true", req.Header, []byte(body)))
+		return req
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	t.Run("valid signature keeps the body readable", func(t *testing.T) {
+		w, principal, body := serve(t, a, signed(`{"model_type":"incident"}`, timestamp))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if principal == nil || principal.Method != MethodHMAC || string(body) != `{"model_type":"incident"}` {
+			t.Errorf("Unexpected principal %+v or body %q", principal, body)
+		}
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		req := signed(`{"model_type":"incident"}`, timestamp)
+		req.Body = io.NopCloser(strings.NewReader(`{"model_type":"github"}`))
+		if w, _, _ := serve(t, a, req); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401, got %d", w.Code)
+		}
+	})
+
+	for _, header := range []string{"Content-Type", "X-Batch-ID", "X-Batch-Chunk-ID", "X-Batch-Complete", HMACNonceHeader} {
+		t.Run("tampered "+header, func(t *testing.T) {
+			req := signed(`{}`, timestamp)
+			req.Header.Set(header, "other")
+			if w, _, _ := serve(t, a, req); w.Code != http.StatusUnauthorized {
+				t.Errorf("Expected 401, got %d", w.Code)
+			}
+		})
+	}
+
+	t.Run("removed X-Batch-Complete", func(t *testing.T) {
+		req := signed(`{}`, timestamp)
+		req.Header.Set("X-Batch-Complete", "true")
+		req.Header.Set(HMACSignatureHeader, Sign([]byte(testSecret), "POST", "/validate?fail_fast=true", req.Header, []byte(`{}`)))
+		req.Header.Del("X-Batch-Complete")
+		if w, _, _ := serve(t, a, req); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("missing nonce", func(t *testing.T) {
+		req := signed(`{}`, timestamp)
+		req.Header.Del(HMACNonceHeader)
+		req.Header.Set(HMACSignatureHeader, Sign([]byte(testSecret), "POST", "/validate?fail_fast=true", req.Header, []byte(`{}`)))
+		if w, _, _ := serve(t, a, req); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("stale timestamp", func(t *testing.T) {
+		stale := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+		if w, _, _ := serve(t, a, signed(`{}`, stale)); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		if w, _, _ := serve(t, a, signed(strings.Repeat("x", 65), timestamp)); w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected 413, got %d", w.Code)
+		}
+	})
+}
+
+func TestMiddleware_HMACReplay(t *testing.T) {
+	a, err := New(Config{HMACKeys: []HMACKey{{ID: "partner", Secret: testSecret}}, HMACMaxNonces: 2})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	now := time.Unix(1760000000, 0)
+	a.hmac.now = func() time.Time { return now }
+
+	signed := func(nonce string, sent time.Time) *http.Request {
+		req := httptest.NewRequest("POST", "/validate", strings.NewReader(`{}`))
+		req.Header.Set(HMACKeyIDHeader, "partner")
+		req.Header.Set(HMACTimestampHeader, strconv.FormatInt(sent.Unix(), 10))
+		req.Header.Set(HMACNonceHeader, nonce)
+		req.Header.Set(HMACSignatureHeader, Sign([]byte(testSecret), "POST", "/validate", req.Header, []byte(`{}`)))
+		return req
+	}
+	send := func(nonce string, sent time.Time) int {
+		w, _, _ := serve(t, a, signed(nonce, sent))
+		return w.Code
+	}
+
+	if code := send("a", now); code != http.StatusOK {
+		t.Fatalf("Expected first request to pass, got %d", code)
+	}
+	if code := send("a", now); code != http.StatusUnauthorized {
+		t.Errorf("Expected replay to get 401, got %d", code)
+	}
+	if code := send("b", now.Add(-4*time.Minute)); code != http.StatusOK {
+		t.Fatalf("Expected new nonce to pass, got %d", code)
+	}
+	if code := send("c", now); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 with the nonce set full, got %d", code)
+	}
+
+	// Once b's timestamp leaves the window it is dropped, making room for c
+	now = now.Add(2 * time.Minute)
+	if code := send("c", now); code != http.StatusOK {
+		t.Errorf("Expected nonce to pass after expired ones are dropped, got %d", code)
+	}
+	if code := send("a", now); code != http.StatusUnauthorized {
+		t.Errorf("Expected replay within the window to get 401, got %d", code)
+	}
+}
+
+// jwtFixture signs tokens with an RSA key published in a JWKS file
+type jwtFixture struct {
+	key  *rsa.PrivateKey
+	jwks string
+}
+
+func newJWTFixture(t *testing.T) *jwtFixture {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	encode := func(n *big.Int) string { return base64.RawURLEncoding.EncodeToString(n.Bytes()) }
+	jwks, _ := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": encode(key.N), "e": encode(big.NewInt(int64(key.E)))},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": encode(ecKey.X), "y": encode(ecKey.Y)},
+	}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return &jwtFixture{key: key, jwks: path}
+}
+
+func (f *jwtFixture) token(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "rsa-1"
+	signed, err := token.SignedString(f.key)
+	if err != nil {
+		t.Fatalf("SignedString failed: %v", err)
+	}
+	return signed
+}
+
+func TestMiddleware_JWT(t *testing.T) {
+	fixture := newJWTFixture(t)
+	a, err := New(Config{JWT: &JWTConfig{JWKSFile: fixture.jwks, Issuer: "https://idp.example", Audience: "validator"}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	valid := jwt.MapClaims{
+		"sub":    "pipeline-7",
+		"iss":    "https://idp.example",
+		"aud":    "validator",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"scope":  "validate batch",
+		"models": []string{"incident"},
+	}
+	with := func(key string, value interface{}) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+	hsToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, valid).SignedString([]byte(testSecret))
+
+	tests := []struct {
+		name     string
+		token    string
+		path     string
+		expected int
+	}{
+		{"valid token", fixture.token(t, valid), "/validate/incident", http.StatusOK},
+		{"model not in claim", fixture.token(t, valid), "/validate/github", http.StatusForbidden},
+		{"operation not in scope", fixture.token(t, valid), "/models", http.StatusForbidden},
+		{"expired", fixture.token(t, with("exp", time.Now().Add(-time.Minute).Unix())), "/validate/incident", http.StatusUnauthorized},
+		{"no expiry", fixture.token(t, with("exp", nil)), "/validate/incident", http.StatusUnauthorized},
+		{"wrong issuer", fixture.token(t, with("iss", "https://evil.example")), "/validate/incident", http.StatusUnauthorized},
+		{"wrong audience", fixture.token(t, with("aud", "other")), "/validate/incident", http.StatusUnauthorized},
+		{"missing models claim", fixture.token(t, with("models", nil)), "/validate/incident", http.StatusUnauthorized},
+		{"shared-secret algorithm", hsToken, "/validate/incident", http.StatusUnauthorized},
+		{"garbage", "not.a.token", "/validate/incident", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, nil)
+			if tt.path == "/models" {
+				req.Method = "GET"
+			}
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w, principal, _ := serve(t, a, req)
+			if w.Code != tt.expected {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expected, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusOK && (principal == nil || principal.ID != "pipeline-7" || principal.Method != MethodJWT) {
+				t.Errorf("Unexpected principal %+v", principal)
+			}
+		})
+	}
+}
+
+// withClientCert returns req as received over a TLS connection whose client
+// certificate with subject was verified
+func withClientCert(req *http.Request, subject pkix.Name) *http.Request {
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: subject}}}}
+	return req
+}
+
+func TestMiddleware_ClientCert(t *testing.T) {
+	a, err := New(Config{
+		APIKeys: []APIKey{{ID: "admin", SHA256: hashKey("admin-key")}},
+		ClientCerts: []ClientCert{
+			{Subject: "deploy-bot", Grant: Grant{Models: []string{"deployment"}, Operations: []string{OpValidate}}},
+			{Subject: "CN=github-relay,O=Example", Grant: Grant{Models: []string{"github"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	deployBot := pkix.Name{CommonName: "deploy-bot", Organization: []string{"Example"}}
+	tests := []struct {
+		name      string
+		req       *http.Request
+		status    int
+		principal string
+	}{
+		{"common name", withClientCert(httptest.NewRequest("POST", "/validate/deployment", nil), deployBot), http.StatusOK, "deploy-bot"},
+		{"distinguished name", withClientCert(httptest.NewRequest("POST", "/validate/github", nil), pkix.Name{CommonName: "github-relay", Organization: []string{"Example"}}), http.StatusOK, "CN=github-relay,O=Example"},
+		{"model not granted", withClientCert(httptest.NewRequest("POST", "/validate/incident", nil), deployBot), http.StatusForbidden, ""},
+		{"unknown subject", withClientCert(httptest.NewRequest("POST", "/validate/deployment", nil), pkix.Name{CommonName: "stranger"}), http.StatusUnauthorized, ""},
+		{"plain connection", httptest.NewRequest("POST", "/validate/deployment", nil), http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, principal, _ := serve(t, a, tt.req)
+			if w.Code != tt.status {
+				t.Fatalf("Expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.principal != "" && (principal == nil || principal.ID != tt.principal || principal.Method != MethodClientCert) {
+				t.Errorf("Expected principal %s, got %+v", tt.principal, principal)
+			}
+		})
+	}
+
+	// Explicit credentials win over the connection's certificate
+	req := withClientCert(httptest.NewRequest("GET", "/models", nil), deployBot)
+	req.Header.Set(APIKeyHeader, "admin-key")
+	if _, principal, _ := serve(t, a, req); principal == nil || principal.ID != "admin" {
+		t.Errorf("Expected the API key principal, got %+v", principal)
+	}
+
+	if _, err := New(Config{ClientCerts: []ClientCert{{}}}); err == nil {
+		t.Error("Expected error for a client cert without a subject")
+	}
+}
+
+func TestAllowModel(t *testing.T) {
+	if err := AllowModel(context.Background(), OpValidate, "incident"); err != nil {
+		t.Errorf("Expected requests without a principal to be allowed, got %v", err)
+	}
+
+	principal := &Principal{ID: "ingest", Grant: Grant{Models: []string{"incident"}}}
+	ctx := context.WithValue(context.Background(), principalKey{}, principal)
+	if err := AllowModel(ctx, OpBatch, "incident"); err != nil {
+		t.Errorf("Expected granted model to be allowed, got %v", err)
+	}
+	if err := AllowModel(ctx, OpValidate, "github"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.json")
+	data := []byte(`{"api_keys":[{"id":"ingest","sha256":"` + hashKey("k") + `","models":["incident"]}]}`)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(cfg.APIKeys) != 1 || cfg.APIKeys[0].Models[0] != "incident" || cfg.PublicPaths != nil {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
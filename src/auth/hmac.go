@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers of an HMAC-signed request
+const (
+	HMACKeyIDHeader     = "X-Auth-Key-ID"
+	HMACTimestampHeader = "X-Auth-Timestamp" // Unix seconds
+	HMACNonceHeader     = "X-Auth-Nonce"     // Unique per request and key, up to 128 characters
+	HMACSignatureHeader = "X-Auth-Signature" // Hex HMAC-SHA256 of StringToSign
+)
+
+// SignedHeaders are the request headers in the string to sign, in order.
+// Absent headers are signed as empty lines.
+var SignedHeaders = []string{HMACTimestampHeader, HMACNonceHeader, "Content-Type", "X-Batch-ID", "X-Batch-Chunk-ID", "X-Batch-Complete"}
+
+// hmacMaxSkew is how far a signed request's timestamp may be from the server clock
+const hmacMaxSkew = 5 * time.Minute
+
+// defaultHMACMaxBodyBytes bounds the body buffered to check a signature
+const defaultHMACMaxBodyBytes = 10 << 20
+
+// defaultHMACMaxNonces bounds the nonces remembered to refuse replays
+const defaultHMACMaxNonces = 100000
+
+// maxHMACNonceLength bounds the X-Auth-Nonce header
+const maxHMACNonceLength = 128
+
+// nonceSweepInterval is how often nonces whose timestamp left the window are dropped
+const nonceSweepInterval = time.Minute
+
+// StringToSign is what a client signs:
+//
+//	METHOD \n REQUEST-URI \n TIMESTAMP \n NONCE \n CONTENT-TYPE \n X-BATCH-ID \n X-BATCH-CHUNK-ID \n X-BATCH-COMPLETE \n hex(SHA-256(body))
+//
+// with the values of SignedHeaders taken from header.
+func StringToSign(method, requestURI string, header http.Header, body []byte) string {
+	var b strings.Builder
+	b.WriteString(method + "\n" + requestURI + "\n")
+	for _, name := range SignedHeaders {
+		b.WriteString(header.Get(name) + "\n")
+	}
+	bodyHash := sha256.Sum256(body)
+	b.WriteString(hex.EncodeToString(bodyHash[:]))
+	return b.String()
+}
+
+// Sign returns the X-Auth-Signature value for a request with header
+func Sign(secret []byte, method, requestURI string, header http.Header, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(StringToSign(method, requestURI, header, body)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// hmacVerifier checks signed requests against the configured secrets
+type hmacVerifier struct {
+	keys         map[string]HMACKey
+	secrets      map[string][]byte
+	maxBodyBytes int64
+	now          func() time.Time
+
+	mu        sync.Mutex
+	nonces    map[string]time.Time // Key ID and nonce of accepted requests, until their timestamp leaves the window
+	maxNonces int
+	lastSweep time.Time
+}
+
+func newHMACVerifier(keys []HMACKey, maxBodyBytes int64, maxNonces int) (*hmacVerifier, error) {
+	v := &hmacVerifier{
+		keys:         make(map[string]HMACKey),
+		secrets:      make(map[string][]byte),
+		maxBodyBytes: maxBodyBytes,
+		now:          time.Now,
+		nonces:       make(map[string]time.Time),
+		maxNonces:    maxNonces,
+	}
+	if v.maxBodyBytes <= 0 {
+		v.maxBodyBytes = defaultHMACMaxBodyBytes
+	}
+	if v.maxNonces <= 0 {
+		v.maxNonces = defaultHMACMaxNonces
+	}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, fmt.Errorf("hmac key without an id")
+		}
+		secret := key.Secret
+		if key.SecretEnv != "" {
+			secret = os.Getenv(key.SecretEnv)
+		}
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("hmac key %s: secret must be at least 32 bytes", key.ID)
+		}
+		if err := checkGrant(key.Grant); err != nil {
+			return nil, fmt.Errorf("hmac key %s: %w", key.ID, err)
+		}
+		v.keys[key.ID] = key
+		v.secrets[key.ID] = []byte(secret)
+	}
+	return v, nil
+}
+
+// verify checks the signature headers of r and refuses nonces seen within the
+// timestamp window. The body is read to hash it and replaced so the handler
+// can read it again.
+func (v *hmacVerifier) verify(r *http.Request) (*Principal, int, error) {
+	keyID := r.Header.Get(HMACKeyIDHeader)
+	secret, ok := v.secrets[keyID]
+	if !ok {
+		return nil, http.StatusUnauthorized, errors.New("unknown signing key")
+	}
+
+	timestamp := r.Header.Get(HMACTimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, http.StatusUnauthorized, fmt.Errorf("%s must be unix seconds", HMACTimestampHeader)
+	}
+	if skew := v.now().Sub(time.Unix(seconds, 0)); skew > hmacMaxSkew || skew < -hmacMaxSkew {
+		return nil, http.StatusUnauthorized, errors.New("signature timestamp outside the allowed window")
+	}
+	nonce := r.Header.Get(HMACNonceHeader)
+	if nonce == "" || len(nonce) > maxHMACNonceLength {
+		return nil, http.StatusUnauthorized, fmt.Errorf("%s must be 1 to %d characters", HMACNonceHeader, maxHMACNonceLength)
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, err = io.ReadAll(io.LimitReader(r.Body, v.maxBodyBytes+1))
+		r.Body.Close()
+		if err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("failed to read signed body: %w", err)
+		}
+		if int64(len(body)) > v.maxBodyBytes {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("signed bodies are limited to %d bytes", v.maxBodyBytes)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	expected := Sign(secret, r.Method, r.URL.RequestURI(), r.Header, body)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(r.Header.Get(HMACSignatureHeader)))) {
+		return nil, http.StatusUnauthorized, errors.New("invalid request signature")
+	}
+	// Nonces are only remembered once the signature holds, so unsigned requests cannot fill the set
+	if status, err := v.useNonce(keyID+"\n"+nonce, time.Unix(seconds, 0).Add(hmacMaxSkew)); err != nil {
+		return nil, status, err
+	}
+
+	key := v.keys[keyID]
+	return &Principal{ID: key.ID, Method: MethodHMAC, Grant: key.Grant}, http.StatusOK, nil
+}
+
+// useNonce remembers nonce until expires, the last moment its timestamp is
+// accepted. It fails for a nonce already remembered, and with 503 when the set
+// is full of nonces that have not expired yet.
+func (v *hmacVerifier) useNonce(nonce string, expires time.Time) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	now := v.now()
+	if len(v.nonces) >= v.maxNonces || now.Sub(v.lastSweep) > nonceSweepInterval {
+		for seen, until := range v.nonces {
+			if now.After(until) {
+				delete(v.nonces, seen)
+			}
+		}
+		v.lastSweep = now
+	}
+	if until, ok := v.nonces[nonce]; ok && !now.After(until) {
+		return http.StatusUnauthorized, errors.New("signed request replayed")
+	}
+	if len(v.nonces) >= v.maxNonces {
+		return http.StatusServiceUnavailable, errors.New("too many signed requests in the timestamp window")
+	}
+	v.nonces[nonce] = expires
+	return http.StatusOK, nil
+}
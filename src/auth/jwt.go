@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTConfig verifies bearer tokens against public keys in a local JWKS file
+type JWTConfig struct {
+	JWKSFile        string `json:"jwks_file"`
+	Issuer          string `json:"issuer,omitempty"`           // Required iss, when set
+	Audience        string `json:"audience,omitempty"`         // Required aud, when set
+	ModelsClaim     string `json:"models_claim,omitempty"`     // Claim listing allowed models (default "models")
+	OperationsClaim string `json:"operations_claim,omitempty"` // Claim listing allowed operations (default "scope")
+}
+
+// jwtMethods are the asymmetric algorithms accepted; shared-secret HS* tokens are not
+var jwtMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// jwtVerifier checks bearer tokens and maps their claims to a Grant
+type jwtVerifier struct {
+	cfg    JWTConfig
+	keys   map[string]crypto.PublicKey
+	parser *jwt.Parser
+}
+
+func newJWTVerifier(cfg JWTConfig) (*jwtVerifier, error) {
+	if cfg.JWKSFile == "" {
+		return nil, fmt.Errorf("jwt: jwks_file is required")
+	}
+	if cfg.ModelsClaim == "" {
+		cfg.ModelsClaim = "models"
+	}
+	if cfg.OperationsClaim == "" {
+		cfg.OperationsClaim = "scope"
+	}
+	keys, err := loadJWKS(cfg.JWKSFile)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods(jwtMethods), jwt.WithExpirationRequired()}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	return &jwtVerifier{cfg: cfg, keys: keys, parser: jwt.NewParser(options...)}, nil
+}
+
+// verify checks the token's signature, expiry, issuer and audience. Tokens must
+// carry both the models and the operations claim; "*" grants everything.
+func (v *jwtVerifier) verify(tokenString string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(tokenString, claims, v.keyFor); err != nil {
+		return nil, fmt.Errorf("invalid bearer token: %w", err)
+	}
+
+	subject, _ := claims.GetSubject()
+	models, err := claimList(claims, v.cfg.ModelsClaim)
+	if err != nil {
+		return nil, err
+	}
+	operations, err := claimList(claims, v.cfg.OperationsClaim)
+	if err != nil {
+		return nil, err
+	}
+	return &Principal{ID: subject, Method: MethodJWT, Grant: Grant{Models: models, Operations: operations}}, nil
+}
+
+// keyFor picks the JWKS key named by the token's kid, or the only key when there is no kid
+func (v *jwtVerifier) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+	key, ok := v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+// claimList reads a claim holding a list, either as a JSON array or as a
+// space-separated string like the OAuth scope claim
+func claimList(claims jwt.MapClaims, name string) ([]string, error) {
+	var values []string
+	switch claim := claims[name].(type) {
+	case string:
+		values = strings.Fields(claim)
+	case []interface{}:
+		for _, item := range claim {
+			if value, ok := item.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("bearer token has no %q claim", name)
+	}
+	return values, nil
+}
+
+// jsonWebKey holds the JWK members needed for RSA, EC and Ed25519 public keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// loadJWKS reads the public signing keys of a JWKS file, by key ID
+func loadJWKS(filename string) (map[string]crypto.PublicKey, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS %s: %w", filename, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("JWKS key %d (%q): %w", i, jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS %s has no signing keys", filename)
+	}
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("EC point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+// decodeBigInt decodes a base64url-encoded unsigned big-endian integer
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
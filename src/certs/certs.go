@@ -0,0 +1,195 @@
+// Package certs serves the server's TLS certificate and verifies client
+// certificates, reloading both when their files change so certificates can be
+// rotated without a restart
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Client certificate modes, for Config.ClientAuth
+const (
+	ClientAuthRequire  = "require"  // Handshakes without a valid client certificate fail
+	ClientAuthOptional = "optional" // A client certificate is verified when one is presented
+)
+
+// Config names the PEM files to serve and trust
+type Config struct {
+	CertFile     string // Certificate chain, leaf first
+	KeyFile      string // Private key for the leaf
+	ClientCAFile string // CA bundle for client certificates; empty means none are requested
+	ClientAuth   string // One of the ClientAuth constants; empty means ClientAuthRequire
+}
+
+// Reloader holds the loaded certificate and client CAs and swaps them when
+// Reload finds the files changed
+type Reloader struct {
+	cfg     Config
+	mu      sync.Mutex // Serializes Reload
+	current atomic.Pointer[loaded]
+}
+
+// loaded is one consistent set of files and what was parsed from them
+type loaded struct {
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	raw       [][]byte // Contents of the cert, key and CA files, to detect changes
+}
+
+// New loads the files in cfg, failing if any is missing or invalid
+func New(cfg Config) (*Reloader, error) {
+	switch cfg.ClientAuth {
+	case "":
+		cfg.ClientAuth = ClientAuthRequire
+	case ClientAuthRequire, ClientAuthOptional:
+	default:
+		return nil, fmt.Errorf("unknown client auth mode %q (want require or optional)", cfg.ClientAuth)
+	}
+	r := &Reloader{cfg: cfg}
+	raw, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	l, err := r.parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	r.current.Store(l)
+	return r, nil
+}
+
+// read returns the contents of every configured file
+func (r *Reloader) read() ([][]byte, error) {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	raw := make([][]byte, len(files))
+	for i, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS file: %w", err)
+		}
+		raw[i] = data
+	}
+	return raw, nil
+}
+
+// parse builds the certificate and CA pool from the contents returned by read
+func (r *Reloader) parse(raw [][]byte) (*loaded, error) {
+	cert, err := tls.X509KeyPair(raw[0], raw[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS certificate %s: %w", r.cfg.CertFile, err)
+	}
+	l := &loaded{cert: &cert, raw: raw}
+	if r.cfg.ClientCAFile != "" {
+		l.clientCAs = x509.NewCertPool()
+		if !l.clientCAs.AppendCertsFromPEM(raw[2]) {
+			return nil, fmt.Errorf("no certificates found in client CA file %s", r.cfg.ClientCAFile)
+		}
+	}
+	return l, nil
+}
+
+// Reload rereads the files and reports whether they changed. Changed files that
+// do not parse are an error and the previous certificate stays in use, so a
+// rotation caught half-written is picked up on the next call.
+func (r *Reloader) Reload() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	raw, err := r.read()
+	if err != nil {
+		return false, err
+	}
+	if sameFiles(raw, r.current.Load().raw) {
+		return false, nil
+	}
+	l, err := r.parse(raw)
+	if err != nil {
+		return false, err
+	}
+	r.current.Store(l)
+	return true, nil
+}
+
+func sameFiles(a, b [][]byte) bool {
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Watch calls Reload every interval until ctx is cancelled, logging each change
+// and failure
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed, err := r.Reload()
+		switch {
+		case err != nil:
+			slog.Warn("Failed to reload TLS certificate, keeping the previous one", "error", err)
+		case changed:
+			slog.Info("TLS certificate reloaded", "cert_file", r.cfg.CertFile, "expires", r.NotAfter())
+		}
+	}
+}
+
+// NotAfter returns when the served certificate expires
+func (r *Reloader) NotAfter() time.Time {
+	cert := r.current.Load().cert
+	if cert.Leaf == nil {
+		return time.Time{}
+	}
+	return cert.Leaf.NotAfter
+}
+
+// TLSConfig returns a server config that always presents the latest
+// certificate and, with a client CA file, verifies client certificates
+// against the latest bundle. Verified chains end up in
+// http.Request.TLS.VerifiedChains.
+func (r *Reloader) TLSConfig() *tls.Config {
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Set here because http.Server only adds them to its own copy, which
+		// GetConfigForClient below does not return
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.current.Load().cert, nil
+		},
+	}
+	if r.cfg.ClientCAFile == "" {
+		return base
+	}
+
+	clientAuth := tls.RequireAndVerifyClientCert
+	if r.cfg.ClientAuth == ClientAuthOptional {
+		clientAuth = tls.VerifyClientCertIfGiven
+	}
+	cfg := base.Clone()
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		// Each handshake gets the CA pool current at the time
+		handshake := base.Clone()
+		handshake.ClientAuth = clientAuth
+		handshake.ClientCAs = r.current.Load().clientCAs
+		return handshake, nil
+	}
+	return cfg
+}
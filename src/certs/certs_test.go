@@ -0,0 +1,282 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCert is a locally generated certificate and its key
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newCert issues a certificate for commonName, signed by parent or self-signed
+// when parent is nil
+func newCert(t *testing.T, commonName string, parent *testCert, isCA bool) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"Validator Tests"}},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if isCA {
+		template.IsCA, template.BasicConstraintsValid = true, true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key}
+}
+
+// writeFiles writes the server certificate and key, and the CA bundle when ca is set
+func writeFiles(t *testing.T, dir string, server, ca *testCert) Config {
+	t.Helper()
+	cfg := Config{CertFile: filepath.Join(dir, "server.crt"), KeyFile: filepath.Join(dir, "server.key")}
+	files := map[string][]byte{cfg.CertFile: server.certPEM, cfg.KeyFile: server.keyPEM}
+	if ca != nil {
+		cfg.ClientCAFile = filepath.Join(dir, "ca.crt")
+		files[cfg.ClientCAFile] = ca.certPEM
+	}
+	for file, data := range files {
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+	}
+	return cfg
+}
+
+// serveTLS serves tlsConfig on a local port and returns its address; the
+// handler echoes the verified client certificate's common name
+func serveTLS(t *testing.T, tlsConfig *tls.Config) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	server := &http.Server{
+		TLSConfig: tlsConfig,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.TLS.VerifiedChains) > 0 {
+				w.Write([]byte(r.TLS.VerifiedChains[0][0].Subject.CommonName))
+			}
+		}),
+	}
+	go server.ServeTLS(listener, "", "")
+	t.Cleanup(func() { server.Close() })
+	return listener.Addr().String()
+}
+
+// dial handshakes with addr trusting root and presenting clientCert when set,
+// and returns the server's leaf certificate
+func dial(addr string, root *testCert, clientCert *testCert) (*x509.Certificate, error) {
+	pool := x509.NewCertPool()
+	pool.AddCert(root.cert)
+	cfg := &tls.Config{RootCAs: pool, ServerName: "localhost"}
+	if clientCert != nil {
+		// Sent even when the server asks for another CA, as a misconfigured client would
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert := clientCert.tlsCertificate()
+			return &cert, nil
+		}
+	}
+	conn, err := tls.Dial("tcp", addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	// TLS 1.3 reports a rejected client certificate on the first read
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+			return nil, err
+		}
+	}
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestReloader_ReloadsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := newCert(t, "localhost", nil, false)
+	cfg := writeFiles(t, dir, first, nil)
+
+	r, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if !r.NotAfter().Equal(first.cert.NotAfter) {
+		t.Errorf("Expected expiry %s, got %s", first.cert.NotAfter, r.NotAfter())
+	}
+	addr := serveTLS(t, r.TLSConfig())
+	if leaf, err := dial(addr, first, nil); err != nil || leaf.SerialNumber.Cmp(first.cert.SerialNumber) != 0 {
+		t.Fatalf("Expected the first certificate, got %v", err)
+	}
+
+	if changed, err := r.Reload(); changed || err != nil {
+		t.Errorf("Expected no change for untouched files, got %v and %v", changed, err)
+	}
+
+	// A half-written rotation keeps the old certificate in service
+	second := newCert(t, "localhost", nil, false)
+	os.WriteFile(cfg.CertFile, second.certPEM, 0o600)
+	if _, err := r.Reload(); err == nil {
+		t.Error("Expected an error for a certificate that does not match its key")
+	}
+	if leaf, err := dial(addr, first, nil); err != nil || leaf.SerialNumber.Cmp(first.cert.SerialNumber) != 0 {
+		t.Fatalf("Expected the first certificate after a failed reload, got %v", err)
+	}
+
+	os.WriteFile(cfg.KeyFile, second.keyPEM, 0o600)
+	if changed, err := r.Reload(); !changed || err != nil {
+		t.Fatalf("Expected the rotated certificate to load, got %v and %v", changed, err)
+	}
+	if leaf, err := dial(addr, second, nil); err != nil || leaf.SerialNumber.Cmp(second.cert.SerialNumber) != 0 {
+		t.Fatalf("Expected the second certificate, got %v", err)
+	}
+}
+
+func TestReloader_Watch(t *testing.T) {
+	dir := t.TempDir()
+	cfg := writeFiles(t, dir, newCert(t, "localhost", nil, false), nil)
+	r, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx, 10*time.Millisecond)
+
+	rotated := newCert(t, "localhost", nil, false)
+	writeFiles(t, dir, rotated, nil)
+	deadline := time.Now().Add(5 * time.Second)
+	for r.current.Load().cert.Leaf.SerialNumber.Cmp(rotated.cert.SerialNumber) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Watch did not pick up the rotated certificate")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReloader_ClientAuth(t *testing.T) {
+	ca := newCert(t, "Test CA", nil, true)
+	server := newCert(t, "localhost", ca, false)
+	client := newCert(t, "deploy-bot", ca, false)
+	stranger := newCert(t, "stranger", nil, false)
+
+	tests := []struct {
+		name       string
+		clientAuth string
+		client     *testCert
+		wantErr    bool
+	}{
+		{"required and presented", ClientAuthRequire, client, false},
+		{"required and missing", ClientAuthRequire, nil, true},
+		{"required from another CA", ClientAuthRequire, stranger, true},
+		{"optional and missing", ClientAuthOptional, nil, false},
+		{"optional from another CA", ClientAuthOptional, stranger, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := writeFiles(t, t.TempDir(), server, ca)
+			cfg.ClientAuth = tt.clientAuth
+			r, err := New(cfg)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			_, err = dial(serveTLS(t, r.TLSConfig()), ca, tt.client)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// The handler sees the verified client certificate
+	cfg := writeFiles(t, t.TempDir(), server, ca)
+	r, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs: pool, ServerName: "localhost", Certificates: []tls.Certificate{client.tlsCertificate()},
+	}}}
+	resp, err := httpClient.Get("https://" + serveTLS(t, r.TLSConfig()))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+	if string(body[:n]) != "deploy-bot" {
+		t.Errorf("Expected the handler to see deploy-bot, got %q", body[:n])
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	dir := t.TempDir()
+	cfg := writeFiles(t, dir, newCert(t, "localhost", nil, false), nil)
+	notPEM := filepath.Join(dir, "not.pem")
+	os.WriteFile(notPEM, []byte("not a certificate"), 0o600)
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"missing cert", func(c *Config) { c.CertFile = filepath.Join(dir, "missing.crt") }},
+		{"invalid key", func(c *Config) { c.KeyFile = notPEM }},
+		{"empty client CA bundle", func(c *Config) { c.ClientCAFile = notPEM }},
+		{"unknown client auth", func(c *Config) { c.ClientAuth = "sometimes" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cfg
+			tt.modify(&c)
+			if _, err := New(c); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
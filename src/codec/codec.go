@@ -0,0 +1,305 @@
+// Package codec reads request bodies and writes responses in the format the
+// client asks for: JSON, YAML, MessagePack or XML. Other formats are converted
+// through JSON, so model structs decode by their json tags and keep their
+// validate tags and business rules, and the JSON input limits still apply.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"goplayground-data-validator/jsonlimit"
+)
+
+// Format is a body encoding for requests and responses
+type Format struct {
+	Name        string   // Used in error messages, such as "YAML"
+	ContentType string   // Sent with responses
+	aliases     []string // Other media types for the format
+	suffix      string   // Structured syntax suffix, such as "+json" in application/problem+json
+
+	// Untyped formats carry every scalar as text. Values are converted to the
+	// type of the field they decode into, and a single element to a list
+	// where the field is a slice.
+	Untyped bool
+
+	decode func(data []byte) (interface{}, error)
+	encode func(w io.Writer, doc interface{}) error // doc is an ordered document, see parseDocument
+}
+
+// The supported formats
+var (
+	JSON = &Format{
+		Name:        "JSON",
+		ContentType: "application/json",
+		aliases:     []string{"text/json"},
+		suffix:      "+json",
+	}
+	YAML = &Format{
+		Name:        "YAML",
+		ContentType: "application/yaml",
+		aliases:     []string{"application/x-yaml", "text/yaml", "text/x-yaml"},
+		decode:      decodeYAML,
+		encode:      encodeYAML,
+	}
+	MessagePack = &Format{
+		Name:        "MessagePack",
+		ContentType: "application/msgpack",
+		aliases:     []string{"application/x-msgpack", "application/vnd.msgpack"},
+		decode:      decodeMsgpack,
+		encode:      encodeMsgpack,
+	}
+	XML = &Format{
+		Name:        "XML",
+		ContentType: "application/xml",
+		aliases:     []string{"text/xml"},
+		suffix:      "+xml",
+		Untyped:     true,
+		decode:      decodeXML,
+		encode:      encodeXML,
+	}
+)
+
+var formats = []*Format{JSON, YAML, MessagePack, XML}
+
+// NotAcceptableMessage is the error for an Accept header that allows none of the formats
+const NotAcceptableMessage = "Accept must allow application/json, application/yaml, application/msgpack or application/xml"
+
+// MediaTypes lists every media type a format is accepted under
+func (f *Format) MediaTypes() []string {
+	return append([]string{f.ContentType}, f.aliases...)
+}
+
+func (f *Format) matches(mediaType string) bool {
+	if mediaType == f.ContentType || (f.suffix != "" && strings.HasSuffix(mediaType, f.suffix)) {
+		return true
+	}
+	for _, alias := range f.aliases {
+		if mediaType == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// ForContentType returns the format of a Content-Type header, and false if it
+// names none of the supported formats
+func ForContentType(header string) (*Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return nil, false
+	}
+	for _, f := range formats {
+		if f.matches(mediaType) {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// ForAccept returns the supported format an Accept header prefers, JSON when
+// it accepts anything, and false when it accepts none of them. The report
+// media types are not formats, see ReportForAccept.
+func ForAccept(header string) (*Format, bool) {
+	if strings.TrimSpace(header) == "" {
+		return JSON, true
+	}
+	var best *Format
+	bestQ := 0.0
+	for _, entry := range acceptEntries(header) {
+		if entry.q <= bestQ {
+			continue // Equal weights keep the first listed
+		}
+		if f, ok := acceptFormat(entry.mediaType); ok {
+			best, bestQ = f, entry.q
+		}
+	}
+	return best, best != nil
+}
+
+// acceptEntry is one media range of an Accept header and its weight
+type acceptEntry struct {
+	mediaType string
+	q         float64
+}
+
+// acceptEntries parses an Accept header in the order it lists media ranges,
+// skipping malformed ones
+func acceptEntries(header string) []acceptEntry {
+	var entries []acceptEntry
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+		entries = append(entries, acceptEntry{mediaType: mediaType, q: q})
+	}
+	return entries
+}
+
+// acceptFormat returns the format an Accept media range selects. Reports
+// carry the +json and +xml suffixes but are not JSON or XML results.
+func acceptFormat(mediaType string) (*Format, bool) {
+	if mediaType == "*/*" || mediaType == "application/*" {
+		return JSON, true
+	}
+	if reportFor(mediaType) != nil {
+		return nil, false
+	}
+	return ForContentType(mediaType)
+}
+
+// Error is a body that is not valid in its format, located by line and
+// column where the format allows
+type Error struct {
+	Format       string
+	Line, Column int // 0 when unknown
+	Err          error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("Invalid %s payload at line %d, column %d: %v", e.Format, e.Line, e.Column, e.Err)
+	case e.Line > 0:
+		return fmt.Sprintf("Invalid %s payload at line %d: %v", e.Format, e.Line, e.Err)
+	}
+	return fmt.Sprintf("Invalid %s payload: %v", e.Format, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// DecodeRequest decodes r's body into v in the format named by its
+// Content-Type and returns that format. Bodies without a supported
+// Content-Type are read as JSON, as they always have been. Exceeded input
+// limits are returned as *jsonlimit.LimitError, anything else as *Error.
+func DecodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) (*Format, error) {
+	f, ok := ForContentType(r.Header.Get("Content-Type"))
+	if !ok || f == JSON {
+		return JSON, jsonError(jsonlimit.DecodeRequest(w, r, v))
+	}
+
+	limits := jsonlimit.Current()
+	body := r.Body
+	if limits.MaxBodyBytes > 0 {
+		body = http.MaxBytesReader(w, r.Body, limits.MaxBodyBytes)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return f, bodyError(err)
+	}
+	return f, f.Decode(data, limits, v)
+}
+
+// jsonError converts the decode errors of jsonlimit.DecodeRequest other than
+// exceeded limits
+func jsonError(err error) error {
+	var limitErr *jsonlimit.LimitError
+	if err == nil || errors.As(err, &limitErr) {
+		return err
+	}
+	var syntaxErr *jsonlimit.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &Error{Format: JSON.Name, Line: syntaxErr.Line, Column: syntaxErr.Column, Err: describe(syntaxErr.Err)}
+	}
+	return &Error{Format: JSON.Name, Err: err}
+}
+
+// Decode decodes data into v under limits
+func (f *Format) Decode(data []byte, limits jsonlimit.Limits, v interface{}) error {
+	if f == JSON {
+		if err := jsonlimit.Check(data, limits); err != nil {
+			return jsonError(err)
+		}
+		return jsonError(json.Unmarshal(data, v))
+	}
+
+	doc, err := f.decode(data)
+	if err != nil {
+		var formatErr *Error
+		if errors.As(err, &formatErr) {
+			formatErr.Format = f.Name // Decoders leave it to break an initialization cycle
+		}
+		return err
+	}
+	if doc == nil {
+		return &Error{Format: f.Name, Err: errors.New("the body holds no document")}
+	}
+	if f.Untyped {
+		doc = coerce(doc, reflect.TypeOf(v))
+	}
+	converted, err := json.Marshal(doc)
+	if err != nil {
+		return &Error{Format: f.Name, Err: err}
+	}
+	if err := jsonlimit.Check(converted, limits); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(converted, v); err != nil {
+		return &Error{Format: f.Name, Err: describe(err)}
+	}
+	return nil
+}
+
+// describe restates a JSON type error without Go type names where it can
+func describe(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return fmt.Errorf("%s must be %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+	return err
+}
+
+// Record converts a record decoded as part of a larger document, such as one
+// element of a data array, for a model struct of type modelStruct. Only
+// untyped formats need it; other formats return record unchanged.
+func (f *Format) Record(record map[string]interface{}, modelStruct reflect.Type) map[string]interface{} {
+	if !f.Untyped || record == nil {
+		return record
+	}
+	converted, _ := coerce(record, modelStruct).(map[string]interface{})
+	return converted
+}
+
+// Write sends v with status in format f. JSON is written by encoding/json;
+// the other formats render the same document with the same field names, in
+// the same order.
+func (f *Format) Write(w http.ResponseWriter, status int, v interface{}) error {
+	w.Header().Add("Vary", "Accept")
+	if f == JSON {
+		w.Header().Set("Content-Type", f.ContentType)
+		w.WriteHeader(status)
+		return json.NewEncoder(w).Encode(v)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	doc, err := parseDocument(data)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := f.encode(&buf, doc); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", f.ContentType)
+	w.WriteHeader(status)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,266 @@
+package codec
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"goplayground-data-validator/jsonlimit"
+)
+
+type owner struct {
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+}
+
+type record struct {
+	ID        string    `json:"id"`
+	Priority  int       `json:"priority"`
+	Active    bool      `json:"active"`
+	Score     float64   `json:"score"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+	Owner     *owner    `json:"owner,omitempty"`
+}
+
+var want = record{
+	ID:        "INC-1",
+	Priority:  3,
+	Active:    true,
+	Score:     0.5,
+	Tags:      []string{"db", "prod"},
+	CreatedAt: time.Date(2025, 1, 4, 10, 30, 0, 0, time.UTC),
+	Owner:     &owner{Name: "ops", Level: 2},
+}
+
+func TestForContentType(t *testing.T) {
+	tests := []struct {
+		header string
+		want   *Format
+	}{
+		{"application/json", JSON},
+		{"application/json; charset=utf-8", JSON},
+		{"application/vnd.api+json", JSON},
+		{"application/yaml", YAML},
+		{"text/x-yaml", YAML},
+		{"application/msgpack", MessagePack},
+		{"application/x-msgpack", MessagePack},
+		{"application/xml", XML},
+		{"text/xml; charset=utf-8", XML},
+		{"application/atom+xml", XML},
+		{"application/x-www-form-urlencoded", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, ok := ForContentType(tt.header)
+		if got != tt.want || ok != (tt.want != nil) {
+			t.Errorf("ForContentType(%q) = %v, %v; want %v", tt.header, got, ok, tt.want)
+		}
+	}
+}
+
+func TestForAccept(t *testing.T) {
+	tests := []struct {
+		header string
+		want   *Format
+	}{
+		{"", JSON},
+		{"*/*", JSON},
+		{"application/*", JSON},
+		{"application/yaml", YAML},
+		{"text/html, application/xml;q=0.9, */*;q=0.8", XML},
+		{"application/json;q=0.5, application/msgpack", MessagePack},
+		{"application/yaml, application/xml", YAML},
+		{"text/html", nil},
+		{"application/yaml;q=0", nil},
+		{"application/junit+xml", nil},
+		{"application/sarif+json, application/yaml;q=0.5", YAML},
+	}
+	for _, tt := range tests {
+		got, ok := ForAccept(tt.header)
+		if got != tt.want || ok != (tt.want != nil) {
+			t.Errorf("ForAccept(%q) = %v, %v; want %v", tt.header, got, ok, tt.want)
+		}
+	}
+}
+
+func decode(t *testing.T, contentType, body string, v interface{}) (*Format, error) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return DecodeRequest(httptest.NewRecorder(), req, v)
+}
+
+func TestDecodeRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"json", "application/json", `{"id":"INC-1","priority":3,"active":true,"score":0.5,"tags":["db","prod"],"created_at":"2025-01-04T10:30:00Z","owner":{"name":"ops","level":2}}`},
+		{"no content type", "", `{"id":"INC-1","priority":3,"active":true,"score":0.5,"tags":["db","prod"],"created_at":"2025-01-04T10:30:00Z","owner":{"name":"ops","level":2}}`},
+		{"yaml", "application/yaml", "id: INC-1\npriority: 3\nactive: true\nscore: 0.5\ntags: [db, prod]\ncreated_at: 2025-01-04T10:30:00Z\nowner:\n  name: ops\n  level: 2\n"},
+		{"xml", "application/xml", `<incident id="INC-1"><priority>3</priority><active>true</active><score>0.5</score>` +
+			`<tags>db</tags><tags>prod</tags><created_at>2025-01-04T10:30:00Z</created_at><owner><name>ops</name><level>2</level></owner></incident>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got record
+			if _, err := decode(t, tt.contentType, tt.body, &got); err != nil {
+				t.Fatalf("DecodeRequest: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	t.Run("xml lists and empty elements", func(t *testing.T) {
+		var got record
+		if _, err := decode(t, "text/xml", `<r><tags>db</tags><priority/><owner/></r>`, &got); err != nil {
+			t.Fatalf("DecodeRequest: %v", err)
+		}
+		if !reflect.DeepEqual(got.Tags, []string{"db"}) || got.Priority != 0 || got.Owner != nil {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("msgpack", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		if err := MessagePack.Write(rec, http.StatusOK, want); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		var got record
+		if _, err := decode(t, "application/msgpack", rec.Body.String(), &got); err != nil {
+			t.Fatalf("DecodeRequest: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+}
+
+func TestDecodeRequestErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		line        int
+		column      int
+		message     string
+	}{
+		{"json syntax", "application/json", "{\n  \"id\": \"INC-1\",\n  }", 3, 3, "Invalid JSON payload at line 3, column 3"},
+		{"json type", "application/json", "{\n  \"priority\": \"high\"\n}", 2, 20, "priority must be int, got string"},
+		{"yaml syntax", "application/yaml", "id: INC-1\n  priority: 3\n", 2, 0, "Invalid YAML payload at line 2: mapping values"},
+		{"yaml type", "application/yaml", "priority: high\n", 0, 0, "priority must be int, got string"},
+		{"xml syntax", "application/xml", "<r>\n  <id>INC-1</r>", 2, 16, "Invalid XML payload at line 2, column 16"},
+		{"xml type", "application/xml", "<r><priority>high</priority></r>", 0, 0, "priority must be int, got string"},
+		{"msgpack truncated", "application/msgpack", "\x82\xa2id", 0, 0, "Invalid MessagePack payload"},
+		{"empty yaml", "application/yaml", "", 0, 0, "no document"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got record
+			_, err := decode(t, tt.contentType, tt.body, &got)
+			var formatErr *Error
+			if !errors.As(err, &formatErr) {
+				t.Fatalf("err = %v, want *Error", err)
+			}
+			if formatErr.Line != tt.line || formatErr.Column != tt.column {
+				t.Errorf("position = %d:%d, want %d:%d (%v)", formatErr.Line, formatErr.Column, tt.line, tt.column, err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("err = %q, want it to contain %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestDecodeRequestLimits(t *testing.T) {
+	defer jsonlimit.Configure(jsonlimit.Current())
+	limits := jsonlimit.DefaultLimits()
+	limits.MaxArrayLength = 2
+	if err := jsonlimit.Configure(limits); err != nil {
+		t.Fatal(err)
+	}
+
+	var got record
+	_, err := decode(t, "application/yaml", "tags: [a, b, c]\n", &got)
+	var limitErr *jsonlimit.LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != jsonlimit.LimitArrayLength || limitErr.Path != "$.tags" {
+		t.Errorf("err = %v, want the array length limit at $.tags", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		format *Format
+		want   string
+	}{
+		{JSON, `{"id":"INC-1","priority":3,`},
+		{YAML, "id: INC-1\npriority: 3\nactive: true\nscore: 0.5\ntags:\n  - db\n  - prod\ncreated_at: \"2025-01-04T10:30:00Z\"\nowner:\n  name: ops\n  level: 2\n"},
+		{XML, "<result>\n  <id>INC-1</id>\n  <priority>3</priority>\n  <active>true</active>\n  <score>0.5</score>\n  <tags>db</tags>\n  <tags>prod</tags>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format.Name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if err := tt.format.Write(rec, http.StatusUnprocessableEntity, want); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if rec.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status = %d", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.format.ContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.format.ContentType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.want)
+			}
+
+			var got record
+			if err := tt.format.Decode(rec.Body.Bytes(), jsonlimit.Current(), &got); err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestWriteXMLNames(t *testing.T) {
+	rec := httptest.NewRecorder()
+	doc := map[string]interface{}{"3rd party": "x", "skipped": nil, "rows": []interface{}{[]interface{}{1, 2}}}
+	if err := XML.Write(rec, http.StatusOK, doc); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{`<entry key="3rd party">x</entry>`, "<rows>\n    <item>1</item>\n    <item>2</item>\n  </rows>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body = %s, want it to contain %s", body, want)
+		}
+	}
+	if strings.Contains(body, "skipped") {
+		t.Errorf("body = %s, want null fields left out", body)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	record := map[string]interface{}{"priority": "2", "active": "false", "tags": "db", "note": "kept"}
+	got := XML.Record(record, reflect.TypeOf(want))
+	wantRecord := map[string]interface{}{"priority": int64(2), "active": false, "tags": []interface{}{"db"}, "note": "kept"}
+	if !reflect.DeepEqual(got, wantRecord) {
+		t.Errorf("XML.Record = %#v, want %#v", got, wantRecord)
+	}
+
+	untouched := map[string]interface{}{"priority": "2"}
+	if got := YAML.Record(untouched, reflect.TypeOf(want)); got["priority"] != "2" {
+		t.Errorf("YAML.Record changed the record: %#v", got)
+	}
+}
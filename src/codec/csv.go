@@ -0,0 +1,352 @@
+package codec
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"goplayground-data-validator/jsonlimit"
+	"goplayground-data-validator/models"
+)
+
+// csvFormat names CSV in errors; CSV is not a Format because it only carries
+// arrays of flat records
+const csvFormat = "CSV"
+
+// CSVMapping is the optional mapping document sent with a CSV body, for
+// exports whose headers are not the JSON names of the model's fields
+type CSVMapping struct {
+	Columns       map[string]string `json:"columns"`        // Header to JSON field name; a dotted name fills a nested object, "-" drops the column
+	ListSeparator string            `json:"list_separator"` // Splits the cells of list fields such as tags; default ","
+	TimeFormats   []string          `json:"time_formats"`   // Go layouts tried before RFC 3339 and the common date forms
+	Delimiter     string            `json:"delimiter"`      // Separates the cells of a line; default ","
+}
+
+// CSVRows are the records of a CSV body and the line each one starts on
+type CSVRows struct {
+	Records []map[string]interface{}
+	Lines   []int
+	File    string // Name of the uploaded file, when the form gives one
+}
+
+// csvTimeLayouts are tried, in order, for time fields after the mapping's own
+var csvTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// IsCSV reports whether a Content-Type header names a CSV body, alone or as
+// the csv part of a multipart form
+func IsCSV(header string) bool {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "text/csv", "application/csv", "multipart/form-data":
+		return true
+	}
+	return false
+}
+
+// ReadCSVRequest reads the CSV body of r for a model struct of type
+// modelStruct. A multipart/form-data body carries the CSV in its "csv" part,
+// after an optional "mapping" part holding a CSVMapping as JSON or YAML.
+func ReadCSVRequest(w http.ResponseWriter, r *http.Request, modelStruct reflect.Type) (*CSVRows, error) {
+	limits := jsonlimit.Current()
+	body := r.Body
+	if limits.MaxBodyBytes > 0 {
+		body = http.MaxBytesReader(w, r.Body, limits.MaxBodyBytes)
+	}
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		rows, err := ReadCSV(body, modelStruct, nil, limits)
+		return rows, bodyError(err)
+	}
+
+	if params["boundary"] == "" {
+		return nil, &Error{Format: csvFormat, Err: errors.New("the multipart Content-Type has no boundary")}
+	}
+	var mapping *CSVMapping
+	parts := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			return nil, &Error{Format: csvFormat, Err: errors.New(`the form has no "csv" part`)}
+		}
+		if err != nil {
+			return nil, bodyError(&Error{Format: csvFormat, Err: err})
+		}
+		switch part.FormName() {
+		case "mapping":
+			if mapping, err = readMapping(part, limits); err != nil {
+				return nil, bodyError(err)
+			}
+		case "csv":
+			rows, err := ReadCSV(part, modelStruct, mapping, limits)
+			if err != nil {
+				return nil, bodyError(err)
+			}
+			rows.File = part.FileName()
+			return rows, nil
+		}
+	}
+}
+
+// readMapping decodes a mapping part; YAML is read for JSON too, as JSON is YAML
+func readMapping(part io.Reader, limits jsonlimit.Limits) (*CSVMapping, error) {
+	data, err := io.ReadAll(part)
+	if err != nil {
+		return nil, err
+	}
+	var mapping CSVMapping
+	if err := YAML.Decode(data, limits, &mapping); err != nil {
+		var formatErr *Error
+		if errors.As(err, &formatErr) {
+			formatErr.Format = "CSV mapping"
+		}
+		return nil, err
+	}
+	if mapping.Delimiter != "" && utf8.RuneCountInString(mapping.Delimiter) != 1 {
+		return nil, &Error{Format: "CSV mapping", Err: errors.New("delimiter must be a single character")}
+	}
+	return &mapping, nil
+}
+
+// bodyError reports a body cut off at the size limit as a *jsonlimit.LimitError
+func bodyError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return &jsonlimit.LimitError{Limit: jsonlimit.LimitBodyBytes, Max: maxBytesErr.Limit}
+	}
+	return err
+}
+
+// csvColumn is where the cells of one column go in a record
+type csvColumn struct {
+	name      string
+	path      []string     // JSON names from the record down to the field
+	fieldType reflect.Type // Nil for columns that are not model fields
+}
+
+// ReadCSV reads a CSV document whose first line is a header. Each header is
+// the JSON name of a field of modelStruct unless mapping renames it, and each
+// cell is converted to the type of its field: numbers, booleans, timestamps,
+// and lists split on the list separator. Empty cells are left out of the
+// record. The array, key and string length limits apply as for JSON bodies.
+func ReadCSV(r io.Reader, modelStruct reflect.Type, mapping *CSVMapping, limits jsonlimit.Limits) (*CSVRows, error) {
+	if mapping == nil {
+		mapping = &CSVMapping{}
+	}
+	reader := csv.NewReader(r)
+	if mapping.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(mapping.Delimiter)
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, &Error{Format: csvFormat, Err: errors.New("the body has no header line")}
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+	if limits.MaxObjectKeys > 0 && len(header) > limits.MaxObjectKeys {
+		return nil, &jsonlimit.LimitError{Limit: jsonlimit.LimitObjectKeys, Max: int64(limits.MaxObjectKeys), Path: "$.data[0]"}
+	}
+	convert := newCSVConverter(mapping)
+	columns := make([]*csvColumn, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		target := name
+		if renamed, ok := mapping.Columns[name]; ok {
+			target = renamed
+		}
+		if target == "" || target == "-" {
+			continue
+		}
+		path := strings.Split(target, ".")
+		columns[i] = &csvColumn{name: target, path: path, fieldType: fieldAt(modelStruct, path)}
+	}
+
+	rows := &CSVRows{}
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, csvError(err)
+		}
+		if limits.MaxArrayLength > 0 && len(rows.Records) == limits.MaxArrayLength {
+			return nil, &jsonlimit.LimitError{Limit: jsonlimit.LimitArrayLength, Max: int64(limits.MaxArrayLength), Path: "$.data"}
+		}
+		line, _ := reader.FieldPos(0)
+		record := make(map[string]interface{}, len(cells))
+		for i, cell := range cells {
+			column := columns[i]
+			if column == nil || strings.TrimSpace(cell) == "" {
+				continue
+			}
+			if limits.MaxStringLength > 0 && len(cell) > limits.MaxStringLength {
+				path := fmt.Sprintf("$.data[%d].%s", len(rows.Records), column.name)
+				return nil, &jsonlimit.LimitError{Limit: jsonlimit.LimitStringLength, Max: int64(limits.MaxStringLength), Path: path}
+			}
+			setPath(record, column.path, convert.value(cell, column.fieldType))
+		}
+		rows.Records = append(rows.Records, record)
+		rows.Lines = append(rows.Lines, line)
+	}
+	if len(rows.Records) == 0 {
+		return nil, &Error{Format: csvFormat, Err: errors.New("the body has no records after the header")}
+	}
+	return rows, nil
+}
+
+// csvError locates a CSV parse error; anything else is a read error
+func csvError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &Error{Format: csvFormat, Line: parseErr.Line, Column: parseErr.Column, Err: parseErr.Err}
+	}
+	if bodyErr := bodyError(err); bodyErr != err {
+		return bodyErr
+	}
+	return &Error{Format: csvFormat, Err: err}
+}
+
+// fieldAt returns the type of the field a dotted JSON path names, or nil
+func fieldAt(t reflect.Type, path []string) reflect.Type {
+	for _, key := range path {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			fieldType, ok := jsonField(t, key)
+			if !ok {
+				return nil
+			}
+			t = fieldType
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return nil
+		}
+	}
+	return t
+}
+
+// setPath sets a value in record, creating the nested objects of a dotted path
+func setPath(record map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := record[key].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			record[key] = child
+		}
+		record = child
+	}
+	record[path[len(path)-1]] = value
+}
+
+// csvConverter converts cells to the types of their fields
+type csvConverter struct {
+	listSeparator string
+	timeLayouts   []string
+}
+
+func newCSVConverter(mapping *CSVMapping) *csvConverter {
+	c := &csvConverter{listSeparator: mapping.ListSeparator}
+	if c.listSeparator == "" {
+		c.listSeparator = ","
+	}
+	c.timeLayouts = append(append(c.timeLayouts, mapping.TimeFormats...), csvTimeLayouts...)
+	return c
+}
+
+// value converts a cell to the type of its field. Cells that do not convert
+// stay text, so their row fails validation with a decode error.
+func (c *csvConverter) value(cell string, t reflect.Type) interface{} {
+	if t == nil {
+		return cell
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	text := strings.TrimSpace(cell)
+
+	switch {
+	case t == timeType:
+		for _, layout := range c.timeLayouts {
+			if parsed, err := time.Parse(layout, text); err == nil {
+				return parsed.Format(time.RFC3339Nano)
+			}
+		}
+		return text
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8:
+		items := []interface{}{}
+		for _, item := range strings.Split(text, c.listSeparator) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, c.value(item, t.Elem()))
+			}
+		}
+		return items
+	case t.Kind() == reflect.Map || t.Kind() == reflect.Struct || t.Kind() == reflect.Interface:
+		// Structured fields can only be given as JSON in a single cell
+		var value interface{}
+		if strings.HasPrefix(text, "{") || strings.HasPrefix(text, "[") {
+			if err := json.Unmarshal([]byte(text), &value); err == nil {
+				return value
+			}
+		}
+		return cell
+	case t.Kind() == reflect.String:
+		return cell
+	case t.Kind() == reflect.Bool:
+		// Spreadsheets export yes and no as often as true and false
+		switch strings.ToLower(text) {
+		case "yes", "y":
+			return true
+		case "no", "n":
+			return false
+		}
+	}
+	return coerce(text, t)
+}
+
+// Renumber reports the rows of result by the line of the CSV each record
+// starts on, which is what someone fixing the file needs, rather than by
+// their position among the records
+func (rows *CSVRows) Renumber(result *models.ArrayValidationResult) {
+	rows.renumber(result.Results)
+	rows.renumber(result.Validated)
+}
+
+func (rows *CSVRows) renumber(results []models.RowValidationResult) {
+	for i := range results {
+		row := &results[i]
+		if row.RowIndex < 0 || row.RowIndex >= len(rows.Lines) {
+			continue
+		}
+		line := rows.Lines[row.RowIndex]
+		if row.RecordIdentifier == models.DetectRecordIdentifier(nil, row.RowIndex) {
+			row.RecordIdentifier = models.DetectRecordIdentifier(nil, line)
+		}
+		row.RowIndex = line
+	}
+}
@@ -0,0 +1,151 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"goplayground-data-validator/jsonlimit"
+	"goplayground-data-validator/models"
+)
+
+var recordType = reflect.TypeOf(record{})
+
+// decodeRecord decodes a CSV record the way array validation does
+func decodeRecord(t *testing.T, fields map[string]interface{}) record {
+	t.Helper()
+	data, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got record
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decoding %s: %v", data, err)
+	}
+	return got
+}
+
+func TestReadCSV(t *testing.T) {
+	body := "\ufeffTicket,priority,active,score,Labels,Opened,owner.name,owner.level,Notes\n" +
+		"INC-1,3,yes,0.5,db;prod,2025-01-04 10:30,ops,2,ignored\n" +
+		"\"INC-2\n(reopened)\",,false,,,2025-01-04T10:30:00Z,,,\n" +
+		"INC-3,high,true,,,,,,\n"
+	mapping := &CSVMapping{
+		Columns:       map[string]string{"Ticket": "id", "Labels": "tags", "Opened": "created_at", "Notes": "-"},
+		ListSeparator: ";",
+	}
+
+	rows, err := ReadCSV(strings.NewReader(body), recordType, mapping, jsonlimit.Current())
+	if err != nil {
+		t.Fatalf("ReadCSV: %v", err)
+	}
+	if !reflect.DeepEqual(rows.Lines, []int{2, 3, 5}) {
+		t.Errorf("Lines = %v, want [2 3 5]", rows.Lines)
+	}
+	if got := decodeRecord(t, rows.Records[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("record 0 = %+v, want %+v", got, want)
+	}
+	if got := decodeRecord(t, rows.Records[1]); got.ID != "INC-2\n(reopened)" || got.Active || got.Owner != nil || !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("record 1 = %+v", got)
+	}
+	if _, ok := rows.Records[1]["priority"]; ok {
+		t.Errorf("record 1 = %v, want empty cells left out", rows.Records[1])
+	}
+	if got := rows.Records[2]["priority"]; got != "high" {
+		t.Errorf("priority = %#v, want the unconvertible text kept", got)
+	}
+	if _, ok := rows.Records[0]["Notes"]; ok {
+		t.Errorf("record 0 = %v, want the dropped column left out", rows.Records[0])
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	limits := jsonlimit.DefaultLimits()
+	limits.MaxArrayLength = 2
+	limits.MaxStringLength = 8
+
+	tests := []struct {
+		name   string
+		body   string
+		line   int
+		column int
+		limit  string
+	}{
+		{"ragged line", "id,priority\nINC-1,3\nINC-2,4,extra\n", 3, 1, ""},
+		{"unterminated quote", "id,priority\nINC-1,\"3\n", 2, 0, ""},
+		{"header only", "id,priority\n", 0, 0, ""},
+		{"empty", "", 0, 0, ""},
+		{"too many rows", "id\nINC-1\nINC-2\nINC-3\n", 0, 0, jsonlimit.LimitArrayLength},
+		{"long cell", "id\nINC-000000001\n", 0, 0, jsonlimit.LimitStringLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCSV(strings.NewReader(tt.body), recordType, nil, limits)
+			if tt.limit != "" {
+				var limitErr *jsonlimit.LimitError
+				if !errors.As(err, &limitErr) || limitErr.Limit != tt.limit {
+					t.Errorf("err = %v, want %s", err, tt.limit)
+				}
+				return
+			}
+			var formatErr *Error
+			if !errors.As(err, &formatErr) {
+				t.Fatalf("err = %v, want *Error", err)
+			}
+			if formatErr.Line != tt.line || (tt.column > 0 && formatErr.Column != tt.column) {
+				t.Errorf("position = %d:%d, want %d:%d (%v)", formatErr.Line, formatErr.Column, tt.line, tt.column, err)
+			}
+		})
+	}
+}
+
+func TestReadCSVRequest(t *testing.T) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	mappingPart, _ := form.CreateFormFile("mapping", "mapping.yaml")
+	mappingPart.Write([]byte("columns:\n  Ticket: id\ndelimiter: \";\"\n"))
+	csvPart, _ := form.CreateFormFile("csv", "incidents.csv")
+	csvPart.Write([]byte("Ticket;priority\nINC-1;3\n"))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/validate?model_type=incident", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if !IsCSV(req.Header.Get("Content-Type")) {
+		t.Fatal("IsCSV = false for a multipart form")
+	}
+	rows, err := ReadCSVRequest(httptest.NewRecorder(), req, recordType)
+	if err != nil {
+		t.Fatalf("ReadCSVRequest: %v", err)
+	}
+	if got := rows.Records[0]; got["id"] != "INC-1" || got["priority"] != int64(3) {
+		t.Errorf("record = %v", got)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/validate?model_type=incident", strings.NewReader("id\nINC-1\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	if _, err := ReadCSVRequest(httptest.NewRecorder(), req, recordType); err != nil {
+		t.Errorf("ReadCSVRequest(text/csv): %v", err)
+	}
+}
+
+func TestCSVRowsRenumber(t *testing.T) {
+	rows := &CSVRows{Lines: []int{2, 4}}
+	result := &models.ArrayValidationResult{Results: []models.RowValidationResult{
+		{RowIndex: 0, RecordIdentifier: "INC-1"},
+		{RowIndex: 1, RecordIdentifier: "row_1"},
+	}}
+	rows.Renumber(result)
+
+	if got := result.Results[0]; got.RowIndex != 2 || got.RecordIdentifier != "INC-1" {
+		t.Errorf("row 0 = %d %s, want 2 INC-1", got.RowIndex, got.RecordIdentifier)
+	}
+	if got := result.Results[1]; got.RowIndex != 4 || got.RecordIdentifier != "row_4" {
+		t.Errorf("row 1 = %d %s, want 4 row_4", got.RowIndex, got.RecordIdentifier)
+	}
+}
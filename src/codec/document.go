@@ -0,0 +1,210 @@
+package codec
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// member is one key of an object
+type member struct {
+	key   string
+	value interface{}
+}
+
+// object is a JSON object that keeps its keys in document order, so responses
+// in other formats list fields in the order the JSON response does
+type object []member
+
+// parseDocument parses JSON into objects, []interface{}, string, json.Number,
+// bool and nil values
+func parseDocument(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return readValue(dec)
+}
+
+func readValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	if delim == '{' {
+		obj := object{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{key: key.(string), value: value})
+		}
+		_, err = dec.Token()
+		return obj, err
+	}
+
+	items := []interface{}{}
+	for dec.More() {
+		value, err := readValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+	}
+	_, err = dec.Token()
+	return items, err
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// coerce converts the text of an untyped document to the kinds of the fields
+// of t that it decodes into. Text that does not parse is left for the JSON
+// decode to report, and types that decode themselves, such as time.Time, get
+// their text unchanged.
+func coerce(value interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return value
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return emptyToNil(value)
+		}
+		coerceFields(fields, t)
+		return fields
+	case reflect.Map:
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return emptyToNil(value)
+		}
+		for key, field := range fields {
+			fields[key] = coerce(field, t.Elem())
+		}
+		return fields
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return value // Base64 text, as in JSON
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			if value == nil || value == "" {
+				return []interface{}{}
+			}
+			items = []interface{}{value} // One repeated element
+		}
+		for i, item := range items {
+			items[i] = coerce(item, t.Elem())
+		}
+		return items
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(strings.TrimSpace(text)); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64); err == nil {
+			return n
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(strings.TrimSpace(text), 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err == nil {
+			return f
+		}
+	default:
+		return value
+	}
+	return emptyToNil(value)
+}
+
+// coerceFields coerces the fields of a struct by their JSON names
+func coerceFields(fields map[string]interface{}, t reflect.Type) {
+	for key, value := range fields {
+		if fieldType, ok := jsonField(t, key); ok {
+			fields[key] = coerce(value, fieldType)
+		}
+	}
+}
+
+// jsonField returns the type of the field of struct t that encoding/json
+// decodes key into, looking through embedded structs and ignoring case as
+// encoding/json does
+func jsonField(t reflect.Type, key string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			if embedded, ok := jsonField(fieldType, key); ok {
+				return embedded, true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.EqualFold(name, key) {
+			return field.Type, true
+		}
+	}
+	return nil, false
+}
+
+// emptyToNil turns an empty element into null, so an optional field left
+// empty decodes as absent rather than as a type error
+func emptyToNil(value interface{}) interface{} {
+	if text, ok := value.(string); ok && strings.TrimSpace(text) == "" {
+		return nil
+	}
+	return value
+}
+
+// scalarText renders a scalar of an ordered document as text
+func scalarText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
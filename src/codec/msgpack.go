@@ -0,0 +1,96 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+func decodeMsgpack(data []byte) (interface{}, error) {
+	doc, rest, err := msgp.ReadIntfBytes(data)
+	if err != nil {
+		return nil, &Error{Err: errors.New(strings.TrimPrefix(err.Error(), "msgp: "))}
+	}
+	if len(rest) > 0 {
+		return nil, &Error{Err: fmt.Errorf("%d bytes follow the document", len(rest))}
+	}
+	doc, err = jsonValue(doc)
+	if err != nil {
+		return nil, &Error{Err: err}
+	}
+	return doc, nil
+}
+
+// jsonValue converts MessagePack values to values JSON can carry. Binary is
+// read as text, since older encoders send strings as binary; extension types
+// have no JSON counterpart and are rejected.
+func jsonValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			converted, err := jsonValue(field)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case nil, string, bool, int64, uint64, float32, float64, time.Time:
+		return v, nil
+	}
+	return nil, fmt.Errorf("unsupported value of type %T", value)
+}
+
+func encodeMsgpack(w io.Writer, doc interface{}) error {
+	_, err := w.Write(appendMsgpack(nil, doc))
+	return err
+}
+
+func appendMsgpack(b []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case object:
+		b = msgp.AppendMapHeader(b, uint32(len(v)))
+		for _, m := range v {
+			b = msgp.AppendString(b, m.key)
+			b = appendMsgpack(b, m.value)
+		}
+		return b
+	case []interface{}:
+		b = msgp.AppendArrayHeader(b, uint32(len(v)))
+		for _, item := range v {
+			b = appendMsgpack(b, item)
+		}
+		return b
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return msgp.AppendInt64(b, n)
+		}
+		if n, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return msgp.AppendUint64(b, n)
+		}
+		f, _ := v.Float64()
+		return msgp.AppendFloat64(b, f)
+	case bool:
+		return msgp.AppendBool(b, v)
+	case nil:
+		return msgp.AppendNil(b)
+	}
+	return msgp.AppendString(b, scalarText(value))
+}
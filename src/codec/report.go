@@ -0,0 +1,478 @@
+package codec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"goplayground-data-validator/models"
+)
+
+// Report renders the rows of an array or batch validation for tools that
+// read test or analysis reports rather than the API's results, such as CI
+// dashboards and code scanning
+type Report struct {
+	Name        string
+	ContentType string
+
+	render func(w io.Writer, run *ReportRun) error
+}
+
+// The supported reports
+var (
+	JUnit = &Report{Name: "JUnit", ContentType: "application/junit+xml", render: renderJUnit}
+	SARIF = &Report{Name: "SARIF", ContentType: "application/sarif+json", render: renderSARIF}
+)
+
+var reports = []*Report{JUnit, SARIF}
+
+// ReportsOnlyMessage is the error for an Accept header that only allows
+// reports on a response that has no rows to report
+const ReportsOnlyMessage = "application/junit+xml and application/sarif+json are only available for array validation and batch results"
+
+// reportFor returns the report an Accept media type names, or nil
+func reportFor(mediaType string) *Report {
+	for _, report := range reports {
+		if mediaType == report.ContentType {
+			return report
+		}
+	}
+	return nil
+}
+
+// ReportForAccept returns the report an Accept header prefers to every
+// format, and false when it prefers a format or names no report
+func ReportForAccept(header string) (*Report, bool) {
+	var best *Report
+	bestQ, formatQ := 0.0, 0.0
+	for _, entry := range acceptEntries(header) {
+		if report := reportFor(entry.mediaType); report != nil {
+			if entry.q > bestQ && entry.q > formatQ {
+				best, bestQ = report, entry.q
+			}
+			continue
+		}
+		if _, ok := acceptFormat(entry.mediaType); ok && entry.q > formatQ {
+			formatQ = entry.q
+		}
+	}
+	// Equal weights keep whichever was listed first
+	if best == nil || bestQ < formatQ {
+		return nil, false
+	}
+	return best, true
+}
+
+// ReportRun is what a report covers: the validated rows of one model
+type ReportRun struct {
+	Model      string
+	ID         string // Batch ID
+	Timestamp  time.Time
+	Duration   time.Duration
+	Rows       []models.RowValidationResult
+	Skipped    int               // Records not validated after a fail-fast stop
+	File       string            // File the rows were read from, whose lines the row indexes are; empty for JSON
+	Properties map[string]string // Totals and settings of the run
+}
+
+// ArrayRun is the report of an array validation. Every validated row is
+// reported where the result still has them, otherwise its failed rows.
+func ArrayRun(model string, result *models.ArrayValidationResult) *ReportRun {
+	rows := result.Validated
+	if rows == nil {
+		rows = result.Results
+	}
+	run := &ReportRun{
+		Model:     model,
+		ID:        result.BatchID,
+		Timestamp: result.CompletedAt,
+		Duration:  time.Duration(result.ProcessingTime) * time.Millisecond,
+		Rows:      rows,
+		Skipped:   result.SkippedRecords,
+		Properties: map[string]string{
+			"batch_id":        result.BatchID,
+			"status":          result.Status,
+			"total_records":   strconv.Itoa(result.TotalRecords),
+			"valid_records":   strconv.Itoa(result.ValidRecords),
+			"invalid_records": strconv.Itoa(result.InvalidRecords),
+			"success_rate":    strconv.FormatFloat(result.Summary.SuccessRate, 'f', 2, 64),
+		},
+	}
+	if result.Threshold != nil {
+		run.Properties["threshold"] = strconv.FormatFloat(*result.Threshold, 'f', -1, 64)
+	}
+	if result.RequestID != "" {
+		run.Properties["request_id"] = result.RequestID
+	}
+	return run
+}
+
+// BatchRun is the report of one page of the failed rows a batch session
+// retains; rows are numbered by their position across the whole batch
+func BatchRun(session *models.BatchSession, page *models.BatchResultPage) *ReportRun {
+	state := session.State()
+	run := &ReportRun{
+		Model:     state.ModelType,
+		ID:        state.BatchID,
+		Timestamp: state.LastUpdated,
+		Rows:      make([]models.RowValidationResult, len(page.Results)),
+		Properties: map[string]string{
+			"batch_id":        state.BatchID,
+			"total_records":   strconv.Itoa(state.TotalRecords),
+			"valid_records":   strconv.Itoa(state.ValidRecords),
+			"invalid_records": strconv.Itoa(state.InvalidRecords),
+		},
+	}
+	if run.Model == "" {
+		run.Model = "batch"
+	}
+	for i, row := range page.Results {
+		run.Rows[i] = row.RowValidationResult
+		run.Rows[i].RowIndex = row.BatchRowIndex
+	}
+	if page.NextCursor != "" {
+		run.Properties["next_cursor"] = page.NextCursor
+	}
+	return run
+}
+
+// Write writes run as the report with an HTTP status
+func (report *Report) Write(w http.ResponseWriter, status int, run *ReportRun) error {
+	w.Header().Set("Content-Type", report.ContentType)
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(status)
+	return report.render(w, run)
+}
+
+// JUnit XML: a test case per record, a failure per error, and warnings in
+// the test case's output, which is what dashboards show beside a test
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	ID         string          `xml:"id,attr,omitempty"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Name      string         `xml:"name,attr"`
+	Classname string         `xml:"classname,attr"`
+	Time      string         `xml:"time,attr"`
+	Failures  []junitFailure `xml:"failure"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func renderJUnit(w io.Writer, run *ReportRun) error {
+	suite := junitSuite{
+		Name:    run.Model,
+		ID:      run.ID,
+		Tests:   len(run.Rows) + run.Skipped,
+		Skipped: run.Skipped,
+		Time:    seconds(run.Duration),
+	}
+	if !run.Timestamp.IsZero() {
+		suite.Timestamp = run.Timestamp.UTC().Format(time.RFC3339)
+	}
+	for _, name := range sortedKeys(run.Properties) {
+		suite.Properties = append(suite.Properties, junitProperty{Name: name, Value: run.Properties[name]})
+	}
+
+	for _, row := range run.Rows {
+		testCase := junitCase{
+			Name:      caseName(row),
+			Classname: run.Model,
+			Time:      seconds(time.Duration(row.ValidationTime) * time.Millisecond),
+		}
+		if !row.IsValid {
+			suite.Failures++
+		}
+		for _, validationError := range rowErrors(row) {
+			testCase.Failures = append(testCase.Failures, junitFailure{
+				Message: validationError.Message,
+				Type:    ruleID(validationError.Code),
+				Text:    describeFinding(validationError.Field, validationError.Path, validationError.Value),
+			})
+		}
+		var out strings.Builder
+		for _, warning := range row.Warnings {
+			out.WriteString("warning")
+			if warning.Category != "" {
+				fmt.Fprintf(&out, " [%s]", warning.Category)
+			}
+			fmt.Fprintf(&out, " %s %s: %s", ruleID(warning.Code), warning.Field, warning.Message)
+			if warning.Suggestion != "" {
+				fmt.Fprintf(&out, " (%s)", warning.Suggestion)
+			}
+			out.WriteString("\n")
+		}
+		testCase.SystemOut = out.String()
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	doc := junitSuites{
+		Name:     run.Model,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// caseName names the test case of a row by its record, and by its row for
+// records without an identifier of their own
+func caseName(row models.RowValidationResult) string {
+	if row.RecordIdentifier != "" {
+		return row.RecordIdentifier
+	}
+	return models.DetectRecordIdentifier(nil, row.RowIndex)
+}
+
+// rowErrors returns the errors of a row, and one standing for them when a
+// validator rejected the row without saying why, so no failed row goes
+// unreported
+func rowErrors(row models.RowValidationResult) []models.ValidationError {
+	if row.IsValid || len(row.Errors) > 0 {
+		return row.Errors
+	}
+	return []models.ValidationError{{Message: "record failed validation"}}
+}
+
+// describeFinding is the detail of a failure: where it is and the value found
+func describeFinding(field, path string, value interface{}) string {
+	var lines []string
+	if field != "" {
+		lines = append(lines, "field: "+field)
+	}
+	if path != "" && path != field {
+		lines = append(lines, "path: "+path)
+	}
+	if value != nil {
+		lines = append(lines, fmt.Sprintf("value: %v", value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ruleID names a finding by its code; findings without one share a rule
+func ruleID(code string) string {
+	if code == "" {
+		return "VALIDATION"
+	}
+	return code
+}
+
+// SARIF 2.1.0: a result per error at level error and per warning at level
+// note, each located at its record and field, with a rule per code
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool              sarifTool         `json:"tool"`
+	AutomationDetails *sarifAutomation  `json:"automationDetails,omitempty"`
+	Invocations       []sarifInvocation `json:"invocations"`
+	Results           []sarifResult     `json:"results"`
+	Properties        map[string]string `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifAutomation struct {
+	ID string `json:"id"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool   `json:"executionSuccessful"`
+	EndTimeUTC          string `json:"endTimeUtc,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func renderSARIF(w io.Writer, run *ReportRun) error {
+	out := sarifRun{
+		Tool:       sarifTool{Driver: sarifDriver{Name: "goplayground-data-validator", Rules: []sarifRule{}}},
+		Results:    []sarifResult{},
+		Properties: run.Properties,
+	}
+	if run.ID != "" {
+		out.AutomationDetails = &sarifAutomation{ID: run.Model + "/" + run.ID}
+	}
+	invocation := sarifInvocation{ExecutionSuccessful: true}
+	if !run.Timestamp.IsZero() {
+		invocation.EndTimeUTC = run.Timestamp.UTC().Format(time.RFC3339)
+	}
+	out.Invocations = []sarifInvocation{invocation}
+
+	rules := map[string]int{}
+	add := func(row models.RowValidationResult, code, level, message, field, path string, properties map[string]interface{}) {
+		id := ruleID(code)
+		index, ok := rules[id]
+		if !ok {
+			index = len(out.Tool.Driver.Rules)
+			rules[id] = index
+			out.Tool.Driver.Rules = append(out.Tool.Driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: id}})
+		}
+		if path == "" {
+			path = field
+		}
+		record := caseName(row)
+		logical := sarifLogicalLocation{Name: record, FullyQualifiedName: record, Kind: "element"}
+		if path != "" {
+			logical = sarifLogicalLocation{Name: field, FullyQualifiedName: record + "/" + path, Kind: "member"}
+		}
+		location := sarifLocation{LogicalLocations: []sarifLogicalLocation{logical}}
+		if run.File != "" {
+			location.PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: run.File},
+				Region:           sarifRegion{StartLine: row.RowIndex},
+			}
+		}
+		properties["row_index"] = row.RowIndex
+		properties["record_identifier"] = record
+		properties["field"] = field
+		out.Results = append(out.Results, sarifResult{
+			RuleID:     id,
+			RuleIndex:  index,
+			Level:      level,
+			Message:    sarifMessage{Text: fmt.Sprintf("%s: %s", record, message)},
+			Locations:  []sarifLocation{location},
+			Properties: properties,
+		})
+	}
+
+	for _, row := range run.Rows {
+		for _, validationError := range rowErrors(row) {
+			properties := map[string]interface{}{}
+			if validationError.Value != nil {
+				properties["value"] = validationError.Value
+			}
+			add(row, validationError.Code, "error", validationError.Message, validationError.Field, validationError.Path, properties)
+		}
+		for _, warning := range row.Warnings {
+			properties := map[string]interface{}{}
+			if warning.Category != "" {
+				properties["category"] = warning.Category
+			}
+			if warning.Suggestion != "" {
+				properties["suggestion"] = warning.Suggestion
+			}
+			if warning.Value != nil {
+				properties["value"] = warning.Value
+			}
+			add(row, warning.Code, "note", warning.Message, warning.Field, warning.Path, properties)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{out}})
+}
@@ -0,0 +1,143 @@
+package codec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"goplayground-data-validator/models"
+)
+
+func TestReportForAccept(t *testing.T) {
+	tests := []struct {
+		header string
+		want   *Report
+	}{
+		{"", nil},
+		{"*/*", nil},
+		{"application/junit+xml", JUnit},
+		{"application/sarif+json", SARIF},
+		{"application/junit+xml, application/json", JUnit},
+		{"application/json, application/junit+xml", nil},
+		{"application/json;q=0.5, application/sarif+json", SARIF},
+		{"application/junit+xml;q=0.5, */*", nil},
+		{"application/xml", nil},
+	}
+	for _, tt := range tests {
+		got, ok := ReportForAccept(tt.header)
+		if got != tt.want || ok != (tt.want != nil) {
+			t.Errorf("ReportForAccept(%q) = %v, %v; want %v", tt.header, got, ok, tt.want)
+		}
+	}
+}
+
+// reportResult is an array result with a valid row, a row with a warning and
+// a failed row with a warning
+func reportResult() *models.ArrayValidationResult {
+	warning := models.ValidationWarning{Field: "title", Message: "Title is short", Code: "SHORT_TITLE", Category: "quality", Suggestion: "Describe the impact"}
+	rows := []models.RowValidationResult{
+		{RowIndex: 0, RecordIdentifier: "INC-1", IsValid: true},
+		{RowIndex: 1, RecordIdentifier: "INC-2", IsValid: true, Warnings: []models.ValidationWarning{warning}},
+		{RowIndex: 2, RecordIdentifier: "INC-3", IsValid: false,
+			Errors: []models.ValidationError{
+				{Field: "priority", Message: "priority must be at most 5", Code: "MAX", Value: 9},
+				{Field: "id", Message: "id is required", Code: "REQUIRED"},
+			},
+			Warnings: []models.ValidationWarning{warning},
+		},
+	}
+	return &models.ArrayValidationResult{
+		BatchID:        "auto_1",
+		Status:         "failed",
+		TotalRecords:   4,
+		ValidRecords:   2,
+		InvalidRecords: 1,
+		SkippedRecords: 1,
+		CompletedAt:    time.Date(2025, 1, 4, 10, 30, 0, 0, time.UTC),
+		Results:        rows[2:],
+		Validated:      rows,
+	}
+}
+
+func TestJUnitReport(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := JUnit.Write(w, http.StatusUnprocessableEntity, ArrayRun("incident", reportResult())); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/junit+xml" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	var doc junitSuites
+	if err := xml.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, w.Body.String())
+	}
+	if doc.Tests != 4 || doc.Failures != 1 || doc.Skipped != 1 || len(doc.Suites) != 1 {
+		t.Fatalf("testsuites = %+v", doc)
+	}
+	cases := doc.Suites[0].Cases
+	if len(cases) != 3 || cases[0].Name != "INC-1" || cases[0].Classname != "incident" || len(cases[0].Failures) != 0 {
+		t.Fatalf("testcases = %+v", cases)
+	}
+	if !strings.Contains(cases[1].SystemOut, "[quality] SHORT_TITLE title: Title is short") {
+		t.Errorf("warning output = %q, want the category", cases[1].SystemOut)
+	}
+	failures := cases[2].Failures
+	if len(failures) != 2 || failures[0].Type != "MAX" || failures[0].Message != "priority must be at most 5" || !strings.Contains(failures[0].Text, "value: 9") {
+		t.Errorf("failures = %+v", failures)
+	}
+}
+
+func TestSARIFReport(t *testing.T) {
+	run := ArrayRun("incident", reportResult())
+	run.File = "incidents.csv"
+	w := httptest.NewRecorder()
+	if err := SARIF.Write(w, http.StatusOK, run); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	var doc sarifLog
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc.Version != "2.1.0" || len(doc.Runs) != 1 {
+		t.Fatalf("log = %+v", doc)
+	}
+	results := doc.Runs[0].Results
+	var levels []string
+	for _, result := range results {
+		levels = append(levels, result.Level+":"+result.RuleID)
+	}
+	if got := strings.Join(levels, " "); got != "note:SHORT_TITLE error:MAX error:REQUIRED note:SHORT_TITLE" {
+		t.Fatalf("results = %s", got)
+	}
+	if rules := doc.Runs[0].Tool.Driver.Rules; len(rules) != 3 || rules[results[1].RuleIndex].ID != "MAX" {
+		t.Errorf("rules = %+v", rules)
+	}
+	if got := results[0].Properties["category"]; got != "quality" {
+		t.Errorf("note category = %v, want quality", got)
+	}
+	location := results[1].Locations[0]
+	if location.PhysicalLocation == nil || location.PhysicalLocation.Region.StartLine != 2 || location.PhysicalLocation.ArtifactLocation.URI != "incidents.csv" {
+		t.Errorf("physical location = %+v", location.PhysicalLocation)
+	}
+	if got := location.LogicalLocations[0].FullyQualifiedName; got != "INC-3/priority" {
+		t.Errorf("logical location = %q", got)
+	}
+}
+
+func TestBatchRun(t *testing.T) {
+	session := &models.BatchSession{BatchSessionState: models.BatchSessionState{BatchID: "batch_1", ModelType: "incident", TotalRecords: 250}}
+	page := &models.BatchResultPage{
+		Results:    []models.BatchRowResult{{BatchRowIndex: 120, RowValidationResult: models.RowValidationResult{RowIndex: 20, RecordIdentifier: "row_20"}}},
+		NextCursor: "next",
+	}
+	run := BatchRun(session, page)
+	if len(run.Rows) != 1 || run.Rows[0].RowIndex != 120 || run.Properties["next_cursor"] != "next" || run.Properties["total_records"] != "250" {
+		t.Errorf("run = %+v", run)
+	}
+}
@@ -0,0 +1,165 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// maxXMLDepth bounds element nesting while a document is read; the configured
+// JSON depth limit applies once it is converted
+const maxXMLDepth = 10000
+
+// XML has no arrays or scalar types, so documents are read by these rules:
+// the root element is the top-level object, attributes and child elements are
+// its keys, a repeated child becomes a list, and an element with neither
+// attributes nor children is its text.
+func decodeXML(data []byte) (interface{}, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, xmlError(dec, err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			doc, err := readElement(dec, start, 1)
+			if err != nil {
+				return nil, xmlError(dec, err)
+			}
+			return doc, nil
+		}
+	}
+}
+
+func readElement(dec *xml.Decoder, start xml.StartElement, depth int) (interface{}, error) {
+	if depth > maxXMLDepth {
+		return nil, fmt.Errorf("elements nested deeper than %d", maxXMLDepth)
+	}
+	fields := map[string]interface{}{}
+	for _, attr := range start.Attr {
+		if attr.Name.Space != "xmlns" && attr.Name.Local != "xmlns" {
+			fields[attr.Name.Local] = attr.Value
+		}
+	}
+
+	var text strings.Builder
+	lists := map[string]bool{}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			value, err := readElement(dec, t, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			existing, seen := fields[name]
+			switch {
+			case !seen:
+				fields[name] = value
+			case lists[name]:
+				fields[name] = append(existing.([]interface{}), value)
+			default:
+				fields[name] = []interface{}{existing, value}
+				lists[name] = true
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(fields) == 0 {
+				return text.String(), nil
+			}
+			return fields, nil
+		}
+	}
+}
+
+// xmlError locates err at the position the decoder stopped
+func xmlError(dec *xml.Decoder, err error) error {
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		err = errors.New(syntaxErr.Msg)
+	}
+	line, column := dec.InputPos()
+	return &Error{Line: line, Column: column, Err: err}
+}
+
+// encodeXML writes a document under a <result> root. Lists repeat their
+// element, null fields are left out, and keys that are not XML names are
+// written as <entry key="...">.
+func encodeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if items, ok := doc.([]interface{}); ok {
+		doc = object{{key: "item", value: items}}
+	}
+	if err := writeXML(enc, "result", doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func writeXML(enc *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !xmlName(name) {
+		start = xml.StartElement{Name: xml.Name{Local: "entry"}, Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}}}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		for _, item := range v {
+			if nested, ok := item.([]interface{}); ok {
+				item = object{{key: "item", value: nested}}
+			}
+			if err := writeXML(enc, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case object:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, m := range v {
+			if err := writeXML(enc, m.key, m.value); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+	}
+	return enc.EncodeElement(scalarText(value), start)
+}
+
+// xmlName reports whether name can be used as an element name as it is
+func xmlName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"time"
+
+	"goplayground-data-validator/certs"
+	"goplayground-data-validator/jsonlimit"
+	"goplayground-data-validator/models"
+)
+
+// Config is the server configuration. Every setting has a default and can be
+// set in the YAML file named by -config or CONFIG_FILE, by the environment
+// variable in its env tag, and by a flag named after its YAML path, such as
+// -server.port; later sources win.
+type Config struct {
+	Server     ServerConfig           `yaml:"server"`
+	TLS        TLSConfig              `yaml:"tls"`
+	GRPC       GRPCConfig             `yaml:"grpc"`
+	Logging    LoggingConfig          `yaml:"logging"`
+	Tracing    TracingConfig          `yaml:"tracing"`
+	Auth       AuthConfig             `yaml:"auth"`
+	Batch      BatchConfig            `yaml:"batch"`
+	Limits     LimitsConfig           `yaml:"limits"`
+	Validation ValidationConfig       `yaml:"validation"`
+	Models     map[string]ModelConfig `yaml:"models" validate:"dive"` // Per-model settings keyed by model type, such as "incident" or "api.response"
+}
+
+// ServerConfig sets the listener and the HTTP server timeouts
+type ServerConfig struct {
+	Port            int           `yaml:"port" env:"PORT" validate:"min=1,max=65535"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT" validate:"gt=0"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT" validate:"gt=0"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" validate:"gt=0"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" validate:"gt=0"` // Drain time for in-flight requests after SIGTERM
+}
+
+// TLSConfig turns on HTTPS when a certificate and key are given, and client
+// certificate verification when a client CA bundle is given too. The files are
+// reread every reload_interval so rotated certificates are served without a restart.
+type TLSConfig struct {
+	CertFile       string        `yaml:"cert_file" env:"TLS_CERT_FILE" validate:"required_with=KeyFile,omitempty,file"`
+	KeyFile        string        `yaml:"key_file" env:"TLS_KEY_FILE" validate:"required_with=CertFile,omitempty,file"`
+	ClientCAFile   string        `yaml:"client_ca_file" env:"TLS_CLIENT_CA_FILE" validate:"excluded_without=CertFile,omitempty,file"`
+	ClientAuth     string        `yaml:"client_auth" env:"TLS_CLIENT_AUTH" validate:"oneof=require optional"` // Whether clients must present a certificate
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"TLS_RELOAD_INTERVAL" validate:"gt=0"`
+}
+
+// GRPCConfig turns on the gRPC service. It shares the HTTP API's TLS
+// certificate, authentication and limits.
+type GRPCConfig struct {
+	Port int `yaml:"port" env:"GRPC_PORT" validate:"min=0,max=65535"` // 0 disables the gRPC service
+}
+
+// LoggingConfig selects the log level and output format
+type LoggingConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" validate:"omitempty,oneof=debug info warn error DEBUG INFO WARN ERROR"`
+	Format string `yaml:"format" env:"LOG_FORMAT" validate:"omitempty,oneof=json text"`
+}
+
+// TracingConfig selects the span exporter. The OTLP exporter also reads the
+// standard OTEL_EXPORTER_OTLP_* variables; the settings here override them.
+type TracingConfig struct {
+	Exporter     string            `yaml:"exporter" env:"TRACES_EXPORTER" validate:"omitempty,oneof=none otlp stdout file"`
+	File         string            `yaml:"file" env:"TRACES_FILE" validate:"required_if=Exporter file"`
+	OTLPEndpoint string            `yaml:"otlp_endpoint" validate:"omitempty,url"`
+	OTLPHeaders  map[string]string `yaml:"otlp_headers" secret:"true"` // Often carry collector API keys
+}
+
+// AuthConfig points at the authentication config; without one every endpoint is open
+type AuthConfig struct {
+	ConfigFile string `yaml:"config_file" env:"AUTH_CONFIG" validate:"omitempty,file"`
+}
+
+// BatchConfig sets where batch sessions are stored and how long and large they may get
+type BatchConfig struct {
+	Store            string        `yaml:"store" env:"BATCH_STORE" validate:"omitempty,oneof=memory file"`
+	StoreDir         string        `yaml:"store_dir" env:"BATCH_STORE_DIR" validate:"required_if=Store file"`
+	TTL              time.Duration `yaml:"ttl" env:"BATCH_TTL" validate:"gt=0"`
+	MaxTTL           time.Duration `yaml:"max_ttl" env:"BATCH_MAX_TTL" validate:"gtefield=TTL"`
+	MaxOpenSessions  int           `yaml:"max_open_sessions" env:"BATCH_MAX_OPEN_SESSIONS" validate:"min=0"`
+	MaxRecords       int           `yaml:"max_records" env:"BATCH_MAX_RECORDS" validate:"min=0"`
+	ExpiredRetention time.Duration `yaml:"expired_retention" env:"BATCH_EXPIRED_RETENTION" validate:"min=0"`
+	SweepInterval    time.Duration `yaml:"sweep_interval" env:"BATCH_SWEEP_INTERVAL" validate:"gt=0"`
+}
+
+// LimitsConfig protects the server from overload and oversized input. Zero
+// turns a limit off.
+type LimitsConfig struct {
+	RateLimitRPS         float64       `yaml:"rate_limit_rps" env:"RATE_LIMIT_RPS" validate:"min=0"`
+	RateLimitBurst       int           `yaml:"rate_limit_burst" env:"RATE_LIMIT_BURST" validate:"min=0"`
+	MaxConcurrentRecords int64         `yaml:"max_concurrent_records" env:"MAX_CONCURRENT_RECORDS" validate:"min=0"`
+	QueueTimeout         time.Duration `yaml:"queue_timeout" env:"ADMISSION_QUEUE_TIMEOUT" validate:"min=0"`
+	MaxQueued            int           `yaml:"max_queued" env:"ADMISSION_MAX_QUEUED" validate:"min=0"`
+	MaxBodyBytes         int64         `yaml:"max_body_bytes" env:"MAX_BODY_BYTES" validate:"min=0"`
+	MaxJSONDepth         int           `yaml:"max_json_depth" env:"MAX_JSON_DEPTH" validate:"min=0"`
+	MaxArrayLength       int           `yaml:"max_array_length" env:"MAX_ARRAY_LENGTH" validate:"min=0"`
+	MaxStringLength      int           `yaml:"max_string_length" env:"MAX_STRING_LENGTH" validate:"min=0"`
+	MaxObjectKeys        int           `yaml:"max_object_keys" env:"MAX_OBJECT_KEYS" validate:"min=0"`
+}
+
+// ValidationConfig holds defaults for validation requests
+type ValidationConfig struct {
+	DefaultThreshold *float64 `yaml:"default_threshold" env:"DEFAULT_THRESHOLD" validate:"omitempty,min=0,max=100"` // Applied to arrays, streams and batch sessions that name no threshold
+}
+
+// ModelConfig overrides settings for one model
+type ModelConfig struct {
+	MaxConcurrentRecords int64    `yaml:"max_concurrent_records" validate:"min=0"`              // Records of this model validated at once; 0 means only the global cap
+	DefaultThreshold     *float64 `yaml:"default_threshold" validate:"omitempty,min=0,max=100"` // Overrides validation.default_threshold
+}
+
+// Default returns the configuration used for anything no source sets
+func Default() Config {
+	batch := models.DefaultBatchLimits()
+	input := jsonlimit.DefaultLimits()
+	return Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    15 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		TLS:     TLSConfig{ClientAuth: certs.ClientAuthRequire, ReloadInterval: 30 * time.Second},
+		Logging: LoggingConfig{Level: "info", Format: "json"},
+		Tracing: TracingConfig{Exporter: "none", File: "traces.jsonl"},
+		Batch: BatchConfig{
+			Store:            models.BatchStoreMemory,
+			StoreDir:         "data/batches",
+			TTL:              batch.DefaultTTL,
+			MaxTTL:           batch.MaxTTL,
+			MaxOpenSessions:  batch.MaxOpenSessions,
+			MaxRecords:       batch.MaxRecordsPerSession,
+			ExpiredRetention: batch.ExpiredRetention,
+			SweepInterval:    batch.SweepInterval,
+		},
+		Limits: LimitsConfig{
+			QueueTimeout:    5 * time.Second,
+			MaxBodyBytes:    input.MaxBodyBytes,
+			MaxJSONDepth:    input.MaxDepth,
+			MaxArrayLength:  input.MaxArrayLength,
+			MaxStringLength: input.MaxStringLength,
+			MaxObjectKeys:   input.MaxObjectKeys,
+		},
+		Models: make(map[string]ModelConfig),
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfigFile writes a YAML config file and returns its path
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "validator.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return file
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Server.Port != 8080 || cfg.Server.ShutdownTimeout != 30*time.Second || cfg.Batch.StoreDir != "data/batches" {
+		t.Errorf("Unexpected defaults: %+v", cfg)
+	}
+	if cfg.Validation.DefaultThreshold != nil {
+		t.Errorf("Expected no default threshold, got %v", *cfg.Validation.DefaultThreshold)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	file := writeConfigFile(t, `
+server:
+  port: 9000
+  read_timeout: 5s
+logging:
+  level: warn
+validation:
+  default_threshold: 90
+models:
+  incident:
+    max_concurrent_records: 100
+    default_threshold: 99.5
+`)
+	t.Setenv(FileEnv, file)
+	t.Setenv("PORT", "9100")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("MODEL_RECORD_LIMITS", "github=20")
+
+	cfg, err := Load([]string{"-server.port=9200"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Server.Port != 9200 {
+		t.Errorf("Expected the flag to win, got port %d", cfg.Server.Port)
+	}
+	if cfg.Logging.Level != "debug" {
+		t.Errorf("Expected the environment to override the file, got level %q", cfg.Logging.Level)
+	}
+	if cfg.Server.ReadTimeout != 5*time.Second || cfg.Server.WriteTimeout != 15*time.Second {
+		t.Errorf("Expected file values over defaults, got %s and %s", cfg.Server.ReadTimeout, cfg.Server.WriteTimeout)
+	}
+	if cfg.Validation.DefaultThreshold == nil || *cfg.Validation.DefaultThreshold != 90 {
+		t.Errorf("Expected default threshold 90, got %v", cfg.Validation.DefaultThreshold)
+	}
+	incident := cfg.Models["incident"]
+	if incident.MaxConcurrentRecords != 100 || incident.DefaultThreshold == nil || *incident.DefaultThreshold != 99.5 {
+		t.Errorf("Unexpected incident settings: %+v", incident)
+	}
+	if cfg.Models["github"].MaxConcurrentRecords != 20 {
+		t.Errorf("Expected MODEL_RECORD_LIMITS to add github, got %+v", cfg.Models)
+	}
+
+	// -config wins over CONFIG_FILE
+	other := writeConfigFile(t, "server:\n  port: 7000\n")
+	t.Setenv("PORT", "")
+	if cfg, err := Load([]string{"-config", other}); err != nil || cfg.Server.Port != 7000 {
+		t.Errorf("Expected port 7000 from -config, got %v", err)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	certFile := writeConfigFile(t, "not read by Load")
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		args    []string
+		message string
+	}{
+		{"unknown key", "server:\n  prot: 80\n", nil, nil, "field prot not found"},
+		{"bad duration in file", "batch:\n  ttl: soon\n", nil, nil, "invalid config file"},
+		{"bad env value", "", map[string]string{"PORT": "http"}, nil, "PORT"},
+		{"bad flag value", "", nil, []string{"-server.idle_timeout=often"}, "server.idle_timeout"},
+		{"unknown flag", "", nil, []string{"-listen=:80"}, "listen"},
+		{"port out of range", "server:\n  port: 70000\n", nil, nil, "server.port: 70000 fails max=65535"},
+		{"threshold out of range", "models:\n  incident:\n    default_threshold: 150\n", nil, nil, "models[incident].default_threshold"},
+		{"max ttl below ttl", "", map[string]string{"BATCH_TTL": "2h", "BATCH_MAX_TTL": "1h"}, nil, "batch.max_ttl"},
+		{"file store without dir", "batch:\n  store: file\n  store_dir: \"\"\n", nil, nil, "batch.store_dir"},
+		{"missing auth config", "auth:\n  config_file: /nonexistent/auth.json\n", nil, nil, "auth.config_file"},
+		{"unknown exporter", "", map[string]string{"TRACES_EXPORTER": "jaeger"}, nil, "tracing.exporter"},
+		{"certificate without key", "", map[string]string{"TLS_CERT_FILE": certFile}, nil, "tls.key_file"},
+		{"client CA without certificate", "", map[string]string{"TLS_CLIENT_CA_FILE": certFile}, nil, "tls.client_ca_file"},
+		{"unknown client auth", "", nil, []string{"-tls.client_auth=sometimes"}, "tls.client_auth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeConfigFile(t, tt.file)}, args...)
+			}
+			for env, value := range tt.env {
+				t.Setenv(env, value)
+			}
+			_, err := Load(args)
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Expected error containing %q, got %v", tt.message, err)
+			}
+		})
+	}
+
+	if _, err := Load([]string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("Expected flag.ErrHelp for -h, got %v", err)
+	}
+}
+
+func TestConfig_LogValue(t *testing.T) {
+	cfg := Default()
+	cfg.Tracing.OTLPHeaders = map[string]string{"authorization": "Bearer collector-secret"}
+	threshold := 95.0
+	cfg.Models["incident"] = ModelConfig{DefaultThreshold: &threshold}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("Configuration loaded", "config", cfg)
+	out := buf.String()
+
+	if strings.Contains(out, "collector-secret") || !strings.Contains(out, "config.tracing.otlp_headers=[REDACTED]") {
+		t.Errorf("Expected OTLP headers to be redacted, got %s", out)
+	}
+	for _, want := range []string{"config.server.port=8080", "config.batch.ttl=30m0s", "config.models.incident.default_threshold=95"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in %s", want, out)
+		}
+	}
+}
@@ -1,4 +1,5 @@
-// Package config provides configuration constants and validation thresholds
+// Package config provides configuration constants, validation thresholds and the
+// server configuration loaded from a file, the environment and flags
 package config
 
 import "time"
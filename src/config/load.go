@@ -0,0 +1,288 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the config file when no -config flag is given
+const FileEnv = "CONFIG_FILE"
+
+// Load builds the configuration from the defaults, the YAML config file, the
+// environment and the command-line flags in args, in that order of precedence,
+// and validates the result. It returns flag.ErrHelp when args ask for usage.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+
+	// Flags are parsed first to find the config file, and applied last
+	fs := flag.NewFlagSet("validator", flag.ContinueOnError)
+	file := fs.String("config", os.Getenv(FileEnv), "YAML config file")
+	var flagValues []*flagValue
+	walk(reflect.ValueOf(&cfg).Elem(), "", func(path string, field reflect.StructField, value reflect.Value) {
+		if isScalar(value.Type()) {
+			fv := &flagValue{path: path, value: value}
+			flagValues = append(flagValues, fv)
+			fs.Var(fv, path, flagUsage(path, field))
+		}
+	})
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *file != "" {
+		if err := loadFile(&cfg, *file); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	walk(reflect.ValueOf(&cfg).Elem(), "", func(path string, field reflect.StructField, value reflect.Value) {
+		env := field.Tag.Get("env")
+		if raw := os.Getenv(env); env != "" && raw != "" {
+			if err := setValue(value, raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", env, err))
+			}
+		}
+	})
+	if raw := os.Getenv("MODEL_RECORD_LIMITS"); raw != "" {
+		errs = append(errs, parseModelRecordLimits(&cfg, raw))
+	}
+
+	for _, fv := range flagValues {
+		if fv.set {
+			if err := setValue(fv.value, fv.raw); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", fv.path, err))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// loadFile decodes a YAML config file over cfg; unknown keys are errors
+func loadFile(cfg *Config, file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", file, err)
+	}
+	return nil
+}
+
+// parseModelRecordLimits reads MODEL_RECORD_LIMITS, "model=records,...", into the per-model settings
+func parseModelRecordLimits(cfg *Config, raw string) error {
+	for _, entry := range strings.Split(raw, ",") {
+		model, limit, found := strings.Cut(strings.TrimSpace(entry), "=")
+		parsed, err := strconv.ParseInt(limit, 10, 64)
+		if !found || model == "" || err != nil {
+			return fmt.Errorf("MODEL_RECORD_LIMITS entry %q must be model=records", entry)
+		}
+		modelCfg := cfg.Models[model]
+		modelCfg.MaxConcurrentRecords = parsed
+		cfg.Models[model] = modelCfg
+	}
+	return nil
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// Report fields by their YAML path, which is also the flag name
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return yamlName(field)
+	})
+	return v
+}
+
+// Validate checks every setting against its validate tag
+func (c *Config) Validate() error {
+	err := validate.Struct(c)
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+	messages := make([]string, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		path := strings.TrimPrefix(fieldErr.Namespace(), "Config.")
+		check := fieldErr.Tag()
+		if fieldErr.Param() != "" {
+			check += "=" + fieldErr.Param()
+		}
+		messages = append(messages, fmt.Sprintf("%s: %v fails %s", path, fieldErr.Value(), check))
+	}
+	return fmt.Errorf("invalid configuration: %s", strings.Join(messages, "; "))
+}
+
+// LogValue prints the configuration grouped like the YAML file, with secret
+// settings redacted
+func (c Config) LogValue() slog.Value {
+	return groupValue(reflect.ValueOf(c))
+}
+
+func groupValue(v reflect.Value) slog.Value {
+	var attrs []slog.Attr
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		name := yamlName(field)
+		switch {
+		case field.Tag.Get("secret") == "true":
+			if !value.IsZero() {
+				attrs = append(attrs, slog.String(name, "[REDACTED]"))
+			}
+		case value.Kind() == reflect.Struct:
+			attrs = append(attrs, slog.Attr{Key: name, Value: groupValue(value)})
+		case value.Kind() == reflect.Map:
+			attrs = append(attrs, slog.Attr{Key: name, Value: mapValue(value)})
+		case value.Kind() == reflect.Pointer:
+			if !value.IsNil() {
+				attrs = append(attrs, slog.Any(name, value.Elem().Interface()))
+			}
+		case value.Type() == reflect.TypeOf(time.Duration(0)):
+			attrs = append(attrs, slog.String(name, value.Interface().(time.Duration).String()))
+		default:
+			attrs = append(attrs, slog.Any(name, value.Interface()))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// mapValue prints a map of settings in key order
+func mapValue(v reflect.Value) slog.Value {
+	keys := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		value := v.MapIndex(reflect.ValueOf(key))
+		if value.Kind() == reflect.Struct {
+			attrs = append(attrs, slog.Attr{Key: key, Value: groupValue(value)})
+		} else {
+			attrs = append(attrs, slog.Any(key, value.Interface()))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// walk calls fn for every setting below v, a struct, with its dotted YAML path
+func walk(v reflect.Value, prefix string, fn func(path string, field reflect.StructField, value reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		path := prefix + yamlName(field)
+		if value.Kind() == reflect.Struct {
+			walk(value, path+".", fn)
+			continue
+		}
+		fn(path, field, value)
+	}
+}
+
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// isScalar reports whether a setting of type t can be given as one string
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Int64, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// setValue parses raw into v according to its type
+func setValue(v reflect.Value, raw string) error {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// flagValue records a flag so it can be applied after the file and environment
+type flagValue struct {
+	path  string
+	value reflect.Value
+	raw   string
+	set   bool
+}
+
+func (f *flagValue) String() string { return f.raw }
+
+func (f *flagValue) Set(raw string) error {
+	// Parse now so a bad value is reported by the flag package, then again when applied
+	if err := setValue(reflect.New(f.value.Type()).Elem(), raw); err != nil {
+		return err
+	}
+	f.raw, f.set = raw, true
+	return nil
+}
+
+func flagUsage(path string, field reflect.StructField) string {
+	if env := field.Tag.Get("env"); env != "" {
+		return fmt.Sprintf("sets %s (env %s)", path, env)
+	}
+	return "sets " + path
+}
@@ -0,0 +1,284 @@
+package grpcserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"goplayground-data-validator/jsonlimit"
+	"goplayground-data-validator/models"
+	"goplayground-data-validator/validatorpb"
+)
+
+// recordJSON encodes a record the way the HTTP API would have received it and
+// checks it against the JSON input limits
+func recordJSON(record *structpb.Struct, limits jsonlimit.Limits) ([]byte, error) {
+	data, err := json.Marshal(record.AsMap())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid record: %v", err)
+	}
+	if err := jsonlimit.Check(data, limits); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return data, nil
+}
+
+// recordMaps converts the records of an array request, enforcing the JSON
+// input limits on the array and on every record
+func recordMaps(records []*structpb.Struct) ([]map[string]interface{}, error) {
+	limits := jsonlimit.Current()
+	if limits.MaxArrayLength > 0 && len(records) > limits.MaxArrayLength {
+		err := &jsonlimit.LimitError{Limit: jsonlimit.LimitArrayLength, Max: int64(limits.MaxArrayLength), Path: "records"}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	maps := make([]map[string]interface{}, len(records))
+	for i, record := range records {
+		if _, err := recordJSON(record, limits); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "records[%d]: %s", i, status.Convert(err).Message())
+		}
+		maps[i] = record.AsMap()
+	}
+	return maps, nil
+}
+
+// batchOptions converts request options, rejecting the values POST /validate rejects
+func batchOptions(opts *validatorpb.ValidationOptions) (models.BatchOptions, error) {
+	if opts == nil {
+		return models.BatchOptions{}, nil
+	}
+	if !models.ValidMaxConcurrency(int(opts.MaxConcurrency)) {
+		return models.BatchOptions{}, status.Error(codes.InvalidArgument, "options."+models.MaxConcurrencyMessage)
+	}
+	if opts.TimeoutMs < 0 {
+		return models.BatchOptions{}, status.Error(codes.InvalidArgument, "options.timeout_ms must not be negative")
+	}
+	return models.BatchOptions{
+		StopOnFirstError: opts.StopOnFirstError,
+		MaxConcurrency:   int(opts.MaxConcurrency),
+		Timeout:          time.Duration(opts.TimeoutMs) * time.Millisecond,
+		FailFast:         opts.FailFast,
+	}, nil
+}
+
+// checkThreshold rejects thresholds outside 0-100
+func checkThreshold(threshold *float64) error {
+	if threshold != nil && (*threshold < 0 || *threshold > 100) {
+		return status.Error(codes.InvalidArgument, "threshold must be between 0 and 100")
+	}
+	return nil
+}
+
+// toValue converts an error's offending value through JSON, so any value the
+// HTTP API can report can be reported here too
+func toValue(v interface{}) *structpb.Value {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return structpb.NewStringValue(fmt.Sprint(v))
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return structpb.NewStringValue(string(data))
+	}
+	value, err := structpb.NewValue(decoded)
+	if err != nil {
+		return structpb.NewStringValue(string(data))
+	}
+	return value
+}
+
+func toErrors(errs []models.ValidationError) []*validatorpb.FieldIssue {
+	issues := make([]*validatorpb.FieldIssue, len(errs))
+	for i, e := range errs {
+		issues[i] = &validatorpb.FieldIssue{
+			Field:    e.Field,
+			Message:  e.Message,
+			Code:     e.Code,
+			Value:    toValue(e.Value),
+			Path:     e.Path,
+			Severity: e.Severity,
+		}
+	}
+	return issues
+}
+
+func toWarnings(warnings []models.ValidationWarning) []*validatorpb.FieldIssue {
+	issues := make([]*validatorpb.FieldIssue, len(warnings))
+	for i, w := range warnings {
+		issues[i] = &validatorpb.FieldIssue{
+			Field:      w.Field,
+			Message:    w.Message,
+			Code:       w.Code,
+			Value:      toValue(w.Value),
+			Path:       w.Path,
+			Suggestion: w.Suggestion,
+			Category:   w.Category,
+		}
+	}
+	return issues
+}
+
+// timestamp converts t, leaving the zero time unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toValidationResult(result models.ValidationResult) *validatorpb.ValidationResult {
+	return &validatorpb.ValidationResult{
+		IsValid:              result.IsValid,
+		ModelType:            result.ModelType,
+		Provider:             result.Provider,
+		Errors:               toErrors(result.Errors),
+		Warnings:             toWarnings(result.Warnings),
+		Timestamp:            timestamp(result.Timestamp),
+		ProcessingDurationNs: int64(result.ProcessingDuration),
+		RequestId:            result.RequestID,
+		TraceId:              result.TraceID,
+	}
+}
+
+func toRowResults(rows []models.RowValidationResult) []*validatorpb.RowValidationResult {
+	results := make([]*validatorpb.RowValidationResult, len(rows))
+	for i, row := range rows {
+		results[i] = &validatorpb.RowValidationResult{
+			RowIndex:         int32(row.RowIndex),
+			RecordIdentifier: row.RecordIdentifier,
+			IsValid:          row.IsValid,
+			ValidationTimeMs: row.ValidationTime,
+			TestName:         row.TestName,
+			Errors:           toErrors(row.Errors),
+			Warnings:         toWarnings(row.Warnings),
+		}
+	}
+	return results
+}
+
+func toSummary(summary models.ValidationSummary) *validatorpb.ValidationSummary {
+	return &validatorpb.ValidationSummary{
+		SuccessRate:           summary.SuccessRate,
+		ValidationErrors:      int32(summary.ValidationErrors),
+		ValidationWarnings:    int32(summary.ValidationWarnings),
+		TotalRecordsProcessed: int32(summary.TotalRecordsProcessed),
+		TotalTestsRan:         int32(summary.TotalTestsRan),
+		SuccessfulTestNames:   summary.SuccessfulTestNames,
+		FailedTestNames:       summary.FailedTestNames,
+	}
+}
+
+func toArrayResult(result *models.ArrayValidationResult) *validatorpb.ArrayValidationResult {
+	return &validatorpb.ArrayValidationResult{
+		BatchId:          result.BatchID,
+		RequestId:        result.RequestID,
+		TraceId:          result.TraceID,
+		Status:           result.Status,
+		TotalRecords:     int32(result.TotalRecords),
+		ValidRecords:     int32(result.ValidRecords),
+		InvalidRecords:   int32(result.InvalidRecords),
+		WarningRecords:   int32(result.WarningRecords),
+		SkippedRecords:   int32(result.SkippedRecords),
+		Threshold:        result.Threshold,
+		ProcessingTimeMs: result.ProcessingTime,
+		CompletedAt:      timestamp(result.CompletedAt),
+		Summary:          toSummary(result.Summary),
+		Results:          toRowResults(result.Results),
+	}
+}
+
+// toStreamResult converts a stream summary and the failed rows kept from it
+func toStreamResult(summary *models.StreamValidationSummary, rows []models.RowValidationResult) *validatorpb.ArrayValidationResult {
+	return &validatorpb.ArrayValidationResult{
+		BatchId:          summary.BatchID,
+		RequestId:        summary.RequestID,
+		TraceId:          summary.TraceID,
+		Status:           summary.Status,
+		TotalRecords:     int32(summary.TotalRecords),
+		ValidRecords:     int32(summary.ValidRecords),
+		InvalidRecords:   int32(summary.InvalidRecords),
+		WarningRecords:   int32(summary.WarningRecords),
+		Threshold:        summary.Threshold,
+		ProcessingTimeMs: summary.ProcessingTime,
+		CompletedAt:      timestamp(summary.CompletedAt),
+		Summary:          toSummary(summary.Summary),
+		Results:          toRowResults(rows),
+		Error:            summary.Error,
+	}
+}
+
+func toChunkReport(report *models.BatchChunkReport) *validatorpb.BatchChunkReport {
+	if report == nil {
+		return nil
+	}
+	ints := func(values []int) []int32 {
+		converted := make([]int32, len(values))
+		for i, v := range values {
+			converted[i] = int32(v)
+		}
+		return converted
+	}
+	return &validatorpb.BatchChunkReport{
+		Received:              int32(report.Received),
+		MissingSequences:      ints(report.MissingSequences),
+		OutOfOrderSequences:   ints(report.OutOfOrderSequences),
+		NonSequentialChunkIds: int32(report.NonSequentialChunkIDs),
+	}
+}
+
+// toBatchSession converts the status map of a batch session, which holds the
+// derived status and success rate the HTTP API reports
+func toBatchSession(session *models.BatchSession) *validatorpb.BatchSession {
+	derived := session.GetStatus()
+	state := session.State()
+	pb := &validatorpb.BatchSession{
+		BatchId:        state.BatchID,
+		ModelType:      state.ModelType,
+		SchemaVersion:  state.SchemaVersion,
+		TotalRecords:   int32(state.TotalRecords),
+		ValidRecords:   int32(state.ValidRecords),
+		InvalidRecords: int32(state.InvalidRecords),
+		WarningRecords: int32(state.WarningRecords),
+		Threshold:      state.Threshold,
+		StartedAt:      timestamp(state.StartedAt),
+		LastUpdated:    timestamp(state.LastUpdated),
+		ExpiresAt:      timestamp(session.ExpiresAt()),
+		IsFinal:        state.IsFinal,
+		Chunks:         toChunkReport(session.ChunkReport()),
+	}
+	pb.Status, _ = derived["status"].(string)
+	pb.SuccessRate, _ = derived["success_rate"].(float64)
+	return pb
+}
+
+// batchError maps a failed batch session operation to a status, as
+// sendBatchStoreError does for HTTP; the bool is false for unexpected errors
+func batchError(batchID string, err error) (error, bool) {
+	switch {
+	case errors.Is(err, models.ErrBatchNotFound):
+		return status.Errorf(codes.NotFound, "Batch session '%s' not found", batchID), true
+	case errors.Is(err, models.ErrBatchModelMismatch):
+		return status.Error(codes.FailedPrecondition, err.Error()), true
+	case errors.Is(err, models.ErrBatchExpired):
+		return status.Errorf(codes.FailedPrecondition, "Batch session '%s' has expired", batchID), true
+	case errors.Is(err, models.ErrBatchCompleted):
+		return status.Errorf(codes.FailedPrecondition, "Batch session '%s' is already completed", batchID), true
+	case errors.Is(err, models.ErrBatchTooManyRecords):
+		return status.Error(codes.ResourceExhausted, err.Error()), true
+	case errors.Is(err, models.ErrBatchLimitExceeded):
+		return status.Error(codes.ResourceExhausted, "Too many open batch sessions; complete or abort one first"), true
+	case errors.Is(err, models.ErrInvalidBatchTTL):
+		return status.Error(codes.InvalidArgument, err.Error()), true
+	case errors.Is(err, models.ErrInvalidChunkID):
+		return status.Errorf(codes.InvalidArgument, "chunk_id sequence numbers must be at most %d", models.MaxChunkSequence), true
+	}
+	return status.Error(codes.Internal, "Batch session storage unavailable"), false
+}
@@ -0,0 +1,196 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"goplayground-data-validator/auth"
+	"goplayground-data-validator/logging"
+	"goplayground-data-validator/tracing"
+	"goplayground-data-validator/validatorpb"
+)
+
+// Options configures NewServer
+type Options struct {
+	TLSConfig      *tls.Config         // Nil serves plaintext
+	Authenticator  *auth.Authenticator // Nil accepts every call, as the HTTP API does without auth
+	MaxRecvMsgSize int                 // Largest request message in bytes; 0 keeps the gRPC default of 4 MiB
+}
+
+// operations classifies RPCs for authorization the way auth.Middleware
+// classifies HTTP routes; anything else, such as reflection, is a read
+var operations = map[string]string{
+	validatorpb.ValidatorService_Validate_FullMethodName:        auth.OpValidate,
+	validatorpb.ValidatorService_ValidateArray_FullMethodName:   auth.OpValidate,
+	validatorpb.ValidatorService_ValidateStream_FullMethodName:  auth.OpValidate,
+	validatorpb.ValidatorService_StartBatch_FullMethodName:      auth.OpBatch,
+	validatorpb.ValidatorService_AddBatchRecords_FullMethodName: auth.OpBatch,
+	validatorpb.ValidatorService_GetBatch_FullMethodName:        auth.OpBatch,
+	validatorpb.ValidatorService_CompleteBatch_FullMethodName:   auth.OpBatch,
+	validatorpb.ValidatorService_AbortBatch_FullMethodName:      auth.OpBatch,
+}
+
+// NewServer creates a gRPC server for svc with server reflection enabled.
+// Every call is traced, authenticated and logged like an HTTP request.
+func NewServer(svc *Service, opts Options) *grpc.Server {
+	i := &interceptor{authenticator: opts.Authenticator}
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(i.unary),
+		grpc.ChainStreamInterceptor(i.stream),
+	}
+	if opts.TLSConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(opts.TLSConfig)))
+	}
+	if opts.MaxRecvMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(opts.MaxRecvMsgSize))
+	}
+	server := grpc.NewServer(serverOpts...)
+	validatorpb.RegisterValidatorServiceServer(server, svc)
+	reflection.Register(server)
+	return server
+}
+
+// Shutdown stops server gracefully, cancelling the calls still running after timeout
+func Shutdown(server *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		server.Stop()
+		<-stopped
+	}
+}
+
+type interceptor struct {
+	authenticator *auth.Authenticator
+}
+
+func (i *interceptor) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var resp interface{}
+	err := i.serve(ctx, info.FullMethod, func(ctx context.Context) error {
+		var err error
+		resp, err = handler(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+func (i *interceptor) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return i.serve(ss.Context(), info.FullMethod, func(ctx context.Context) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	})
+}
+
+// serve runs call with the trace, request ID, log fields and principal of the
+// call in its context, and logs one "rpc" line when it returns
+func (i *interceptor) serve(ctx context.Context, method string, call func(context.Context) error) error {
+	start := time.Now()
+	md, _ := metadata.FromIncomingContext(ctx)
+	header := http.Header{}
+	for key, values := range md {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+
+	ctx = tracing.Extract(ctx, propagation.HeaderCarrier(header))
+	ctx, span := tracing.Tracer().Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("rpc.system", "grpc"), attribute.String("rpc.method", method)))
+	defer span.End()
+
+	requestID := tracing.UsableRequestID(header.Get(tracing.RequestIDHeader))
+	ctx = tracing.WithRequestID(ctx, requestID)
+	span.SetAttributes(attribute.String("request.id", requestID))
+	grpc.SetHeader(ctx, metadata.Pairs(tracing.RequestIDHeader, requestID))
+
+	ctx, fields := logging.WithFields(ctx)
+	ctx, err := i.authorize(ctx, method, header)
+	if err == nil {
+		err = call(ctx)
+	}
+
+	code := status.Code(err)
+	span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+	}
+	attrs = append(attrs, fields()...)
+	level := slog.LevelInfo
+	if serverError(code) {
+		span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+		level = slog.LevelError
+	}
+	slog.LogAttrs(ctx, level, "rpc", attrs...)
+	return err
+}
+
+// authorize authenticates the call from its metadata and peer certificate and
+// returns a copy of ctx carrying the principal
+func (i *interceptor) authorize(ctx context.Context, method string, header http.Header) (context.Context, error) {
+	if i.authenticator == nil {
+		return ctx, nil
+	}
+	var state *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state = &info.State
+		}
+	}
+	principal, err := i.authenticator.Authenticate(header, state)
+	if err != nil {
+		slog.InfoContext(ctx, "Authentication failed", "error", err)
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+	logging.AddFields(ctx, slog.String("principal", principal.ID), slog.String("auth_method", principal.Method))
+
+	operation, ok := operations[method]
+	if !ok {
+		operation = auth.OpRead
+	}
+	if !principal.Allows(operation, "") {
+		return ctx, status.Errorf(codes.PermissionDenied, "%s may not perform %s", principal.ID, operation)
+	}
+	return auth.NewContext(ctx, principal), nil
+}
+
+// serverError reports whether code means the server, not the caller, failed
+func serverError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		return true
+	}
+	return false
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,327 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"goplayground-data-validator/auth"
+	"goplayground-data-validator/models"
+	"goplayground-data-validator/registry"
+	_ "goplayground-data-validator/validations" // Registers the built-in models
+	"goplayground-data-validator/validatorpb"
+)
+
+// startServer serves a service backed by every built-in model over an
+// in-memory connection and returns a client for it
+func startServer(t *testing.T, opts Options) (validatorpb.ValidatorServiceClient, *grpc.ClientConn) {
+	t.Helper()
+	reg := registry.NewUnifiedRegistry()
+	if err := reg.StartAutoRegistration(context.Background(), http.NewServeMux()); err != nil {
+		t.Fatalf("Registration failed: %v", err)
+	}
+	server := NewServer(NewService(reg, models.NewBatchSessionManager(models.NewMemoryBatchStore())), opts)
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return validatorpb.NewValidatorServiceClient(conn), conn
+}
+
+// incident returns a valid incident record, or an invalid one when valid is false
+func incident(t *testing.T, valid bool) *structpb.Struct {
+	t.Helper()
+	fields := map[string]interface{}{
+		"id":          "INC-20250104-0001",
+		"title":       "Production Database Connection Failure",
+		"description": "Critical database connection failure affecting all production services",
+		"severity":    "critical",
+		"status":      "open",
+		"priority":    5,
+		"category":    "bug",
+		"environment": "production",
+		"reported_by": "ops@example.com",
+		"reported_at": "2025-01-04T10:00:00Z",
+		"assigned_to": "oncall@example.com",
+	}
+	if !valid {
+		fields["severity"] = "invalid"
+		fields["priority"] = 999
+	}
+	record, err := structpb.NewStruct(fields)
+	if err != nil {
+		t.Fatalf("Failed to build record: %v", err)
+	}
+	return record
+}
+
+// failedRows returns the indexes of the invalid rows in result; rows that are
+// valid with warnings are reported too and skipped here
+func failedRows(result *validatorpb.ArrayValidationResult) []int32 {
+	var failed []int32
+	for _, row := range result.Results {
+		if !row.IsValid {
+			failed = append(failed, row.RowIndex)
+		}
+	}
+	return failed
+}
+
+func TestValidate(t *testing.T) {
+	client, _ := startServer(t, Options{})
+	ctx := context.Background()
+
+	result, err := client.Validate(ctx, &validatorpb.ValidateRequest{ModelType: "incident", Record: incident(t, true)})
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if !result.IsValid || result.ModelType != "incident" || len(result.Errors) != 0 {
+		t.Errorf("Expected a valid incident, got %v", result)
+	}
+
+	result, err = client.Validate(ctx, &validatorpb.ValidateRequest{ModelType: "incident", Record: incident(t, false)})
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if result.IsValid || len(result.Errors) == 0 {
+		t.Errorf("Expected errors for an invalid incident, got %v", result)
+	}
+
+	tests := []struct {
+		name string
+		req  *validatorpb.ValidateRequest
+	}{
+		{"missing model", &validatorpb.ValidateRequest{Record: incident(t, true)}},
+		{"unknown model", &validatorpb.ValidateRequest{ModelType: "nonexistent", Record: incident(t, true)}},
+		{"missing record", &validatorpb.ValidateRequest{ModelType: "incident"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.Validate(ctx, tt.req); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateArray(t *testing.T) {
+	client, _ := startServer(t, Options{})
+	threshold := 50.0
+	result, err := client.ValidateArray(context.Background(), &validatorpb.ValidateArrayRequest{
+		ModelType: "incident",
+		Records:   []*structpb.Struct{incident(t, true), incident(t, false)},
+		Threshold: &threshold,
+	})
+	if err != nil {
+		t.Fatalf("ValidateArray failed: %v", err)
+	}
+	if result.TotalRecords != 2 || result.ValidRecords != 1 || result.InvalidRecords != 1 {
+		t.Errorf("Expected 1 valid and 1 invalid record, got %v", result)
+	}
+	if result.Status != "success" || result.GetThreshold() != 50 {
+		t.Errorf("Expected success at a 50%% threshold, got %q and %v", result.Status, result.Threshold)
+	}
+	if failed := failedRows(result); len(failed) != 1 || failed[0] != 1 {
+		t.Errorf("Expected row 1 to fail, got rows %v", failed)
+	}
+
+	_, err = client.ValidateArray(context.Background(), &validatorpb.ValidateArrayRequest{
+		ModelType: "incident",
+		Records:   []*structpb.Struct{incident(t, true)},
+		Options:   &validatorpb.ValidationOptions{MaxConcurrency: 500},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for max_concurrency 500, got %v", err)
+	}
+}
+
+func TestValidateStream(t *testing.T) {
+	client, _ := startServer(t, Options{})
+	stream, err := client.ValidateStream(context.Background())
+	if err != nil {
+		t.Fatalf("ValidateStream failed: %v", err)
+	}
+	requests := []*validatorpb.ValidateStreamRequest{
+		{ModelType: "incident"}, // Settings only
+		{Record: incident(t, true)},
+		{Record: incident(t, false)},
+		{Record: incident(t, true)},
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	result, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("CloseAndRecv failed: %v", err)
+	}
+	if result.TotalRecords != 3 || result.ValidRecords != 2 || result.InvalidRecords != 1 {
+		t.Errorf("Expected 2 valid and 1 invalid record, got %v", result)
+	}
+	if failed := failedRows(result); len(failed) != 1 || failed[0] != 1 {
+		t.Errorf("Expected row 1 to fail, got rows %v", failed)
+	}
+
+	// The first message must name the model
+	stream, _ = client.ValidateStream(context.Background())
+	stream.Send(&validatorpb.ValidateStreamRequest{Record: incident(t, true)})
+	if _, err := stream.CloseAndRecv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument without a model, got %v", err)
+	}
+}
+
+func TestBatchSession(t *testing.T) {
+	client, _ := startServer(t, Options{})
+	ctx := context.Background()
+
+	session, err := client.StartBatch(ctx, &validatorpb.StartBatchRequest{ModelType: "incident", JobId: "nightly"})
+	if err != nil {
+		t.Fatalf("StartBatch failed: %v", err)
+	}
+	if session.ModelType != "incident" || session.Status != "in_progress" {
+		t.Errorf("Expected an in-progress incident session, got %v", session)
+	}
+
+	add := &validatorpb.AddBatchRecordsRequest{
+		BatchId: session.BatchId,
+		ChunkId: "0",
+		Records: []*structpb.Struct{incident(t, true), incident(t, false)},
+	}
+	added, err := client.AddBatchRecords(ctx, add)
+	if err != nil {
+		t.Fatalf("AddBatchRecords failed: %v", err)
+	}
+	if added.Records != 2 || added.InvalidRecords != 1 || added.SessionTotal != 2 || added.Replayed {
+		t.Errorf("Unexpected chunk response %v", added)
+	}
+
+	// A resent chunk is answered from the session without counting it again
+	replayed, err := client.AddBatchRecords(ctx, add)
+	if err != nil || !replayed.Replayed || replayed.SessionTotal != 2 {
+		t.Errorf("Expected a replayed chunk, got %v and %v", replayed, err)
+	}
+
+	current, err := client.GetBatch(ctx, &validatorpb.GetBatchRequest{BatchId: session.BatchId})
+	if err != nil || current.TotalRecords != 2 || current.Chunks.GetReceived() != 1 {
+		t.Errorf("Expected 2 records in 1 chunk, got %v and %v", current, err)
+	}
+
+	completed, err := client.CompleteBatch(ctx, &validatorpb.CompleteBatchRequest{BatchId: session.BatchId})
+	if err != nil {
+		t.Fatalf("CompleteBatch failed: %v", err)
+	}
+	if !completed.IsFinal || completed.SuccessRate != 50 {
+		t.Errorf("Expected a final session at 50%%, got %v", completed)
+	}
+
+	if _, err := client.AbortBatch(ctx, &validatorpb.AbortBatchRequest{BatchId: session.BatchId}); err != nil {
+		t.Fatalf("AbortBatch failed: %v", err)
+	}
+	if _, err := client.GetBatch(ctx, &validatorpb.GetBatchRequest{BatchId: session.BatchId}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound after abort, got %v", err)
+	}
+	if _, err := client.StartBatch(ctx, &validatorpb.StartBatchRequest{ModelType: "incident", JobId: "../etc"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a job ID with a slash, got %v", err)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	hash := func(key string) string {
+		sum := sha256.Sum256([]byte(key))
+		return hex.EncodeToString(sum[:])
+	}
+	authenticator, err := auth.New(auth.Config{APIKeys: []auth.APIKey{
+		{ID: "reader", SHA256: hash("read-key"), Grant: auth.Grant{Operations: []string{auth.OpRead}}},
+		{ID: "deployer", SHA256: hash("deploy-key"), Grant: auth.Grant{Models: []string{"deployment"}}},
+	}})
+	if err != nil {
+		t.Fatalf("auth.New failed: %v", err)
+	}
+	client, _ := startServer(t, Options{Authenticator: authenticator})
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyHeader, key)
+	}
+	validate := &validatorpb.ValidateRequest{ModelType: "incident", Record: incident(t, true)}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"no credentials", context.Background(), codes.Unauthenticated},
+		{"unknown key", withKey("wrong"), codes.Unauthenticated},
+		{"operation not granted", withKey("read-key"), codes.PermissionDenied},
+		{"model not granted", withKey("deploy-key"), codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.Validate(tt.ctx, validate); status.Code(err) != tt.want {
+				t.Errorf("Expected %s, got %v", tt.want, err)
+			}
+		})
+	}
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(withKey("read-key"), "x-request-id", "req-42")
+	listed, err := client.ListModels(ctx, &validatorpb.ListModelsRequest{}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("ListModels failed: %v", err)
+	}
+	if len(listed.ModelTypes) == 0 {
+		t.Error("Expected registered models")
+	}
+	if got := header.Get("x-request-id"); len(got) != 1 || got[0] != "req-42" {
+		t.Errorf("Expected the request ID echoed, got %v", got)
+	}
+}
+
+func TestReflection(t *testing.T) {
+	_, conn := startServer(t, Options{})
+	stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatalf("ServerReflectionInfo failed: %v", err)
+	}
+	err = stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Expected EOF after CloseSend, got %v", err)
+	}
+
+	found := false
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		found = found || service.Name == validatorpb.ValidatorService_ServiceDesc.ServiceName
+	}
+	if !found {
+		t.Errorf("Expected %s to be listed, got %v", validatorpb.ValidatorService_ServiceDesc.ServiceName, resp)
+	}
+}
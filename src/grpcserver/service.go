@@ -0,0 +1,410 @@
+// Package grpcserver serves the validator.v1.ValidatorService gRPC API from the
+// same model registry, batch sessions, admission control and authentication as
+// the HTTP API
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"sort"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"goplayground-data-validator/admission"
+	"goplayground-data-validator/auth"
+	"goplayground-data-validator/jsonlimit"
+	"goplayground-data-validator/logging"
+	"goplayground-data-validator/models"
+	"goplayground-data-validator/registry"
+	"goplayground-data-validator/validatorpb"
+)
+
+// maxStreamResults bounds the failed rows ValidateStream keeps for its
+// response; the counts and summary still cover every row
+const maxStreamResults = 1000
+
+// maxChunkIDLength bounds the chunk IDs stored with each batch chunk, as
+// X-Batch-Chunk-ID is bounded over HTTP
+const maxChunkIDLength = 128
+
+// Service implements validatorpb.ValidatorServiceServer
+type Service struct {
+	validatorpb.UnimplementedValidatorServiceServer
+	registry *registry.UnifiedRegistry
+	batches  *models.BatchSessionManager
+}
+
+// NewService creates a service validating with reg and keeping batch sessions in batches
+func NewService(reg *registry.UnifiedRegistry, batches *models.BatchSessionManager) *Service {
+	return &Service{registry: reg, batches: batches}
+}
+
+// model checks that model is registered and that the caller may use it for operation
+func (s *Service) model(ctx context.Context, model, operation string) (registry.ModelType, error) {
+	if model == "" {
+		return "", status.Error(codes.InvalidArgument, "model_type is required")
+	}
+	logging.AddFields(ctx, slog.String("model", model))
+	if err := auth.AllowModel(ctx, operation, model); err != nil {
+		return "", status.Error(codes.PermissionDenied, err.Error())
+	}
+	if !s.registry.IsRegistered(registry.ModelType(model)) {
+		return "", status.Errorf(codes.InvalidArgument, "Model type '%s' is not registered", model)
+	}
+	return registry.ModelType(model), nil
+}
+
+// admit holds admission capacity for records of modelType until release is called
+func admit(ctx context.Context, modelType registry.ModelType, records int) (release func(), err error) {
+	release, err = admission.Default().Acquire(ctx, string(modelType), records)
+	if err == nil {
+		return release, nil
+	}
+	var rejection *admission.RejectedError
+	if !errors.As(err, &rejection) {
+		return nil, status.FromContextError(err).Err()
+	}
+	st := status.New(codes.ResourceExhausted, rejection.Message)
+	if detailed, detailErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(rejection.RetryAfter)}); detailErr == nil {
+		st = detailed
+	}
+	return nil, st.Err()
+}
+
+// arrayError maps a failed array validation as sendArrayValidationError does for HTTP
+func arrayError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, "Array validation timed out: "+err.Error())
+	}
+	return status.Error(codes.Internal, "Array validation failed: "+err.Error())
+}
+
+// Validate checks one record
+func (s *Service) Validate(ctx context.Context, req *validatorpb.ValidateRequest) (*validatorpb.ValidationResult, error) {
+	modelType, err := s.model(ctx, req.ModelType, auth.OpValidate)
+	if err != nil {
+		return nil, err
+	}
+	if req.Record == nil {
+		return nil, status.Error(codes.InvalidArgument, "record is required")
+	}
+	data, err := recordJSON(req.Record, jsonlimit.Current())
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := s.registry.CreateModelInstance(modelType)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to create model instance: %v", err)
+	}
+	if err := json.Unmarshal(data, instance); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Failed to parse record into model struct: %v", err)
+	}
+
+	release, err := admit(ctx, modelType, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, err := s.registry.ValidatePayloadContext(ctx, modelType, reflect.ValueOf(instance).Elem().Interface())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Validation failed: %v", err)
+	}
+	return toValidationResult(result), nil
+}
+
+// ValidateArray checks a list of records and applies the threshold. A batch
+// below its threshold is a normal response with status "failed".
+func (s *Service) ValidateArray(ctx context.Context, req *validatorpb.ValidateArrayRequest) (*validatorpb.ArrayValidationResult, error) {
+	modelType, err := s.model(ctx, req.ModelType, auth.OpValidate)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Records) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "records must not be empty")
+	}
+	opts, err := batchOptions(req.Options)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkThreshold(req.Threshold); err != nil {
+		return nil, err
+	}
+	records, err := recordMaps(req.Records)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := admit(ctx, modelType, len(records))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	threshold := req.Threshold
+	if threshold == nil {
+		threshold = s.registry.DefaultThreshold(modelType)
+	}
+	result, err := s.registry.ValidateArrayWithOptions(ctx, modelType, records, threshold, opts)
+	if err != nil {
+		return nil, arrayError(err)
+	}
+	return toArrayResult(result), nil
+}
+
+// ValidateStream checks records as they arrive, through the same code path as
+// the NDJSON stream endpoint, and answers once the client closes the stream
+func (s *Service) ValidateStream(stream validatorpb.ValidatorService_ValidateStreamServer) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "stream must name a model_type in its first message")
+	} else if err != nil {
+		return err
+	}
+	modelType, err := s.model(ctx, first.ModelType, auth.OpValidate)
+	if err != nil {
+		return err
+	}
+	opts, err := batchOptions(first.Options)
+	if err != nil {
+		return err
+	}
+	if err := checkThreshold(first.Threshold); err != nil {
+		return err
+	}
+	threshold := first.Threshold
+	if threshold == nil {
+		threshold = s.registry.DefaultThreshold(modelType)
+	}
+
+	// Rows are validated one at a time, so a stream holds one record of capacity
+	release, err := admit(ctx, modelType, 1)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	var failed []models.RowValidationResult
+	emit := func(row models.RowValidationResult) error {
+		if (!row.IsValid || len(row.Warnings) > 0) && len(failed) < maxStreamResults {
+			failed = append(failed, row)
+		}
+		return nil
+	}
+	summary, err := s.registry.ValidateStream(ctx, modelType, &recordReader{stream: stream, next: first}, threshold, opts, emit)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return stream.SendAndClose(toStreamResult(summary, failed))
+}
+
+// recordReader presents the records of a ValidateStream call as NDJSON, one
+// line per record, so they can be handed to UnifiedRegistry.ValidateStream.
+// Messages without a record are skipped.
+type recordReader struct {
+	stream validatorpb.ValidatorService_ValidateStreamServer
+	next   *validatorpb.ValidateStreamRequest // Received but not yet read
+	buf    bytes.Buffer
+}
+
+func (r *recordReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		msg := r.next
+		r.next = nil
+		if msg == nil {
+			var err error
+			if msg, err = r.stream.Recv(); err != nil {
+				return 0, err
+			}
+		}
+		if msg.Record == nil {
+			continue
+		}
+		// Struct values always encode; a record that cannot becomes an invalid row
+		line, _ := json.Marshal(msg.Record.AsMap())
+		r.buf.Write(line)
+		r.buf.WriteByte('\n')
+	}
+	return r.buf.Read(p)
+}
+
+// StartBatch opens a batch session bound to one model
+func (s *Service) StartBatch(ctx context.Context, req *validatorpb.StartBatchRequest) (*validatorpb.BatchSession, error) {
+	modelType, err := s.model(ctx, req.ModelType, auth.OpBatch)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.SchemaVersion) > 64 {
+		return nil, status.Error(codes.InvalidArgument, "schema_version must be at most 64 characters")
+	}
+	// The job ID becomes part of the batch ID, which the file store uses as a file name
+	if req.JobId != "" && (len(req.JobId) > 64 || !models.ValidBatchID(req.JobId)) {
+		return nil, status.Error(codes.InvalidArgument, "job_id must be at most 64 letters, digits, '.', '_' or '-'")
+	}
+	if err := checkThreshold(req.Threshold); err != nil {
+		return nil, err
+	}
+	threshold := req.Threshold
+	if threshold == nil {
+		threshold = s.registry.DefaultThreshold(modelType)
+	}
+
+	batchID := models.GenerateBatchID(req.JobId)
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+	session, err := s.batches.CreateModelBatchSession(batchID, req.ModelType, req.SchemaVersion, threshold, ttl)
+	if err != nil {
+		return nil, s.batchError(ctx, batchID, err)
+	}
+	logging.AddFields(ctx, slog.String("batch_id", batchID))
+	return toBatchSession(session), nil
+}
+
+// session looks up a batch session and checks that the caller may use its model
+func (s *Service) session(ctx context.Context, batchID string) (*models.BatchSession, error) {
+	if batchID == "" {
+		return nil, status.Error(codes.InvalidArgument, "batch_id is required")
+	}
+	logging.AddFields(ctx, slog.String("batch_id", batchID))
+	session, exists := s.batches.GetBatchSession(batchID)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "Batch session '%s' not found", batchID)
+	}
+	if err := auth.AllowModel(ctx, auth.OpBatch, session.ModelType); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return session, nil
+}
+
+// batchError converts a batch session failure, logging unexpected ones
+func (s *Service) batchError(ctx context.Context, batchID string, err error) error {
+	converted, expected := batchError(batchID, err)
+	if !expected {
+		slog.ErrorContext(ctx, "Batch store error", "batch_id", batchID, "error", err)
+	}
+	return converted
+}
+
+// AddBatchRecords validates a chunk of records into a batch session
+func (s *Service) AddBatchRecords(ctx context.Context, req *validatorpb.AddBatchRecordsRequest) (*validatorpb.AddBatchRecordsResponse, error) {
+	session, err := s.session(ctx, req.BatchId)
+	if err != nil {
+		return nil, err
+	}
+	modelType := registry.ModelType(session.ModelType)
+	if err := session.CheckModel(session.ModelType, req.SchemaVersion); err != nil {
+		return nil, s.batchError(ctx, req.BatchId, err)
+	}
+	if len(req.ChunkId) > maxChunkIDLength {
+		return nil, status.Errorf(codes.InvalidArgument, "chunk_id must be at most %d characters", maxChunkIDLength)
+	}
+	if err := models.CheckChunkID(req.ChunkId); err != nil {
+		return nil, s.batchError(ctx, req.BatchId, err)
+	}
+	if req.ChunkId != "" {
+		if chunk, recorded := session.FindChunk(req.ChunkId); recorded {
+			return chunkResponse(req.BatchId, chunk, true), nil
+		}
+	}
+	if len(req.Records) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "records must not be empty")
+	}
+	opts, err := batchOptions(req.Options)
+	if err != nil {
+		return nil, err
+	}
+	records, err := recordMaps(req.Records)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refuse chunks for expired or full sessions before validating them
+	if err := s.batches.AdmitChunk(session, len(records)); err != nil {
+		return nil, s.batchError(ctx, req.BatchId, err)
+	}
+	release, err := admit(ctx, modelType, len(records))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, err := s.registry.ValidateArrayWithOptions(ctx, modelType, records, session.Threshold, opts)
+	if err != nil {
+		return nil, arrayError(err)
+	}
+	chunk, replayed, err := s.batches.RecordBatchChunk(req.BatchId, req.ChunkId, result)
+	if err != nil {
+		return nil, s.batchError(ctx, req.BatchId, err)
+	}
+	return chunkResponse(req.BatchId, chunk, replayed), nil
+}
+
+func chunkResponse(batchID string, chunk models.BatchChunk, replayed bool) *validatorpb.AddBatchRecordsResponse {
+	return &validatorpb.AddBatchRecordsResponse{
+		BatchId:        batchID,
+		Records:        int32(chunk.Records),
+		ValidRecords:   int32(chunk.ValidRecords),
+		InvalidRecords: int32(chunk.InvalidRecords),
+		WarningRecords: int32(chunk.WarningRecords),
+		SessionTotal:   int32(chunk.SessionTotal),
+		Replayed:       replayed,
+	}
+}
+
+// GetBatch returns a batch session's running totals
+func (s *Service) GetBatch(ctx context.Context, req *validatorpb.GetBatchRequest) (*validatorpb.BatchSession, error) {
+	session, err := s.session(ctx, req.BatchId)
+	if err != nil {
+		return nil, err
+	}
+	return toBatchSession(session), nil
+}
+
+// CompleteBatch finalizes a batch session; its failed rows stay available over
+// HTTP until the session expires
+func (s *Service) CompleteBatch(ctx context.Context, req *validatorpb.CompleteBatchRequest) (*validatorpb.BatchSession, error) {
+	if _, err := s.session(ctx, req.BatchId); err != nil {
+		return nil, err
+	}
+	if _, err := s.batches.FinalizeBatchSession(req.BatchId); err != nil {
+		return nil, s.batchError(ctx, req.BatchId, err)
+	}
+	// Re-read the session: stores other than memory return snapshots
+	session, exists := s.batches.GetBatchSession(req.BatchId)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "Batch session '%s' not found", req.BatchId)
+	}
+	return toBatchSession(session), nil
+}
+
+// AbortBatch discards a batch session and its retained rows
+func (s *Service) AbortBatch(ctx context.Context, req *validatorpb.AbortBatchRequest) (*validatorpb.AbortBatchResponse, error) {
+	if _, err := s.session(ctx, req.BatchId); err != nil {
+		return nil, err
+	}
+	if err := s.batches.AbortBatchSession(req.BatchId); err != nil {
+		return nil, s.batchError(ctx, req.BatchId, err)
+	}
+	return &validatorpb.AbortBatchResponse{BatchId: req.BatchId}, nil
+}
+
+// ListModels returns the registered model types in name order
+func (s *Service) ListModels(ctx context.Context, req *validatorpb.ListModelsRequest) (*validatorpb.ListModelsResponse, error) {
+	modelTypes := s.registry.ListModels()
+	names := make([]string, len(modelTypes))
+	for i, modelType := range modelTypes {
+		names[i] = string(modelType)
+	}
+	sort.Strings(names)
+	return &validatorpb.ListModelsResponse{ModelTypes: names}, nil
+}
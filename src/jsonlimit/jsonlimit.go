@@ -0,0 +1,279 @@
+// Package jsonlimit decodes untrusted JSON under limits on body size, nesting
+// depth, array length, string length and object key count
+package jsonlimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Names of the limits, as reported in LimitError.Limit
+const (
+	LimitBodyBytes    = "max_body_bytes"
+	LimitDepth        = "max_json_depth"
+	LimitArrayLength  = "max_array_length"
+	LimitStringLength = "max_string_length"
+	LimitObjectKeys   = "max_object_keys"
+)
+
+// Limits bounds the JSON a client may send. Zero means no limit.
+type Limits struct {
+	MaxBodyBytes    int64 // Bytes in a request body
+	MaxDepth        int   // Nesting of objects and arrays; the top-level value is depth 1
+	MaxArrayLength  int   // Elements in any one array, including the data array of a batch
+	MaxStringLength int   // Bytes in any one string value or object key
+	MaxObjectKeys   int   // Keys in any one object
+}
+
+// DefaultLimits returns the limits used when the server configures none
+func DefaultLimits() Limits {
+	return Limits{
+		MaxBodyBytes:    32 << 20,
+		MaxDepth:        64,
+		MaxArrayLength:  100000,
+		MaxStringLength: 1 << 20,
+		MaxObjectKeys:   1000,
+	}
+}
+
+// Validate rejects negative limits
+func (l Limits) Validate() error {
+	if l.MaxBodyBytes < 0 || l.MaxDepth < 0 || l.MaxArrayLength < 0 || l.MaxStringLength < 0 || l.MaxObjectKeys < 0 {
+		return fmt.Errorf("JSON input limits must not be negative")
+	}
+	return nil
+}
+
+var current atomic.Pointer[Limits]
+
+func init() {
+	limits := DefaultLimits()
+	current.Store(&limits)
+}
+
+// Configure replaces the limits used by DecodeRequest and Current
+func Configure(limits Limits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	current.Store(&limits)
+	return nil
+}
+
+// Current returns the configured limits
+func Current() Limits {
+	return *current.Load()
+}
+
+// LimitError reports the limit a body exceeded and where
+type LimitError struct {
+	Limit string // One of the Limit constants
+	Max   int64  // The configured value
+	Path  string // JSONPath of the offending value, empty for the body size
+}
+
+func (e *LimitError) Error() string {
+	switch e.Limit {
+	case LimitBodyBytes:
+		return fmt.Sprintf("request body exceeds %s (%d bytes)", e.Limit, e.Max)
+	case LimitDepth:
+		return fmt.Sprintf("JSON nesting at %s exceeds %s (%d)", e.Path, e.Limit, e.Max)
+	case LimitArrayLength:
+		return fmt.Sprintf("array at %s has more than %s (%d) elements", e.Path, e.Limit, e.Max)
+	case LimitStringLength:
+		return fmt.Sprintf("string at %s exceeds %s (%d bytes)", e.Path, e.Limit, e.Max)
+	default:
+		return fmt.Sprintf("object at %s has more than %s (%d) keys", e.Path, e.Limit, e.Max)
+	}
+}
+
+// Status is the HTTP status for e: 413 for an oversized body, 400 otherwise
+func (e *LimitError) Status() int {
+	if e.Limit == LimitBodyBytes {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// SyntaxError is malformed JSON, or a value of the wrong type for the field it
+// is decoded into, located by line and column
+type SyntaxError struct {
+	Line, Column int   // 1-based
+	Err          error // *json.SyntaxError or *json.UnmarshalTypeError
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *SyntaxError) Unwrap() error { return e.Err }
+
+// DecodeRequest decodes the first JSON value of r's body into v under the current
+// limits. The body is checked token by token as it is read, so an oversized or
+// too deeply nested body is rejected before it is held in memory. Exceeded limits
+// are returned as *LimitError, malformed JSON and mistyped values as *SyntaxError.
+func DecodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	limits := Current()
+	body := r.Body
+	if limits.MaxBodyBytes > 0 {
+		body = http.MaxBytesReader(w, r.Body, limits.MaxBodyBytes)
+	}
+
+	var buf bytes.Buffer
+	if err := scan(io.TeeReader(body, &buf), limits); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return &LimitError{Limit: LimitBodyBytes, Max: maxBytesErr.Limit}
+		}
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			// The tokenizer reports where its last token started; a plain
+			// decode of the same bytes points at the offending character
+			var discard json.RawMessage
+			if exact := json.Unmarshal(buf.Bytes(), &discard); errors.As(exact, &syntaxErr) {
+				err = exact
+			}
+		}
+		return locate(buf.Bytes(), err)
+	}
+	data := buf.Bytes()
+	return locate(data, json.NewDecoder(bytes.NewReader(data)).Decode(v))
+}
+
+// locate wraps JSON syntax and type errors in a *SyntaxError with their
+// position in data; other errors are returned unchanged
+func locate(data []byte, err error) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+	// Offsets count the bytes read, so the last of them is where decoding stopped
+	pos := int(min(max(offset-1, 0), int64(len(data))))
+	before := data[:pos]
+	return &SyntaxError{
+		Line:   bytes.Count(before, []byte("\n")) + 1,
+		Column: pos - bytes.LastIndexByte(before, '\n'),
+		Err:    err,
+	}
+}
+
+// Check verifies that the first JSON value in data is within limits; MaxBodyBytes
+// is not applied
+func Check(data []byte, limits Limits) error {
+	return scan(bytes.NewReader(data), limits)
+}
+
+// frame is an object or array being scanned
+type frame struct {
+	object  bool
+	n       int    // Keys or elements seen so far
+	key     string // Current key of an object
+	wantKey bool   // An object's next token is a key or its end
+}
+
+// scan walks the tokens of the first JSON value in src and stops at the first
+// limit it exceeds
+func scan(src io.Reader, limits Limits) error {
+	dec := json.NewDecoder(src)
+	dec.UseNumber()
+	var stack []*frame
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			switch {
+			case top.object && top.wantKey:
+				if tok == json.Delim('}') {
+					stack = stack[:len(stack)-1]
+					if len(stack) == 0 {
+						return nil
+					}
+					continue
+				}
+				key := tok.(string)
+				top.n++
+				if limits.MaxObjectKeys > 0 && top.n > limits.MaxObjectKeys {
+					return &LimitError{Limit: LimitObjectKeys, Max: int64(limits.MaxObjectKeys), Path: path(stack[:len(stack)-1])}
+				}
+				if limits.MaxStringLength > 0 && len(key) > limits.MaxStringLength {
+					// Report the object; the key itself is too long to echo back
+					return &LimitError{Limit: LimitStringLength, Max: int64(limits.MaxStringLength), Path: path(stack[:len(stack)-1])}
+				}
+				top.key, top.wantKey = key, false
+				continue
+			case top.object:
+				top.wantKey = true
+			case tok == json.Delim(']'):
+				stack = stack[:len(stack)-1]
+				if len(stack) == 0 {
+					return nil
+				}
+				continue
+			default:
+				top.n++
+				if limits.MaxArrayLength > 0 && top.n > limits.MaxArrayLength {
+					return &LimitError{Limit: LimitArrayLength, Max: int64(limits.MaxArrayLength), Path: path(stack[:len(stack)-1])}
+				}
+			}
+		}
+
+		switch value := tok.(type) {
+		case json.Delim:
+			// Only '{' and '[' reach here; closing delimiters were handled above
+			stack = append(stack, &frame{object: value == '{', wantKey: value == '{'})
+			if limits.MaxDepth > 0 && len(stack) > limits.MaxDepth {
+				return &LimitError{Limit: LimitDepth, Max: int64(limits.MaxDepth), Path: path(stack[:len(stack)-1])}
+			}
+			continue
+		case string:
+			if limits.MaxStringLength > 0 && len(value) > limits.MaxStringLength {
+				return &LimitError{Limit: LimitStringLength, Max: int64(limits.MaxStringLength), Path: path(stack)}
+			}
+		}
+		if len(stack) == 0 {
+			return nil // A scalar top-level value
+		}
+	}
+}
+
+// path renders the position of the value the innermost frame of stack is on as a
+// JSONPath such as $.data[3].name
+func path(stack []*frame) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, f := range stack {
+		if f.object {
+			if f.n > 0 {
+				b.WriteString(".")
+				b.WriteString(f.key)
+			}
+		} else if f.n > 0 {
+			b.WriteString("[")
+			b.WriteString(strconv.Itoa(f.n - 1))
+			b.WriteString("]")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package jsonlimit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	limits := Limits{MaxDepth: 3, MaxArrayLength: 2, MaxStringLength: 5, MaxObjectKeys: 2}
+
+	tests := []struct {
+		name  string
+		input string
+		limit string // Empty when the input is within limits
+		path  string
+	}{
+		{"within limits", `{"data":[{"id":"a"},{"id":"b"}]}`, "", ""},
+		{"scalar", `42`, "", ""},
+		{"empty containers", `{"a":[],"b":{}}`, "", ""},
+		{"too deep", `{"a":{"b":{"c":{}}}}`, LimitDepth, "$.a.b.c"},
+		{"too deep in array", `[[[[1]]]]`, LimitDepth, "$[0][0][0]"},
+		{"long array", `{"data":[1,2,3]}`, LimitArrayLength, "$.data"},
+		{"long string", `{"data":[{"id":"abcdef"}]}`, LimitStringLength, "$.data[0].id"},
+		{"long key", `{"abcdef":1}`, LimitStringLength, "$"},
+		{"many keys", `{"data":{"a":1,"b":2,"c":3}}`, LimitObjectKeys, "$.data"},
+		{"trailing data ignored", `{"a":1} {"b":{"c":{"d":{}}}}`, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check([]byte(tt.input), limits)
+			if tt.limit == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("Expected a LimitError, got %v", err)
+			}
+			if limitErr.Limit != tt.limit || limitErr.Path != tt.path {
+				t.Errorf("Expected %s at %s, got %s at %s", tt.limit, tt.path, limitErr.Limit, limitErr.Path)
+			}
+			if limitErr.Status() != http.StatusBadRequest || !strings.Contains(limitErr.Error(), tt.limit) {
+				t.Errorf("Unexpected error %q with status %d", limitErr.Error(), limitErr.Status())
+			}
+		})
+	}
+
+	if err := Check([]byte(`{"a":`), limits); err == nil || errors.As(err, new(*LimitError)) {
+		t.Errorf("Expected a syntax error for truncated JSON, got %v", err)
+	}
+	if err := Check([]byte(`{"a":[1,2,3,4]}`), Limits{}); err != nil {
+		t.Errorf("Expected zero limits to allow everything, got %v", err)
+	}
+}
+
+func TestDecodeRequest(t *testing.T) {
+	defer Configure(DefaultLimits())
+	if err := Configure(Limits{MaxBodyBytes: 32, MaxDepth: 4}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	var v struct {
+		ModelType string `json:"model_type"`
+	}
+	req := httptest.NewRequest("POST", "/validate", strings.NewReader(`{"model_type":"incident"}`))
+	if err := DecodeRequest(httptest.NewRecorder(), req, &v); err != nil || v.ModelType != "incident" {
+		t.Fatalf("Expected body to decode, got %v and %+v", err, v)
+	}
+
+	req = httptest.NewRequest("POST", "/validate", strings.NewReader(`{"model_type":"`+strings.Repeat("x", 64)+`"}`))
+	var limitErr *LimitError
+	if err := DecodeRequest(httptest.NewRecorder(), req, &v); !errors.As(err, &limitErr) || limitErr.Limit != LimitBodyBytes {
+		t.Fatalf("Expected %s error, got %v", LimitBodyBytes, err)
+	}
+	if limitErr.Status() != http.StatusRequestEntityTooLarge || limitErr.Max != 32 {
+		t.Errorf("Unexpected body size error: %+v", limitErr)
+	}
+
+	// Malformed bodies and mistyped values report where decoding stopped
+	var typed struct {
+		Priority int `json:"priority"`
+	}
+	positions := []struct {
+		body         string
+		line, column int
+	}{
+		{`{"a" 1}`, 1, 6},
+		{"{\n  \"priority\": 1,\n  }", 3, 3},
+		{"{\n  \"priority\": \"high\"\n}", 2, 20}, // The end of the value
+	}
+	if err := Configure(DefaultLimits()); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	for _, tt := range positions {
+		req = httptest.NewRequest("POST", "/validate", strings.NewReader(tt.body))
+		var syntaxErr *SyntaxError
+		if err := DecodeRequest(httptest.NewRecorder(), req, &typed); !errors.As(err, &syntaxErr) {
+			t.Errorf("Expected a syntax error for %q, got %v", tt.body, err)
+		} else if syntaxErr.Line != tt.line || syntaxErr.Column != tt.column {
+			t.Errorf("Expected %q to fail at %d:%d, got %d:%d", tt.body, tt.line, tt.column, syntaxErr.Line, syntaxErr.Column)
+		}
+	}
+
+	if err := Configure(Limits{MaxDepth: -1}); err == nil {
+		t.Error("Expected negative limits to be rejected")
+	}
+}
@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// accessFields collects the attributes handlers add to a request's access log line
+type accessFields struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+type accessFieldsKey struct{}
+
+// AddFields attaches attrs to the access log line of the request carried by ctx.
+// A later attribute replaces an earlier one with the same key. Outside a request
+// wrapped by AccessLog it does nothing.
+func AddFields(ctx context.Context, attrs ...slog.Attr) {
+	fields, ok := ctx.Value(accessFieldsKey{}).(*accessFields)
+	if !ok {
+		return
+	}
+	fields.mu.Lock()
+	defer fields.mu.Unlock()
+	for _, attr := range attrs {
+		replaced := false
+		for i := range fields.attrs {
+			if fields.attrs[i].Key == attr.Key {
+				fields.attrs[i], replaced = attr, true
+				break
+			}
+		}
+		if !replaced {
+			fields.attrs = append(fields.attrs, attr)
+		}
+	}
+}
+
+// Field returns the value last added under key to the access log line of the
+// request carried by ctx
+func Field(ctx context.Context, key string) (slog.Value, bool) {
+	fields, ok := ctx.Value(accessFieldsKey{}).(*accessFields)
+	if !ok {
+		return slog.Value{}, false
+	}
+	fields.mu.Lock()
+	defer fields.mu.Unlock()
+	for _, attr := range fields.attrs {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return slog.Value{}, false
+}
+
+// WithFields returns a copy of ctx that collects the attributes passed to
+// AddFields, and a function returning them. AccessLog uses it for HTTP
+// requests; other servers, such as the gRPC server, use it to log the same fields.
+func WithFields(ctx context.Context) (context.Context, func() []slog.Attr) {
+	fields := &accessFields{}
+	return context.WithValue(ctx, accessFieldsKey{}, fields), func() []slog.Attr {
+		fields.mu.Lock()
+		defer fields.mu.Unlock()
+		return append([]slog.Attr(nil), fields.attrs...)
+	}
+}
+
+// AccessLog logs one "request" line per request with its method, route, status,
+// response size and latency, plus any fields added with AddFields such as the
+// model type and record counts. Server errors are logged at error level.
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		ctx, fields := WithFields(r.Context())
+		r = r.WithContext(ctx)
+
+		next.ServeHTTP(recorder, r)
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+		}
+		if r.Pattern != "" {
+			attrs = append(attrs, slog.String("route", r.Pattern))
+		}
+		attrs = append(attrs,
+			slog.Int("status", recorder.status),
+			slog.Int64("bytes", recorder.bytes),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+		)
+		attrs = append(attrs, fields()...)
+
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(r.Context(), level, "request", attrs...)
+	})
+}
+
+// responseRecorder remembers the status code and body size written by a handler
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if !rr.wroteHeader {
+		rr.status = status
+		rr.wroteHeader = true
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.wroteHeader = true
+	n, err := rr.ResponseWriter.Write(b)
+	rr.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
@@ -0,0 +1,80 @@
+// Package logging configures the service's structured logger and its per-request access log
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"goplayground-data-validator/tracing"
+)
+
+// Formats accepted in Config.Format
+const (
+	FormatJSON = "json" // One JSON object per line
+	FormatText = "text" // logfmt-style key=value pairs
+)
+
+// Config selects the minimum level and the output format of the logger
+type Config struct {
+	Level  string // debug, info, warn or error; empty means info
+	Format string // One of the Format constants; empty means json
+}
+
+// New builds a logger writing to w. Records logged with a request context carry
+// that request's request_id and trace_id.
+func New(w io.Writer, cfg Config) (*slog.Logger, error) {
+	var level slog.Level
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", cfg.Level)
+		}
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch cfg.Format {
+	case "", FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	case FormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want json or text)", cfg.Format)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+// Setup installs a logger writing to standard error as the slog and log default
+func Setup(cfg Config) error {
+	logger, err := New(os.Stderr, cfg)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+// contextHandler adds the request and trace IDs found in the record's context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := tracing.RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		record.AddAttrs(slog.String("trace_id", traceID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
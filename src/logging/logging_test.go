@@ -0,0 +1,179 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"goplayground-data-validator/tracing"
+)
+
+// useBuffer installs a JSON logger writing to a buffer as the default logger
+func useBuffer(t *testing.T, cfg Config) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	logger, err := New(&buf, cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+// decodeLines parses every JSON log line in buf
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid JSON log line %q: %v", line, err)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestNew(t *testing.T) {
+	t.Run("level filters records", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, Config{Level: "warn"})
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		logger.Info("hidden")
+		logger.Warn("shown")
+		if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+			t.Errorf("Expected only the warning, got %s", buf.String())
+		}
+	})
+
+	t.Run("text format", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, Config{Format: FormatText})
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		logger.Info("started", "port", "8080")
+		if !strings.Contains(buf.String(), "msg=started port=8080") {
+			t.Errorf("Expected key=value output, got %s", buf.String())
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		if _, err := New(&bytes.Buffer{}, Config{Level: "verbose"}); err == nil {
+			t.Error("Expected error for unknown level")
+		}
+		if _, err := New(&bytes.Buffer{}, Config{Format: "xml"}); err == nil {
+			t.Error("Expected error for unknown format")
+		}
+	})
+}
+
+func TestNew_AddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, Config{})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	logger.InfoContext(tracing.WithRequestID(context.Background(), "req-7"), "validated")
+	logger.Info("no request")
+
+	lines := decodeLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	if lines[0]["request_id"] != "req-7" {
+		t.Errorf("Expected request_id on the request line, got %v", lines[0])
+	}
+	if _, ok := lines[1]["request_id"]; ok {
+		t.Errorf("Expected no request_id without a request, got %v", lines[1])
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	buf := useBuffer(t, Config{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /validate/{model}", func(w http.ResponseWriter, r *http.Request) {
+		AddFields(r.Context(), slog.String("model", "draft"), slog.Int("records", 1))
+		AddFields(r.Context(), slog.String("model", "incident"), slog.Int("invalid_records", 2))
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"is_valid":false}`))
+	})
+
+	req := httptest.NewRequest("POST", "/validate/incident", nil)
+	req.Header.Set(tracing.RequestIDHeader, "req-9")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	tracing.Middleware(AccessLog(mux)).ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 access log line, got %d", len(lines))
+	}
+	entry := lines[0]
+	expected := map[string]interface{}{
+		"msg":             "request",
+		"level":           "INFO",
+		"method":          "POST",
+		"path":            "/validate/incident",
+		"route":           "POST /validate/{model}",
+		"status":          float64(http.StatusUnprocessableEntity),
+		"bytes":           float64(len(`{"is_valid":false}`)),
+		"model":           "incident",
+		"records":         float64(1),
+		"invalid_records": float64(2),
+		"request_id":      "req-9",
+		"trace_id":        "4bf92f3577b34da6a3ce929d0e0e4736",
+	}
+	for key, want := range expected {
+		if entry[key] != want {
+			t.Errorf("Expected %s=%v, got %v", key, want, entry[key])
+		}
+	}
+	if _, ok := entry["duration_ms"]; !ok {
+		t.Error("Expected duration_ms")
+	}
+}
+
+func TestAccessLog_ServerErrorLevel(t *testing.T) {
+	buf := useBuffer(t, Config{})
+
+	handler := AccessLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/models", nil))
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 1 || lines[0]["level"] != "ERROR" {
+		t.Errorf("Expected one error-level line, got %v", lines)
+	}
+}
+
+func TestAddFields_OutsideRequest(t *testing.T) {
+	// Must not panic when no access log is collecting fields
+	AddFields(context.Background(), slog.String("model", "incident"))
+}
+
+func TestField(t *testing.T) {
+	ctx, _ := WithFields(context.Background())
+	AddFields(ctx, slog.String("model", "incident"))
+	AddFields(ctx, slog.String("model", "api"))
+	if value, ok := Field(ctx, "model"); !ok || value.String() != "api" {
+		t.Errorf("Field(model) = %v, %v; want api", value, ok)
+	}
+	if _, ok := Field(context.Background(), "model"); ok {
+		t.Error("Field outside a request should report false")
+	}
+}
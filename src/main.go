@@ -3,110 +3,409 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	httpswagger "github.com/swaggo/http-swagger"
+	"goplayground-data-validator/admission"
+	"goplayground-data-validator/auth"
+	"goplayground-data-validator/certs"
+	"goplayground-data-validator/codec"
+	"goplayground-data-validator/config"
+	"goplayground-data-validator/grpcserver"
+	"goplayground-data-validator/jsonlimit"
+	"goplayground-data-validator/logging"
+	"goplayground-data-validator/metrics"
 	"goplayground-data-validator/models"
+	"goplayground-data-validator/problem"
 	"goplayground-data-validator/registry"
+	"goplayground-data-validator/tracing"
+	_ "goplayground-data-validator/validations" // Registers the built-in models
 )
 
 // Global server start time for uptime tracking
 var startTime = time.Now()
 
+// Build information, injected at link time:
+//
+//	go build -ldflags "-X main.version=1.4.0 -X main.commit=$(git rev-parse --short HEAD)"
+var (
+	version = "dev"
+	commit  = "unknown"
+)
+
+// apiVersion is the version of the HTTP API contract in the Swagger spec. Bump
+// it when endpoints, parameters or response bodies change; the build version
+// goes with it as x-build-version.
+const apiVersion = "3.0.0"
+
 // main starts the modular validation server with optimized performance
 func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+
+	// Every later log line, including those of the registry and batch manager, goes through slog
+	if err := logging.Setup(loggingConfig(cfg)); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid logging configuration: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Default to modular server - clean, simplified architecture
 	serverMode := os.Getenv("SERVER_MODE")
 
 	if serverMode == "legacy" {
-		log.Println("Starting Legacy Validation Server (deprecated)...")
-		log.Println("Legacy mode is no longer supported - using modular server")
+		slog.Warn("Legacy mode is no longer supported - using modular server")
 	}
 
 	// Use the modular validation server by default
-	log.Println("Starting Modular Validation Server...")
-	startModularServer()
+	slog.Info("Starting modular validation server", "version", version, "commit", commit)
+	slog.Info("Configuration loaded", "config", cfg)
+	startModularServer(cfg)
 }
 
-// startModularServer starts the optimized modular validation server with automatic endpoint registration
-func startModularServer() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080" // Default port
-	}
+// fatal logs msg at error level and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
 
+// startModularServer starts the optimized modular validation server with automatic endpoint registration
+func startModularServer(cfg *config.Config) {
 	// Create HTTP multiplexer with optimized routing
 	mux := http.NewServeMux()
 
 	// Register system endpoints
-	mux.HandleFunc("GET /health", handleHealth)               // Health check endpoint
-	mux.HandleFunc("POST /validate", handleGenericValidation) // Generic validation with model type
-	mux.HandleFunc("GET /models", handleListModels)           // List available models
+	mux.HandleFunc("GET /health", handleHealth)                 // Health check endpoint
+	mux.HandleFunc("GET /health/live", handleLiveness)          // Liveness probe
+	mux.HandleFunc("GET /health/ready", handleReadiness)        // Readiness probe
+	mux.HandleFunc("GET /health/details", handleHealthDetails)  // Subsystem and build details
+	mux.HandleFunc("POST /validate", handleGenericValidation)   // Generic validation with model type
+	mux.HandleFunc("GET /models", handleListModels)             // List available models
+	mux.HandleFunc("GET /metrics", metrics.Default().Handler()) // Prometheus metrics
 
 	// Register batch management endpoints (Phase 2)
 	mux.HandleFunc("POST /validate/batch/start", handleBatchStart)            // Start new batch session
 	mux.HandleFunc("GET /validate/batch/{id}", handleBatchStatus)             // Get batch status
 	mux.HandleFunc("POST /validate/batch/{id}/complete", handleBatchComplete) // Complete batch validation
+	mux.HandleFunc("GET /validate/batch/{id}/results", handleBatchResults)    // Page through failed rows
+	mux.HandleFunc("DELETE /validate/batch/{id}", handleBatchAbort)           // Abort batch session
 
 	// Register Swagger documentation endpoints
 	mux.Handle("/swagger/", httpswagger.WrapHandler)           // Swagger UI
 	mux.HandleFunc("GET /swagger/doc.json", handleSwaggerJSON) // Swagger JSON spec
 	mux.HandleFunc("GET /swagger/models", handleSwaggerModels) // Dynamic model schemas
 
-	// 🚀 UNIFIED AUTOMATIC REGISTRATION - Start the consolidated registration system
-	log.Println("🔄 Initializing unified automatic model registration...")
+	// SIGINT/SIGTERM cancel this context: startup stops early, a running server drains
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Start unified registration in a goroutine
-	ctx := context.Background()
-	go func() {
-		if err := registry.StartRegistration(ctx, mux); err != nil {
-			log.Printf("❌ Registration error: %v", err)
-		}
-	}()
+	// Tracing is set up before anything that creates spans
+	shutdownTracing, err := tracing.Setup(ctx, tracingConfig(cfg))
+	if err != nil {
+		fatal("Failed to set up tracing", "error", err)
+	}
+	if cfg.Tracing.Exporter != tracing.ExporterNone {
+		slog.Info("Exporting traces", "exporter", cfg.Tracing.Exporter)
+	}
 
-	// Allow some time for initial model scanning
-	time.Sleep(1 * time.Second)
+	// 🚀 UNIFIED AUTOMATIC REGISTRATION - every model is registered before the port opens
+	if err := registry.StartRegistration(ctx, mux); err != nil {
+		fatal("Registration failed", "error", err)
+	}
 
-	// Start batch session cleanup routine (Phase 2)
+	// Thresholds for arrays, streams and batch sessions that name none
+	globalRegistry := registry.GetGlobalRegistry()
+	globalRegistry.SetDefaultThreshold("", cfg.Validation.DefaultThreshold)
+	for model, modelCfg := range cfg.Models {
+		if !globalRegistry.IsRegistered(registry.ModelType(model)) {
+			slog.Warn("Configured model is not registered", "model", model)
+		}
+		globalRegistry.SetDefaultThreshold(registry.ModelType(model), modelCfg.DefaultThreshold)
+	}
+
+	// Select the batch session store: "memory" (default) or "file" for sessions
+	// that survive restarts and are shared by replicas mounting the same directory
+	batchStore, err := models.NewBatchStore(cfg.Batch.Store, cfg.Batch.StoreDir)
+	if err != nil {
+		fatal("Failed to open batch store", "error", err)
+	}
 	batchManager := models.GetBatchSessionManager()
-	batchManager.StartCleanupRoutine()
-	log.Println("🧹 Batch session cleanup routine started (30min expiration)")
+	if err := batchManager.SetStore(batchStore); err != nil {
+		fatal("Failed to switch batch store", "error", err)
+	}
+	if cfg.Batch.Store == models.BatchStoreFile {
+		slog.Info("Batch sessions stored on disk", "dir", cfg.Batch.StoreDir)
+	}
+
+	if err := batchManager.SetLimits(batchLimits(cfg)); err != nil {
+		fatal("Invalid batch session limits", "error", err)
+	}
+
+	registerBatchMetrics(metrics.Default(), batchManager)
+
+	// The auth config is read first: its public paths are exempt from rate limiting
+	var authConfig *auth.Config
+	if authConfigFile := cfg.Auth.ConfigFile; authConfigFile != "" {
+		loaded, err := auth.LoadConfig(authConfigFile)
+		if err != nil {
+			fatal("Failed to load auth config", "error", err)
+		}
+		authConfig = &loaded
+	}
+
+	admissionCfg, rateCfg := admissionConfig(cfg, authConfig)
+	admission.Default().Configure(admissionCfg)
+	rateLimiter := admission.NewRateLimiter(rateCfg)
+
+	if err := jsonlimit.Configure(jsonLimits(cfg)); err != nil {
+		fatal("Invalid JSON input limits", "error", err)
+	}
+
+	// Authentication wraps the rate limiter so clients are limited by identity;
+	// without an auth config every endpoint is open
+	var handler http.Handler = rateLimiter.Middleware(withProblemFallback(mux))
+	var authenticator *auth.Authenticator
+	if authConfig != nil {
+		authenticator, err = auth.New(*authConfig)
+		if err != nil {
+			fatal("Invalid auth config", "file", cfg.Auth.ConfigFile, "error", err)
+		}
+		handler = authenticator.Middleware(handler)
+		slog.Info("Authentication enabled", "file", cfg.Auth.ConfigFile)
+	}
+	registerLimitRoutes(mux, authenticator)
+	if authenticator == nil {
+		slog.Warn("Authentication disabled: set auth.config_file or AUTH_CONFIG to require credentials; admission limits are read-only")
+	}
 
 	// Create optimized HTTP server
 	server := &http.Server{
-		Addr:    ":" + port,
-		Handler: mux,
-		// Optimized timeouts for production use
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	}
-
-	log.Printf("🚀 Modular server starting on port %s", port)
-	log.Printf("📋 Available endpoints:")
-	log.Printf("  📊 GET  /health                - Server health check")
-	log.Printf("  🔄 POST /validate              - Generic validation with model type")
-	log.Printf("  📝 GET  /models               - List available models")
-	log.Printf("  📚 GET  /swagger/             - Swagger UI documentation")
-	log.Printf("  🔍 GET  /swagger/doc.json     - Swagger JSON specification")
-	log.Printf("  📄 GET  /swagger/models       - Dynamic model schemas")
-	log.Printf("")
-	log.Printf("🎯 Platform-specific validation endpoints (AUTO-GENERATED):")
-
-	// Dynamically list all registered model endpoints (after models are loaded)
-	log.Printf("  📂 Dynamic endpoints will be listed after model discovery completes...")
-
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Modular server failed to start: %v", err)
+		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:      tracing.Middleware(logging.AccessLog(handler)),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	// Serve HTTPS when a certificate is configured; the files are polled so a
+	// rotated certificate or client CA bundle takes effect without a restart
+	if cfg.TLS.CertFile != "" {
+		reloader, err := certs.New(certsConfig(cfg))
+		if err != nil {
+			fatal("Failed to load TLS certificate", "error", err)
+		}
+		server.TLSConfig = reloader.TLSConfig()
+		go reloader.Watch(ctx, cfg.TLS.ReloadInterval)
+		clientAuth := "none"
+		if cfg.TLS.ClientCAFile != "" {
+			clientAuth = cfg.TLS.ClientAuth
+		}
+		slog.Info("TLS enabled", "cert_file", cfg.TLS.CertFile, "expires", reloader.NotAfter(), "client_auth", clientAuth)
+	}
+
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		fatal("Modular server failed to start", "error", err)
+	}
+
+	// The gRPC service has its own port but the same certificate, credentials
+	// and input limits; it drains alongside the HTTP server on shutdown
+	grpcStopped := make(chan struct{})
+	if cfg.GRPC.Port != 0 {
+		grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+		if err != nil {
+			fatal("gRPC server failed to start", "error", err)
+		}
+		grpcServer := grpcserver.NewServer(grpcserver.NewService(globalRegistry, batchManager), grpcserver.Options{
+			TLSConfig:      server.TLSConfig,
+			Authenticator:  authenticator,
+			MaxRecvMsgSize: int(cfg.Limits.MaxBodyBytes),
+		})
+		go func() {
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				slog.Error("gRPC server stopped with error", "error", err)
+				stop()
+			}
+		}()
+		go func() {
+			<-ctx.Done()
+			grpcserver.Shutdown(grpcServer, cfg.Server.ShutdownTimeout)
+			close(grpcStopped)
+		}()
+		slog.Info("gRPC service starting", "port", cfg.GRPC.Port)
+	} else {
+		close(grpcStopped)
+	}
+
+	// Start batch session cleanup routine (Phase 2)
+	batchManager.StartCleanupRoutine()
+	slog.Info("Batch session cleanup routine started",
+		"ttl", cfg.Batch.TTL, "max_ttl", cfg.Batch.MaxTTL, "sweep_interval", cfg.Batch.SweepInterval)
+
+	modelTypes := globalRegistry.ListModels()
+	sort.Slice(modelTypes, func(i, j int) bool { return modelTypes[i] < modelTypes[j] })
+	slog.Info("Modular server starting", "port", cfg.Server.Port, "models", modelTypes)
+	for _, endpoint := range systemEndpoints {
+		slog.Debug("Endpoint available", "route", endpoint[0], "description", endpoint[1])
+	}
+
+	serveErr := serveUntilDone(ctx, server, listener, cfg.Server.ShutdownTimeout)
+	stop() // Also stops the gRPC server when the HTTP server failed
+	<-grpcStopped
+
+	// Stop background work only after the last request has finished with it
+	batchManager.StopCleanupRoutine()
+	if err := batchManager.Store().Close(); err != nil {
+		slog.Warn("Failed to close batch store", "error", err)
+	}
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Warn("Failed to flush traces", "error", err)
+	}
+	cancelFlush()
+
+	if serveErr != nil {
+		fatal("Server stopped with error", "error", serveErr)
+	}
+	slog.Info("Server stopped")
+}
+
+// systemEndpoints lists the fixed routes with a description, logged at debug level on startup
+var systemEndpoints = [][2]string{
+	{"GET /health", "Server health check"},
+	{"GET /health/live", "Liveness probe"},
+	{"GET /health/ready", "Readiness probe"},
+	{"GET /health/details", "Subsystem and build details"},
+	{"POST /validate", "Generic validation with model type"},
+	{"GET /models", "List available models"},
+	{"GET /metrics", "Prometheus metrics"},
+	{"GET /limits", "Admission limits and usage"},
+	{"PUT /limits/models/{model}", "Change a model's concurrent record cap (with authentication only)"},
+	{"GET /swagger/", "Swagger UI documentation"},
+	{"GET /swagger/doc.json", "Swagger JSON specification"},
+	{"GET /swagger/models", "Dynamic model schemas"},
+}
+
+// withProblemFallback serves mux, answering the requests it has no route for
+// with problem details rather than its plain text replies: 404 for an unknown
+// path or model, and 405 with the Allow header for a method a path does not take
+func withProblemFallback(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallback, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		// Run the mux's own reply on a discarding writer to learn which it is
+		reply := &discardWriter{header: http.Header{}}
+		fallback.ServeHTTP(reply, r)
+		switch reply.status {
+		case http.StatusMethodNotAllowed:
+			w.Header().Set("Allow", reply.header.Get("Allow"))
+			problem.Write(w, r, http.StatusMethodNotAllowed, "", fmt.Sprintf("%s is not allowed on %s", r.Method, r.URL.Path))
+		case http.StatusNotFound:
+			if model, ok := unknownModelPath(r); ok {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeModelNotRegistered, fmt.Sprintf("Model type '%s' is not registered", model))
+				return
+			}
+			problem.Write(w, r, http.StatusNotFound, "", fmt.Sprintf("No endpoint at %s", r.URL.Path))
+		default:
+			mux.ServeHTTP(w, r)
+		}
+	})
+}
+
+// unknownModelPath returns the model of a POST to /validate/{model} or
+// /validate/{model}/stream, the paths the registry adds for every model
+func unknownModelPath(r *http.Request) (string, bool) {
+	rest, ok := strings.CutPrefix(r.URL.Path, "/validate/")
+	if !ok || r.Method != http.MethodPost {
+		return "", false
+	}
+	model, suffix, _ := strings.Cut(rest, "/")
+	if model == "" || model == "batch" || (suffix != "" && suffix != "stream") {
+		return "", false
+	}
+	return model, true
+}
+
+// discardWriter records the status and headers of a response and drops its body
+type discardWriter struct {
+	header http.Header
+	status int
+}
+
+func (d *discardWriter) Header() http.Header { return d.header }
+
+func (d *discardWriter) Write(data []byte) (int, error) {
+	if d.status == 0 {
+		d.status = http.StatusOK
+	}
+	return len(data), nil
+}
+
+func (d *discardWriter) WriteHeader(status int) {
+	if d.status == 0 {
+		d.status = status
+	}
+}
+
+// serveUntilDone serves on listener, over TLS when server.TLSConfig is set,
+// until ctx is cancelled, then stops accepting connections and waits up to
+// drainTimeout for in-flight requests, including batch chunk submissions and
+// NDJSON streams, to finish. Connections still
+// active after that are closed and an error is returned.
+func serveUntilDone(ctx context.Context, server *http.Server, listener net.Listener, drainTimeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		if server.TLSConfig != nil {
+			serveErr <- server.ServeTLS(listener, "", "")
+			return
+		}
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
 	}
+
+	slog.Info("Shutdown requested, draining in-flight requests", "timeout", drainTimeout)
+	drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(drainCtx); err != nil {
+		server.Close()
+		return fmt.Errorf("requests still running after %s: %w", drainTimeout, err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
 // handleHealth provides optimized health check with minimal overhead
@@ -116,7 +415,7 @@ func handleHealth(w http.ResponseWriter, r *http.Request) {
 	// Simple health response with uptime
 	health := map[string]interface{}{
 		"status":  "healthy",
-		"version": "2.0.0-modular",
+		"version": version,
 		"uptime":  time.Since(startTime).String(),
 		"server":  "modular-validation-server",
 	}
@@ -124,6 +423,98 @@ func handleHealth(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(health)
 }
 
+// handleLiveness reports that the process is running and serving requests
+func handleLiveness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "alive",
+		"uptime": time.Since(startTime).String(),
+	})
+}
+
+// readinessChecks checks model registration and batch storage. It returns the
+// result of each check and whether all of them passed.
+func readinessChecks(reg *registry.UnifiedRegistry, batchManager *models.BatchSessionManager) (map[string]interface{}, bool) {
+	ready := true
+	check := func(err error) map[string]interface{} {
+		if err != nil {
+			ready = false
+			return map[string]interface{}{"status": "fail", "error": err.Error()}
+		}
+		return map[string]interface{}{"status": "ok"}
+	}
+
+	var registrationErr error
+	status := reg.RegistrationStatus()
+	switch {
+	case !status.Complete:
+		registrationErr = errors.New("model registration has not finished")
+	case status.Failed > 0:
+		registrationErr = fmt.Errorf("%d of %d models failed to register", status.Failed, status.Expected)
+	case !status.Ready():
+		registrationErr = fmt.Errorf("%d of %d models registered", status.Registered, status.Expected)
+	}
+
+	checks := map[string]interface{}{
+		"registry":    check(registrationErr),
+		"batch_store": check(batchManager.Store().Ping()),
+	}
+	return checks, ready
+}
+
+// handleReadiness returns 200 once the server can validate requests and 503 otherwise
+func handleReadiness(w http.ResponseWriter, r *http.Request) {
+	checks, ready := readinessChecks(registry.GetGlobalRegistry(), models.GetBatchSessionManager())
+
+	w.Header().Set("Content-Type", "application/json")
+	status := "ready"
+	if !ready {
+		status = "not_ready"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": status,
+		"checks": checks,
+	})
+}
+
+// handleHealthDetails reports readiness together with per-model registration
+// status, build information and batch session usage
+func handleHealthDetails(w http.ResponseWriter, r *http.Request) {
+	globalRegistry := registry.GetGlobalRegistry()
+	batchManager := models.GetBatchSessionManager()
+	checks, ready := readinessChecks(globalRegistry, batchManager)
+
+	status := "ready"
+	if !ready {
+		status = "not_ready"
+	}
+
+	batchSessions := map[string]interface{}{}
+	if open, err := batchManager.OpenSessionCount(); err != nil {
+		batchSessions["error"] = err.Error()
+	} else {
+		batchSessions["open"] = open
+	}
+	limits := batchManager.Limits()
+	batchSessions["max_open"] = limits.MaxOpenSessions
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":         status,
+		"checks":         checks,
+		"registration":   globalRegistry.RegistrationStatus(),
+		"batch_sessions": batchSessions,
+		"build": map[string]interface{}{
+			"version":    version,
+			"commit":     commit,
+			"go_version": runtime.Version(),
+		},
+		"started_at": startTime,
+		"uptime":     time.Since(startTime).String(),
+	})
+}
+
 // NOTE: Individual platform handlers (handleGitHubValidation, etc.)
 // are no longer needed as they are automatically generated by the registry system.
 // All platform-specific validation is now handled dynamically via registry.RegisterHTTPEndpoints()
@@ -135,14 +526,70 @@ func handleGenericValidation(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	var request struct {
-		ModelType string                   `json:"model_type"`
-		Payload   map[string]interface{}   `json:"payload"`             // Single object validation
-		Data      []map[string]interface{} `json:"data,omitempty"`      // Array validation
-		Threshold *float64                 `json:"threshold,omitempty"` // Optional threshold percentage for batch validation
+		ModelType string                 `json:"model_type"`
+		Payload   map[string]interface{} `json:"payload"`             // Single object validation
+		Data      []json.RawMessage      `json:"data,omitempty"`      // Array validation, decoded per record by the registry
+		Threshold *float64               `json:"threshold,omitempty"` // Optional threshold percentage for batch validation
+		Options   models.BatchOptions    `json:"options"`             // Optional worker pool / fail-fast / timeout settings
+
+		SchemaVersion string `json:"schema_version,omitempty"` // Checked against the batch session's schema version
+	}
+
+	// Responses use the format Accept prefers, whatever the request was sent in,
+	// and array results can be a JUnit or SARIF report instead
+	report, _ := codec.ReportForAccept(r.Header.Get("Accept"))
+	respond, ok := codec.ForAccept(r.Header.Get("Accept"))
+	if !ok && report == nil {
+		problem.Write(w, r, http.StatusNotAcceptable, problem.CodeNotAcceptable, codec.NotAcceptableMessage)
+		return
+	}
+
+	if codec.IsCSV(r.Header.Get("Content-Type")) {
+		handleCSVValidation(w, r, respond, report)
+		return
+	}
+
+	_, decodeSpan := tracing.Start(r.Context(), "decode")
+	format, err := codec.DecodeRequest(w, r, &request)
+	if err != nil {
+		tracing.Fail(decodeSpan, err)
+		decodeSpan.End()
+		var limitErr *jsonlimit.LimitError
+		if errors.As(err, &limitErr) {
+			problem.Write(w, r, limitErr.Status(), limitErr.Limit, limitErr.Error())
+			return
+		}
+		var formatErr *codec.Error
+		if errors.As(err, &formatErr) {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, formatErr.Error())
+			return
+		}
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, fmt.Sprintf("Invalid %s payload", format.Name))
+		return
+	}
+	decodeSpan.End()
+
+	// Records are decoded by the registry, so their shape is checked here
+	for i, record := range request.Data {
+		if !isJSONObject(record) {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, fmt.Sprintf("data[%d] must be an object", i))
+			return
+		}
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		sendJSONError(w, "Invalid JSON payload", http.StatusBadRequest)
+	// Only a plain array validation has rows to report
+	if respond == nil && (len(request.Data) == 0 || r.Header.Get("X-Batch-ID") != "" || r.Header.Get("X-Batch-Complete") != "") {
+		problem.Write(w, r, http.StatusNotAcceptable, problem.CodeNotAcceptable, codec.ReportsOnlyMessage)
+		return
+	}
+
+	logging.AddFields(r.Context(), slog.String("model", request.ModelType))
+
+	operation := auth.OpValidate
+	if r.Header.Get("X-Batch-ID") != "" || r.Header.Get("X-Batch-Complete") != "" {
+		operation = auth.OpBatch
+	}
+	if !authorizeModel(w, r, operation, request.ModelType) {
 		return
 	}
 
@@ -151,7 +598,26 @@ func handleGenericValidation(w http.ResponseWriter, r *http.Request) {
 	modelType := registry.ModelType(request.ModelType)
 
 	if !globalRegistry.IsRegistered(modelType) {
-		sendJSONError(w, fmt.Sprintf("Model type '%s' is not registered", request.ModelType), http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeModelNotRegistered, fmt.Sprintf("Model type '%s' is not registered", request.ModelType))
+		return
+	}
+
+	// The envelope decoded without knowing the model; XML text still needs
+	// converting to the types of the model's fields
+	if format.Untyped {
+		if info, err := globalRegistry.GetModel(modelType); err == nil {
+			request.Payload = format.Record(request.Payload, info.ModelStruct)
+			for i, raw := range request.Data {
+				var record map[string]interface{}
+				if json.Unmarshal(raw, &record) == nil && record != nil {
+					request.Data[i], _ = json.Marshal(format.Record(record, info.ModelStruct))
+				}
+			}
+		}
+	}
+
+	if !models.ValidMaxConcurrency(request.Options.MaxConcurrency) {
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "options."+models.MaxConcurrencyMessage)
 		return
 	}
 
@@ -166,41 +632,64 @@ func handleGenericValidation(w http.ResponseWriter, r *http.Request) {
 		// Check if batch session exists
 		session, exists := batchManager.GetBatchSession(batchComplete)
 		if !exists {
-			sendJSONError(w, fmt.Sprintf("Batch session '%s' not found", batchComplete), http.StatusNotFound)
+			problem.Write(w, r, http.StatusNotFound, problem.CodeBatchNotFound, fmt.Sprintf("Batch session '%s' not found", batchComplete))
+			return
+		}
+		if err := session.CheckModel(request.ModelType, request.SchemaVersion); err != nil {
+			sendBatchStoreError(w, r, batchComplete, err)
 			return
 		}
 
 		status, err := batchManager.FinalizeBatchSession(batchComplete)
 		if err != nil {
-			sendJSONError(w, err.Error(), http.StatusNotFound)
+			sendBatchStoreError(w, r, batchComplete, err)
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
+		// Re-read the session: stores other than memory return snapshots
+		session, exists = batchManager.GetBatchSession(batchComplete)
+		if !exists {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeBatchNotFound, fmt.Sprintf("Batch session '%s' not found", batchComplete))
+			return
+		}
 
+		state := session.State()
+		response := map[string]interface{}{
+			"batch_id":        state.BatchID,
+			"status":          status,
+			"total_records":   state.TotalRecords,
+			"valid_records":   state.ValidRecords,
+			"invalid_records": state.InvalidRecords,
+			"warning_records": state.WarningRecords,
+			"threshold":       state.Threshold,
+			"started_at":      state.StartedAt,
+			"completed_at":    state.LastUpdated,
+		}
+		if report := session.ChunkReport(); report != nil {
+			response["chunks"] = report
+		}
+		httpStatus := http.StatusOK
 		if status == "failed" {
-			w.WriteHeader(http.StatusUnprocessableEntity)
+			httpStatus = http.StatusUnprocessableEntity
 		}
+		respond.Write(w, httpStatus, response)
 
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"batch_id":        session.BatchID,
-			"status":          status,
-			"total_records":   session.TotalRecords,
-			"valid_records":   session.ValidRecords,
-			"invalid_records": session.InvalidRecords,
-			"warning_records": session.WarningRecords,
-			"threshold":       session.Threshold,
-			"started_at":      session.StartedAt,
-			"completed_at":    session.LastUpdated,
-		})
+		// The session is kept so its failed rows stay available from
+		// GET /validate/batch/{id}/results until the cleanup routine expires it
+		return
+	}
 
-		// Clean up after returning response
-		go func() {
-			time.Sleep(1 * time.Second)
-			batchManager.DeleteBatchSession(batchComplete)
-		}()
+	// Hold capacity for every record until the response is written
+	records := 1
+	if len(request.Data) > 0 {
+		records = len(request.Data)
+	}
+	release, err := admission.Default().Acquire(r.Context(), request.ModelType, records)
+	if err != nil {
+		admission.WriteError(w, r, err)
 		return
 	}
+	defer release()
 
 	// NEW: Detect array vs single object validation
 	if len(request.Data) > 0 {
@@ -208,52 +697,81 @@ func handleGenericValidation(w http.ResponseWriter, r *http.Request) {
 		if batchID != "" {
 			// Batch accumulation: validate and update batch session
 			batchManager := models.GetBatchSessionManager()
-			_, exists := batchManager.GetBatchSession(batchID)
+			session, exists := batchManager.GetBatchSession(batchID)
 			if !exists {
-				sendJSONError(w, fmt.Sprintf("Batch session '%s' not found", batchID), http.StatusNotFound)
+				problem.Write(w, r, http.StatusNotFound, problem.CodeBatchNotFound, fmt.Sprintf("Batch session '%s' not found", batchID))
+				return
+			}
+
+			// Records for another model would make the batch totals meaningless
+			if err := session.CheckModel(request.ModelType, request.SchemaVersion); err != nil {
+				sendBatchStoreError(w, r, batchID, err)
+				return
+			}
+
+			// A retried chunk is answered from the session without validating it again
+			chunkID := r.Header.Get("X-Batch-Chunk-ID")
+			if len(chunkID) > maxBatchChunkIDLength {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, fmt.Sprintf("X-Batch-Chunk-ID must be at most %d characters", maxBatchChunkIDLength))
+				return
+			}
+			if err := models.CheckChunkID(chunkID); err != nil {
+				sendBatchStoreError(w, r, batchID, err)
+				return
+			}
+			if chunkID != "" {
+				if chunk, recorded := session.FindChunk(chunkID); recorded {
+					sendBatchChunkResponse(w, respond, batchID, chunk, true)
+					return
+				}
+			}
+
+			// Refuse chunks for expired or full sessions before validating them
+			if err := batchManager.AdmitChunk(session, len(request.Data)); err != nil {
+				sendBatchStoreError(w, r, batchID, err)
 				return
 			}
 
 			// Validate the array
-			result, err := globalRegistry.ValidateArray(modelType, request.Data, request.Threshold)
+			result, err := globalRegistry.ValidateRawArrayWithOptions(r.Context(), modelType, request.Data, request.Threshold, request.Options)
 			if err != nil {
-				sendJSONError(w, "Array validation failed: "+err.Error(), http.StatusInternalServerError)
+				sendArrayValidationError(w, r, err)
 				return
 			}
 
-			// Update batch session with validation counts
-			batchManager.UpdateBatchSession(batchID, result.ValidRecords, result.InvalidRecords, result.WarningRecords)
-
-			// Get updated session
-			updatedSession, _ := batchManager.GetBatchSession(batchID)
+			// Update batch session with validation counts and retain the failed rows
+			chunk, replayed, err := batchManager.RecordBatchChunk(batchID, chunkID, result)
+			if err != nil {
+				sendBatchStoreError(w, r, batchID, err)
+				return
+			}
 
-			// Return accumulation status
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"batch_id":      batchID,
-				"status":        "accumulating",
-				"records_count": updatedSession.TotalRecords,
-				"message":       fmt.Sprintf("Added %d records to batch. Use X-Batch-Complete header to finalize.", result.TotalRecords),
-			})
+			sendBatchChunkResponse(w, respond, batchID, chunk, replayed)
 			return
 		}
 
 		// Normal array validation path (no batch)
-		result, err := globalRegistry.ValidateArray(modelType, request.Data, request.Threshold)
+		if request.Threshold == nil {
+			request.Threshold = globalRegistry.DefaultThreshold(modelType)
+		}
+		result, err := globalRegistry.ValidateRawArrayWithOptions(r.Context(), modelType, request.Data, request.Threshold, request.Options)
 		if err != nil {
-			sendJSONError(w, "Array validation failed: "+err.Error(), http.StatusInternalServerError)
+			sendArrayValidationError(w, r, err)
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-
 		// Set appropriate status code based on validation status
 		// "success" = all validation passed threshold, "failed" = below threshold
+		status := http.StatusOK
 		if result.Status == "failed" {
-			w.WriteHeader(http.StatusUnprocessableEntity)
+			status = http.StatusUnprocessableEntity
 		}
 
-		json.NewEncoder(w).Encode(result)
+		if report != nil {
+			report.Write(w, status, codec.ArrayRun(request.ModelType, result))
+			return
+		}
+		respond.Write(w, status, result)
 		return
 	}
 
@@ -261,38 +779,187 @@ func handleGenericValidation(w http.ResponseWriter, r *http.Request) {
 	// Create an instance of the model struct
 	modelInstance, err := globalRegistry.CreateModelInstance(modelType)
 	if err != nil {
-		sendJSONError(w, "Failed to create model instance: "+err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, "", "Failed to create model instance: "+err.Error())
 		return
 	}
 
 	// Convert map to struct using optimized direct conversion
+	_, convertSpan := tracing.Start(r.Context(), "convert")
 	if err := convertMapToStruct(request.Payload, modelInstance); err != nil {
-		sendJSONError(w, "Failed to parse payload into model struct: "+err.Error(), http.StatusBadRequest)
+		tracing.Fail(convertSpan, err)
+		convertSpan.End()
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, "Failed to parse payload into model struct: "+err.Error())
 		return
 	}
+	convertSpan.End()
 
 	// Dereference the pointer to get the actual struct value
 	modelValue := reflect.ValueOf(modelInstance).Elem().Interface()
 
 	// Validate using the registry
-	result, err := globalRegistry.ValidatePayload(modelType, modelValue)
+	result, err := globalRegistry.ValidatePayloadContext(r.Context(), modelType, modelValue)
 	if err != nil {
-		sendJSONError(w, "Validation failed: "+err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, problem.CodeValidationFailed, "Validation failed: "+err.Error())
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-
 	// Check if result indicates invalid payload
-	if resultMap, ok := result.(map[string]interface{}); ok {
-		if isValid, exists := resultMap["is_valid"]; exists {
-			if valid, ok := isValid.(bool); ok && !valid {
-				w.WriteHeader(http.StatusUnprocessableEntity)
-			}
+	status := http.StatusOK
+	if !result.IsValid {
+		status = http.StatusUnprocessableEntity
+	}
+
+	respond.Write(w, status, result)
+}
+
+// handleCSVValidation validates the records of a CSV body as an array. CSV has
+// no envelope, so the model type, threshold and options come from the query
+// string, and rows are reported by their line in the file.
+func handleCSVValidation(w http.ResponseWriter, r *http.Request, respond *codec.Format, report *codec.Report) {
+	if r.Header.Get("X-Batch-ID") != "" || r.Header.Get("X-Batch-Complete") != "" {
+		problem.Write(w, r, http.StatusBadRequest, "", "CSV bodies cannot be added to batch sessions; send each chunk as a JSON data array")
+		return
+	}
+
+	query := r.URL.Query()
+	modelName := query.Get("model_type")
+	logging.AddFields(r.Context(), slog.String("model", modelName))
+	if !authorizeModel(w, r, auth.OpValidate, modelName) {
+		return
+	}
+
+	globalRegistry := registry.GetGlobalRegistry()
+	modelType := registry.ModelType(modelName)
+	modelInfo, err := globalRegistry.GetModel(modelType)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeModelNotRegistered, fmt.Sprintf("Model type '%s' is not registered", modelName))
+		return
+	}
+
+	threshold := globalRegistry.DefaultThreshold(modelType)
+	if raw := query.Get("threshold"); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 || value > 100 {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "threshold must be a number between 0 and 100")
+			return
+		}
+		threshold = &value
+	}
+	var opts models.BatchOptions
+	if raw := query.Get("fail_fast"); raw != "" {
+		if opts.FailFast, err = strconv.ParseBool(raw); err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "fail_fast must be true or false")
+			return
+		}
+	}
+	if raw := query.Get("max_concurrency"); raw != "" {
+		if opts.MaxConcurrency, err = strconv.Atoi(raw); err != nil || !models.ValidMaxConcurrency(opts.MaxConcurrency) {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, models.MaxConcurrencyMessage)
+			return
 		}
 	}
 
-	json.NewEncoder(w).Encode(result)
+	_, decodeSpan := tracing.Start(r.Context(), "decode")
+	rows, err := codec.ReadCSVRequest(w, r, modelInfo.ModelStruct)
+	if err != nil {
+		tracing.Fail(decodeSpan, err)
+		decodeSpan.End()
+		var limitErr *jsonlimit.LimitError
+		if errors.As(err, &limitErr) {
+			problem.Write(w, r, limitErr.Status(), limitErr.Limit, limitErr.Error())
+			return
+		}
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, err.Error())
+		return
+	}
+	decodeSpan.End()
+
+	release, err := admission.Default().Acquire(r.Context(), modelName, len(rows.Records))
+	if err != nil {
+		admission.WriteError(w, r, err)
+		return
+	}
+	defer release()
+
+	result, err := globalRegistry.ValidateArrayWithOptions(r.Context(), modelType, rows.Records, threshold, opts)
+	if err != nil {
+		sendArrayValidationError(w, r, err)
+		return
+	}
+	rows.Renumber(result)
+
+	status := http.StatusOK
+	if result.Status == "failed" {
+		status = http.StatusUnprocessableEntity
+	}
+	if report != nil {
+		run := codec.ArrayRun(modelName, result)
+		run.File = rows.File
+		report.Write(w, status, run)
+		return
+	}
+	respond.Write(w, status, result)
+}
+
+// handleGetLimits reports the admission limits and the records currently in flight
+func handleGetLimits(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(admission.Default().Status())
+}
+
+// registerLimitRoutes adds the admission limit routes. Changing a limit takes the
+// admin operation, so without an authenticator the limits are read-only.
+func registerLimitRoutes(mux *http.ServeMux, authenticator *auth.Authenticator) {
+	mux.HandleFunc("GET /limits", handleGetLimits) // Admission limits and usage
+	if authenticator != nil {
+		mux.HandleFunc("PUT /limits/models/{model}", handleSetModelLimit) // Change a model's record cap
+	}
+}
+
+// handleSetModelLimit changes a model's concurrent record cap while the server runs
+func handleSetModelLimit(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	model := r.PathValue("model")
+	if !registry.GetGlobalRegistry().IsRegistered(registry.ModelType(model)) {
+		problem.Write(w, r, http.StatusNotFound, problem.CodeModelNotRegistered, fmt.Sprintf("Model type '%s' is not registered", model))
+		return
+	}
+
+	var request struct {
+		MaxConcurrentRecords *int64 `json:"max_concurrent_records"` // 0 removes the model's cap
+	}
+	const invalidLimit = "max_concurrent_records must be a non-negative integer"
+	if !decodeJSONBody(w, r, &request, problem.CodeInvalidParameter, invalidLimit) {
+		return
+	}
+	if request.MaxConcurrentRecords == nil || *request.MaxConcurrentRecords < 0 {
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, invalidLimit)
+		return
+	}
+
+	admission.Default().SetModelLimit(model, *request.MaxConcurrentRecords)
+	slog.InfoContext(r.Context(), "Model record limit changed", "model", model, "max_concurrent_records", *request.MaxConcurrentRecords)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(admission.Default().Status())
+}
+
+// decodeJSONBody decodes the JSON body of r into v under the input limits. A
+// body over a limit is answered with that limit's problem, and any other
+// decode error with a 400 of code and detail; either way it returns false.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, v interface{}, code, detail string) bool {
+	err := jsonlimit.DecodeRequest(w, r, v)
+	if err == nil {
+		return true
+	}
+	var limitErr *jsonlimit.LimitError
+	if errors.As(err, &limitErr) {
+		problem.Write(w, r, limitErr.Status(), limitErr.Limit, limitErr.Error())
+		return false
+	}
+	problem.Write(w, r, http.StatusBadRequest, code, detail)
+	return false
 }
 
 // handleListModels returns available model types dynamically
@@ -303,14 +970,113 @@ func handleListModels(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(modelsWithDetails)
 }
 
-// sendJSONError sends a standardized JSON error response
-func sendJSONError(w http.ResponseWriter, message string, status int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"error":  message,
-		"status": status,
-	})
+// batchLimits converts the batch settings for the session manager
+func batchLimits(cfg *config.Config) models.BatchLimits {
+	return models.BatchLimits{
+		DefaultTTL:           cfg.Batch.TTL,
+		MaxTTL:               cfg.Batch.MaxTTL,
+		MaxOpenSessions:      cfg.Batch.MaxOpenSessions,
+		MaxRecordsPerSession: cfg.Batch.MaxRecords,
+		ExpiredRetention:     cfg.Batch.ExpiredRetention,
+		SweepInterval:        cfg.Batch.SweepInterval,
+	}
+}
+
+// jsonLimits converts the request body limits for jsonlimit
+func jsonLimits(cfg *config.Config) jsonlimit.Limits {
+	return jsonlimit.Limits{
+		MaxBodyBytes:    cfg.Limits.MaxBodyBytes,
+		MaxDepth:        cfg.Limits.MaxJSONDepth,
+		MaxArrayLength:  cfg.Limits.MaxArrayLength,
+		MaxStringLength: cfg.Limits.MaxStringLength,
+		MaxObjectKeys:   cfg.Limits.MaxObjectKeys,
+	}
+}
+
+// admissionConfig converts the load limits: the record caps, overall and per
+// model, and the per-client rate limit
+func admissionConfig(cfg *config.Config, authConfig *auth.Config) (admission.Config, admission.RateConfig) {
+	admissionCfg := admission.Config{
+		MaxConcurrentRecords: cfg.Limits.MaxConcurrentRecords,
+		QueueTimeout:         cfg.Limits.QueueTimeout,
+		MaxQueued:            cfg.Limits.MaxQueued,
+		ModelLimits:          make(map[string]int64),
+	}
+	for model, modelCfg := range cfg.Models {
+		if modelCfg.MaxConcurrentRecords > 0 {
+			admissionCfg.ModelLimits[model] = modelCfg.MaxConcurrentRecords
+		}
+	}
+	rate := admission.RateConfig{
+		RequestsPerSecond: cfg.Limits.RateLimitRPS,
+		Burst:             cfg.Limits.RateLimitBurst,
+	}
+	if authConfig != nil {
+		rate.ExemptPaths = authConfig.PublicPaths
+	}
+	return admissionCfg, rate
+}
+
+// certsConfig returns the certificate files to serve and trust
+func certsConfig(cfg *config.Config) certs.Config {
+	return certs.Config{
+		CertFile:     cfg.TLS.CertFile,
+		KeyFile:      cfg.TLS.KeyFile,
+		ClientCAFile: cfg.TLS.ClientCAFile,
+		ClientAuth:   cfg.TLS.ClientAuth,
+	}
+}
+
+// loggingConfig converts the logging settings
+func loggingConfig(cfg *config.Config) logging.Config {
+	return logging.Config{
+		Level:  cfg.Logging.Level,
+		Format: cfg.Logging.Format,
+	}
+}
+
+// tracingConfig converts the tracing settings; the file exporter writes to
+// traces.jsonl unless a file is set
+func tracingConfig(cfg *config.Config) tracing.Config {
+	return tracing.Config{
+		Exporter:       cfg.Tracing.Exporter,
+		File:           cfg.Tracing.File,
+		ServiceName:    "goplayground-data-validator",
+		ServiceVersion: version,
+		OTLPEndpoint:   cfg.Tracing.OTLPEndpoint,
+		OTLPHeaders:    cfg.Tracing.OTLPHeaders,
+	}
+}
+
+// registerBatchMetrics exposes the batch session gauges, read from the manager on each scrape
+func registerBatchMetrics(reg *metrics.Registry, batchManager *models.BatchSessionManager) {
+	reg.NewGaugeFunc("validator_batch_sessions_open",
+		"Batch sessions that are neither completed nor expired.",
+		func() (float64, error) {
+			open, err := batchManager.OpenSessionCount()
+			return float64(open), err
+		})
+	reg.NewGaugeFunc("validator_batch_sessions_max_open",
+		"Configured limit on open batch sessions; 0 means unlimited.",
+		func() (float64, error) {
+			return float64(batchManager.Limits().MaxOpenSessions), nil
+		})
+}
+
+// isJSONObject reports whether a data record is an object or null, the values
+// a record decoded into a map could have
+func isJSONObject(record json.RawMessage) bool {
+	record = bytes.TrimSpace(record)
+	return len(record) > 0 && (record[0] == '{' || string(record) == "null")
+}
+
+// sendArrayValidationError maps array validation failures to a status code
+func sendArrayValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		problem.Write(w, r, http.StatusGatewayTimeout, problem.CodeValidationTimeout, "Array validation timed out: "+err.Error())
+		return
+	}
+	problem.Write(w, r, http.StatusInternalServerError, problem.CodeValidationFailed, "Array validation failed: "+err.Error())
 }
 
 // convertMapToStruct efficiently converts a map to a struct using reflection
@@ -569,6 +1335,40 @@ func handleSwaggerModels(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(modelsWithDetails)
 }
 
+// withFormats adds the other formats /validate reads and writes to an OpenAPI
+// content map, with the schema of its application/json entry
+func withFormats(content map[string]interface{}) map[string]interface{} {
+	schema := content[codec.JSON.ContentType].(map[string]interface{})["schema"]
+	for _, format := range []*codec.Format{codec.YAML, codec.MessagePack, codec.XML} {
+		content[format.ContentType] = map[string]interface{}{"schema": schema}
+	}
+	return content
+}
+
+// withReports adds the JUnit and SARIF reports of array results to an
+// OpenAPI content map
+func withReports(content map[string]interface{}) map[string]interface{} {
+	content[codec.JUnit.ContentType] = map[string]interface{}{
+		"schema": map[string]interface{}{"type": "string", "description": "JUnit XML: a test case per record, a failure per error, warnings with their category in system-out"},
+	}
+	content[codec.SARIF.ContentType] = map[string]interface{}{
+		"schema": map[string]interface{}{"type": "object", "description": "SARIF 2.1.0 log: an error result per error and a note result per warning, with its category"},
+	}
+	return content
+}
+
+// problemResponse is an OpenAPI error response carrying problem details
+func problemResponse(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			problem.ContentType: map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/Problem"},
+			},
+		},
+	}
+}
+
 // getSwaggerSpec returns the Swagger specification as a Go map
 func getSwaggerSpec() map[string]interface{} {
 	// Get model list from registry
@@ -582,9 +1382,10 @@ func getSwaggerSpec() map[string]interface{} {
 	return map[string]interface{}{
 		"openapi": "3.0.3",
 		"info": map[string]interface{}{
-			"title":       "Modular Multi-Platform Validation API",
-			"description": "A comprehensive modular validation server supporting multiple platforms and data formats.",
-			"version":     "2.0.0-modular",
+			"title":           "Modular Multi-Platform Validation API",
+			"description":     "A comprehensive modular validation server supporting multiple platforms and data formats.",
+			"version":         apiVersion,
+			"x-build-version": version,
 		},
 		"servers": []map[string]interface{}{
 			{"url": "http://localhost:8080", "description": "Development server"},
@@ -633,9 +1434,31 @@ func getSwaggerSpec() map[string]interface{} {
 					"summary":     "Validate single object or array of objects",
 					"description": "Validates a single object or an array of objects with optional threshold-based validation. Supports both single object validation (payload field) and batch validation (data field). For batch validation, you can optionally specify a threshold percentage for success criteria.",
 					"tags":        []string{"Generic Validation"},
+					"parameters": []map[string]interface{}{
+						{"name": "model_type", "in": "query", "description": "Model of the records in a text/csv body", "schema": map[string]interface{}{"type": "string"}},
+						{"name": "threshold", "in": "query", "description": "Threshold percentage for a text/csv body", "schema": map[string]interface{}{"type": "number", "minimum": 0, "maximum": 100}},
+						{"name": "fail_fast", "in": "query", "description": "Stop at the first invalid row of a text/csv body", "schema": map[string]interface{}{"type": "boolean"}},
+						{"name": "max_concurrency", "in": "query", "description": "Workers validating the rows of a text/csv body; 0 uses one per CPU", "schema": map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 100}},
+					},
 					"requestBody": map[string]interface{}{
 						"required": true,
-						"content": map[string]interface{}{
+						"content": withFormats(map[string]interface{}{
+							"text/csv": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type":        "string",
+									"description": "Records with a header line of JSON field names; rows are reported by line number",
+								},
+							},
+							"multipart/form-data": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type": "object",
+									"properties": map[string]interface{}{
+										"mapping": map[string]interface{}{"type": "string", "format": "binary", "description": "Column mapping document (JSON or YAML), sent before csv"},
+										"csv":     map[string]interface{}{"type": "string", "format": "binary"},
+									},
+									"required": []string{"csv"},
+								},
+							},
 							"application/json": map[string]interface{}{
 								"schema": map[string]interface{}{
 									"oneOf": []map[string]interface{}{
@@ -703,35 +1526,33 @@ func getSwaggerSpec() map[string]interface{} {
 									},
 								},
 							},
-						},
+						}),
 					},
 					"responses": map[string]interface{}{
 						"200": map[string]interface{}{
 							"description": "Single object validation result",
-							"content": map[string]interface{}{
+							"content": withReports(withFormats(map[string]interface{}{
 								"application/json": map[string]interface{}{
 									"schema": map[string]interface{}{
 										"$ref": "#/components/schemas/ValidationResult",
 									},
 								},
-							},
+							})),
 						},
 						"422": map[string]interface{}{
 							"description": "Array validation result (status: failed when threshold not met)",
-							"content": map[string]interface{}{
+							"content": withReports(withFormats(map[string]interface{}{
 								"application/json": map[string]interface{}{
 									"schema": map[string]interface{}{
 										"$ref": "#/components/schemas/ArrayValidationResult",
 									},
 								},
-							},
-						},
-						"400": map[string]interface{}{
-							"description": "Bad request - invalid model type or malformed payload",
-						},
-						"500": map[string]interface{}{
-							"description": "Internal server error",
+							})),
 						},
+						"400":     problemResponse("Bad request - invalid model type or malformed payload"),
+						"406":     problemResponse("The Accept header allows none of the supported formats, or only a report for a request without array results"),
+						"500":     problemResponse("Internal server error"),
+						"default": problemResponse("Any other error, such as 403, 413 or 429"),
 					},
 				},
 			},
@@ -742,6 +1563,21 @@ func getSwaggerSpec() map[string]interface{} {
 		},
 		"components": map[string]interface{}{
 			"schemas": map[string]interface{}{
+				"Problem": map[string]interface{}{
+					"type":        "object",
+					"description": "RFC 7807 problem details, sent as application/problem+json for every error. Problems coded only by their status have type about:blank; the others have a type of /problems/ followed by their code.",
+					"required":    []string{"type", "title", "status", "code"},
+					"properties": map[string]interface{}{
+						"type":       map[string]interface{}{"type": "string", "format": "uri-reference", "example": "/problems/model-not-registered"},
+						"title":      map[string]interface{}{"type": "string", "description": "Summary of the kind of problem", "example": "Model type not registered"},
+						"status":     map[string]interface{}{"type": "integer", "example": 400},
+						"detail":     map[string]interface{}{"type": "string", "description": "What went wrong with this request", "example": "Model type 'ticket' is not registered"},
+						"instance":   map[string]interface{}{"type": "string", "format": "uri-reference", "description": "Path of the request", "example": "/validate"},
+						"request_id": map[string]interface{}{"type": "string", "description": "X-Request-ID of the request"},
+						"model_type": map[string]interface{}{"type": "string", "description": "Model the request was for, once known"},
+						"code":       map[string]interface{}{"type": "string", "description": "Machine-readable error code, such as model_not_registered, batch_not_found, max_body_bytes or rate_limit", "example": "model_not_registered"},
+					},
+				},
 				"AvailableModels": map[string]interface{}{
 					"type":        "array",
 					"description": "List of available model types that can be validated",
@@ -755,7 +1591,7 @@ func getSwaggerSpec() map[string]interface{} {
 					"type": "object",
 					"properties": map[string]interface{}{
 						"status":  map[string]interface{}{"type": "string", "example": "healthy"},
-						"version": map[string]interface{}{"type": "string", "example": "2.0.0-modular"},
+						"version": map[string]interface{}{"type": "string", "example": "1.4.0"},
 						"uptime":  map[string]interface{}{"type": "string", "example": "1h30m45s"},
 						"server":  map[string]interface{}{"type": "string", "example": "modular-validation-server"},
 					},
@@ -1054,51 +1890,229 @@ func handleBatchStart(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	var request struct {
-		ModelType string   `json:"model_type"`
-		JobID     string   `json:"job_id,omitempty"`
-		Threshold *float64 `json:"threshold,omitempty"`
+		ModelType     string   `json:"model_type"`
+		SchemaVersion string   `json:"schema_version,omitempty"`
+		JobID         string   `json:"job_id,omitempty"`
+		Threshold     *float64 `json:"threshold,omitempty"`
+		TTLSeconds    int      `json:"ttl_seconds,omitempty"` // Idle expiry; defaults to the server's BATCH_TTL
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		sendJSONError(w, "Invalid JSON payload", http.StatusBadRequest)
+	if !decodeJSONBody(w, r, &request, problem.CodeInvalidPayload, "Invalid JSON payload") {
 		return
 	}
 
 	if request.ModelType == "" {
-		sendJSONError(w, "model_type is required", http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, "model_type is required")
+		return
+	}
+	if !authorizeModel(w, r, auth.OpBatch, request.ModelType) {
+		return
+	}
+
+	// The session is bound to this model, so it must exist now
+	if !registry.GetGlobalRegistry().IsRegistered(registry.ModelType(request.ModelType)) {
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeModelNotRegistered, fmt.Sprintf("Model type '%s' is not registered", request.ModelType))
+		return
+	}
+
+	if len(request.SchemaVersion) > 64 {
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "schema_version must be at most 64 characters")
+		return
+	}
+
+	// The job ID becomes part of the batch ID, which the file store uses as a file name
+	if request.JobID != "" && (len(request.JobID) > 64 || !models.ValidBatchID(request.JobID)) {
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "job_id must be at most 64 letters, digits, '.', '_' or '-'")
 		return
 	}
 
+	if request.Threshold == nil {
+		request.Threshold = registry.GetGlobalRegistry().DefaultThreshold(registry.ModelType(request.ModelType))
+	}
+
 	// Generate batch ID
 	batchID := models.GenerateBatchID(request.JobID)
 
 	// Create batch session
 	batchManager := models.GetBatchSessionManager()
-	session := batchManager.CreateBatchSession(batchID, request.Threshold)
+	ttl := time.Duration(request.TTLSeconds) * time.Second
+	session, err := batchManager.CreateModelBatchSession(batchID, request.ModelType, request.SchemaVersion, request.Threshold, ttl)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidBatchTTL) || errors.Is(err, models.ErrBatchLimitExceeded) {
+			sendBatchStoreError(w, r, batchID, err)
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to create batch session", "batch_id", batchID, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "", "Failed to create batch session")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := map[string]interface{}{
+		"batch_id":    session.BatchID,
+		"model_type":  session.ModelType,
+		"status":      "active",
+		"started_at":  session.StartedAt,
+		"expires_at":  session.ExpiresAt(), // Moves forward with every chunk
+		"ttl_seconds": int(session.TTL / time.Second),
+		"threshold":   session.Threshold,
+		"message":     "Batch session created. Use X-Batch-ID header to add data.",
+	}
+	if session.SchemaVersion != "" {
+		response["schema_version"] = session.SchemaVersion
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// maxBatchChunkIDLength bounds the X-Batch-Chunk-ID header stored with each chunk
+const maxBatchChunkIDLength = 128
+
+// sendBatchChunkResponse returns the accumulation status for a chunk. A replayed
+// chunk gets the same body as the original submission plus X-Batch-Chunk-Replayed.
+func sendBatchChunkResponse(w http.ResponseWriter, respond *codec.Format, batchID string, chunk models.BatchChunk, replayed bool) {
+	if replayed {
+		w.Header().Set("X-Batch-Chunk-Replayed", "true")
+	}
+	respond.Write(w, http.StatusOK, map[string]interface{}{
+		"batch_id":      batchID,
+		"status":        "accumulating",
+		"records_count": chunk.SessionTotal,
+		"message":       fmt.Sprintf("Added %d records to batch. Use X-Batch-Complete header to finalize.", chunk.Records),
+	})
+}
+
+// Page sizes accepted by GET /validate/batch/{id}/results
+const (
+	defaultBatchResultsLimit = 100
+	maxBatchResultsLimit     = 1000
+)
+
+// handleBatchResults pages through the failed rows retained by a batch session
+func handleBatchResults(w http.ResponseWriter, r *http.Request) {
+	batchID := r.PathValue("id")
+	if batchID == "" {
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "batch ID is required")
+		return
+	}
+	if !authorizeBatch(w, r, batchID) {
+		return
+	}
+
+	query := r.URL.Query()
+	limit := defaultBatchResultsLimit
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxBatchResultsLimit {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, fmt.Sprintf("limit must be between 1 and %d", maxBatchResultsLimit))
+			return
+		}
+		limit = parsed
+	}
+	filter := models.BatchResultFilter{Code: query.Get("code"), Field: query.Get("field")}
+
+	batchManager := models.GetBatchSessionManager()
+	page, err := batchManager.ListBatchResults(batchID, query.Get("cursor"), limit, filter)
+	if errors.Is(err, models.ErrInvalidCursor) {
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid cursor")
+		return
+	} else if err != nil {
+		sendBatchStoreError(w, r, batchID, err)
+		return
+	}
+
+	// CI jobs can fetch the failed rows as a JUnit or SARIF report
+	if report, ok := codec.ReportForAccept(r.Header.Get("Accept")); ok {
+		session, exists := batchManager.GetBatchSession(batchID)
+		if !exists {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeBatchNotFound, fmt.Sprintf("Batch session '%s' not found", batchID))
+			return
+		}
+		report.Write(w, http.StatusOK, codec.BatchRun(session, &page))
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"batch_id":   session.BatchID,
-		"status":     "active",
-		"started_at": session.StartedAt,
-		"expires_at": session.StartedAt.Add(30 * time.Minute), // 30min expiration
-		"threshold":  session.Threshold,
-		"message":    "Batch session created. Use X-Batch-ID header to add data.",
+		"batch_id":    batchID,
+		"results":     page.Results,
+		"count":       len(page.Results),
+		"next_cursor": page.NextCursor,
+		"has_more":    page.NextCursor != "",
 	})
 }
 
+// authorizeModel answers 403 and returns false when the caller may not perform operation on model
+func authorizeModel(w http.ResponseWriter, r *http.Request, operation, model string) bool {
+	if err := auth.AllowModel(r.Context(), operation, model); err != nil {
+		denied := problem.New(http.StatusForbidden, "", err.Error())
+		denied.ModelType = model
+		denied.Write(w, r)
+		return false
+	}
+	return true
+}
+
+// authorizeBatch answers 403 and returns false when the caller may not use the
+// model a batch session is bound to; unknown sessions are left to the handler
+func authorizeBatch(w http.ResponseWriter, r *http.Request, batchID string) bool {
+	if _, authenticated := auth.PrincipalFrom(r.Context()); !authenticated {
+		return true
+	}
+	session, exists := models.GetBatchSessionManager().GetBatchSession(batchID)
+	if !exists {
+		return true
+	}
+	return authorizeModel(w, r, auth.OpBatch, session.ModelType)
+}
+
+// sendBatchStoreError maps a failed batch session operation to a status code;
+// anything unexpected is logged and reported as 500
+func sendBatchStoreError(w http.ResponseWriter, r *http.Request, batchID string, err error) {
+	switch {
+	case errors.Is(err, models.ErrBatchNotFound):
+		problem.Write(w, r, http.StatusNotFound, problem.CodeBatchNotFound, fmt.Sprintf("Batch session '%s' not found", batchID))
+		return
+	case errors.Is(err, models.ErrBatchModelMismatch):
+		problem.Write(w, r, http.StatusConflict, problem.CodeBatchModelMismatch, err.Error())
+		return
+	case errors.Is(err, models.ErrBatchExpired):
+		problem.Write(w, r, http.StatusGone, problem.CodeBatchExpired, fmt.Sprintf("Batch session '%s' has expired", batchID))
+		return
+	case errors.Is(err, models.ErrBatchCompleted):
+		problem.Write(w, r, http.StatusConflict, problem.CodeBatchCompleted, fmt.Sprintf("Batch session '%s' is already completed", batchID))
+		return
+	case errors.Is(err, models.ErrBatchTooManyRecords):
+		problem.Write(w, r, http.StatusRequestEntityTooLarge, problem.CodeBatchTooManyRecords, err.Error())
+		return
+	case errors.Is(err, models.ErrBatchLimitExceeded):
+		problem.Write(w, r, http.StatusTooManyRequests, problem.CodeBatchLimitExceeded, "Too many open batch sessions; complete or abort one first")
+		return
+	case errors.Is(err, models.ErrInvalidBatchTTL):
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidBatchTTL, err.Error())
+		return
+	case errors.Is(err, models.ErrInvalidChunkID):
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, fmt.Sprintf("X-Batch-Chunk-ID sequence numbers must be at most %d", models.MaxChunkSequence))
+		return
+	}
+	slog.ErrorContext(r.Context(), "Batch store error", "batch_id", batchID, "error", err)
+	problem.Write(w, r, http.StatusInternalServerError, "", "Batch session storage unavailable")
+}
+
 // handleBatchStatus retrieves the current status of a batch session
 func handleBatchStatus(w http.ResponseWriter, r *http.Request) {
 	batchID := r.PathValue("id")
 	if batchID == "" {
-		sendJSONError(w, "batch ID is required", http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "batch ID is required")
+		return
+	}
+	if !authorizeBatch(w, r, batchID) {
 		return
 	}
 
 	batchManager := models.GetBatchSessionManager()
 	session, exists := batchManager.GetBatchSession(batchID)
 	if !exists {
-		sendJSONError(w, fmt.Sprintf("Batch session '%s' not found", batchID), http.StatusNotFound)
+		problem.Write(w, r, http.StatusNotFound, problem.CodeBatchNotFound, fmt.Sprintf("Batch session '%s' not found", batchID))
 		return
 	}
 
@@ -1106,22 +2120,53 @@ func handleBatchStatus(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(session.GetStatus())
 }
 
+// handleBatchAbort discards a batch session and its retained rows
+func handleBatchAbort(w http.ResponseWriter, r *http.Request) {
+	batchID := r.PathValue("id")
+	if batchID == "" {
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "batch ID is required")
+		return
+	}
+	if !authorizeBatch(w, r, batchID) {
+		return
+	}
+
+	if err := models.GetBatchSessionManager().AbortBatchSession(batchID); err != nil {
+		sendBatchStoreError(w, r, batchID, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"batch_id": batchID,
+		"status":   "aborted",
+		"message":  "Batch session and its results were discarded.",
+	})
+}
+
 // handleBatchComplete finalizes a batch session and returns validation results
 func handleBatchComplete(w http.ResponseWriter, r *http.Request) {
 	batchID := r.PathValue("id")
 	if batchID == "" {
-		sendJSONError(w, "batch ID is required", http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "batch ID is required")
+		return
+	}
+	if !authorizeBatch(w, r, batchID) {
 		return
 	}
 
 	batchManager := models.GetBatchSessionManager()
 	status, err := batchManager.FinalizeBatchSession(batchID)
 	if err != nil {
-		sendJSONError(w, err.Error(), http.StatusNotFound)
+		sendBatchStoreError(w, r, batchID, err)
 		return
 	}
 
-	session, _ := batchManager.GetBatchSession(batchID)
+	session, exists := batchManager.GetBatchSession(batchID)
+	if !exists {
+		problem.Write(w, r, http.StatusNotFound, problem.CodeBatchNotFound, fmt.Sprintf("Batch session '%s' not found", batchID))
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 
@@ -1130,22 +2175,22 @@ func handleBatchComplete(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnprocessableEntity)
 	}
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"batch_id":        session.BatchID,
+	state := session.State()
+	response := map[string]interface{}{
+		"batch_id":        state.BatchID,
 		"status":          status,
-		"total_records":   session.TotalRecords,
-		"valid_records":   session.ValidRecords,
-		"invalid_records": session.InvalidRecords,
-		"warning_records": session.WarningRecords,
-		"threshold":       session.Threshold,
-		"started_at":      session.StartedAt,
-		"completed_at":    session.LastUpdated,
+		"total_records":   state.TotalRecords,
+		"valid_records":   state.ValidRecords,
+		"invalid_records": state.InvalidRecords,
+		"warning_records": state.WarningRecords,
+		"threshold":       state.Threshold,
+		"started_at":      state.StartedAt,
+		"completed_at":    state.LastUpdated,
 		"message":         fmt.Sprintf("Batch validation completed with status: %s", status),
-	})
-
-	// Clean up after returning response
-	go func() {
-		time.Sleep(1 * time.Second) // Small delay to ensure response is sent
-		batchManager.DeleteBatchSession(batchID)
-	}()
+	}
+	// Missing or out-of-order chunk sequence numbers are reported here
+	if report := session.ChunkReport(); report != nil {
+		response["chunks"] = report
+	}
+	json.NewEncoder(w).Encode(response)
 }
@@ -2,14 +2,28 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"goplayground-data-validator/admission"
+	"goplayground-data-validator/auth"
+	"goplayground-data-validator/config"
+	"goplayground-data-validator/jsonlimit"
+	"goplayground-data-validator/logging"
 	"goplayground-data-validator/models"
+	"goplayground-data-validator/problem"
+	"goplayground-data-validator/registry"
+	"goplayground-data-validator/tracing"
 )
 
 // Test Batch Handlers
@@ -42,6 +56,45 @@ func TestHandleBatchStart(t *testing.T) {
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name: "custom ttl",
+			payload: map[string]interface{}{
+				"model_type":  "testmodel",
+				"ttl_seconds": 600,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "ttl above server maximum",
+			payload: map[string]interface{}{
+				"model_type":  "testmodel",
+				"ttl_seconds": 7 * 24 * 3600,
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "negative ttl",
+			payload: map[string]interface{}{
+				"model_type":  "testmodel",
+				"ttl_seconds": -5,
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unregistered model_type",
+			payload: map[string]interface{}{
+				"model_type": "nosuchmodel",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "job_id with path separators",
+			payload: map[string]interface{}{
+				"model_type": "testmodel",
+				"job_id":     "../../etc/passwd",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -75,7 +128,10 @@ func TestHandleBatchStatus(t *testing.T) {
 	// Create a test batch session
 	batchManager := models.GetBatchSessionManager()
 	threshold := 50.0
-	session := batchManager.CreateBatchSession("test-batch-123", &threshold)
+	session, err := batchManager.CreateBatchSession("test-batch-123", &threshold)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 
 	tests := []struct {
 		name           string
@@ -125,7 +181,10 @@ func TestHandleBatchComplete(t *testing.T) {
 	// Create and populate a test batch session
 	batchManager := models.GetBatchSessionManager()
 	threshold := 50.0
-	session := batchManager.CreateBatchSession("test-batch-456", &threshold)
+	session, err := batchManager.CreateBatchSession("test-batch-456", &threshold)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 	batchManager.UpdateBatchSession(session.BatchID, 60, 40, 5)
 
 	tests := []struct {
@@ -167,6 +226,8 @@ func TestHandleBatchComplete(t *testing.T) {
 			}
 		})
 	}
+
+	batchManager.DeleteBatchSession(session.BatchID)
 }
 
 // Test Helper Functions
@@ -462,9 +523,777 @@ func TestBatchSessionIntegration(t *testing.T) {
 			t.Errorf("Expected batch complete to succeed, got %d", w.Code)
 		}
 
-		// Wait for cleanup
-		time.Sleep(2 * time.Second)
+		// Completed sessions are kept for their results; clean up explicitly
+		models.GetBatchSessionManager().DeleteBatchSession(batchID)
+	})
+}
+
+// TestHandleBatchResults tests paging through failed rows retained across chunks
+func TestHandleBatchResults(t *testing.T) {
+	batchManager := models.GetBatchSessionManager()
+	session, err := batchManager.CreateBatchSession("test-batch-results", nil)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	defer batchManager.DeleteBatchSession(session.BatchID)
+
+	// Two chunks of invalid rows; the second chunk's rows continue the numbering
+	for _, chunk := range [][]interface{}{
+		{map[string]interface{}{"id": "a"}, map[string]interface{}{"id": "b"}, map[string]interface{}{"id": "c"}},
+		{map[string]interface{}{"id": "d"}, map[string]interface{}{"id": "e"}},
+	} {
+		body, _ := json.Marshal(map[string]interface{}{"model_type": "invalidmodel", "data": chunk})
+		req := httptest.NewRequest("POST", "/validate", bytes.NewBuffer(body))
+		req.Header.Set("X-Batch-ID", session.BatchID)
+		w := httptest.NewRecorder()
+		handleGenericValidation(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Chunk upload failed: %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	// Results stay available after the batch is completed
+	req := httptest.NewRequest("POST", "/validate/batch/"+session.BatchID+"/complete", nil)
+	req.SetPathValue("id", session.BatchID)
+	handleBatchComplete(httptest.NewRecorder(), req)
+
+	getPage := func(query string) (int, map[string]interface{}) {
+		req := httptest.NewRequest("GET", "/validate/batch/"+session.BatchID+"/results"+query, nil)
+		req.SetPathValue("id", session.BatchID)
+		w := httptest.NewRecorder()
+		handleBatchResults(w, req)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	var identifiers []string
+	var indexes []float64
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("Too many pages")
+		}
+		code, response := getPage("?limit=2&cursor=" + cursor)
+		if code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %v", code, response)
+		}
+		for _, row := range response["results"].([]interface{}) {
+			result := row.(map[string]interface{})
+			identifiers = append(identifiers, result["record_identifier"].(string))
+			indexes = append(indexes, result["batch_row_index"].(float64))
+		}
+		if response["has_more"] != true {
+			break
+		}
+		cursor = response["next_cursor"].(string)
+	}
+
+	if strings.Join(identifiers, ",") != "a,b,c,d,e" {
+		t.Errorf("Identifiers = %v, want a..e", identifiers)
+	}
+	for i, index := range indexes {
+		if int(index) != i {
+			t.Errorf("Row %d has batch_row_index %v", i, index)
+		}
+	}
+
+	if code, response := getPage("?code=NO_SUCH_CODE"); code != http.StatusOK || response["count"].(float64) != 0 {
+		t.Errorf("Filter by unknown code: %d %v", code, response)
+	}
+
+	// CI jobs can fetch the same page as a JUnit report
+	req = httptest.NewRequest("GET", "/validate/batch/"+session.BatchID+"/results?limit=2", nil)
+	req.SetPathValue("id", session.BatchID)
+	req.Header.Set("Accept", "application/junit+xml")
+	w := httptest.NewRecorder()
+	handleBatchResults(w, req)
+	if w.Header().Get("Content-Type") != "application/junit+xml" || strings.Count(w.Body.String(), "<testcase ") != 2 || !strings.Contains(w.Body.String(), `name="next_cursor"`) {
+		t.Errorf("JUnit report: %d %s", w.Code, w.Body.String())
+	}
+
+	errorCases := []struct {
+		query string
+		want  int
+	}{
+		{"?limit=0", http.StatusBadRequest},
+		{"?limit=5000", http.StatusBadRequest},
+		{"?cursor=abc", http.StatusBadRequest},
+		{"?cursor=999999", http.StatusBadRequest},
+	}
+	for _, tc := range errorCases {
+		if code, _ := getPage(tc.query); code != tc.want {
+			t.Errorf("%s: expected status %d, got %d", tc.query, tc.want, code)
+		}
+	}
+
+	req = httptest.NewRequest("GET", "/validate/batch/missing/results", nil)
+	req.SetPathValue("id", "missing")
+	w = httptest.NewRecorder()
+	handleBatchResults(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown batch, got %d", w.Code)
+	}
+}
+
+func TestHandleGenericValidation_BatchChunkReplay(t *testing.T) {
+	batchManager := models.GetBatchSessionManager()
+	session, err := batchManager.CreateBatchSession("test-batch-chunk-replay", nil)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	defer batchManager.DeleteBatchSession(session.BatchID)
+
+	postChunk := func(chunkID string, ids ...string) *httptest.ResponseRecorder {
+		var data []interface{}
+		for _, id := range ids {
+			data = append(data, map[string]interface{}{"id": id})
+		}
+		body, _ := json.Marshal(map[string]interface{}{"model_type": "invalidmodel", "data": data})
+		req := httptest.NewRequest("POST", "/validate", bytes.NewBuffer(body))
+		req.Header.Set("X-Batch-ID", session.BatchID)
+		req.Header.Set("X-Batch-Chunk-ID", chunkID)
+		w := httptest.NewRecorder()
+		handleGenericValidation(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Chunk %s upload failed: %d %s", chunkID, w.Code, w.Body.String())
+		}
+		return w
+	}
+
+	first := postChunk("1", "a", "b")
+	postChunk("3", "c")
+	replay := postChunk("1", "a", "b")
+
+	if replay.Header().Get("X-Batch-Chunk-Replayed") != "true" {
+		t.Error("Expected X-Batch-Chunk-Replayed on the retried chunk")
+	}
+	if first.Header().Get("X-Batch-Chunk-Replayed") != "" {
+		t.Error("First submission must not be marked as replayed")
+	}
+	if replay.Body.String() != first.Body.String() {
+		t.Errorf("Replay body %s differs from original %s", replay.Body.String(), first.Body.String())
+	}
+
+	current, _ := batchManager.GetBatchSession(session.BatchID)
+	if current.TotalRecords != 3 {
+		t.Errorf("TotalRecords = %d after replay, want 3", current.TotalRecords)
+	}
+
+	req := httptest.NewRequest("POST", "/validate/batch/"+session.BatchID+"/complete", nil)
+	req.SetPathValue("id", session.BatchID)
+	w := httptest.NewRecorder()
+	handleBatchComplete(w, req)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	chunks, ok := response["chunks"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected chunk report in finalize response, got %v", response)
+	}
+	if chunks["received"].(float64) != 2 {
+		t.Errorf("received = %v, want 2", chunks["received"])
+	}
+	if missing, _ := chunks["missing_sequences"].([]interface{}); len(missing) != 1 || missing[0].(float64) != 2 {
+		t.Errorf("missing_sequences = %v, want [2]", chunks["missing_sequences"])
+	}
+
+	// Oversized chunk IDs are rejected
+	body, _ := json.Marshal(map[string]interface{}{"model_type": "invalidmodel", "data": []interface{}{map[string]interface{}{"id": "x"}}})
+	req = httptest.NewRequest("POST", "/validate", bytes.NewBuffer(body))
+	req.Header.Set("X-Batch-ID", session.BatchID)
+	req.Header.Set("X-Batch-Chunk-ID", strings.Repeat("9", maxBatchChunkIDLength+1))
+	w = httptest.NewRecorder()
+	handleGenericValidation(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for oversized chunk ID, got %d", w.Code)
+	}
+
+	// So are sequence numbers that would make the chunk report unbounded
+	req = httptest.NewRequest("POST", "/validate", bytes.NewBuffer(body))
+	req.Header.Set("X-Batch-ID", session.BatchID)
+	req.Header.Set("X-Batch-Chunk-ID", "2000000000")
+	w = httptest.NewRecorder()
+	handleGenericValidation(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a huge chunk sequence number, got %d: %s", w.Code, w.Body.String())
+	}
+	if chunk, recorded := session.FindChunk("2000000000"); recorded {
+		t.Errorf("Expected huge chunk sequence number not to be recorded, got %+v", chunk)
+	}
+}
+
+func TestHandleGenericValidation_BatchModelBinding(t *testing.T) {
+	body, _ := json.Marshal(map[string]interface{}{"model_type": "testmodel", "schema_version": "v2"})
+	req := httptest.NewRequest("POST", "/validate/batch/start", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	handleBatchStart(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Batch start failed: %d %s", w.Code, w.Body.String())
+	}
+	var startResp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &startResp)
+	batchID := startResp["batch_id"].(string)
+	defer models.GetBatchSessionManager().DeleteBatchSession(batchID)
+
+	if startResp["model_type"] != "testmodel" || startResp["schema_version"] != "v2" {
+		t.Errorf("Start response does not report the binding: %v", startResp)
+	}
+
+	tests := []struct {
+		name          string
+		modelType     string
+		schemaVersion string
+		batchHeader   string
+		want          int
+	}{
+		{"matching model", "testmodel", "", "X-Batch-ID", http.StatusOK},
+		{"matching schema version", "testmodel", "v2", "X-Batch-ID", http.StatusOK},
+		{"other model", "invalidmodel", "", "X-Batch-ID", http.StatusConflict},
+		{"other schema version", "testmodel", "v1", "X-Batch-ID", http.StatusConflict},
+		{"complete with other model", "invalidmodel", "", "X-Batch-Complete", http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := map[string]interface{}{
+				"model_type": tt.modelType,
+				"data":       []interface{}{map[string]interface{}{"id": "1"}},
+			}
+			if tt.schemaVersion != "" {
+				payload["schema_version"] = tt.schemaVersion
+			}
+			body, _ := json.Marshal(payload)
+			req := httptest.NewRequest("POST", "/validate", bytes.NewBuffer(body))
+			req.Header.Set(tt.batchHeader, batchID)
+			w := httptest.NewRecorder()
+			handleGenericValidation(w, req)
+			if w.Code != tt.want {
+				t.Errorf("Expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	req = httptest.NewRequest("GET", "/validate/batch/"+batchID, nil)
+	req.SetPathValue("id", batchID)
+	w = httptest.NewRecorder()
+	handleBatchStatus(w, req)
+	var status map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &status)
+	if status["model_type"] != "testmodel" || status["total_records"].(float64) != 2 {
+		t.Errorf("Status should report model testmodel with 2 records, got %v", status)
+	}
+}
+
+func TestHandleBatchAbort(t *testing.T) {
+	batchManager := models.GetBatchSessionManager()
+	session, err := batchManager.CreateBatchSession("test-batch-abort", nil)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+
+	abort := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("DELETE", "/validate/batch/"+session.BatchID, nil)
+		req.SetPathValue("id", session.BatchID)
+		w := httptest.NewRecorder()
+		handleBatchAbort(w, req)
+		return w
+	}
+
+	if w := abort(); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"aborted"`) {
+		t.Errorf("Expected aborted response, got %d %s", w.Code, w.Body.String())
+	}
+	if _, exists := batchManager.GetBatchSession(session.BatchID); exists {
+		t.Error("Expected aborted session to be removed")
+	}
+	if w := abort(); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a second abort, got %d", w.Code)
+	}
+}
+
+func TestHandleGenericValidation_BatchExpiredAndFull(t *testing.T) {
+	batchManager := models.GetBatchSessionManager()
+	expired, err := batchManager.CreateBatchSession("test-batch-expired", nil)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	defer batchManager.DeleteBatchSession(expired.BatchID)
+	expired.LastUpdated = time.Now().Add(-time.Hour)
+	if err := batchManager.CleanupExpiredBatches(); err != nil {
+		t.Fatalf("CleanupExpiredBatches failed: %v", err)
+	}
+
+	post := func(batchID string, records int) *httptest.ResponseRecorder {
+		data := make([]interface{}, records)
+		for i := range data {
+			data[i] = map[string]interface{}{"id": fmt.Sprint(i)}
+		}
+		body, _ := json.Marshal(map[string]interface{}{"model_type": "testmodel", "data": data})
+		req := httptest.NewRequest("POST", "/validate", bytes.NewBuffer(body))
+		req.Header.Set("X-Batch-ID", batchID)
+		w := httptest.NewRecorder()
+		handleGenericValidation(w, req)
+		return w
+	}
+
+	if w := post(expired.BatchID, 1); w.Code != http.StatusGone {
+		t.Errorf("Expected 410 for a chunk to an expired session, got %d", w.Code)
+	}
+
+	// Expired sessions still answer status requests
+	req := httptest.NewRequest("GET", "/validate/batch/"+expired.BatchID, nil)
+	req.SetPathValue("id", expired.BatchID)
+	w := httptest.NewRecorder()
+	handleBatchStatus(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"expired"`) {
+		t.Errorf("Expected expired status, got %d %s", w.Code, w.Body.String())
+	}
+
+	limits := batchManager.Limits()
+	defer batchManager.SetLimits(limits)
+	small := limits
+	small.MaxRecordsPerSession = 3
+	if err := batchManager.SetLimits(small); err != nil {
+		t.Fatalf("SetLimits failed: %v", err)
+	}
+	full, err := batchManager.CreateBatchSession("test-batch-full", nil)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	defer batchManager.DeleteBatchSession(full.BatchID)
+	if w := post(full.BatchID, 2); w.Code != http.StatusOK {
+		t.Fatalf("Expected first chunk to be accepted, got %d %s", w.Code, w.Body.String())
+	}
+	if w := post(full.BatchID, 2); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 past the record limit, got %d", w.Code)
+	}
+
+	// A completed session keeps its totals: later chunks are refused
+	completed, err := batchManager.CreateBatchSession("test-batch-completed", nil)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	defer batchManager.DeleteBatchSession(completed.BatchID)
+	if w := post(completed.BatchID, 1); w.Code != http.StatusOK {
+		t.Fatalf("Expected chunk to be accepted, got %d %s", w.Code, w.Body.String())
+	}
+	if _, err := batchManager.FinalizeBatchSession(completed.BatchID); err != nil {
+		t.Fatalf("FinalizeBatchSession failed: %v", err)
+	}
+	w = post(completed.BatchID, 1)
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), problem.CodeBatchCompleted) {
+		t.Errorf("Expected 409 %s for a chunk after completion, got %d %s", problem.CodeBatchCompleted, w.Code, w.Body.String())
+	}
+	if state := completed.State(); state.TotalRecords != 1 || !state.IsFinal {
+		t.Errorf("Expected completed session to keep 1 record, got %+v", state)
+	}
+	if _, err := batchManager.FinalizeBatchSession(completed.BatchID); err != nil {
+		t.Errorf("Expected completing again to succeed, got %v", err)
+	}
+}
+
+func TestBatchLimits(t *testing.T) {
+	t.Setenv("BATCH_TTL", "10m")
+	t.Setenv("BATCH_MAX_OPEN_SESSIONS", "5")
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("config.Load failed: %v", err)
+	}
+	limits := batchLimits(cfg)
+	if limits.DefaultTTL != 10*time.Minute || limits.MaxOpenSessions != 5 {
+		t.Errorf("Unexpected limits: %+v", limits)
+	}
+	if limits.MaxTTL != models.DefaultBatchLimits().MaxTTL {
+		t.Errorf("Unset values should keep their defaults, got MaxTTL %s", limits.MaxTTL)
+	}
+	if err := limits.Validate(); err != nil {
+		t.Errorf("Expected valid limits, got %v", err)
+	}
+
+	for env, value := range map[string]string{"BATCH_MAX_TTL": "soon", "BATCH_MAX_RECORDS": "many", "BATCH_SWEEP_INTERVAL": "0s"} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, value)
+			if _, err := config.Load(nil); err == nil {
+				t.Errorf("Expected error for %s=%s", env, value)
+			}
+		})
+	}
+}
+
+func TestTracingConfig(t *testing.T) {
+	t.Setenv("TRACES_EXPORTER", "file")
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("config.Load failed: %v", err)
+	}
+	if tc := tracingConfig(cfg); tc.Exporter != tracing.ExporterFile || tc.File != "traces.jsonl" || tc.ServiceVersion != version {
+		t.Errorf("Unexpected tracing config: %+v", tc)
+	}
+
+	t.Setenv("TRACES_FILE", "/tmp/spans.jsonl")
+	cfg, _ = config.Load(nil)
+	if tc := tracingConfig(cfg); tc.File != "/tmp/spans.jsonl" {
+		t.Errorf("Expected TRACES_FILE to be used, got %q", tc.File)
+	}
+}
+
+// TestGenericValidation_TraceAndRequestIDs tests that the IDs of the request are echoed in the result
+func TestGenericValidation_TraceAndRequestIDs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /validate", handleGenericValidation)
+
+	jsonData, _ := json.Marshal(map[string]interface{}{
+		"model_type": "invalidmodel",
+		"data":       []map[string]interface{}{{"id": "INVALID-001"}},
+	})
+	req := httptest.NewRequest("POST", "/validate", bytes.NewBuffer(jsonData))
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("X-Request-ID", "req-123")
+	w := httptest.NewRecorder()
+	tracing.Middleware(mux).ServeHTTP(w, req)
+
+	var result models.ArrayValidationResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || result.RequestID != "req-123" {
+		t.Errorf("Expected trace and request IDs in result, got %q and %q", result.TraceID, result.RequestID)
+	}
+}
+
+func TestLoggingConfig(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_FORMAT", "text")
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("config.Load failed: %v", err)
+	}
+	if lc := loggingConfig(cfg); lc.Level != "debug" || lc.Format != logging.FormatText {
+		t.Errorf("Unexpected logging config: %+v", lc)
+	}
+}
+
+func TestAdmissionConfig(t *testing.T) {
+	t.Setenv("RATE_LIMIT_RPS", "2.5")
+	t.Setenv("RATE_LIMIT_BURST", "10")
+	t.Setenv("MAX_CONCURRENT_RECORDS", "5000")
+	t.Setenv("MODEL_RECORD_LIMITS", "incident=100, github=50")
+	cfg, err := config.Load([]string{"-limits.max_queued=20"})
+	if err != nil {
+		t.Fatalf("config.Load failed: %v", err)
+	}
+	admissionCfg, rate := admissionConfig(cfg, nil)
+	if rate.RequestsPerSecond != 2.5 || rate.Burst != 10 || rate.ExemptPaths != nil {
+		t.Errorf("Unexpected rate config: %+v", rate)
+	}
+	// The public paths of the auth config are exempt from rate limiting
+	_, rate = admissionConfig(cfg, &auth.Config{PublicPaths: []string{"/status/"}})
+	if len(rate.ExemptPaths) != 1 || rate.ExemptPaths[0] != "/status/" {
+		t.Errorf("Expected exempt paths from the auth config, got %v", rate.ExemptPaths)
+	}
+	if admissionCfg.MaxConcurrentRecords != 5000 || admissionCfg.QueueTimeout != 5*time.Second || admissionCfg.MaxQueued != 20 {
+		t.Errorf("Unexpected admission config: %+v", admissionCfg)
+	}
+	if admissionCfg.ModelLimits["incident"] != 100 || admissionCfg.ModelLimits["github"] != 50 {
+		t.Errorf("Unexpected model limits: %v", admissionCfg.ModelLimits)
+	}
+
+	for env, value := range map[string]string{
+		"MODEL_RECORD_LIMITS":     "incident",
+		"MAX_CONCURRENT_RECORDS":  "-1",
+		"ADMISSION_QUEUE_TIMEOUT": "soon",
+		"RATE_LIMIT_RPS":          "fast",
+	} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, value)
+			if _, err := config.Load(nil); err == nil {
+				t.Errorf("Expected error for %s=%s", env, value)
+			}
+		})
+	}
+}
+
+// TestRegisterLimitRoutes tests that limits can only be changed with authentication on
+func TestRegisterLimitRoutes(t *testing.T) {
+	digest := sha256.Sum256([]byte("admin-key"))
+	authenticator, err := auth.New(auth.Config{APIKeys: []auth.APIKey{{ID: "admin", SHA256: hex.EncodeToString(digest[:])}}})
+	if err != nil {
+		t.Fatalf("auth.New failed: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		authenticator *auth.Authenticator
+		wantPut       bool
+	}{
+		{"without authentication", nil, false},
+		{"with authentication", authenticator, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			registerLimitRoutes(mux, tt.authenticator)
+
+			if _, pattern := mux.Handler(httptest.NewRequest("GET", "/limits", nil)); pattern != "GET /limits" {
+				t.Errorf("Expected GET /limits to be served, got pattern %q", pattern)
+			}
+			_, pattern := mux.Handler(httptest.NewRequest("PUT", "/limits/models/testmodel", strings.NewReader(`{"max_concurrent_records":1}`)))
+			if served := pattern != ""; served != tt.wantPut {
+				t.Errorf("PUT /limits/models/{model} served = %v, want %v", served, tt.wantPut)
+			}
+		})
+	}
+}
+
+// TestHandleSetModelLimit tests that a model cap set at runtime turns away validations that do not fit
+func TestHandleSetModelLimit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /limits", handleGetLimits)
+	mux.HandleFunc("PUT /limits/models/{model}", handleSetModelLimit)
+	mux.HandleFunc("POST /validate", handleGenericValidation)
+	defer admission.Default().SetModelLimit("testmodel", 0)
+
+	put := func(model, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PUT", "/limits/models/"+model, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	if w := put("unknown", `{"max_concurrent_records":1}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unregistered model, got %d", w.Code)
+	}
+	if w := put("testmodel", `{"max_concurrent_records":-1}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a negative limit, got %d", w.Code)
+	}
+	if w := put("testmodel", `{"max_concurrent_records":1}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	release, err := admission.Default().Acquire(context.Background(), "testmodel", 1)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer release()
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/limits", nil))
+	var status admission.Status
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode limits: %v", err)
+	}
+	if model := status.Models["testmodel"]; model.MaxConcurrentRecords != 1 || model.InFlight != 1 {
+		t.Errorf("Unexpected testmodel limits: %+v", model)
+	}
+
+	req := httptest.NewRequest("POST", "/validate", strings.NewReader(`{"model_type":"testmodel","payload":{"id":"1"}}`))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 429 with Retry-After while the model is at its cap, got %d", w.Code)
+	}
+}
+
+func TestJSONLimits(t *testing.T) {
+	t.Setenv("MAX_BODY_BYTES", "1048576")
+	t.Setenv("MAX_JSON_DEPTH", "0")
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("config.Load failed: %v", err)
+	}
+	limits := jsonLimits(cfg)
+	if limits.MaxBodyBytes != 1048576 || limits.MaxDepth != 0 || limits.MaxObjectKeys != jsonlimit.DefaultLimits().MaxObjectKeys {
+		t.Errorf("Unexpected JSON limits: %+v", limits)
+	}
+
+	t.Setenv("MAX_ARRAY_LENGTH", "-5")
+	if _, err := config.Load(nil); err == nil {
+		t.Error("Expected error for a negative MAX_ARRAY_LENGTH")
+	}
+}
+
+// TestGenericValidation_JSONLimits tests that oversized and too deeply nested bodies are rejected with the limit named
+func TestGenericValidation_JSONLimits(t *testing.T) {
+	defer jsonlimit.Configure(jsonlimit.DefaultLimits())
+	if err := jsonlimit.Configure(jsonlimit.Limits{MaxBodyBytes: 256, MaxDepth: 3, MaxArrayLength: 2}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+		limit    string
+	}{
+		{"within limits", `{"model_type":"testmodel","payload":{"id":"1"}}`, http.StatusOK, ""},
+		{"body too large", `{"model_type":"testmodel","payload":{"id":"` + strings.Repeat("x", 300) + `"}}`, http.StatusRequestEntityTooLarge, jsonlimit.LimitBodyBytes},
+		{"too deep", `{"model_type":"testmodel","payload":{"a":{"b":{}}}}`, http.StatusBadRequest, jsonlimit.LimitDepth},
+		{"too many records", `{"model_type":"testmodel","data":[{},{},{}]}`, http.StatusBadRequest, jsonlimit.LimitArrayLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/validate", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handleGenericValidation(w, req)
+
+			if w.Code != tt.expected {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expected, w.Code, w.Body.String())
+			}
+			if tt.limit != "" && !strings.Contains(w.Body.String(), tt.limit) {
+				t.Errorf("Expected the error to name %s, got %s", tt.limit, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestBatchStartAndModelLimit_JSONLimits tests that the batch start and model limit bodies are held to the same limits
+func TestBatchStartAndModelLimit_JSONLimits(t *testing.T) {
+	defer jsonlimit.Configure(jsonlimit.DefaultLimits())
+	if err := jsonlimit.Configure(jsonlimit.Limits{MaxBodyBytes: 256, MaxDepth: 2}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /validate/batch/start", handleBatchStart)
+	mux.HandleFunc("PUT /limits/models/{model}", handleSetModelLimit)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		expected int
+		limit    string
+	}{
+		{"batch start too large", "POST", "/validate/batch/start", `{"model_type":"testmodel","job_id":"` + strings.Repeat("x", 300) + `"}`, http.StatusRequestEntityTooLarge, jsonlimit.LimitBodyBytes},
+		{"batch start too deep", "POST", "/validate/batch/start", `{"model_type":"testmodel","x":{"y":{}}}`, http.StatusBadRequest, jsonlimit.LimitDepth},
+		{"model limit too large", "PUT", "/limits/models/testmodel", `{"max_concurrent_records":1,"x":"` + strings.Repeat("x", 300) + `"}`, http.StatusRequestEntityTooLarge, jsonlimit.LimitBodyBytes},
+		{"model limit too deep", "PUT", "/limits/models/testmodel", `{"max_concurrent_records":1,"x":{"y":{}}}`, http.StatusBadRequest, jsonlimit.LimitDepth},
+		{"model limit malformed", "PUT", "/limits/models/testmodel", `{"max_concurrent_records":`, http.StatusBadRequest, problem.CodeInvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if w.Code != tt.expected {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expected, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), `"code":"`+tt.limit+`"`) {
+				t.Errorf("Expected the problem code %s, got %s", tt.limit, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestGenericValidation_DefaultThreshold tests that a configured default threshold applies when the request names none
+func TestGenericValidation_DefaultThreshold(t *testing.T) {
+	threshold := 50.0
+	registry.GetGlobalRegistry().SetDefaultThreshold("invalidmodel", &threshold)
+	defer registry.GetGlobalRegistry().SetDefaultThreshold("invalidmodel", nil)
+
+	validate := func(body string) models.ArrayValidationResult {
+		req := httptest.NewRequest("POST", "/validate", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		handleGenericValidation(w, req)
+		var result models.ArrayValidationResult
+		if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return result
+	}
+
+	result := validate(`{"model_type":"invalidmodel","data":[{"id":"1"},{"id":"2"}]}`)
+	if result.Threshold == nil || *result.Threshold != 50 || result.Status != "failed" {
+		t.Errorf("Expected the default threshold to fail the batch, got %v and %s", result.Threshold, result.Status)
+	}
+	result = validate(`{"model_type":"invalidmodel","data":[{"id":"1"},{"id":"2"}],"threshold":0}`)
+	if result.Threshold == nil || *result.Threshold != 0 || result.Status != "success" {
+		t.Errorf("Expected the request threshold to win, got %v and %s", result.Threshold, result.Status)
+	}
+}
+
+// TestGenericValidation_AccessLog tests that the access log line carries the model and record counts
+func TestGenericValidation_AccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.Config{})
+	if err != nil {
+		t.Fatalf("logging.New failed: %v", err)
+	}
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(previous)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /validate", handleGenericValidation)
+
+	jsonData, _ := json.Marshal(map[string]interface{}{
+		"model_type": "invalidmodel",
+		"data":       []map[string]interface{}{{"id": "INVALID-001"}, {"id": "INVALID-002"}},
 	})
+	req := httptest.NewRequest("POST", "/validate", bytes.NewBuffer(jsonData))
+	w := httptest.NewRecorder()
+	tracing.Middleware(logging.AccessLog(mux)).ServeHTTP(w, req)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON access log line, got %q: %v", buf.String(), err)
+	}
+	if entry["msg"] != "request" || entry["model"] != "invalidmodel" || entry["records"] != float64(2) {
+		t.Errorf("Unexpected access log line: %v", entry)
+	}
+	if entry["status"] != float64(w.Code) {
+		t.Errorf("Expected status %d in access log, got %v", w.Code, entry["status"])
+	}
+	if requestID := w.Header().Get(tracing.RequestIDHeader); requestID == "" || entry["request_id"] != requestID {
+		t.Errorf("Expected generated request ID %q in access log, got %v", requestID, entry["request_id"])
+	}
+}
+
+// TestHandlers_ModelAuthorization tests that models named in the body are checked against the caller's grant
+func TestHandlers_ModelAuthorization(t *testing.T) {
+	digest := sha256.Sum256([]byte("ingest-key"))
+	authenticator, err := auth.New(auth.Config{APIKeys: []auth.APIKey{{
+		ID:     "ingest",
+		SHA256: hex.EncodeToString(digest[:]),
+		Grant:  auth.Grant{Models: []string{"testmodel"}},
+	}}})
+	if err != nil {
+		t.Fatalf("auth.New failed: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /validate", handleGenericValidation)
+	mux.HandleFunc("POST /validate/batch/start", handleBatchStart)
+	mux.HandleFunc("GET /validate/batch/{id}", handleBatchStatus)
+	handler := authenticator.Middleware(mux)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set(auth.APIKeyHeader, "ingest-key")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("/validate", `{"model_type":"invalidmodel","payload":{}}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a model outside the grant, got %d", w.Code)
+	}
+	if w := post("/validate/batch/start", `{"model_type":"invalidmodel"}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a batch of a model outside the grant, got %d", w.Code)
+	}
+
+	// A session bound to another model is hidden from this caller
+	session, err := models.GetBatchSessionManager().CreateModelBatchSession(models.GenerateBatchID("auth"), "invalidmodel", "", nil, 0)
+	if err != nil {
+		t.Fatalf("CreateModelBatchSession failed: %v", err)
+	}
+	defer models.GetBatchSessionManager().AbortBatchSession(session.BatchID)
+	req := httptest.NewRequest("GET", "/validate/batch/"+session.BatchID, nil)
+	req.Header.Set(auth.APIKeyHeader, "ingest-key")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for another model's batch session, got %d", w.Code)
+	}
 }
 
 // ============================================================================
@@ -510,7 +1339,10 @@ func TestHandleBatchComplete_EmptyID(t *testing.T) {
 func TestHandleBatchComplete_FailedThreshold(t *testing.T) {
 	batchManager := models.GetBatchSessionManager()
 	threshold := 90.0 // High threshold
-	session := batchManager.CreateBatchSession("test-batch-fail", &threshold)
+	session, err := batchManager.CreateBatchSession("test-batch-fail", &threshold)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 	// Add few valid, mostly invalid records (below threshold)
 	batchManager.UpdateBatchSession(session.BatchID, 10, 90, 0)
 
@@ -530,8 +1362,7 @@ func TestHandleBatchComplete_FailedThreshold(t *testing.T) {
 		t.Errorf("Expected status 'failed', got %v", response["status"])
 	}
 
-	// Wait for cleanup
-	time.Sleep(2 * time.Second)
+	batchManager.DeleteBatchSession(session.BatchID)
 }
 
 // Test array validation path in handleGenericValidation
@@ -606,7 +1437,10 @@ func TestHandleGenericValidation_BatchAccumulation(t *testing.T) {
 	// Create a batch session first
 	batchManager := models.GetBatchSessionManager()
 	threshold := 50.0
-	session := batchManager.CreateBatchSession("test-batch-accum", &threshold)
+	session, err := batchManager.CreateBatchSession("test-batch-accum", &threshold)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 
 	t.Run("accumulate with valid batch ID", func(t *testing.T) {
 		payload := map[string]interface{}{
@@ -660,7 +1494,10 @@ func TestHandleGenericValidation_BatchComplete(t *testing.T) {
 	// Create and populate a batch session
 	batchManager := models.GetBatchSessionManager()
 	threshold := 50.0
-	session := batchManager.CreateBatchSession("test-batch-complete-header", &threshold)
+	session, err := batchManager.CreateBatchSession("test-batch-complete-header", &threshold)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 	batchManager.UpdateBatchSession(session.BatchID, 60, 40, 5)
 
 	t.Run("complete with valid batch ID", func(t *testing.T) {
@@ -684,8 +1521,7 @@ func TestHandleGenericValidation_BatchComplete(t *testing.T) {
 			t.Errorf("Expected status 'success', got '%v'", result["status"])
 		}
 
-		// Wait for cleanup
-		time.Sleep(2 * time.Second)
+		batchManager.DeleteBatchSession(session.BatchID)
 	})
 
 	t.Run("complete with non-existent batch ID", func(t *testing.T) {
@@ -709,7 +1545,10 @@ func TestHandleGenericValidation_BatchComplete(t *testing.T) {
 func TestHandleGenericValidation_BatchCompleteFailed(t *testing.T) {
 	batchManager := models.GetBatchSessionManager()
 	threshold := 90.0
-	session := batchManager.CreateBatchSession("test-batch-fail-header", &threshold)
+	session, err := batchManager.CreateBatchSession("test-batch-fail-header", &threshold)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 	batchManager.UpdateBatchSession(session.BatchID, 10, 90, 0) // 10% success rate
 
 	payload := map[string]interface{}{
@@ -732,8 +1571,7 @@ func TestHandleGenericValidation_BatchCompleteFailed(t *testing.T) {
 		t.Errorf("Expected status 'failed', got '%v'", result["status"])
 	}
 
-	// Wait for cleanup
-	time.Sleep(2 * time.Second)
+	batchManager.DeleteBatchSession(session.BatchID)
 }
 
 // Test error creating model instance
@@ -1041,7 +1879,10 @@ func TestSetMapValue_KeyConversion(t *testing.T) {
 func TestConcurrentBatchAccess(t *testing.T) {
 	batchManager := models.GetBatchSessionManager()
 	threshold := 50.0
-	session := batchManager.CreateBatchSession("concurrent-test", &threshold)
+	session, err := batchManager.CreateBatchSession("concurrent-test", &threshold)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 
 	done := make(chan bool)
 
@@ -1446,7 +2287,10 @@ func TestConvertMapToStruct_FieldConversionError(t *testing.T) {
 func TestHandleGenericValidation_BatchAccumulationValidationError(t *testing.T) {
 	batchManager := models.GetBatchSessionManager()
 	threshold := 50.0
-	session := batchManager.CreateBatchSession("test-batch-validation-error", &threshold)
+	session, err := batchManager.CreateBatchSession("test-batch-validation-error", &threshold)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 
 	// Send invalid data that will cause validation to fail
 	payload := map[string]interface{}{
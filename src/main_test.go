@@ -2,15 +2,30 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"goplayground-data-validator/logging"
+	"goplayground-data-validator/metrics"
+	"goplayground-data-validator/models"
 	"goplayground-data-validator/registry"
+	"goplayground-data-validator/tracing"
 )
 
 // testValidatorWrapper implements ValidatorInterface for testing
@@ -20,13 +35,13 @@ type testValidatorWrapper struct {
 	isValid   bool
 }
 
-func (tvw *testValidatorWrapper) ValidatePayload(payload interface{}) interface{} {
-	return map[string]interface{}{
-		"is_valid":   tvw.isValid,
-		"model_type": tvw.modelType,
-		"provider":   "go-playground",
-		"errors":     []interface{}{},
-		"warnings":   []interface{}{},
+func (tvw *testValidatorWrapper) ValidatePayload(payload interface{}) models.ValidationResult {
+	return models.ValidationResult{
+		IsValid:   tvw.isValid,
+		ModelType: tvw.modelType,
+		Provider:  "go-playground",
+		Errors:    []models.ValidationError{},
+		Warnings:  []models.ValidationWarning{},
 	}
 }
 
@@ -102,8 +117,112 @@ func TestHandleHealth(t *testing.T) {
 		}
 	}
 
-	if health["version"] != "2.0.0-modular" {
-		t.Errorf("Expected version '2.0.0-modular', got %v", health["version"])
+	if health["version"] != version {
+		t.Errorf("Expected build version %q, got %v", version, health["version"])
+	}
+}
+
+// TestHandleLiveness tests the liveness probe
+func TestHandleLiveness(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleLiveness(w, httptest.NewRequest("GET", "/health/live", nil))
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"alive"`) {
+		t.Errorf("Expected alive, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+// TestReadinessChecks tests readiness against registry and batch store state
+func TestReadinessChecks(t *testing.T) {
+	// The global test registry is filled by init, not StartAutoRegistration
+	w := httptest.NewRecorder()
+	handleReadiness(w, httptest.NewRequest("GET", "/health/ready", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 before registration completes, got %d", w.Code)
+	}
+
+	reg := registry.NewUnifiedRegistry()
+	if err := reg.StartAutoRegistration(context.Background(), http.NewServeMux()); err != nil {
+		t.Fatalf("StartAutoRegistration failed: %v", err)
+	}
+	manager := models.NewBatchSessionManager(models.NewMemoryBatchStore())
+	checks, ready := readinessChecks(reg, manager)
+	if !ready {
+		t.Errorf("Expected ready after registration, got %v", checks)
+	}
+
+	// A file store whose directory disappeared is not ready
+	dir := t.TempDir()
+	store, err := models.NewFileBatchStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileBatchStore failed: %v", err)
+	}
+	defer store.Close()
+	if err := manager.SetStore(store); err != nil {
+		t.Fatalf("SetStore failed: %v", err)
+	}
+	os.RemoveAll(dir)
+	checks, ready = readinessChecks(reg, manager)
+	if ready || checks["batch_store"].(map[string]interface{})["status"] != "fail" {
+		t.Errorf("Expected batch_store check to fail, got %v", checks)
+	}
+}
+
+// TestRegisterBatchMetrics tests the batch session gauges on a scrape
+func TestRegisterBatchMetrics(t *testing.T) {
+	manager := models.NewBatchSessionManager(models.NewMemoryBatchStore())
+	if _, err := manager.CreateBatchSession("metrics-open", nil); err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	if _, err := manager.CreateBatchSession("metrics-done", nil); err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	if _, err := manager.FinalizeBatchSession("metrics-done"); err != nil {
+		t.Fatalf("FinalizeBatchSession failed: %v", err)
+	}
+
+	reg := metrics.NewRegistry()
+	registerBatchMetrics(reg, manager)
+
+	w := httptest.NewRecorder()
+	reg.Handler()(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, sample := range []string{
+		"validator_batch_sessions_open 1\n",
+		fmt.Sprintf("validator_batch_sessions_max_open %d\n", manager.Limits().MaxOpenSessions),
+	} {
+		if !strings.Contains(body, sample) {
+			t.Errorf("Expected %q in scrape:\n%s", sample, body)
+		}
+	}
+}
+
+// TestHandleHealthDetails tests the detailed health report
+func TestHandleHealthDetails(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleHealthDetails(w, httptest.NewRequest("GET", "/health/details", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var details map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &details); err != nil {
+		t.Fatalf("Failed to decode details: %v", err)
+	}
+	for _, field := range []string{"status", "checks", "registration", "batch_sessions", "build", "uptime"} {
+		if _, ok := details[field]; !ok {
+			t.Errorf("Missing field %s", field)
+		}
+	}
+	if build := details["build"].(map[string]interface{}); build["version"] != version || build["commit"] != commit {
+		t.Errorf("Unexpected build info: %v", build)
+	}
+	if _, ok := details["batch_sessions"].(map[string]interface{})["open"]; !ok {
+		t.Errorf("Expected open batch session count, got %v", details["batch_sessions"])
+	}
+	registration := details["registration"].(map[string]interface{})
+	if len(registration["models"].([]interface{})) == 0 {
+		t.Error("Expected per-model registration status")
 	}
 }
 
@@ -228,6 +347,163 @@ func TestHandleGenericValidation(t *testing.T) {
 	}
 }
 
+// TestHandleGenericValidation_ArrayOptions tests batch options on array validation
+func TestHandleGenericValidation_ArrayOptions(t *testing.T) {
+	data := []map[string]interface{}{{"id": "ROW-1"}, {"id": "ROW-2"}, {"id": "ROW-3"}}
+
+	tests := []struct {
+		name           string
+		options        map[string]interface{}
+		expectedStatus int
+		expectSkipped  float64
+	}{
+		{"fail fast", map[string]interface{}{"fail_fast": true, "max_concurrency": 2, "timeout": "5s"}, http.StatusUnprocessableEntity, 2},
+		{"max concurrency too high", map[string]interface{}{"max_concurrency": 101}, http.StatusBadRequest, 0},
+		{"max concurrency negative", map[string]interface{}{"max_concurrency": -1}, http.StatusBadRequest, 0},
+		{"max concurrency default", map[string]interface{}{"max_concurrency": 0}, http.StatusOK, 0},
+		{"invalid timeout", map[string]interface{}{"timeout": "soon"}, http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonData, _ := json.Marshal(map[string]interface{}{
+				"model_type": "invalidmodel",
+				"data":       data,
+				"options":    tt.options,
+			})
+			req := httptest.NewRequest("POST", "/validate", bytes.NewBuffer(jsonData))
+			w := httptest.NewRecorder()
+
+			handleGenericValidation(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusUnprocessableEntity {
+				return
+			}
+
+			var result map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if result["skipped_records"] != tt.expectSkipped {
+				t.Errorf("Expected skipped_records=%v, got %v", tt.expectSkipped, result["skipped_records"])
+			}
+		})
+	}
+}
+
+// TestHandleGenericValidation_ContentNegotiation tests YAML, XML and MessagePack bodies
+func TestHandleGenericValidation_ContentNegotiation(t *testing.T) {
+	payloadYAML := "model_type: testmodel\npayload:\n  id: TEST-001\n  name: Test payload\n  status: active\n"
+	arrayXML := "<request><model_type>testmodel</model_type><data><id>ROW-1</id></data><data><id>ROW-2</id></data></request>"
+
+	tests := []struct {
+		name            string
+		contentType     string
+		accept          string
+		body            string
+		expectedStatus  int
+		expectedType    string
+		expectedContent string
+	}{
+		{"yaml request", "application/yaml", "", payloadYAML, http.StatusOK, "application/json", `"is_valid":true`},
+		{"yaml response", "application/json", "application/yaml", `{"model_type":"invalidmodel","payload":{"id":"X"}}`, http.StatusUnprocessableEntity, "application/yaml", "is_valid: false"},
+		{"xml array", "application/xml", "application/xml", arrayXML, http.StatusOK, "application/xml", "<total_records>2</total_records>"},
+		{"msgpack response", "application/yaml", "application/msgpack", payloadYAML, http.StatusOK, "application/msgpack", "is_valid"},
+		{"yaml syntax error", "application/yaml", "", "model_type: testmodel\n  payload: 1\n", http.StatusBadRequest, "application/problem+json", "Invalid YAML payload at line 2"},
+		{"xml syntax error", "text/xml", "", "<request>\n<model_type>testmodel</request>", http.StatusBadRequest, "application/problem+json", "Invalid XML payload at line 2"},
+		{"array of non-objects", "application/json", "", `{"model_type":"testmodel","data":[{"id":"X"},2]}`, http.StatusBadRequest, "application/problem+json", "data[1] must be an object"},
+		{"not acceptable", "application/yaml", "text/html", payloadYAML, http.StatusNotAcceptable, "application/problem+json", "Accept must allow"},
+		{"junit array", "application/xml", "application/junit+xml", arrayXML, http.StatusOK, "application/junit+xml", `<testcase name="ROW-2" classname="testmodel"`},
+		{"sarif array", "application/json", "application/sarif+json", `{"model_type":"invalidmodel","threshold":50,"data":[{"id":"X"}]}`, http.StatusUnprocessableEntity, "application/sarif+json", `"level": "error"`},
+		{"junit single object", "application/yaml", "application/junit+xml", payloadYAML, http.StatusNotAcceptable, "application/problem+json", "only available for array validation"},
+		{"junit or json single object", "application/yaml", "application/junit+xml, application/json;q=0.5", payloadYAML, http.StatusOK, "application/json", `"is_valid":true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/validate", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			handleGenericValidation(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != tt.expectedType {
+				t.Errorf("Expected Content-Type %s, got %s", tt.expectedType, contentType)
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedContent) {
+				t.Errorf("Expected body to contain %q, got %s", tt.expectedContent, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestHandleCSVValidation tests CSV array validation on /validate
+func TestHandleCSVValidation(t *testing.T) {
+	body := "id,name\nROW-1,First\n\"ROW-2\",\"Second\nline\"\nROW-3,Third\n"
+
+	tests := []struct {
+		name           string
+		query          string
+		header         string
+		expectedStatus int
+		expectedRows   []float64
+	}{
+		{"rows by line", "?model_type=invalidmodel&threshold=50", "", http.StatusUnprocessableEntity, []float64{2, 3, 5}},
+		{"valid rows", "?model_type=testmodel&threshold=50", "", http.StatusOK, nil},
+		{"unknown model", "?model_type=nonexistent", "", http.StatusBadRequest, nil},
+		{"invalid threshold", "?model_type=testmodel&threshold=150", "", http.StatusBadRequest, nil},
+		{"default concurrency", "?model_type=testmodel&max_concurrency=0", "", http.StatusOK, nil},
+		{"negative concurrency", "?model_type=testmodel&max_concurrency=-1", "", http.StatusBadRequest, nil},
+		{"batch session", "?model_type=testmodel", "X-Batch-ID", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/validate"+tt.query, strings.NewReader(body))
+			req.Header.Set("Content-Type", "text/csv")
+			if tt.header != "" {
+				req.Header.Set(tt.header, "batch_1")
+			}
+			w := httptest.NewRecorder()
+
+			handleGenericValidation(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedRows == nil {
+				return
+			}
+
+			var result struct {
+				TotalRecords int `json:"total_records"`
+				Results      []struct {
+					RowIndex float64 `json:"row_index"`
+				} `json:"results"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			var rows []float64
+			for _, row := range result.Results {
+				rows = append(rows, row.RowIndex)
+			}
+			if result.TotalRecords != 3 || !reflect.DeepEqual(rows, tt.expectedRows) {
+				t.Errorf("Expected 3 records on lines %v, got %d on %v", tt.expectedRows, result.TotalRecords, rows)
+			}
+		})
+	}
+}
+
 // TestHandleSwaggerModels tests the swagger models endpoint
 func TestHandleSwaggerModels(t *testing.T) {
 	req := httptest.NewRequest("GET", "/swagger/models", nil)
@@ -271,31 +547,98 @@ func TestHandleSwaggerJSON(t *testing.T) {
 	if response["swagger"] == nil && response["openapi"] == nil {
 		t.Error("Expected swagger or openapi field in response")
 	}
+	info, _ := response["info"].(map[string]interface{})
+	if info["version"] != apiVersion || info["x-build-version"] != version {
+		t.Errorf("Expected info version %s and build version %s, got %v", apiVersion, version, info)
+	}
 }
 
-// TestSendJSONError tests the error response helper
-func TestSendJSONError(t *testing.T) {
+// TestErrorResponses_Problem tests that handler errors are problem details
+func TestErrorResponses_Problem(t *testing.T) {
+	req := httptest.NewRequest("POST", "/validate", strings.NewReader(`{"model_type":"nonexistent","payload":{}}`))
+	ctx, _ := logging.WithFields(tracing.WithRequestID(req.Context(), "req-42"))
 	w := httptest.NewRecorder()
 
-	sendJSONError(w, "Test error message", http.StatusBadRequest)
+	handleGenericValidation(w, req.WithContext(ctx))
 
 	if w.Code != http.StatusBadRequest {
 		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
 	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Expected Content-Type application/problem+json, got %s", contentType)
+	}
 
-	contentType := w.Header().Get("Content-Type")
-	if contentType != "application/json" {
-		t.Errorf("Expected Content-Type application/json, got %s", contentType)
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal error response: %v", err)
+	}
+	expected := map[string]interface{}{
+		"type":       "/problems/model-not-registered",
+		"status":     float64(http.StatusBadRequest),
+		"detail":     "Model type 'nonexistent' is not registered",
+		"instance":   "/validate",
+		"request_id": "req-42",
+		"model_type": "nonexistent",
+		"code":       "model_not_registered",
+	}
+	for key, value := range expected {
+		if response[key] != value {
+			t.Errorf("Expected %s %v, got %v", key, value, response[key])
+		}
 	}
+}
 
-	var errorResponse map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &errorResponse)
-	if err != nil {
-		t.Errorf("Failed to unmarshal error response: %v", err)
+// TestErrorResponses_ProblemFallback tests problem details for requests the mux has no route for
+func TestErrorResponses_ProblemFallback(t *testing.T) {
+	mux := http.NewServeMux()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	mux.HandleFunc("POST /validate", ok)
+	mux.HandleFunc("POST /validate/incident", ok)
+	mux.HandleFunc("GET /validate/batch/{id}", ok)
+	mux.HandleFunc("DELETE /validate/batch/{id}", ok)
+	handler := withProblemFallback(mux)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedCode   string
+		expectedAllow  string
+	}{
+		{"route", "POST", "/validate/incident", http.StatusOK, "", ""},
+		{"unknown model", "POST", "/validate/unknown", http.StatusNotFound, "model_not_registered", ""},
+		{"unknown model stream", "POST", "/validate/unknown/stream", http.StatusNotFound, "model_not_registered", ""},
+		{"unknown path", "GET", "/unknown", http.StatusNotFound, "not_found", ""},
+		{"wrong method", "GET", "/validate", http.StatusMethodNotAllowed, "method_not_allowed", "POST"},
+		{"wrong method with pattern", "PUT", "/validate/batch/b1", http.StatusMethodNotAllowed, "method_not_allowed", "DELETE, GET, HEAD"},
 	}
 
-	if errorResponse["error"] != "Test error message" {
-		t.Errorf("Expected error message 'Test error message', got %v", errorResponse["error"])
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if allow := w.Header().Get("Allow"); allow != tt.expectedAllow {
+				t.Errorf("Expected Allow %q, got %q", tt.expectedAllow, allow)
+			}
+			if tt.expectedCode == "" {
+				return
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+				t.Errorf("Expected Content-Type application/problem+json, got %s", contentType)
+			}
+			var response map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal error response: %v", err)
+			}
+			if response["code"] != tt.expectedCode || response["instance"] != tt.path {
+				t.Errorf("Expected code %s for %s, got %v", tt.expectedCode, tt.path, response)
+			}
+		})
 	}
 }
 
@@ -508,3 +851,140 @@ func BenchmarkConvertMapToStruct(b *testing.B) {
 		convertMapToStruct(sourceMap, &benchStruct)
 	}
 }
+
+// TestServeUntilDone_DrainsInFlightRequests tests that shutdown waits for a
+// running request and then stops accepting connections
+func TestServeUntilDone_DrainsInFlightRequests(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	started, release := make(chan struct{}), make(chan struct{})
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- serveUntilDone(ctx, server, listener, 5*time.Second) }()
+
+	url := "http://" + listener.Addr().String()
+	responses := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Errorf("In-flight request failed: %v", err)
+			close(responses)
+			return
+		}
+		responses <- resp
+	}()
+
+	<-started
+	cancel()
+	select {
+	case err := <-served:
+		t.Fatalf("serveUntilDone returned before the request finished: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	if resp := <-responses; resp != nil {
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected 200 for the drained request, got %d", resp.StatusCode)
+		}
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Expected clean shutdown, got %v", err)
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Error("Expected new connections to be refused after shutdown")
+	}
+}
+
+// TestServeUntilDone_DrainTimeout tests that requests outliving the drain
+// deadline are cut off and reported
+func TestServeUntilDone_DrainTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	started := make(chan struct{})
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- serveUntilDone(ctx, server, listener, 50*time.Millisecond) }()
+	go http.Get("http://" + listener.Addr().String())
+
+	<-started
+	cancel()
+	select {
+	case err := <-served:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serveUntilDone did not give up after the drain timeout")
+	}
+}
+
+// TestServeUntilDone_TLS tests that a server with a TLS config is served over HTTPS
+func TestServeUntilDone_TLS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	leaf, _ := x509.ParseCertificate(der)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	server := &http.Server{
+		Handler:   http.HandlerFunc(handleLiveness),
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- serveUntilDone(ctx, server, listener, time.Second) }()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := client.Get("https://" + listener.Addr().String() + "/health/live")
+	if err != nil {
+		t.Fatalf("HTTPS request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.TLS == nil {
+		t.Errorf("Expected 200 over TLS, got %d", resp.StatusCode)
+	}
+	if resp, err := http.Get("http://" + listener.Addr().String() + "/health/live"); err == nil {
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected plain HTTP to be refused, got %d", resp.StatusCode)
+		}
+	}
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Errorf("Expected clean shutdown, got %v", err)
+	}
+}
@@ -0,0 +1,356 @@
+// Package metrics exposes counters, histograms and gauges in the Prometheus text format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the media type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultDurationBuckets are histogram bounds in seconds suited to request latencies
+var DefaultDurationBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// collector is a metric family that can write itself in the text format
+type collector interface {
+	name() string
+	write(w *bufio.Writer) error
+}
+
+// Registry holds metric families and renders them for a scrape
+type Registry struct {
+	mutex      sync.RWMutex
+	collectors map[string]collector
+}
+
+var (
+	defaultRegistry *Registry
+	defaultOnce     sync.Once
+)
+
+// Default returns the process-wide registry served on /metrics
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		defaultRegistry = NewRegistry()
+	})
+	return defaultRegistry
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// register adds c to the registry; metric names must be unique
+func (r *Registry) register(c collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exists := r.collectors[c.name()]; exists {
+		panic(fmt.Sprintf("metrics: %s registered twice", c.name()))
+	}
+	r.collectors[c.name()] = c
+}
+
+// WriteText writes every registered family in the Prometheus text format, sorted by name
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mutex.RLock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	collectors := make([]collector, 0, len(names))
+	sort.Strings(names)
+	for _, name := range names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.mutex.RUnlock()
+
+	buf := bufio.NewWriter(w)
+	for _, c := range collectors {
+		if err := c.write(buf); err != nil {
+			return err
+		}
+	}
+	return buf.Flush()
+}
+
+// Handler serves the registry for Prometheus scrapes
+func (r *Registry) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		if err := r.WriteText(w); err != nil {
+			slog.ErrorContext(req.Context(), "Failed to write metrics", "error", err)
+		}
+	}
+}
+
+// family carries the name, help text and label names shared by a metric's series
+type family struct {
+	metricName string
+	help       string
+	labelNames []string
+}
+
+func (f family) name() string { return f.metricName }
+
+// key joins label values into a map key; the separator cannot appear in valid UTF-8
+func (f family) key(labelValues []string) string {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.metricName, len(f.labelNames), len(labelValues)))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+// writeHeader writes the HELP and TYPE lines
+func (f family) writeHeader(w *bufio.Writer, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.metricName, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.metricName, metricType)
+}
+
+// writeSample writes one sample line; extra is appended after the family's labels
+func (f family) writeSample(w *bufio.Writer, suffix string, labelValues []string, extraName, extraValue string, value float64) {
+	w.WriteString(f.metricName)
+	w.WriteString(suffix)
+	if len(labelValues) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, labelValue := range labelValues {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", f.labelNames[i], escapeLabelValue(labelValue))
+		}
+		if extraName != "" {
+			if len(labelValues) > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", extraName, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+// CounterVec is a counter partitioned by label values
+type CounterVec struct {
+	family
+	mutex  sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec registers a counter with the given label names
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		family: family{metricName: name, help: help, labelNames: labelNames},
+		series: make(map[string]*counterSeries),
+	}
+	r.register(c)
+	return c
+}
+
+// Add increases the series for labelValues by delta; negative deltas are ignored
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	key := c.key(labelValues)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, exists := c.series[key]
+	if !exists {
+		s = &counterSeries{labelValues: append([]string(nil), labelValues...)}
+		c.series[key] = s
+	}
+	s.value += delta
+}
+
+// Inc increases the series for labelValues by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Value returns the current value of the series for labelValues
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	key := c.key(labelValues)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if s, exists := c.series[key]; exists {
+		return s.value
+	}
+	return 0
+}
+
+func (c *CounterVec) write(w *bufio.Writer) error {
+	c.mutex.Lock()
+	series := make([]counterSeries, 0, len(c.series))
+	for _, s := range c.series {
+		series = append(series, *s)
+	}
+	c.mutex.Unlock()
+	sortSeries(series, func(s counterSeries) []string { return s.labelValues })
+
+	c.writeHeader(w, "counter")
+	for _, s := range series {
+		c.writeSample(w, "", s.labelValues, "", "", s.value)
+	}
+	return nil
+}
+
+// HistogramVec is a histogram partitioned by label values
+type HistogramVec struct {
+	family
+	buckets []float64
+	mutex   sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // Per bucket, not cumulative; the last slot is +Inf
+	sum         float64
+	count       uint64
+}
+
+// NewHistogramVec registers a histogram with the given upper bounds and label names
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	h := &HistogramVec{
+		family:  family{metricName: name, help: help, labelNames: labelNames},
+		buckets: bounds,
+		series:  make(map[string]*histogramSeries),
+	}
+	r.register(h)
+	return h
+}
+
+// Observe records value in the series for labelValues
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := h.key(labelValues)
+	bucket := sort.SearchFloat64s(h.buckets, value)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	s, exists := h.series[key]
+	if !exists {
+		s = &histogramSeries{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(h.buckets)+1),
+		}
+		h.series[key] = s
+	}
+	s.counts[bucket]++
+	s.sum += value
+	s.count++
+}
+
+// Count returns how many values the series for labelValues has observed
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	key := h.key(labelValues)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if s, exists := h.series[key]; exists {
+		return s.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) error {
+	h.mutex.Lock()
+	series := make([]histogramSeries, 0, len(h.series))
+	for _, s := range h.series {
+		snapshot := *s
+		snapshot.counts = append([]uint64(nil), s.counts...)
+		series = append(series, snapshot)
+	}
+	h.mutex.Unlock()
+	sortSeries(series, func(s histogramSeries) []string { return s.labelValues })
+
+	h.writeHeader(w, "histogram")
+	for _, s := range series {
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			h.writeSample(w, "_bucket", s.labelValues, "le", formatFloat(bound), float64(cumulative))
+		}
+		h.writeSample(w, "_bucket", s.labelValues, "le", "+Inf", float64(s.count))
+		h.writeSample(w, "_sum", s.labelValues, "", "", s.sum)
+		h.writeSample(w, "_count", s.labelValues, "", "", float64(s.count))
+	}
+	return nil
+}
+
+// GaugeFunc is a gauge whose value is read at scrape time
+type GaugeFunc struct {
+	family
+	read func() (float64, error)
+}
+
+// NewGaugeFunc registers a gauge that calls read on every scrape.
+// A scrape where read fails omits the sample and logs the error.
+func (r *Registry) NewGaugeFunc(name, help string, read func() (float64, error)) *GaugeFunc {
+	g := &GaugeFunc{family: family{metricName: name, help: help}, read: read}
+	r.register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w *bufio.Writer) error {
+	g.writeHeader(w, "gauge")
+	value, err := g.read()
+	if err != nil {
+		slog.Warn("Metric unavailable", "metric", g.metricName, "error", err)
+		return nil
+	}
+	g.writeSample(w, "", nil, "", "", value)
+	return nil
+}
+
+// sortSeries orders series by their label values so scrapes are stable
+func sortSeries[S any](series []S, labels func(S) []string) {
+	sort.Slice(series, func(i, j int) bool {
+		a, b := labels(series[i]), labels(series[j])
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+}
+
+// formatFloat renders a sample value the way Prometheus parses it
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string { return helpEscaper.Replace(help) }
+
+func escapeLabelValue(value string) string { return labelEscaper.Replace(value) }
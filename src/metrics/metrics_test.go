@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.NewCounterVec("test_requests_total", "Requests by model.", "model", "mode")
+	latency := reg.NewHistogramVec("test_duration_seconds", "Latency.", []float64{1, 0.1}, "model")
+	reg.NewGaugeFunc("test_open", "Open sessions.", func() (float64, error) { return 3, nil })
+
+	requests.Inc("incident", "single")
+	requests.Add(2, "api", "array")
+	requests.Add(-5, "api", "array") // Counters never decrease
+	latency.Observe(0.0625, "api")
+	latency.Observe(0.03125, "api")
+	latency.Observe(7, "api")
+
+	var out strings.Builder
+	if err := reg.WriteText(&out); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+
+	expected := `# HELP test_duration_seconds Latency.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{model="api",le="0.1"} 2
+test_duration_seconds_bucket{model="api",le="1"} 2
+test_duration_seconds_bucket{model="api",le="+Inf"} 3
+test_duration_seconds_sum{model="api"} 7.09375
+test_duration_seconds_count{model="api"} 3
+# HELP test_open Open sessions.
+# TYPE test_open gauge
+test_open 3
+# HELP test_requests_total Requests by model.
+# TYPE test_requests_total counter
+test_requests_total{model="api",mode="array"} 2
+test_requests_total{model="incident",mode="single"} 1
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected exposition:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestRegistry_Escaping(t *testing.T) {
+	reg := NewRegistry()
+	codes := reg.NewCounterVec("test_codes_total", "Codes with \\ and\nnewlines.", "code")
+	codes.Inc("a\"b\\c\nd")
+
+	var out strings.Builder
+	if err := reg.WriteText(&out); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	if !strings.Contains(out.String(), `# HELP test_codes_total Codes with \\ and\nnewlines.`) {
+		t.Errorf("HELP text not escaped:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), `test_codes_total{code="a\"b\\c\nd"} 1`) {
+		t.Errorf("Label value not escaped:\n%s", out.String())
+	}
+}
+
+func TestRegistry_GaugeFuncError(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewGaugeFunc("test_broken", "Unavailable gauge.", func() (float64, error) {
+		return 0, errors.New("store offline")
+	})
+
+	var out strings.Builder
+	if err := reg.WriteText(&out); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	if strings.Contains(out.String(), "test_broken 0") {
+		t.Errorf("Expected the sample to be omitted when the gauge fails:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "# TYPE test_broken gauge") {
+		t.Errorf("Expected the family header to be written:\n%s", out.String())
+	}
+}
+
+func TestRegistry_DuplicateName(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewCounterVec("test_total", "First.")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic when registering a name twice")
+		}
+	}()
+	reg.NewCounterVec("test_total", "Second.")
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewCounterVec("test_total", "Total.").Inc()
+
+	w := httptest.NewRecorder()
+	reg.Handler()(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Expected Content-Type %q, got %q", ContentType, ct)
+	}
+	if !strings.Contains(w.Body.String(), "test_total 1\n") {
+		t.Errorf("Expected counter sample in body:\n%s", w.Body.String())
+	}
+}
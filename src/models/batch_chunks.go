@@ -0,0 +1,132 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// MaxChunkSequence is the highest sequence number a numeric chunk ID may carry
+const MaxChunkSequence = 1000000
+
+// maxMissingSequences bounds the gaps listed in a chunk report
+const maxMissingSequences = 1000
+
+// ErrInvalidChunkID is returned for a numeric chunk ID above MaxChunkSequence
+var ErrInvalidChunkID = errors.New("invalid chunk id")
+
+// BatchChunk records a chunk submitted to a batch session with X-Batch-Chunk-ID,
+// so a retried chunk can be answered without counting its records twice
+type BatchChunk struct {
+	ID             string    `json:"id"`
+	Sequence       *int      `json:"sequence,omitempty"` // Set when the ID is a non-negative integer
+	Records        int       `json:"records"`            // Records validated in this chunk
+	ValidRecords   int       `json:"valid_records"`
+	InvalidRecords int       `json:"invalid_records"`
+	WarningRecords int       `json:"warning_records"`
+	SessionTotal   int       `json:"session_total"` // Session total_records right after this chunk
+	ReceivedAt     time.Time `json:"received_at"`
+}
+
+// BatchChunkReport summarizes the chunk IDs a session received
+type BatchChunkReport struct {
+	Received              int   `json:"received"`                         // Chunks recorded with an ID
+	MissingSequences      []int `json:"missing_sequences,omitempty"`      // Gaps in the sequence numbers, the first maxMissingSequences of them
+	MissingTruncated      bool  `json:"missing_truncated,omitempty"`      // More sequence numbers are missing than are listed
+	OutOfOrderSequences   []int `json:"out_of_order_sequences,omitempty"` // Numbers that arrived after a higher one
+	NonSequentialChunkIDs int   `json:"non_sequential_chunk_ids"`         // IDs that are not sequence numbers
+}
+
+// CheckChunkID rejects a chunk ID whose sequence number is above MaxChunkSequence
+func CheckChunkID(chunkID string) error {
+	if sequence, err := strconv.Atoi(chunkID); err == nil && sequence > MaxChunkSequence {
+		return fmt.Errorf("%w: sequence number %d exceeds %d", ErrInvalidChunkID, sequence, MaxChunkSequence)
+	}
+	return nil
+}
+
+// newBatchChunk builds the chunk record for a validated array
+func newBatchChunk(chunkID string, result *ArrayValidationResult) BatchChunk {
+	chunk := BatchChunk{
+		ID:             chunkID,
+		Records:        result.ValidRecords + result.InvalidRecords,
+		ValidRecords:   result.ValidRecords,
+		InvalidRecords: result.InvalidRecords,
+		WarningRecords: result.WarningRecords,
+		ReceivedAt:     time.Now(),
+	}
+	if sequence, err := strconv.Atoi(chunkID); err == nil && sequence >= 0 {
+		chunk.Sequence = &sequence
+	}
+	return chunk
+}
+
+// FindChunk returns the chunk recorded under chunkID
+func (bs *BatchSession) FindChunk(chunkID string) (BatchChunk, bool) {
+	bs.mutex.RLock()
+	defer bs.mutex.RUnlock()
+	return bs.findChunk(chunkID)
+}
+
+// findChunk looks up a chunk without locking
+func (state *BatchSessionState) findChunk(chunkID string) (BatchChunk, bool) {
+	for _, chunk := range state.Chunks {
+		if chunk.ID == chunkID {
+			return chunk, true
+		}
+	}
+	return BatchChunk{}, false
+}
+
+// chunkReport summarizes the session's chunks, or returns nil if none carried an ID.
+// Sequences are expected to start at 0 or 1 and increase by one per chunk.
+func (state *BatchSessionState) chunkReport() *BatchChunkReport {
+	if len(state.Chunks) == 0 {
+		return nil
+	}
+
+	report := &BatchChunkReport{Received: len(state.Chunks)}
+	var sequences []int
+	highest := -1
+	for _, chunk := range state.Chunks {
+		if chunk.Sequence == nil {
+			report.NonSequentialChunkIDs++
+			continue
+		}
+		sequence := *chunk.Sequence
+		if sequence < highest {
+			report.OutOfOrderSequences = append(report.OutOfOrderSequences, sequence)
+		}
+		if sequence > highest {
+			highest = sequence
+		}
+		sequences = append(sequences, sequence)
+	}
+
+	// Walk the gaps between received numbers rather than every number up to
+	// the highest, so a single large sequence number stays cheap to report
+	sort.Ints(sequences)
+	next := 1
+	if len(sequences) > 0 && sequences[0] == 0 {
+		next = 0
+	}
+	for _, sequence := range sequences {
+		for ; next < sequence; next++ {
+			if len(report.MissingSequences) == maxMissingSequences {
+				report.MissingTruncated = true
+				break
+			}
+			report.MissingSequences = append(report.MissingSequences, next)
+		}
+		if report.MissingTruncated {
+			break
+		}
+		if sequence >= next {
+			next = sequence + 1
+		}
+	}
+	sort.Ints(report.OutOfOrderSequences)
+	return report
+}
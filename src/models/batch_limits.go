@@ -0,0 +1,161 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrBatchExpired is returned when a chunk or completion targets an expired session
+	ErrBatchExpired = errors.New("batch session expired")
+	// ErrBatchCompleted is returned when a chunk targets a session that was already completed
+	ErrBatchCompleted = errors.New("batch session completed")
+	// ErrBatchLimitExceeded is returned when the server already holds the maximum number of open sessions
+	ErrBatchLimitExceeded = errors.New("too many open batch sessions")
+	// ErrBatchTooManyRecords is returned when a chunk would take a session past its record limit
+	ErrBatchTooManyRecords = errors.New("batch session record limit exceeded")
+	// ErrInvalidBatchTTL is returned when a requested session TTL is outside the configured bounds
+	ErrInvalidBatchTTL = errors.New("invalid batch session ttl")
+)
+
+// BatchLimits bounds the lifetime and size of batch sessions.
+// Zero MaxOpenSessions or MaxRecordsPerSession means no limit.
+type BatchLimits struct {
+	DefaultTTL           time.Duration // Idle time before an open session expires when the client asks for none
+	MaxTTL               time.Duration // Longest TTL a client may request
+	MaxOpenSessions      int           // Sessions that are neither completed nor expired
+	MaxRecordsPerSession int           // Records accumulated across all chunks of one session
+	ExpiredRetention     time.Duration // How long an expired session stays visible before it is removed
+	SweepInterval        time.Duration // How often the cleanup routine runs
+}
+
+// DefaultBatchLimits returns the limits used when the server configures none
+func DefaultBatchLimits() BatchLimits {
+	return BatchLimits{
+		DefaultTTL:           30 * time.Minute,
+		MaxTTL:               24 * time.Hour,
+		MaxOpenSessions:      1000,
+		MaxRecordsPerSession: 1000000,
+		ExpiredRetention:     15 * time.Minute,
+		SweepInterval:        time.Minute,
+	}
+}
+
+// Validate checks that the limits are usable
+func (l BatchLimits) Validate() error {
+	switch {
+	case l.DefaultTTL <= 0:
+		return fmt.Errorf("default batch ttl must be positive, got %s", l.DefaultTTL)
+	case l.MaxTTL < l.DefaultTTL:
+		return fmt.Errorf("max batch ttl %s is shorter than the default %s", l.MaxTTL, l.DefaultTTL)
+	case l.MaxOpenSessions < 0:
+		return fmt.Errorf("max open batch sessions must not be negative, got %d", l.MaxOpenSessions)
+	case l.MaxRecordsPerSession < 0:
+		return fmt.Errorf("max records per batch session must not be negative, got %d", l.MaxRecordsPerSession)
+	case l.ExpiredRetention < 0:
+		return fmt.Errorf("expired batch retention must not be negative, got %s", l.ExpiredRetention)
+	case l.SweepInterval <= 0:
+		return fmt.Errorf("batch sweep interval must be positive, got %s", l.SweepInterval)
+	}
+	return nil
+}
+
+// SessionTTL resolves a requested TTL: zero selects DefaultTTL and anything
+// above MaxTTL is rejected with ErrInvalidBatchTTL
+func (l BatchLimits) SessionTTL(requested time.Duration) (time.Duration, error) {
+	switch {
+	case requested == 0:
+		return l.DefaultTTL, nil
+	case requested < 0:
+		return 0, fmt.Errorf("%w: %s is negative", ErrInvalidBatchTTL, requested)
+	case requested > l.MaxTTL:
+		return 0, fmt.Errorf("%w: %s exceeds the maximum of %s", ErrInvalidBatchTTL, requested, l.MaxTTL)
+	}
+	return requested, nil
+}
+
+// checkChunk rejects a chunk of records for an expired or completed session,
+// or one that would exceed the record limit
+func (l BatchLimits) checkChunk(state *BatchSessionState, records int, now time.Time) error {
+	if state.ExpiredAt != nil || (!state.IsFinal && now.After(state.expiresAt())) {
+		return fmt.Errorf("%w: %s", ErrBatchExpired, state.BatchID)
+	}
+	if state.IsFinal {
+		return fmt.Errorf("%w: %s", ErrBatchCompleted, state.BatchID)
+	}
+	if l.MaxRecordsPerSession > 0 && state.TotalRecords+records > l.MaxRecordsPerSession {
+		return fmt.Errorf("%w: batch session '%s' holds %d records, limit is %d", ErrBatchTooManyRecords, state.BatchID, state.TotalRecords, l.MaxRecordsPerSession)
+	}
+	return nil
+}
+
+// expiresAt returns when an open session expires if it receives no more chunks.
+// Sessions stored before TTLs were recorded use the original 30 minute expiry.
+func (state *BatchSessionState) expiresAt() time.Time {
+	ttl := state.TTL
+	if ttl <= 0 {
+		ttl = 30 * time.Minute
+	}
+	return state.LastUpdated.Add(ttl)
+}
+
+// ExpiresAt returns when the session expires if it receives no more chunks
+func (bs *BatchSession) ExpiresAt() time.Time {
+	bs.mutex.RLock()
+	defer bs.mutex.RUnlock()
+	return bs.expiresAt()
+}
+
+// SetLimits replaces the manager's batch limits
+func (bsm *BatchSessionManager) SetLimits(limits BatchLimits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	bsm.mutex.Lock()
+	defer bsm.mutex.Unlock()
+	bsm.limits = limits
+	return nil
+}
+
+// Limits returns the manager's batch limits
+func (bsm *BatchSessionManager) Limits() BatchLimits {
+	bsm.mutex.RLock()
+	defer bsm.mutex.RUnlock()
+	return bsm.limits
+}
+
+// AdmitChunk reports whether a chunk of records may still be added to session.
+// RecordBatchChunk repeats the check atomically; this lets callers refuse a
+// chunk before spending time validating it.
+func (bsm *BatchSessionManager) AdmitChunk(session *BatchSession, records int) error {
+	session.mutex.RLock()
+	defer session.mutex.RUnlock()
+	return bsm.Limits().checkChunk(&session.BatchSessionState, records, time.Now())
+}
+
+// OpenSessionCount counts the sessions that are neither completed nor expired
+func (bsm *BatchSessionManager) OpenSessionCount() (int, error) {
+	store := bsm.Store()
+	batchIDs, err := store.List()
+	if err != nil {
+		return 0, err
+	}
+
+	open := 0
+	for _, batchID := range batchIDs {
+		session, err := store.Get(batchID)
+		if errors.Is(err, ErrBatchNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		session.mutex.RLock()
+		if !session.IsFinal && session.ExpiredAt == nil {
+			open++
+		}
+		session.mutex.RUnlock()
+	}
+	return open, nil
+}
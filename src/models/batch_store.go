@@ -0,0 +1,299 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// Batch store backends accepted by NewBatchStore
+const (
+	BatchStoreMemory = "memory"
+	BatchStoreFile   = "file"
+)
+
+var (
+	// ErrBatchNotFound is returned when a batch session does not exist
+	ErrBatchNotFound = errors.New("batch session not found")
+	// ErrBatchExists is returned when creating a batch session whose ID is taken
+	ErrBatchExists = errors.New("batch session already exists")
+	// ErrInvalidCursor is returned when a results cursor was not issued by the store
+	ErrInvalidCursor = errors.New("invalid results cursor")
+	// ErrBatchModelMismatch is returned when a chunk targets a different model than its session
+	ErrBatchModelMismatch = errors.New("batch session model mismatch")
+)
+
+// BatchRowResult is a failed row retained by a batch session
+type BatchRowResult struct {
+	BatchRowIndex int `json:"batch_row_index"` // Position of the row across all chunks of the batch
+	RowValidationResult
+}
+
+// BatchResultFilter selects retained rows by error code and/or field
+type BatchResultFilter struct {
+	Code  string
+	Field string
+}
+
+// Matches reports whether any error of the row satisfies every set filter field
+func (f BatchResultFilter) Matches(row BatchRowResult) bool {
+	if f.Code == "" && f.Field == "" {
+		return true
+	}
+	for _, validationError := range row.Errors {
+		if (f.Code == "" || validationError.Code == f.Code) && (f.Field == "" || validationError.Field == f.Field) {
+			return true
+		}
+	}
+	return false
+}
+
+// BatchResultPage is one page of retained rows
+type BatchResultPage struct {
+	Results    []BatchRowResult `json:"results"`
+	NextCursor string           `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// BatchStore persists batch sessions for BatchSessionManager.
+// Update, UpdateWithResults and DeleteIf must be atomic with respect to every
+// other call on the same session, including calls made by other processes
+// sharing the store.
+type BatchStore interface {
+	// Create stores a new session and fails with ErrBatchExists if its ID is taken
+	Create(session *BatchSession) error
+	// Get returns the session with the given ID, or ErrBatchNotFound
+	Get(batchID string) (*BatchSession, error)
+	// Update applies fn to the stored session and saves the result; if fn
+	// returns an error nothing is saved. fn may only change the session's
+	// progress, not the fields it was created with.
+	Update(batchID string, fn func(session *BatchSession) error) (*BatchSession, error)
+	// UpdateWithResults applies fn as Update does and appends the rows fn
+	// returns to the session's result log; either both are saved or neither is
+	UpdateWithResults(batchID string, fn func(session *BatchSession) ([]BatchRowResult, error)) (*BatchSession, error)
+	// DeleteIf removes the session if cond reports true and tells whether it did
+	DeleteIf(batchID string, cond func(session *BatchSession) bool) (bool, error)
+	// List returns the IDs of all stored sessions
+	List() ([]string, error)
+	// AppendResults adds failed rows to the session's result log
+	AppendResults(batchID string, rows []BatchRowResult) error
+	// ListResults returns up to limit logged rows matching filter, starting at
+	// cursor ("" for the first page). Cursors are opaque and backend specific.
+	ListResults(batchID, cursor string, limit int, filter BatchResultFilter) (BatchResultPage, error)
+	// Ping reports whether the store can currently be read and written
+	Ping() error
+	// Close releases any resources held by the store
+	Close() error
+}
+
+// NewBatchStore opens the batch store backend named by kind ("memory" or "file").
+// dir is only used by the file backend and holds one JSON file per session.
+func NewBatchStore(kind, dir string) (BatchStore, error) {
+	switch kind {
+	case "", BatchStoreMemory:
+		return NewMemoryBatchStore(), nil
+	case BatchStoreFile:
+		return NewFileBatchStore(dir)
+	default:
+		return nil, fmt.Errorf("unknown batch store %q (want %q or %q)", kind, BatchStoreMemory, BatchStoreFile)
+	}
+}
+
+// MemoryBatchStore keeps sessions in a process-local map. Sessions it returns
+// are the stored instances, so they reflect later updates; Update changes
+// their progress under the session lock.
+type MemoryBatchStore struct {
+	sessions map[string]*BatchSession
+	results  map[string][]BatchRowResult
+	mutex    sync.RWMutex
+}
+
+// NewMemoryBatchStore creates an empty in-memory batch store
+func NewMemoryBatchStore() *MemoryBatchStore {
+	return &MemoryBatchStore{
+		sessions: make(map[string]*BatchSession),
+		results:  make(map[string][]BatchRowResult),
+	}
+}
+
+// Create stores a new session
+func (ms *MemoryBatchStore) Create(session *BatchSession) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	if _, exists := ms.sessions[session.BatchID]; exists {
+		return fmt.Errorf("%w: %s", ErrBatchExists, session.BatchID)
+	}
+	ms.sessions[session.BatchID] = session
+	return nil
+}
+
+// Get returns the stored session
+func (ms *MemoryBatchStore) Get(batchID string) (*BatchSession, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	session, exists := ms.sessions[batchID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrBatchNotFound, batchID)
+	}
+	return session, nil
+}
+
+// Update applies fn under the session lock
+func (ms *MemoryBatchStore) Update(batchID string, fn func(session *BatchSession) error) (*BatchSession, error) {
+	ms.mutex.RLock()
+	session, exists := ms.sessions[batchID]
+	ms.mutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrBatchNotFound, batchID)
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	// Work on a copy so a failing fn leaves the session untouched
+	updated := &BatchSession{BatchSessionState: session.BatchSessionState.clone()}
+	if err := fn(updated); err != nil {
+		return nil, err
+	}
+	session.setProgress(updated.BatchSessionState)
+	return session, nil
+}
+
+// UpdateWithResults applies fn under the session lock and appends its rows
+func (ms *MemoryBatchStore) UpdateWithResults(batchID string, fn func(session *BatchSession) ([]BatchRowResult, error)) (*BatchSession, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	session, exists := ms.sessions[batchID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrBatchNotFound, batchID)
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	updated := &BatchSession{BatchSessionState: session.BatchSessionState.clone()}
+	rows, err := fn(updated)
+	if err != nil {
+		return nil, err
+	}
+	session.setProgress(updated.BatchSessionState)
+	ms.results[batchID] = append(ms.results[batchID], rows...)
+	return session, nil
+}
+
+// DeleteIf removes the session and its results if cond holds
+func (ms *MemoryBatchStore) DeleteIf(batchID string, cond func(session *BatchSession) bool) (bool, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	session, exists := ms.sessions[batchID]
+	if !exists {
+		return false, nil
+	}
+	if cond != nil {
+		session.mutex.RLock()
+		matches := cond(session)
+		session.mutex.RUnlock()
+		if !matches {
+			return false, nil
+		}
+	}
+	delete(ms.sessions, batchID)
+	delete(ms.results, batchID)
+	return true, nil
+}
+
+// List returns the IDs of all sessions
+func (ms *MemoryBatchStore) List() ([]string, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	ids := make([]string, 0, len(ms.sessions))
+	for batchID := range ms.sessions {
+		ids = append(ids, batchID)
+	}
+	return ids, nil
+}
+
+// AppendResults adds rows to the session's result slice
+func (ms *MemoryBatchStore) AppendResults(batchID string, rows []BatchRowResult) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	if _, exists := ms.sessions[batchID]; !exists {
+		return fmt.Errorf("%w: %s", ErrBatchNotFound, batchID)
+	}
+	ms.results[batchID] = append(ms.results[batchID], rows...)
+	return nil
+}
+
+// ListResults pages through the session's result slice; the cursor is a slice index
+func (ms *MemoryBatchStore) ListResults(batchID, cursor string, limit int, filter BatchResultFilter) (BatchResultPage, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	if _, exists := ms.sessions[batchID]; !exists {
+		return BatchResultPage{}, fmt.Errorf("%w: %s", ErrBatchNotFound, batchID)
+	}
+	rows := ms.results[batchID]
+
+	start, err := parseCursor(cursor, int64(len(rows)))
+	if err != nil {
+		return BatchResultPage{}, err
+	}
+
+	page := BatchResultPage{Results: []BatchRowResult{}}
+	for i := int(start); i < len(rows); i++ {
+		if !filter.Matches(rows[i]) {
+			continue
+		}
+		if len(page.Results) == limit {
+			// Point the cursor at the next match so the last page is never empty
+			page.NextCursor = strconv.Itoa(i)
+			break
+		}
+		page.Results = append(page.Results, rows[i])
+	}
+	return page, nil
+}
+
+// Ping always succeeds for the memory store
+func (ms *MemoryBatchStore) Ping() error {
+	return nil
+}
+
+// Close is a no-op for the memory store
+func (ms *MemoryBatchStore) Close() error {
+	return nil
+}
+
+// parseCursor decodes a results cursor into a position no greater than end
+func parseCursor(cursor string, end int64) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	position, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || position < 0 || position > end {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	return position, nil
+}
+
+// ValidBatchID reports whether a batch ID is safe to use as a file name.
+// IDs are limited to letters, digits, '.', '_' and '-' and may not start with a dot.
+func ValidBatchID(batchID string) bool {
+	if batchID == "" || len(batchID) > 200 || batchID[0] == '.' {
+		return false
+	}
+	for _, r := range batchID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,355 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// batchFileSuffix is the extension of session files in a FileBatchStore
+	batchFileSuffix = ".json"
+	// batchResultsSuffix is the extension of the NDJSON result log kept next to each session
+	batchResultsSuffix = ".results.ndjson"
+)
+
+// FileBatchStore keeps one JSON file per session in a directory, plus an
+// append-only NDJSON log of the session's failed rows. Every operation holds an
+// flock on the directory's lock file, so replicas that mount the same volume
+// share sessions and never lose each other's counter updates.
+type FileBatchStore struct {
+	dir      string
+	lockFile *os.File
+	mutex    sync.Mutex // flock is held per open file, so goroutines take turns on it
+}
+
+// NewFileBatchStore opens (creating if needed) a file batch store in dir
+func NewFileBatchStore(dir string) (*FileBatchStore, error) {
+	if dir == "" {
+		return nil, errors.New("file batch store requires a directory")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create batch store directory: %w", err)
+	}
+	lockFile, err := os.OpenFile(filepath.Join(dir, ".lock"), os.O_CREATE|os.O_RDWR, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("open batch store lock: %w", err)
+	}
+	return &FileBatchStore{dir: dir, lockFile: lockFile}, nil
+}
+
+// Create writes a new session file
+func (fs *FileBatchStore) Create(session *BatchSession) error {
+	if !ValidBatchID(session.BatchID) {
+		return fmt.Errorf("invalid batch session ID %q", session.BatchID)
+	}
+	return fs.withLock(true, func() error {
+		if _, err := os.Stat(fs.path(session.BatchID)); err == nil {
+			return fmt.Errorf("%w: %s", ErrBatchExists, session.BatchID)
+		}
+		return fs.write(session)
+	})
+}
+
+// Get reads a session file
+func (fs *FileBatchStore) Get(batchID string) (*BatchSession, error) {
+	var session *BatchSession
+	err := fs.withLock(false, func() error {
+		var err error
+		session, err = fs.read(batchID)
+		return err
+	})
+	return session, err
+}
+
+// Update reads, modifies and rewrites a session file under the exclusive lock
+func (fs *FileBatchStore) Update(batchID string, fn func(session *BatchSession) error) (*BatchSession, error) {
+	return fs.UpdateWithResults(batchID, func(session *BatchSession) ([]BatchRowResult, error) {
+		return nil, fn(session)
+	})
+}
+
+// UpdateWithResults appends fn's rows to the result log before rewriting the
+// session file, and truncates the rows away again if the rewrite fails
+func (fs *FileBatchStore) UpdateWithResults(batchID string, fn func(session *BatchSession) ([]BatchRowResult, error)) (*BatchSession, error) {
+	var session *BatchSession
+	err := fs.withLock(true, func() error {
+		var err error
+		if session, err = fs.read(batchID); err != nil {
+			return err
+		}
+		rows, err := fn(session)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return fs.write(session)
+		}
+
+		data, err := encodeResults(rows)
+		if err != nil {
+			return err
+		}
+		size, err := fs.appendResults(batchID, data)
+		if err != nil {
+			return err
+		}
+		if err := fs.write(session); err != nil {
+			if truncateErr := os.Truncate(fs.resultsPath(batchID), size); truncateErr != nil {
+				return errors.Join(err, fmt.Errorf("drop results of failed update: %w", truncateErr))
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// DeleteIf removes a session file if cond holds
+func (fs *FileBatchStore) DeleteIf(batchID string, cond func(session *BatchSession) bool) (bool, error) {
+	deleted := false
+	err := fs.withLock(true, func() error {
+		session, err := fs.read(batchID)
+		if errors.Is(err, ErrBatchNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if cond != nil && !cond(session) {
+			return nil
+		}
+		for _, path := range []string{fs.path(batchID), fs.resultsPath(batchID)} {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
+// List returns the IDs of all session files
+func (fs *FileBatchStore) List() ([]string, error) {
+	var ids []string
+	err := fs.withLock(false, func() error {
+		entries, err := os.ReadDir(fs.dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			batchID, ok := strings.CutSuffix(entry.Name(), batchFileSuffix)
+			if ok && entry.Type().IsRegular() && ValidBatchID(batchID) {
+				ids = append(ids, batchID)
+			}
+		}
+		return nil
+	})
+	return ids, err
+}
+
+// AppendResults appends rows to the session's result log
+func (fs *FileBatchStore) AppendResults(batchID string, rows []BatchRowResult) error {
+	data, err := encodeResults(rows)
+	if err != nil {
+		return err
+	}
+	return fs.withLock(true, func() error {
+		if _, err := fs.read(batchID); err != nil {
+			return err
+		}
+		_, err := fs.appendResults(batchID, data)
+		return err
+	})
+}
+
+// encodeResults encodes rows as NDJSON lines of a result log
+func encodeResults(rows []BatchRowResult) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return nil, fmt.Errorf("encode batch result: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// appendResults appends data to the session's result log and returns the size
+// the log had before; a failed write is truncated away. The caller must hold
+// the exclusive lock.
+func (fs *FileBatchStore) appendResults(batchID string, data []byte) (int64, error) {
+	resultLog, err := os.OpenFile(fs.resultsPath(batchID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return 0, err
+	}
+	info, err := resultLog.Stat()
+	if err != nil {
+		resultLog.Close()
+		return 0, err
+	}
+	if _, err = resultLog.Write(data); err == nil {
+		err = resultLog.Sync()
+	}
+	if err != nil {
+		resultLog.Truncate(info.Size())
+		resultLog.Close()
+		return 0, err
+	}
+	return info.Size(), resultLog.Close()
+}
+
+// ListResults pages through the session's result log; the cursor is a byte offset
+func (fs *FileBatchStore) ListResults(batchID, cursor string, limit int, filter BatchResultFilter) (BatchResultPage, error) {
+	page := BatchResultPage{Results: []BatchRowResult{}}
+	err := fs.withLock(false, func() error {
+		if _, err := fs.read(batchID); err != nil {
+			return err
+		}
+		resultLog, err := os.Open(fs.resultsPath(batchID))
+		if os.IsNotExist(err) {
+			_, err = parseCursor(cursor, 0)
+			return err
+		} else if err != nil {
+			return err
+		}
+		defer resultLog.Close()
+
+		info, err := resultLog.Stat()
+		if err != nil {
+			return err
+		}
+		offset, err := parseCursor(cursor, info.Size())
+		if err != nil {
+			return err
+		}
+		if _, err := resultLog.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+
+		reader := bufio.NewReader(resultLog)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err == io.EOF && len(line) == 0 {
+				return nil
+			} else if err != nil && err != io.EOF {
+				return err
+			}
+
+			var row BatchRowResult
+			if jsonErr := json.Unmarshal(line, &row); jsonErr != nil {
+				// Only a cursor that does not sit on a line boundary lands here
+				return fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+			}
+			if filter.Matches(row) {
+				if len(page.Results) == limit {
+					page.NextCursor = strconv.FormatInt(offset, 10)
+					return nil
+				}
+				page.Results = append(page.Results, row)
+			}
+			offset += int64(len(line))
+		}
+	})
+	if err != nil {
+		return BatchResultPage{}, err
+	}
+	return page, nil
+}
+
+// Ping takes the store lock and checks that the directory is still writable
+func (fs *FileBatchStore) Ping() error {
+	return fs.withLock(false, func() error {
+		probe, err := os.CreateTemp(fs.dir, ".ping-*")
+		if err != nil {
+			return err
+		}
+		probe.Close()
+		return os.Remove(probe.Name())
+	})
+}
+
+// Close releases the lock file
+func (fs *FileBatchStore) Close() error {
+	return fs.lockFile.Close()
+}
+
+// withLock runs fn while holding the store lock, shared or exclusive
+func (fs *FileBatchStore) withLock(exclusive bool, fn func() error) error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	if err := lockFile(fs.lockFile, exclusive); err != nil {
+		return fmt.Errorf("lock batch store: %w", err)
+	}
+	defer unlockFile(fs.lockFile)
+
+	return fn()
+}
+
+// path returns the file holding a session
+func (fs *FileBatchStore) path(batchID string) string {
+	return filepath.Join(fs.dir, batchID+batchFileSuffix)
+}
+
+// resultsPath returns the path of a session's failed-row results log
+func (fs *FileBatchStore) resultsPath(batchID string) string {
+	return filepath.Join(fs.dir, batchID+batchResultsSuffix)
+}
+
+// read loads a session file; the caller must hold the lock
+func (fs *FileBatchStore) read(batchID string) (*BatchSession, error) {
+	if !ValidBatchID(batchID) {
+		return nil, fmt.Errorf("%w: %s", ErrBatchNotFound, batchID)
+	}
+	data, err := os.ReadFile(fs.path(batchID))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrBatchNotFound, batchID)
+	} else if err != nil {
+		return nil, err
+	}
+
+	session := &BatchSession{}
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, fmt.Errorf("decode batch session %s: %w", batchID, err)
+	}
+	return session, nil
+}
+
+// write replaces a session file atomically; the caller must hold the exclusive lock
+func (fs *FileBatchStore) write(session *BatchSession) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("encode batch session %s: %w", session.BatchID, err)
+	}
+
+	tmp, err := os.CreateTemp(fs.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once the rename succeeded
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fs.path(session.BatchID))
+}
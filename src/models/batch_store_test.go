@@ -0,0 +1,799 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchStoreBackends returns a fresh store of every backend
+func batchStoreBackends(t *testing.T) map[string]BatchStore {
+	t.Helper()
+	fileStore, err := NewFileBatchStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBatchStore failed: %v", err)
+	}
+	t.Cleanup(func() { fileStore.Close() })
+
+	return map[string]BatchStore{
+		BatchStoreMemory: NewMemoryBatchStore(),
+		BatchStoreFile:   fileStore,
+	}
+}
+
+func newTestSession(batchID string) *BatchSession {
+	threshold := 80.0
+	return &BatchSession{BatchSessionState: BatchSessionState{
+		BatchID:     batchID,
+		Threshold:   &threshold,
+		StartedAt:   time.Now(),
+		LastUpdated: time.Now(),
+	}}
+}
+
+func TestBatchStore_Lifecycle(t *testing.T) {
+	for name, store := range batchStoreBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.Create(newTestSession("batch-a")); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			if err := store.Create(newTestSession("batch-a")); !errors.Is(err, ErrBatchExists) {
+				t.Errorf("Expected ErrBatchExists, got %v", err)
+			}
+
+			updated, err := store.Update("batch-a", func(session *BatchSession) error {
+				session.ValidRecords += 3
+				session.TotalRecords += 3
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Update failed: %v", err)
+			}
+			if updated.ValidRecords != 3 {
+				t.Errorf("Update returned ValidRecords = %d, want 3", updated.ValidRecords)
+			}
+
+			// A failing update must not be saved
+			if _, err := store.Update("batch-a", func(session *BatchSession) error {
+				session.ValidRecords = 100
+				return errors.New("rejected")
+			}); err == nil {
+				t.Error("Expected error from failing update")
+			}
+
+			session, err := store.Get("batch-a")
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			if session.ValidRecords != 3 || session.TotalRecords != 3 {
+				t.Errorf("valid/total = %d/%d, want 3/3", session.ValidRecords, session.TotalRecords)
+			}
+			if session.Threshold == nil || *session.Threshold != 80.0 {
+				t.Errorf("Threshold = %v, want 80", session.Threshold)
+			}
+
+			if err := store.Create(newTestSession("batch-b")); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			ids, err := store.List()
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			sort.Strings(ids)
+			if len(ids) != 2 || ids[0] != "batch-a" || ids[1] != "batch-b" {
+				t.Errorf("List = %v, want [batch-a batch-b]", ids)
+			}
+
+			deleted, err := store.DeleteIf("batch-a", func(session *BatchSession) bool { return session.ValidRecords > 10 })
+			if err != nil || deleted {
+				t.Errorf("DeleteIf with false condition: deleted=%v err=%v", deleted, err)
+			}
+			deleted, err = store.DeleteIf("batch-a", nil)
+			if err != nil || !deleted {
+				t.Errorf("DeleteIf: deleted=%v err=%v", deleted, err)
+			}
+			if _, err := store.Get("batch-a"); !errors.Is(err, ErrBatchNotFound) {
+				t.Errorf("Expected ErrBatchNotFound after delete, got %v", err)
+			}
+			if _, err := store.Update("batch-a", func(*BatchSession) error { return nil }); !errors.Is(err, ErrBatchNotFound) {
+				t.Errorf("Expected ErrBatchNotFound from Update, got %v", err)
+			}
+			if deleted, err := store.DeleteIf("batch-a", nil); err != nil || deleted {
+				t.Errorf("DeleteIf on missing session: deleted=%v err=%v", deleted, err)
+			}
+		})
+	}
+}
+
+func TestBatchStore_ConcurrentUpdates(t *testing.T) {
+	for name, store := range batchStoreBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.Create(newTestSession("batch-concurrent")); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := store.Update("batch-concurrent", func(session *BatchSession) error {
+						session.ValidRecords++
+						return nil
+					}); err != nil {
+						t.Errorf("Update failed: %v", err)
+					}
+					// Readers do not race with the updates (run with -race)
+					if session, err := store.Get("batch-concurrent"); err == nil && session.State().ValidRecords > 50 {
+						t.Errorf("%s saw %d valid records", session.BatchID, session.State().ValidRecords)
+					}
+				}()
+			}
+			wg.Wait()
+
+			session, err := store.Get("batch-concurrent")
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			if session.ValidRecords != 50 {
+				t.Errorf("ValidRecords = %d, want 50", session.ValidRecords)
+			}
+		})
+	}
+}
+
+func TestFileBatchStore_SharedDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	// Two stores on one directory stand in for two replicas sharing a volume;
+	// each holds its own lock file descriptor, so flock arbitrates between them
+	replicas := make([]*FileBatchStore, 2)
+	for i := range replicas {
+		store, err := NewFileBatchStore(dir)
+		if err != nil {
+			t.Fatalf("NewFileBatchStore failed: %v", err)
+		}
+		defer store.Close()
+		replicas[i] = store
+	}
+
+	if err := replicas[0].Create(newTestSession("batch-shared")); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(store *FileBatchStore) {
+			defer wg.Done()
+			if _, err := store.Update("batch-shared", func(session *BatchSession) error {
+				session.InvalidRecords++
+				return nil
+			}); err != nil {
+				t.Errorf("Update failed: %v", err)
+			}
+		}(replicas[i%2])
+	}
+	wg.Wait()
+
+	session, err := replicas[1].Get("batch-shared")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if session.InvalidRecords != 40 {
+		t.Errorf("InvalidRecords = %d, want 40", session.InvalidRecords)
+	}
+
+	// A restarted process sees the same session
+	replicas[0].Close()
+	reopened, err := NewFileBatchStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileBatchStore failed: %v", err)
+	}
+	defer reopened.Close()
+	if session, err := reopened.Get("batch-shared"); err != nil || session.InvalidRecords != 40 {
+		t.Errorf("After reopen: session=%+v err=%v", session, err)
+	}
+}
+
+func TestFileBatchStore_RejectsUnsafeIDs(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileBatchStore(filepath.Join(dir, "batches"))
+	if err != nil {
+		t.Fatalf("NewFileBatchStore failed: %v", err)
+	}
+	defer store.Close()
+
+	for _, batchID := range []string{"", "../escape", "a/b", ".hidden", "white space"} {
+		if err := store.Create(newTestSession(batchID)); err == nil {
+			t.Errorf("Expected Create(%q) to fail", batchID)
+		}
+		if _, err := store.Get(batchID); !errors.Is(err, ErrBatchNotFound) {
+			t.Errorf("Expected ErrBatchNotFound for Get(%q), got %v", batchID, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "escape.json")); !os.IsNotExist(err) {
+		t.Errorf("Session file escaped the store directory")
+	}
+}
+
+func TestNewBatchStore(t *testing.T) {
+	if store, err := NewBatchStore("", ""); err != nil {
+		t.Errorf("Default store failed: %v", err)
+	} else if _, ok := store.(*MemoryBatchStore); !ok {
+		t.Errorf("Default store is %T, want *MemoryBatchStore", store)
+	}
+
+	store, err := NewBatchStore(BatchStoreFile, t.TempDir())
+	if err != nil {
+		t.Fatalf("File store failed: %v", err)
+	}
+	store.Close()
+
+	if _, err := NewBatchStore(BatchStoreFile, ""); err == nil {
+		t.Error("Expected error for file store without directory")
+	}
+	if _, err := NewBatchStore("bolt", ""); err == nil {
+		t.Error("Expected error for unknown store")
+	}
+}
+
+func TestBatchSessionManager_FileStore(t *testing.T) {
+	store, err := NewFileBatchStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBatchStore failed: %v", err)
+	}
+	manager := NewBatchSessionManager(store)
+	defer manager.Store().Close()
+
+	threshold := 90.0
+	if _, err := manager.CreateBatchSession("batch-file", &threshold); err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	if err := manager.UpdateBatchSession("batch-file", 95, 5, 1); err != nil {
+		t.Fatalf("UpdateBatchSession failed: %v", err)
+	}
+	status, err := manager.FinalizeBatchSession("batch-file")
+	if err != nil || status != "success" {
+		t.Fatalf("FinalizeBatchSession = %s, %v; want success", status, err)
+	}
+
+	session, exists := manager.GetBatchSession("batch-file")
+	if !exists || !session.IsFinal || session.TotalRecords != 100 || session.WarningRecords != 1 {
+		t.Errorf("Unexpected session: %+v", session)
+	}
+	if session.GetStatus()["status"] != "success" {
+		t.Errorf("GetStatus = %v", session.GetStatus())
+	}
+
+	if err := manager.UpdateBatchSession("missing", 1, 0, 0); !errors.Is(err, ErrBatchNotFound) {
+		t.Errorf("Expected ErrBatchNotFound, got %v", err)
+	}
+}
+
+func TestBatchSessionManager_SetStore(t *testing.T) {
+	previous := NewMemoryBatchStore()
+	manager := NewBatchSessionManager(previous)
+
+	dir := t.TempDir()
+	broken, err := NewFileBatchStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileBatchStore failed: %v", err)
+	}
+	defer broken.Close()
+	os.RemoveAll(dir)
+	if err := manager.SetStore(broken); err == nil {
+		t.Error("Expected SetStore to reject a store that fails Ping")
+	}
+	if manager.Store() != previous {
+		t.Error("Expected the previous store to stay in use")
+	}
+
+	working := NewMemoryBatchStore()
+	if err := manager.SetStore(working); err != nil || manager.Store() != working {
+		t.Errorf("SetStore(working) = %v, store switched: %v", err, manager.Store() == working)
+	}
+}
+
+func TestBatchSessionManager_CleanupExpiredBatches(t *testing.T) {
+	manager := NewBatchSessionManager(NewMemoryBatchStore())
+	if _, err := manager.CreateBatchSession("batch-fresh", nil); err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	stale, err := manager.CreateBatchSession("batch-stale", nil)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	stale.LastUpdated = time.Now().Add(-time.Hour)
+	completed, err := manager.CreateBatchSession("batch-completed", nil)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	completed.IsFinal = true
+	completed.LastUpdated = time.Now().Add(-time.Hour)
+
+	if err := manager.CleanupExpiredBatches(); err != nil {
+		t.Fatalf("CleanupExpiredBatches failed: %v", err)
+	}
+	session, exists := manager.GetBatchSession("batch-stale")
+	if !exists {
+		t.Fatal("Expected stale session to stay visible as expired")
+	}
+	if status := session.GetStatus(); status["status"] != "expired" || status["expired_at"] == nil {
+		t.Errorf("Expected expired status, got %v", status)
+	}
+	if _, exists := manager.GetBatchSession("batch-completed"); exists {
+		t.Error("Expected completed session past its TTL to be removed")
+	}
+	if _, exists := manager.GetBatchSession("batch-fresh"); !exists {
+		t.Error("Expected fresh session to be kept")
+	}
+
+	if _, _, err := manager.RecordBatchChunk("batch-stale", "", &ArrayValidationResult{ValidRecords: 1}); !errors.Is(err, ErrBatchExpired) {
+		t.Errorf("Expected ErrBatchExpired for a chunk, got %v", err)
+	}
+	if _, err := manager.FinalizeBatchSession("batch-stale"); !errors.Is(err, ErrBatchExpired) {
+		t.Errorf("Expected ErrBatchExpired on finalize, got %v", err)
+	}
+
+	// The expired session is removed once the retention period has passed
+	expiredAt := time.Now().Add(-DefaultBatchLimits().ExpiredRetention)
+	stale.ExpiredAt = &expiredAt
+	if err := manager.CleanupExpiredBatches(); err != nil {
+		t.Fatalf("CleanupExpiredBatches failed: %v", err)
+	}
+	if _, exists := manager.GetBatchSession("batch-stale"); exists {
+		t.Error("Expected expired session to be removed after retention")
+	}
+}
+
+func TestBatchSessionManager_Limits(t *testing.T) {
+	manager := NewBatchSessionManager(NewMemoryBatchStore())
+	limits := DefaultBatchLimits()
+	limits.MaxTTL = time.Hour
+	limits.MaxOpenSessions = 2
+	limits.MaxRecordsPerSession = 10
+	if err := manager.SetLimits(limits); err != nil {
+		t.Fatalf("SetLimits failed: %v", err)
+	}
+
+	if _, err := manager.CreateModelBatchSession("batch-long", "", "", nil, 2*time.Hour); !errors.Is(err, ErrInvalidBatchTTL) {
+		t.Errorf("Expected ErrInvalidBatchTTL for a TTL above the maximum, got %v", err)
+	}
+	short, err := manager.CreateModelBatchSession("batch-short", "", "", nil, time.Minute)
+	if err != nil {
+		t.Fatalf("CreateModelBatchSession failed: %v", err)
+	}
+	if short.TTL != time.Minute || !short.ExpiresAt().Equal(short.LastUpdated.Add(time.Minute)) {
+		t.Errorf("TTL = %s, expires_at = %v", short.TTL, short.ExpiresAt())
+	}
+	if _, err := manager.CreateBatchSession("batch-second", nil); err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	if _, err := manager.CreateBatchSession("batch-third", nil); !errors.Is(err, ErrBatchLimitExceeded) {
+		t.Errorf("Expected ErrBatchLimitExceeded, got %v", err)
+	}
+
+	// Completed sessions no longer count as open
+	if _, err := manager.FinalizeBatchSession("batch-second"); err != nil {
+		t.Fatalf("FinalizeBatchSession failed: %v", err)
+	}
+	if _, err := manager.CreateBatchSession("batch-third", nil); err != nil {
+		t.Errorf("Expected a free slot after completion, got %v", err)
+	}
+
+	if _, _, err := manager.RecordBatchChunk("batch-short", "", &ArrayValidationResult{ValidRecords: 8}); err != nil {
+		t.Fatalf("RecordBatchChunk failed: %v", err)
+	}
+	if _, _, err := manager.RecordBatchChunk("batch-short", "", &ArrayValidationResult{ValidRecords: 3}); !errors.Is(err, ErrBatchTooManyRecords) {
+		t.Errorf("Expected ErrBatchTooManyRecords, got %v", err)
+	}
+	if err := manager.AdmitChunk(short, 2); err != nil {
+		t.Errorf("AdmitChunk within the limit failed: %v", err)
+	}
+
+	if err := manager.SetLimits(BatchLimits{}); err == nil {
+		t.Error("Expected SetLimits to reject zero limits")
+	}
+}
+
+func TestBatchSessionManager_AbortBatchSession(t *testing.T) {
+	manager := NewBatchSessionManager(NewMemoryBatchStore())
+	if _, err := manager.CreateBatchSession("batch-abort", nil); err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+	if err := manager.AbortBatchSession("batch-abort"); err != nil {
+		t.Fatalf("AbortBatchSession failed: %v", err)
+	}
+	if err := manager.AbortBatchSession("batch-abort"); !errors.Is(err, ErrBatchNotFound) {
+		t.Errorf("Expected ErrBatchNotFound on second abort, got %v", err)
+	}
+}
+
+// failedRow builds a retained row with one error
+func failedRow(index int, code, field string) BatchRowResult {
+	return BatchRowResult{
+		BatchRowIndex: index,
+		RowValidationResult: RowValidationResult{
+			RowIndex:         index,
+			RecordIdentifier: "rec-" + strconv.Itoa(index),
+			Errors:           []ValidationError{{Field: field, Code: code, Message: "bad"}},
+		},
+	}
+}
+
+func TestBatchStore_Results(t *testing.T) {
+	for name, store := range batchStoreBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.AppendResults("missing", []BatchRowResult{failedRow(0, "X", "f")}); !errors.Is(err, ErrBatchNotFound) {
+				t.Errorf("Expected ErrBatchNotFound, got %v", err)
+			}
+			if err := store.Create(newTestSession("batch-results")); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+
+			page, err := store.ListResults("batch-results", "", 10, BatchResultFilter{})
+			if err != nil || len(page.Results) != 0 || page.NextCursor != "" {
+				t.Fatalf("Empty log: page=%+v err=%v", page, err)
+			}
+
+			var rows []BatchRowResult
+			for i := 0; i < 7; i++ {
+				code := "REQUIRED_FIELD_MISSING"
+				if i%2 == 1 {
+					code = "INVALID_FORMAT"
+				}
+				rows = append(rows, failedRow(i, code, "field"+strconv.Itoa(i%3)))
+			}
+			if err := store.AppendResults("batch-results", rows[:4]); err != nil {
+				t.Fatalf("AppendResults failed: %v", err)
+			}
+			if err := store.AppendResults("batch-results", rows[4:]); err != nil {
+				t.Fatalf("AppendResults failed: %v", err)
+			}
+
+			// Page through everything three rows at a time
+			var seen []int
+			cursor := ""
+			for pages := 1; ; pages++ {
+				page, err := store.ListResults("batch-results", cursor, 3, BatchResultFilter{})
+				if err != nil {
+					t.Fatalf("ListResults failed: %v", err)
+				}
+				for _, row := range page.Results {
+					seen = append(seen, row.BatchRowIndex)
+				}
+				if page.NextCursor == "" {
+					if pages != 3 {
+						t.Errorf("Got %d pages, want 3", pages)
+					}
+					break
+				}
+				cursor = page.NextCursor
+			}
+			if len(seen) != 7 {
+				t.Fatalf("Saw rows %v, want 0..6", seen)
+			}
+			for i, index := range seen {
+				if index != i {
+					t.Errorf("Row %d has index %d", i, index)
+				}
+			}
+
+			// Filtered pages never end with an empty page
+			page, err = store.ListResults("batch-results", "", 3, BatchResultFilter{Code: "INVALID_FORMAT"})
+			if err != nil || len(page.Results) != 3 || page.NextCursor != "" {
+				t.Errorf("Code filter: %d results, cursor %q, err %v", len(page.Results), page.NextCursor, err)
+			}
+			page, err = store.ListResults("batch-results", "", 10, BatchResultFilter{Code: "REQUIRED_FIELD_MISSING", Field: "field0"})
+			if err != nil || len(page.Results) != 2 || page.Results[0].BatchRowIndex != 0 || page.Results[1].BatchRowIndex != 6 {
+				t.Errorf("Code+field filter: %+v err %v", page.Results, err)
+			}
+
+			for _, cursor := range []string{"abc", "-1", "99999"} {
+				if _, err := store.ListResults("batch-results", cursor, 3, BatchResultFilter{}); !errors.Is(err, ErrInvalidCursor) {
+					t.Errorf("Cursor %q: expected ErrInvalidCursor, got %v", cursor, err)
+				}
+			}
+
+			if _, err := store.DeleteIf("batch-results", nil); err != nil {
+				t.Fatalf("DeleteIf failed: %v", err)
+			}
+			if _, err := store.ListResults("batch-results", "", 3, BatchResultFilter{}); !errors.Is(err, ErrBatchNotFound) {
+				t.Errorf("Expected ErrBatchNotFound after delete, got %v", err)
+			}
+			if err := store.Create(newTestSession("batch-results")); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			if page, err := store.ListResults("batch-results", "", 10, BatchResultFilter{}); err != nil || len(page.Results) != 0 {
+				t.Errorf("Recreated session inherited results: %+v err %v", page, err)
+			}
+		})
+	}
+}
+
+func TestBatchSessionManager_RecordBatchChunk(t *testing.T) {
+	for name, store := range batchStoreBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			manager := NewBatchSessionManager(store)
+			if _, err := manager.CreateBatchSession("batch-chunks", nil); err != nil {
+				t.Fatalf("CreateBatchSession failed: %v", err)
+			}
+
+			chunk := func(valid int, invalidRows ...int) *ArrayValidationResult {
+				result := &ArrayValidationResult{ValidRecords: valid, InvalidRecords: len(invalidRows)}
+				for _, rowIndex := range invalidRows {
+					result.Results = append(result.Results, RowValidationResult{RowIndex: rowIndex, RecordIdentifier: "r" + strconv.Itoa(rowIndex)})
+				}
+				return result
+			}
+
+			if _, _, err := manager.RecordBatchChunk("batch-chunks", "", chunk(3, 1, 4)); err != nil {
+				t.Fatalf("RecordBatchChunk failed: %v", err)
+			}
+			recorded, _, err := manager.RecordBatchChunk("batch-chunks", "", chunk(2, 0))
+			if err != nil {
+				t.Fatalf("RecordBatchChunk failed: %v", err)
+			}
+			if recorded.SessionTotal != 8 {
+				t.Errorf("SessionTotal = %d, want 8", recorded.SessionTotal)
+			}
+			session, _ := manager.GetBatchSession("batch-chunks")
+			if session.TotalRecords != 8 || session.InvalidRecords != 3 {
+				t.Errorf("total/invalid = %d/%d, want 8/3", session.TotalRecords, session.InvalidRecords)
+			}
+
+			page, err := manager.ListBatchResults("batch-chunks", "", 10, BatchResultFilter{})
+			if err != nil {
+				t.Fatalf("ListBatchResults failed: %v", err)
+			}
+			want := []int{1, 4, 5}
+			if len(page.Results) != len(want) {
+				t.Fatalf("Got %d results, want %d", len(page.Results), len(want))
+			}
+			for i, row := range page.Results {
+				if row.BatchRowIndex != want[i] {
+					t.Errorf("Result %d: batch_row_index %d, want %d", i, row.BatchRowIndex, want[i])
+				}
+			}
+
+			if _, _, err := manager.RecordBatchChunk("missing", "", chunk(1)); !errors.Is(err, ErrBatchNotFound) {
+				t.Errorf("Expected ErrBatchNotFound, got %v", err)
+			}
+			if _, _, err := manager.RecordBatchChunk("batch-chunks", "2000000000", chunk(1)); !errors.Is(err, ErrInvalidChunkID) {
+				t.Errorf("Expected ErrInvalidChunkID for a huge sequence number, got %v", err)
+			}
+		})
+	}
+}
+
+// TestBatchSessionManager_ChunkReplay tests that a repeated chunk ID leaves totals unchanged
+func TestBatchSessionManager_ChunkReplay(t *testing.T) {
+	for name, store := range batchStoreBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			manager := NewBatchSessionManager(store)
+			if _, err := manager.CreateBatchSession("batch-replay", nil); err != nil {
+				t.Fatalf("CreateBatchSession failed: %v", err)
+			}
+
+			result := &ArrayValidationResult{ValidRecords: 2, InvalidRecords: 1, Results: []RowValidationResult{{RowIndex: 1}}}
+			first, replayed, err := manager.RecordBatchChunk("batch-replay", "1", result)
+			if err != nil || replayed {
+				t.Fatalf("First RecordBatchChunk: replayed=%v err=%v", replayed, err)
+			}
+			second, replayed, err := manager.RecordBatchChunk("batch-replay", "1", result)
+			if err != nil || !replayed {
+				t.Fatalf("Second RecordBatchChunk: replayed=%v err=%v", replayed, err)
+			}
+			if second.SessionTotal != first.SessionTotal || second.Records != 3 {
+				t.Errorf("Replayed chunk %+v differs from original %+v", second, first)
+			}
+
+			session, _ := manager.GetBatchSession("batch-replay")
+			if session.TotalRecords != 3 || session.InvalidRecords != 1 {
+				t.Errorf("total/invalid = %d/%d, want 3/1", session.TotalRecords, session.InvalidRecords)
+			}
+			if chunk, found := session.FindChunk("1"); !found || chunk.Sequence == nil || *chunk.Sequence != 1 {
+				t.Errorf("FindChunk(1) = %+v, %v", chunk, found)
+			}
+			page, err := manager.ListBatchResults("batch-replay", "", 10, BatchResultFilter{})
+			if err != nil || len(page.Results) != 1 {
+				t.Errorf("Expected 1 retained row after replay, got %d (%v)", len(page.Results), err)
+			}
+
+			// After completion a retry is still answered, but new chunks are refused
+			if _, err := manager.FinalizeBatchSession("batch-replay"); err != nil {
+				t.Fatalf("FinalizeBatchSession failed: %v", err)
+			}
+			if _, replayed, err := manager.RecordBatchChunk("batch-replay", "1", result); err != nil || !replayed {
+				t.Errorf("Retry after completion: replayed=%v err=%v", replayed, err)
+			}
+			if _, _, err := manager.RecordBatchChunk("batch-replay", "2", result); !errors.Is(err, ErrBatchCompleted) {
+				t.Errorf("Expected ErrBatchCompleted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBatchStore_UpdateWithResults(t *testing.T) {
+	for name, store := range batchStoreBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.Create(newTestSession("batch-atomic")); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+
+			// A failing fn saves neither the counters nor the rows
+			_, err := store.UpdateWithResults("batch-atomic", func(session *BatchSession) ([]BatchRowResult, error) {
+				session.InvalidRecords = 1
+				return []BatchRowResult{failedRow(0, "X", "f")}, errors.New("refused")
+			})
+			if err == nil {
+				t.Fatal("Expected fn's error")
+			}
+			session, _ := store.Get("batch-atomic")
+			page, _ := store.ListResults("batch-atomic", "", 10, BatchResultFilter{})
+			if session.State().InvalidRecords != 0 || len(page.Results) != 0 {
+				t.Errorf("Expected nothing saved, got %d invalid and %d rows", session.State().InvalidRecords, len(page.Results))
+			}
+
+			if _, err := store.UpdateWithResults("batch-atomic", func(session *BatchSession) ([]BatchRowResult, error) {
+				session.InvalidRecords = 1
+				return []BatchRowResult{failedRow(0, "X", "f")}, nil
+			}); err != nil {
+				t.Fatalf("UpdateWithResults failed: %v", err)
+			}
+			session, _ = store.Get("batch-atomic")
+			page, _ = store.ListResults("batch-atomic", "", 10, BatchResultFilter{})
+			if session.State().InvalidRecords != 1 || len(page.Results) != 1 {
+				t.Errorf("Expected 1 invalid and 1 row, got %d and %d", session.State().InvalidRecords, len(page.Results))
+			}
+		})
+	}
+}
+
+// TestBatchSessionManager_RecordBatchChunkStoreFailure tests that a chunk whose
+// rows cannot be written is not counted, so retrying it records it in full
+func TestBatchSessionManager_RecordBatchChunkStoreFailure(t *testing.T) {
+	store, err := NewFileBatchStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBatchStore failed: %v", err)
+	}
+	defer store.Close()
+	manager := NewBatchSessionManager(store)
+	if _, err := manager.CreateBatchSession("batch-full-disk", nil); err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+
+	// A directory in place of the result log makes appending to it fail
+	if err := os.Mkdir(store.resultsPath("batch-full-disk"), 0o750); err != nil {
+		t.Fatalf("Mkdir failed: %v", err)
+	}
+	result := &ArrayValidationResult{ValidRecords: 1, InvalidRecords: 1, Results: []RowValidationResult{{RowIndex: 1}}}
+	if _, _, err := manager.RecordBatchChunk("batch-full-disk", "1", result); err == nil {
+		t.Fatal("Expected RecordBatchChunk to fail")
+	}
+	session, _ := manager.GetBatchSession("batch-full-disk")
+	if state := session.State(); state.TotalRecords != 0 || len(state.Chunks) != 0 {
+		t.Fatalf("Expected failed chunk not to be counted, got %d records and %d chunks", state.TotalRecords, len(state.Chunks))
+	}
+
+	if err := os.Remove(store.resultsPath("batch-full-disk")); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, replayed, err := manager.RecordBatchChunk("batch-full-disk", "1", result); err != nil || replayed {
+		t.Fatalf("Retry: replayed=%v err=%v", replayed, err)
+	}
+	page, err := manager.ListBatchResults("batch-full-disk", "", 10, BatchResultFilter{})
+	if err != nil || len(page.Results) != 1 {
+		t.Errorf("Expected the retried chunk's row, got %d (%v)", len(page.Results), err)
+	}
+}
+
+// TestBatchSessionState_ChunkReport tests gap and ordering detection for chunk sequences
+func TestBatchSessionState_ChunkReport(t *testing.T) {
+	tests := []struct {
+		name           string
+		ids            []string
+		wantMissing    string
+		wantOutOfOrder string
+		wantNonSeq     int
+	}{
+		{"in order from one", []string{"1", "2", "3"}, "[]", "[]", 0},
+		{"in order from zero", []string{"0", "1", "2"}, "[]", "[]", 0},
+		{"gaps", []string{"1", "3", "6"}, "[2 4 5]", "[]", 0},
+		{"out of order", []string{"1", "3", "2", "4"}, "[]", "[2]", 0},
+		{"named chunks", []string{"a", "2", "b"}, "[1]", "[]", 2},
+		{"duplicates", []string{"0", "2", "2", "4"}, "[1 3]", "[]", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var state BatchSessionState
+			for _, id := range tt.ids {
+				state.Chunks = append(state.Chunks, newBatchChunk(id, &ArrayValidationResult{}))
+			}
+			report := state.chunkReport()
+			if report.Received != len(tt.ids) {
+				t.Errorf("Received = %d, want %d", report.Received, len(tt.ids))
+			}
+			if got := fmt.Sprint(report.MissingSequences); got != tt.wantMissing {
+				t.Errorf("MissingSequences = %s, want %s", got, tt.wantMissing)
+			}
+			if got := fmt.Sprint(report.OutOfOrderSequences); got != tt.wantOutOfOrder {
+				t.Errorf("OutOfOrderSequences = %s, want %s", got, tt.wantOutOfOrder)
+			}
+			if report.NonSequentialChunkIDs != tt.wantNonSeq {
+				t.Errorf("NonSequentialChunkIDs = %d, want %d", report.NonSequentialChunkIDs, tt.wantNonSeq)
+			}
+		})
+	}
+
+	if (&BatchSessionState{}).chunkReport() != nil {
+		t.Error("Expected nil report for a session without chunk IDs")
+	}
+
+	// A large sequence number lists only the first gaps
+	state := BatchSessionState{Chunks: []BatchChunk{
+		newBatchChunk("1", &ArrayValidationResult{}),
+		newBatchChunk(strconv.Itoa(MaxChunkSequence), &ArrayValidationResult{}),
+	}}
+	report := state.chunkReport()
+	if len(report.MissingSequences) != maxMissingSequences || !report.MissingTruncated || report.MissingSequences[0] != 2 {
+		t.Errorf("Expected %d missing sequences from 2, truncated; got %d (truncated %v)", maxMissingSequences, len(report.MissingSequences), report.MissingTruncated)
+	}
+}
+
+func TestBatchSessionManager_StopCleanupRoutine(t *testing.T) {
+	manager := NewBatchSessionManager(NewMemoryBatchStore())
+	limits := DefaultBatchLimits()
+	limits.SweepInterval = time.Millisecond
+	if err := manager.SetLimits(limits); err != nil {
+		t.Fatalf("SetLimits failed: %v", err)
+	}
+
+	manager.StartCleanupRoutine()
+	manager.StartCleanupRoutine() // Second start is a no-op
+	time.Sleep(10 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		manager.StopCleanupRoutine()
+		manager.StopCleanupRoutine() // Stopping twice is safe
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("StopCleanupRoutine did not return")
+	}
+}
+
+func TestBatchStore_Ping(t *testing.T) {
+	for name, store := range batchStoreBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.Ping(); err != nil {
+				t.Errorf("Ping failed: %v", err)
+			}
+		})
+	}
+
+	dir := t.TempDir()
+	store, err := NewFileBatchStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileBatchStore failed: %v", err)
+	}
+	defer store.Close()
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("RemoveAll failed: %v", err)
+	}
+	if err := store.Ping(); err == nil {
+		t.Error("Expected Ping to fail once the directory is gone")
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package models
+
+import "os"
+
+// lockFile is a no-op where flock is unavailable; a FileBatchStore is then only
+// safe to use from a single process
+func lockFile(f *os.File, exclusive bool) error {
+	return nil
+}
+
+// unlockFile is a no-op where flock is unavailable
+func unlockFile(f *os.File) error {
+	return nil
+}
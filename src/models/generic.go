@@ -3,6 +3,8 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -33,6 +35,7 @@ type ValidationResult struct {
 	ProcessingDuration time.Duration          `json:"processing_duration,omitempty"`
 	PerformanceMetrics *PerformanceMetrics    `json:"performance_metrics,omitempty"`
 	RequestID          string                 `json:"request_id,omitempty"`
+	TraceID            string                 `json:"trace_id,omitempty"`
 	Context            map[string]interface{} `json:"context,omitempty"`
 }
 
@@ -242,6 +245,18 @@ type BatchValidationResponse struct {
 	Duration     time.Duration      `json:"duration"`
 }
 
+// MaxConcurrencyLimit is the largest BatchOptions.MaxConcurrency a request may
+// ask for. Zero is the default of one worker per CPU.
+const MaxConcurrencyLimit = 100
+
+// MaxConcurrencyMessage describes the accepted values of max_concurrency
+const MaxConcurrencyMessage = "max_concurrency must be between 0 and 100, where 0 uses one worker per CPU"
+
+// ValidMaxConcurrency reports whether n is 0 or between 1 and MaxConcurrencyLimit
+func ValidMaxConcurrency(n int) bool {
+	return n >= 0 && n <= MaxConcurrencyLimit
+}
+
 // BatchOptions represents options for batch validation.
 type BatchOptions struct {
 	StopOnFirstError bool          `json:"stop_on_first_error"`
@@ -251,6 +266,33 @@ type BatchOptions struct {
 	FailFast         bool          `json:"fail_fast"`
 }
 
+// UnmarshalJSON accepts the timeout either as a duration string ("30s") or as nanoseconds.
+func (o *BatchOptions) UnmarshalJSON(data []byte) error {
+	type plain BatchOptions
+	aux := struct {
+		*plain
+		Timeout json.RawMessage `json:"timeout"`
+	}{plain: (*plain)(o)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Timeout) == 0 || string(aux.Timeout) == "null" {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(aux.Timeout, &text); err == nil {
+		timeout, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", text, err)
+		}
+		o.Timeout = timeout
+		return nil
+	}
+	return json.Unmarshal(aux.Timeout, (*int64)(&o.Timeout))
+}
+
 // BatchSummary represents a summary of batch validation results.
 type BatchSummary struct {
 	SuccessRate           float64            `json:"success_rate"`
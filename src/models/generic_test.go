@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBatchOptions_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    BatchOptions
+		wantErr bool
+	}{
+		{"duration string", `{"max_concurrency": 8, "fail_fast": true, "timeout": "1m30s"}`, BatchOptions{MaxConcurrency: 8, FailFast: true, Timeout: 90 * time.Second}, false},
+		{"nanoseconds", `{"timeout": 5000000000, "stop_on_first_error": true}`, BatchOptions{StopOnFirstError: true, Timeout: 5 * time.Second}, false},
+		{"no timeout", `{"include_metrics": true}`, BatchOptions{IncludeMetrics: true}, false},
+		{"null timeout", `{"timeout": null}`, BatchOptions{}, false},
+		{"invalid duration", `{"timeout": "soon"}`, BatchOptions{}, true},
+		{"invalid type", `{"timeout": true}`, BatchOptions{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got BatchOptions
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Unmarshal = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
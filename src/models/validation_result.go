@@ -4,24 +4,54 @@ package models
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"sort"
 	"sync"
 	"time"
 )
 
 // ArrayValidationResult represents the result of validating an array of records
 type ArrayValidationResult struct {
-	BatchID        string                `json:"batch_id"`            // Universal tracking
-	Status         string                `json:"status"`              // "success" or "failed" based on threshold
-	TotalRecords   int                   `json:"total_records"`       // Total number of records
-	ValidRecords   int                   `json:"valid_records"`       // Number of valid records
-	InvalidRecords int                   `json:"invalid_records"`     // Number of invalid records
-	WarningRecords int                   `json:"warning_records"`     // Number of records with warnings only
-	Threshold      *float64              `json:"threshold,omitempty"` // Optional threshold percentage (e.g., 20.0 for 20%)
-	ProcessingTime int64                 `json:"processing_time_ms"`  // Processing time in milliseconds
-	CompletedAt    time.Time             `json:"completed_at"`        // Completion timestamp
-	Summary        ValidationSummary     `json:"summary"`             // Summary of validation
-	Results        []RowValidationResult `json:"results"`             // Individual row results (only invalid/warning rows)
+	BatchID        string                `json:"batch_id"`                  // Universal tracking
+	RequestID      string                `json:"request_id,omitempty"`      // X-Request-ID of the request that was validated
+	TraceID        string                `json:"trace_id,omitempty"`        // W3C trace ID of the validation span
+	Status         string                `json:"status"`                    // "success" or "failed" based on threshold
+	TotalRecords   int                   `json:"total_records"`             // Total number of records
+	ValidRecords   int                   `json:"valid_records"`             // Number of valid records
+	InvalidRecords int                   `json:"invalid_records"`           // Number of invalid records
+	WarningRecords int                   `json:"warning_records"`           // Number of records with warnings only
+	SkippedRecords int                   `json:"skipped_records,omitempty"` // Records not validated after a fail-fast stop
+	Threshold      *float64              `json:"threshold,omitempty"`       // Optional threshold percentage (e.g., 20.0 for 20%)
+	ProcessingTime int64                 `json:"processing_time_ms"`        // Processing time in milliseconds
+	CompletedAt    time.Time             `json:"completed_at"`              // Completion timestamp
+	Summary        ValidationSummary     `json:"summary"`                   // Summary of validation
+	Results        []RowValidationResult `json:"results"`                   // Individual row results (only invalid/warning rows)
+
+	// Validated holds every validated row, valid ones included, for the
+	// JUnit and SARIF reports; it is not part of the JSON result
+	Validated []RowValidationResult `json:"-"`
+}
+
+// StreamValidationSummary is the trailer line of an NDJSON validation stream.
+// It follows the per-row results and carries the threshold decision.
+type StreamValidationSummary struct {
+	Type           string            `json:"type"`                 // Always "summary", tells the trailer apart from row lines
+	BatchID        string            `json:"batch_id"`             // Universal tracking
+	RequestID      string            `json:"request_id,omitempty"` // X-Request-ID of the request that was validated
+	TraceID        string            `json:"trace_id,omitempty"`   // W3C trace ID of the validation span
+	Status         string            `json:"status"`               // "success" or "failed" based on threshold
+	TotalRecords   int               `json:"total_records"`        // Number of records read from the stream
+	ValidRecords   int               `json:"valid_records"`        // Number of valid records
+	InvalidRecords int               `json:"invalid_records"`      // Number of invalid records
+	WarningRecords int               `json:"warning_records"`      // Number of records with warnings only
+	Threshold      *float64          `json:"threshold,omitempty"`  // Optional threshold percentage
+	ProcessingTime int64             `json:"processing_time_ms"`   // Processing time in milliseconds
+	CompletedAt    time.Time         `json:"completed_at"`         // Completion timestamp
+	Summary        ValidationSummary `json:"summary"`              // Summary of validation
+	Error          string            `json:"error,omitempty"`      // Why the stream ended early, if it did
 }
 
 // RowValidationResult represents the validation result for a single row
@@ -56,11 +86,12 @@ func GenerateBatchID(prefix string) string {
 	return fmt.Sprintf("%s_%s", prefix, hex.EncodeToString(bytes))
 }
 
+// idPatterns are the record fields DetectRecordIdentifier checks, in order
+var idPatterns = []string{"id", "ID", "_id", "uuid", "UUID", "identifier", "recordId", "record_id"}
+
 // DetectRecordIdentifier attempts to extract a unique identifier from a record
 func DetectRecordIdentifier(record map[string]interface{}, rowIndex int) string {
 	// Common ID field patterns to check
-	idPatterns := []string{"id", "ID", "_id", "uuid", "UUID", "identifier", "recordId", "record_id"}
-
 	for _, pattern := range idPatterns {
 		if val, ok := record[pattern]; ok && val != nil {
 			return fmt.Sprintf("%v", val)
@@ -71,63 +102,114 @@ func DetectRecordIdentifier(record map[string]interface{}, rowIndex int) string
 	return fmt.Sprintf("row_%d", rowIndex)
 }
 
+// recordIdentifiers holds the fields of idPatterns when decoding a record that
+// is still JSON
+type recordIdentifiers struct {
+	ID         interface{} `json:"id"`
+	UpperID    interface{} `json:"ID"`
+	MongoID    interface{} `json:"_id"`
+	UUID       interface{} `json:"uuid"`
+	UpperUUID  interface{} `json:"UUID"`
+	Identifier interface{} `json:"identifier"`
+	RecordID   interface{} `json:"recordId"`
+	SnakeID    interface{} `json:"record_id"`
+}
+
+// DetectRawRecordIdentifier is DetectRecordIdentifier for a record that is
+// still JSON; only the identifier fields are decoded. Keys that match none of
+// the patterns exactly are matched ignoring case, as encoding/json does.
+func DetectRawRecordIdentifier(record json.RawMessage, rowIndex int) string {
+	var ids recordIdentifiers
+	if err := json.Unmarshal(record, &ids); err == nil {
+		for _, val := range []interface{}{ids.ID, ids.UpperID, ids.MongoID, ids.UUID, ids.UpperUUID, ids.Identifier, ids.RecordID, ids.SnakeID} {
+			if val != nil {
+				return fmt.Sprintf("%v", val)
+			}
+		}
+	}
+	return fmt.Sprintf("row_%d", rowIndex)
+}
+
 // BuildSummary builds a ValidationSummary from an array of RowValidationResults
 func BuildSummary(results []RowValidationResult) ValidationSummary {
-	// Pre-allocate slices with estimated capacity to avoid repeated allocations
-	totalRecords := len(results)
-	summary := ValidationSummary{
-		TotalRecordsProcessed: totalRecords,
-		SuccessfulTestNames:   make([]string, 0, totalRecords),
-		FailedTestNames:       make([]string, 0, totalRecords),
+	builder := NewSummaryBuilder()
+	for _, result := range results {
+		builder.Add(result)
 	}
+	return builder.Summary()
+}
 
-	validCount := 0
-	totalErrors := 0
-	totalWarnings := 0
+// SummaryBuilder accumulates a ValidationSummary one row at a time, so streamed
+// validations never need to hold every RowValidationResult in memory
+type SummaryBuilder struct {
+	totalRecords  int
+	validCount    int
+	totalErrors   int
+	totalWarnings int
 
 	// Use maps to track unique test names
-	successfulTests := make(map[string]bool)
-	failedTests := make(map[string]bool)
+	successfulTests map[string]bool
+	failedTests     map[string]bool
+}
 
-	for _, result := range results {
-		if result.IsValid {
-			validCount++
-			// Add to successful test names set if test name is provided
-			if result.TestName != "" {
-				successfulTests[result.TestName] = true
-			}
-		} else {
-			// Add to failed test names set if test name is provided
-			if result.TestName != "" {
-				failedTests[result.TestName] = true
-			}
+// NewSummaryBuilder creates an empty summary builder
+func NewSummaryBuilder() *SummaryBuilder {
+	return &SummaryBuilder{
+		successfulTests: make(map[string]bool),
+		failedTests:     make(map[string]bool),
+	}
+}
+
+// Add records one row result
+func (sb *SummaryBuilder) Add(result RowValidationResult) {
+	sb.totalRecords++
+	if result.IsValid {
+		sb.validCount++
+		// Add to successful test names set if test name is provided
+		if result.TestName != "" {
+			sb.successfulTests[result.TestName] = true
+		}
+	} else {
+		// Add to failed test names set if test name is provided
+		if result.TestName != "" {
+			sb.failedTests[result.TestName] = true
 		}
-		totalErrors += len(result.Errors)
-		totalWarnings += len(result.Warnings)
+	}
+	sb.totalErrors += len(result.Errors)
+	sb.totalWarnings += len(result.Warnings)
+}
+
+// Summary returns the summary of all rows added so far
+func (sb *SummaryBuilder) Summary() ValidationSummary {
+	summary := ValidationSummary{
+		TotalRecordsProcessed: sb.totalRecords,
+		ValidationErrors:      sb.totalErrors,
+		ValidationWarnings:    sb.totalWarnings,
+		SuccessfulTestNames:   make([]string, 0, len(sb.successfulTests)),
+		FailedTestNames:       make([]string, 0, len(sb.failedTests)),
 	}
 
-	// Convert sets to slices for unique test names
-	for testName := range successfulTests {
+	// Convert sets to sorted slices for unique test names
+	for testName := range sb.successfulTests {
 		summary.SuccessfulTestNames = append(summary.SuccessfulTestNames, testName)
 	}
-	for testName := range failedTests {
+	for testName := range sb.failedTests {
 		summary.FailedTestNames = append(summary.FailedTestNames, testName)
 	}
+	sort.Strings(summary.SuccessfulTestNames)
+	sort.Strings(summary.FailedTestNames)
 
-	summary.ValidationErrors = totalErrors
-	summary.ValidationWarnings = totalWarnings
-
-	if totalRecords > 0 {
-		summary.SuccessRate = float64(validCount) / float64(totalRecords) * 100
+	if sb.totalRecords > 0 {
+		summary.SuccessRate = float64(sb.validCount) / float64(sb.totalRecords) * 100
 	}
 
 	// Total tests ran is the number of unique test types that were executed
 	// Count all unique test names (merge both successful and failed)
 	allTests := make(map[string]bool)
-	for testName := range successfulTests {
+	for testName := range sb.successfulTests {
 		allTests[testName] = true
 	}
-	for testName := range failedTests {
+	for testName := range sb.failedTests {
 		allTests[testName] = true
 	}
 	summary.TotalTestsRan = len(allTests)
@@ -135,9 +217,20 @@ func BuildSummary(results []RowValidationResult) ValidationSummary {
 	return summary
 }
 
-// BatchSession tracks validation across multiple requests
+// BatchSession tracks validation across multiple requests. The fields a
+// session is created with never change; its progress (counters, LastUpdated,
+// IsFinal, ExpiredAt and Chunks) may be updated by concurrent chunks, so read
+// it through State or the other methods rather than the fields.
 type BatchSession struct {
+	BatchSessionState
+	mutex sync.RWMutex
+}
+
+// BatchSessionState is the part of a BatchSession that a BatchStore persists
+type BatchSessionState struct {
 	BatchID        string    `json:"batch_id"`
+	ModelType      string    `json:"model_type,omitempty"`     // Model every chunk must use; empty accepts any model
+	SchemaVersion  string    `json:"schema_version,omitempty"` // Optional client schema version chunks must match
 	TotalRecords   int       `json:"total_records"`
 	ValidRecords   int       `json:"valid_records"`
 	InvalidRecords int       `json:"invalid_records"`
@@ -146,13 +239,57 @@ type BatchSession struct {
 	StartedAt      time.Time `json:"started_at"`
 	LastUpdated    time.Time `json:"last_updated"`
 	IsFinal        bool      `json:"is_final"` // Set to true when client sends final batch
-	mutex          sync.RWMutex
+
+	TTL       time.Duration `json:"ttl,omitempty"`        // Idle time after which an open session expires
+	ExpiredAt *time.Time    `json:"expired_at,omitempty"` // Set when the cleanup routine expires the session
+
+	Chunks []BatchChunk `json:"chunks,omitempty"` // Chunks submitted with X-Batch-Chunk-ID, in arrival order
+}
+
+// State returns a copy of the session's state taken under its lock
+func (bs *BatchSession) State() BatchSessionState {
+	bs.mutex.RLock()
+	defer bs.mutex.RUnlock()
+	return bs.BatchSessionState.clone()
+}
+
+// setProgress copies the fields that change after a session is created from
+// other, leaving the fields it was created with untouched for lock-free readers
+func (state *BatchSessionState) setProgress(other BatchSessionState) {
+	state.TotalRecords = other.TotalRecords
+	state.ValidRecords = other.ValidRecords
+	state.InvalidRecords = other.InvalidRecords
+	state.WarningRecords = other.WarningRecords
+	state.LastUpdated = other.LastUpdated
+	state.IsFinal = other.IsFinal
+	state.ExpiredAt = other.ExpiredAt
+	state.Chunks = other.Chunks
+}
+
+// clone returns a copy of the state that shares no memory with the original
+func (state BatchSessionState) clone() BatchSessionState {
+	if state.Threshold != nil {
+		threshold := *state.Threshold
+		state.Threshold = &threshold
+	}
+	if state.ExpiredAt != nil {
+		expiredAt := *state.ExpiredAt
+		state.ExpiredAt = &expiredAt
+	}
+	state.Chunks = append([]BatchChunk(nil), state.Chunks...)
+	return state
 }
 
 // BatchSessionManager manages batch sessions across multiple requests
 type BatchSessionManager struct {
-	sessions map[string]*BatchSession
-	mutex    sync.RWMutex
+	store  BatchStore
+	limits BatchLimits
+	mutex  sync.RWMutex // Guards store and limits
+
+	createMutex sync.Mutex // Serializes the open session count taken before each create
+
+	cleanupStop chan struct{} // Closed by StopCleanupRoutine
+	cleanupDone chan struct{} // Closed when the cleanup goroutine has exited
 }
 
 var (
@@ -160,132 +297,323 @@ var (
 	batchManagerOnce   sync.Once
 )
 
-// GetBatchSessionManager returns the global batch session manager
+// GetBatchSessionManager returns the global batch session manager.
+// It starts out with an in-memory store; use SetStore to switch backends.
 func GetBatchSessionManager() *BatchSessionManager {
 	batchManagerOnce.Do(func() {
-		globalBatchManager = &BatchSessionManager{
-			sessions: make(map[string]*BatchSession),
-		}
+		globalBatchManager = NewBatchSessionManager(NewMemoryBatchStore())
 	})
 	return globalBatchManager
 }
 
-// CreateBatchSession creates a new batch session
-func (bsm *BatchSessionManager) CreateBatchSession(batchID string, threshold *float64) *BatchSession {
+// NewBatchSessionManager creates a batch session manager backed by store
+func NewBatchSessionManager(store BatchStore) *BatchSessionManager {
+	return &BatchSessionManager{store: store, limits: DefaultBatchLimits()}
+}
+
+// SetStore switches the manager to a different store and closes the previous one.
+// Sessions are not copied between stores. A store that fails its Ping is not
+// used, and the manager keeps the previous one.
+func (bsm *BatchSessionManager) SetStore(store BatchStore) error {
+	if err := store.Ping(); err != nil {
+		return fmt.Errorf("batch store unavailable: %w", err)
+	}
 	bsm.mutex.Lock()
-	defer bsm.mutex.Unlock()
+	previous := bsm.store
+	bsm.store = store
+	bsm.mutex.Unlock()
 
-	session := &BatchSession{
-		BatchID:     batchID,
-		Threshold:   threshold,
-		StartedAt:   time.Now(),
-		LastUpdated: time.Now(),
-		IsFinal:     false,
+	if previous != nil && previous != store {
+		return previous.Close()
 	}
-	bsm.sessions[batchID] = session
-	return session
+	return nil
 }
 
-// GetBatchSession retrieves a batch session by ID
-func (bsm *BatchSessionManager) GetBatchSession(batchID string) (*BatchSession, bool) {
+// Store returns the store the manager currently uses
+func (bsm *BatchSessionManager) Store() BatchStore {
 	bsm.mutex.RLock()
 	defer bsm.mutex.RUnlock()
+	return bsm.store
+}
 
-	session, exists := bsm.sessions[batchID]
-	return session, exists
+// CreateBatchSession creates a new batch session with the default TTL
+func (bsm *BatchSessionManager) CreateBatchSession(batchID string, threshold *float64) (*BatchSession, error) {
+	return bsm.CreateModelBatchSession(batchID, "", "", threshold, 0)
 }
 
-// UpdateBatchSession adds validation results to existing batch session
-func (bsm *BatchSessionManager) UpdateBatchSession(batchID string, validCount, invalidCount, warningCount int) error {
-	bsm.mutex.Lock()
-	defer bsm.mutex.Unlock()
+// CreateModelBatchSession creates a batch session bound to modelType and,
+// if set, schemaVersion. Chunks for any other model are rejected by CheckModel.
+// A zero ttl selects the configured default; the open session limit applies.
+func (bsm *BatchSessionManager) CreateModelBatchSession(batchID, modelType, schemaVersion string, threshold *float64, ttl time.Duration) (*BatchSession, error) {
+	limits := bsm.Limits()
+	ttl, err := limits.SessionTTL(ttl)
+	if err != nil {
+		return nil, err
+	}
 
-	session, exists := bsm.sessions[batchID]
-	if !exists {
-		return fmt.Errorf("batch session %s not found", batchID)
+	if limits.MaxOpenSessions > 0 {
+		bsm.createMutex.Lock()
+		defer bsm.createMutex.Unlock()
+		open, err := bsm.OpenSessionCount()
+		if err != nil {
+			return nil, err
+		}
+		if open >= limits.MaxOpenSessions {
+			return nil, fmt.Errorf("%w: limit is %d", ErrBatchLimitExceeded, limits.MaxOpenSessions)
+		}
 	}
 
-	session.mutex.Lock()
-	defer session.mutex.Unlock()
+	now := time.Now()
+	session := &BatchSession{BatchSessionState: BatchSessionState{
+		BatchID:       batchID,
+		ModelType:     modelType,
+		SchemaVersion: schemaVersion,
+		Threshold:     threshold,
+		StartedAt:     now,
+		LastUpdated:   now,
+		IsFinal:       false,
+		TTL:           ttl,
+	}}
+	if err := bsm.Store().Create(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
 
-	session.TotalRecords += validCount + invalidCount
-	session.ValidRecords += validCount
-	session.InvalidRecords += invalidCount
-	session.WarningRecords += warningCount
-	session.LastUpdated = time.Now()
+// GetBatchSession retrieves a batch session by ID. A session the store cannot
+// read is reported as missing.
+func (bsm *BatchSessionManager) GetBatchSession(batchID string) (*BatchSession, bool) {
+	session, err := bsm.Store().Get(batchID)
+	if err != nil {
+		return nil, false
+	}
+	return session, true
+}
 
-	return nil
+// UpdateBatchSession adds validation results to existing batch session
+func (bsm *BatchSessionManager) UpdateBatchSession(batchID string, validCount, invalidCount, warningCount int) error {
+	_, err := bsm.Store().Update(batchID, func(session *BatchSession) error {
+		session.TotalRecords += validCount + invalidCount
+		session.ValidRecords += validCount
+		session.InvalidRecords += invalidCount
+		session.WarningRecords += warningCount
+		session.LastUpdated = time.Now()
+		return nil
+	})
+	return err
 }
 
-// FinalizeBatchSession marks the batch as complete and returns final status
-func (bsm *BatchSessionManager) FinalizeBatchSession(batchID string) (string, error) {
-	bsm.mutex.Lock()
-	defer bsm.mutex.Unlock()
+// RecordBatchChunk adds a validated chunk to a batch session. The counters,
+// the chunk ID and the chunk's failed rows are saved in one store operation;
+// the rows keep their position in the whole batch so they can be paged
+// through later.
+//
+// A non-empty chunkID makes the call idempotent: if the session already holds
+// a chunk with that ID, nothing changes and the original chunk is returned with
+// replayed set to true.
+func (bsm *BatchSessionManager) RecordBatchChunk(batchID, chunkID string, result *ArrayValidationResult) (chunk BatchChunk, replayed bool, err error) {
+	if err := CheckChunkID(chunkID); err != nil {
+		return BatchChunk{}, false, err
+	}
+	store := bsm.Store()
+	limits := bsm.Limits()
+
+	// Reserve this chunk's row range while updating the counters, so concurrent
+	// chunks never share a batch row index
+	_, err = store.UpdateWithResults(batchID, func(session *BatchSession) ([]BatchRowResult, error) {
+		// A retried chunk is answered even once the session is completed, as
+		// the HTTP and gRPC handlers do before validating it
+		if chunkID != "" {
+			if previous, exists := session.findChunk(chunkID); exists {
+				chunk, replayed = previous, true
+				return nil, errChunkReplayed
+			}
+		}
+		if err := limits.checkChunk(&session.BatchSessionState, result.ValidRecords+result.InvalidRecords, time.Now()); err != nil {
+			return nil, err
+		}
+
+		offset := session.TotalRecords
+		session.TotalRecords += result.ValidRecords + result.InvalidRecords
+		session.ValidRecords += result.ValidRecords
+		session.InvalidRecords += result.InvalidRecords
+		session.WarningRecords += result.WarningRecords
+		session.LastUpdated = time.Now()
+
+		chunk = newBatchChunk(chunkID, result)
+		chunk.SessionTotal = session.TotalRecords
+		if chunkID != "" {
+			session.Chunks = append(session.Chunks, chunk)
+		}
 
-	session, exists := bsm.sessions[batchID]
-	if !exists {
-		return "", fmt.Errorf("batch session %s not found", batchID)
+		var failures []BatchRowResult
+		for _, row := range result.Results {
+			if !row.IsValid {
+				failures = append(failures, BatchRowResult{BatchRowIndex: offset + row.RowIndex, RowValidationResult: row})
+			}
+		}
+		return failures, nil
+	})
+	if replayed {
+		return chunk, true, nil
+	}
+	if err != nil {
+		return BatchChunk{}, false, err
 	}
+	return chunk, false, nil
+}
 
-	session.mutex.Lock()
-	defer session.mutex.Unlock()
+// errChunkReplayed aborts a store update for a chunk that was already recorded
+var errChunkReplayed = errors.New("chunk already recorded")
 
-	session.IsFinal = true
-	session.LastUpdated = time.Now()
+// ListBatchResults returns a page of the failed rows retained by a batch session
+func (bsm *BatchSessionManager) ListBatchResults(batchID, cursor string, limit int, filter BatchResultFilter) (BatchResultPage, error) {
+	return bsm.Store().ListResults(batchID, cursor, limit, filter)
+}
 
-	// Calculate final status based on threshold
+// FinalizeBatchSession marks the batch as complete and returns final status
+func (bsm *BatchSessionManager) FinalizeBatchSession(batchID string) (string, error) {
 	status := "success"
-	if session.Threshold != nil && session.TotalRecords > 0 {
-		successRate := (float64(session.ValidRecords) / float64(session.TotalRecords)) * 100.0
-		if successRate < *session.Threshold {
+	_, err := bsm.Store().Update(batchID, func(session *BatchSession) error {
+		// Completing a completed session again returns its final status
+		if !session.IsFinal {
+			if err := bsm.Limits().checkChunk(&session.BatchSessionState, 0, time.Now()); err != nil {
+				return err
+			}
+		}
+		session.IsFinal = true
+		session.LastUpdated = time.Now()
+
+		// Calculate final status based on threshold
+		if session.Threshold != nil && session.TotalRecords > 0 {
+			successRate := (float64(session.ValidRecords) / float64(session.TotalRecords)) * 100.0
+			if successRate < *session.Threshold {
+				status = "failed"
+			}
+		} else if session.TotalRecords == 1 && session.InvalidRecords > 0 {
 			status = "failed"
 		}
-	} else if session.TotalRecords == 1 && session.InvalidRecords > 0 {
-		status = "failed"
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	return status, nil
 }
 
 // DeleteBatchSession removes a batch session
-func (bsm *BatchSessionManager) DeleteBatchSession(batchID string) {
-	bsm.mutex.Lock()
-	defer bsm.mutex.Unlock()
+func (bsm *BatchSessionManager) DeleteBatchSession(batchID string) error {
+	_, err := bsm.Store().DeleteIf(batchID, nil)
+	return err
+}
 
-	delete(bsm.sessions, batchID)
+// AbortBatchSession removes a batch session and its retained rows on the
+// client's request, or returns ErrBatchNotFound
+func (bsm *BatchSessionManager) AbortBatchSession(batchID string) error {
+	deleted, err := bsm.Store().DeleteIf(batchID, nil)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", ErrBatchNotFound, batchID)
+	}
+	slog.Info("Batch session aborted by client", "batch_id", batchID)
+	return nil
 }
 
-// CleanupExpiredBatches removes batch sessions older than 30 minutes
-func (bsm *BatchSessionManager) CleanupExpiredBatches() {
-	bsm.mutex.Lock()
-	defer bsm.mutex.Unlock()
+// CleanupExpiredBatches expires open sessions that outlived their TTL and
+// removes expired sessions after the retention period. Completed sessions are
+// removed once their TTL has passed since completion.
+func (bsm *BatchSessionManager) CleanupExpiredBatches() error {
+	store := bsm.Store()
+	batchIDs, err := store.List()
+	if err != nil {
+		return err
+	}
 
+	retention := bsm.Limits().ExpiredRetention
 	now := time.Now()
-	expirationDuration := 30 * time.Minute
-
-	for batchID, session := range bsm.sessions {
-		session.mutex.RLock()
-		age := now.Sub(session.LastUpdated)
-		session.mutex.RUnlock()
+	for _, batchID := range batchIDs {
+		// Both checks run again under the store lock, so a session updated by
+		// another replica in the meantime survives
+		_, err := store.Update(batchID, func(session *BatchSession) error {
+			if session.IsFinal || session.ExpiredAt != nil || !now.After(session.expiresAt()) {
+				return errSessionUnchanged
+			}
+			session.ExpiredAt = &now
+			return nil
+		})
+		switch {
+		case err == nil:
+			slog.Info("Batch session expired", "batch_id", batchID)
+		case !errors.Is(err, errSessionUnchanged) && !errors.Is(err, ErrBatchNotFound):
+			return err
+		}
 
-		if age > expirationDuration {
-			delete(bsm.sessions, batchID)
+		deleted, err := store.DeleteIf(batchID, func(session *BatchSession) bool {
+			if session.ExpiredAt != nil {
+				return now.Sub(*session.ExpiredAt) >= retention
+			}
+			return session.IsFinal && now.After(session.expiresAt())
+		})
+		if err != nil {
+			return err
+		}
+		if deleted {
+			slog.Debug("Batch session removed", "batch_id", batchID)
 		}
 	}
+	return nil
 }
 
+// errSessionUnchanged aborts a store update that has nothing to change
+var errSessionUnchanged = errors.New("session unchanged")
+
 // StartCleanupRoutine starts a background goroutine to cleanup expired batches
+// every SweepInterval. Calling it while the routine is running does nothing.
 func (bsm *BatchSessionManager) StartCleanupRoutine() {
+	bsm.mutex.Lock()
+	defer bsm.mutex.Unlock()
+	if bsm.cleanupStop != nil {
+		return
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	bsm.cleanupStop, bsm.cleanupDone = stop, done
+
 	go func() {
-		ticker := time.NewTicker(5 * time.Minute) // Check every 5 minutes
+		defer close(done)
+		ticker := time.NewTicker(bsm.Limits().SweepInterval)
 		defer ticker.Stop()
 
-		for range ticker.C {
-			bsm.CleanupExpiredBatches()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := bsm.CleanupExpiredBatches(); err != nil {
+					slog.Error("Batch session cleanup failed", "error", err)
+				}
+			}
 		}
 	}()
 }
 
+// StopCleanupRoutine stops the cleanup goroutine and waits for a sweep in
+// progress to finish
+func (bsm *BatchSessionManager) StopCleanupRoutine() {
+	bsm.mutex.Lock()
+	stop, done := bsm.cleanupStop, bsm.cleanupDone
+	bsm.cleanupStop, bsm.cleanupDone = nil, nil
+	bsm.mutex.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
 // GetBatchStatus returns current status of batch session
 func (bs *BatchSession) GetStatus() map[string]interface{} {
 	bs.mutex.RLock()
@@ -297,7 +625,9 @@ func (bs *BatchSession) GetStatus() map[string]interface{} {
 	}
 
 	status := "in_progress"
-	if bs.IsFinal {
+	if bs.ExpiredAt != nil {
+		status = "expired"
+	} else if bs.IsFinal {
 		status = "success"
 		if bs.Threshold != nil && successRate < *bs.Threshold {
 			status = "failed"
@@ -306,8 +636,9 @@ func (bs *BatchSession) GetStatus() map[string]interface{} {
 		}
 	}
 
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"batch_id":        bs.BatchID,
+		"model_type":      bs.ModelType,
 		"status":          status,
 		"total_records":   bs.TotalRecords,
 		"valid_records":   bs.ValidRecords,
@@ -318,5 +649,39 @@ func (bs *BatchSession) GetStatus() map[string]interface{} {
 		"started_at":      bs.StartedAt,
 		"last_updated":    bs.LastUpdated,
 		"is_final":        bs.IsFinal,
+		"expires_at":      bs.expiresAt(),
+	}
+	if bs.ExpiredAt != nil {
+		result["expired_at"] = *bs.ExpiredAt
+	}
+	if bs.SchemaVersion != "" {
+		result["schema_version"] = bs.SchemaVersion
+	}
+	if report := bs.chunkReport(); report != nil {
+		result["chunks"] = report
+	}
+	return result
+}
+
+// CheckModel returns ErrBatchModelMismatch if the session is bound to a model
+// other than modelType, or if both sides name a schema version and they differ
+func (bs *BatchSession) CheckModel(modelType, schemaVersion string) error {
+	bs.mutex.RLock()
+	defer bs.mutex.RUnlock()
+
+	if bs.ModelType != "" && modelType != bs.ModelType {
+		return fmt.Errorf("%w: batch session '%s' accepts model '%s', got '%s'", ErrBatchModelMismatch, bs.BatchID, bs.ModelType, modelType)
 	}
+	if bs.SchemaVersion != "" && schemaVersion != "" && schemaVersion != bs.SchemaVersion {
+		return fmt.Errorf("%w: batch session '%s' accepts schema version '%s', got '%s'", ErrBatchModelMismatch, bs.BatchID, bs.SchemaVersion, schemaVersion)
+	}
+	return nil
+}
+
+// ChunkReport summarizes the chunk IDs the session received, or returns nil if
+// the client did not send X-Batch-Chunk-ID
+func (bs *BatchSession) ChunkReport() *BatchChunkReport {
+	bs.mutex.RLock()
+	defer bs.mutex.RUnlock()
+	return bs.chunkReport()
 }
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -71,8 +73,17 @@ func TestDetectRecordIdentifier(t *testing.T) {
 			if result != tt.expected {
 				t.Errorf("DetectRecordIdentifier() = %v, want %v", result, tt.expected)
 			}
+
+			raw, _ := json.Marshal(tt.record)
+			if result := DetectRawRecordIdentifier(raw, tt.rowIndex); result != tt.expected {
+				t.Errorf("DetectRawRecordIdentifier() = %v, want %v", result, tt.expected)
+			}
 		})
 	}
+
+	if result := DetectRawRecordIdentifier(json.RawMessage(`[1]`), 7); result != "row_7" {
+		t.Errorf("DetectRawRecordIdentifier() of an array = %v, want row_7", result)
+	}
 }
 
 func TestBuildSummary(t *testing.T) {
@@ -213,7 +224,10 @@ func TestBatchSessionManager_CreateSession(t *testing.T) {
 	manager := GetBatchSessionManager()
 	threshold := 20.0
 
-	session := manager.CreateBatchSession("batch-001", &threshold)
+	session, err := manager.CreateBatchSession("batch-001", &threshold)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 
 	if session.BatchID != "batch-001" {
 		t.Errorf("Expected BatchID = batch-001, got %s", session.BatchID)
@@ -235,7 +249,11 @@ func TestBatchSessionManager_UpdateSession(t *testing.T) {
 	manager := GetBatchSessionManager()
 	threshold := 50.0
 
-	_ = manager.CreateBatchSession("batch-002", &threshold)
+	if _, err := manager.CreateBatchSession("batch-002", &threshold); err != nil {
+
+		t.Fatalf("CreateBatchSession failed: %v", err)
+
+	}
 
 	// Update with validation results
 	err := manager.UpdateBatchSession("batch-002", 80, 20, 5)
@@ -326,7 +344,9 @@ func TestBatchSessionManager_FinalizeSession(t *testing.T) {
 	for i, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			batchID := fmt.Sprintf("batch-finalize-%d", i)
-			_ = manager.CreateBatchSession(batchID, tt.threshold)
+			if _, err := manager.CreateBatchSession(batchID, tt.threshold); err != nil {
+				t.Fatalf("CreateBatchSession failed: %v", err)
+			}
 
 			// Update session with counts
 			manager.UpdateBatchSession(batchID, tt.validCount, tt.invalidCount, 0)
@@ -357,7 +377,10 @@ func TestBatchSession_GetStatus(t *testing.T) {
 	manager := GetBatchSessionManager()
 	threshold := 30.0
 
-	session := manager.CreateBatchSession("batch-status", &threshold)
+	session, err := manager.CreateBatchSession("batch-status", &threshold)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 	manager.UpdateBatchSession("batch-status", 40, 60, 5)
 
 	status := session.GetStatus()
@@ -402,7 +425,9 @@ func TestBatchSession_ThresholdEdgeCases(t *testing.T) {
 
 	// Test exact threshold match (20.0% with 20% threshold)
 	threshold := 20.0
-	_ = manager.CreateBatchSession("batch-edge-1", &threshold)
+	if _, err := manager.CreateBatchSession("batch-edge-1", &threshold); err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 	manager.UpdateBatchSession("batch-edge-1", 20, 80, 0)
 	status, _ := manager.FinalizeBatchSession("batch-edge-1")
 
@@ -412,7 +437,9 @@ func TestBatchSession_ThresholdEdgeCases(t *testing.T) {
 	manager.DeleteBatchSession("batch-edge-1")
 
 	// Test just above threshold (20.0001% with 20% threshold)
-	_ = manager.CreateBatchSession("batch-edge-2", &threshold)
+	if _, err := manager.CreateBatchSession("batch-edge-2", &threshold); err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 	manager.UpdateBatchSession("batch-edge-2", 20001, 79999, 0)
 	status2, _ := manager.FinalizeBatchSession("batch-edge-2")
 
@@ -422,7 +449,9 @@ func TestBatchSession_ThresholdEdgeCases(t *testing.T) {
 	manager.DeleteBatchSession("batch-edge-2")
 
 	// Test just below threshold (19.9999% with 20% threshold)
-	_ = manager.CreateBatchSession("batch-edge-3", &threshold)
+	if _, err := manager.CreateBatchSession("batch-edge-3", &threshold); err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
 	manager.UpdateBatchSession("batch-edge-3", 19999, 80001, 0)
 	status3, _ := manager.FinalizeBatchSession("batch-edge-3")
 
@@ -436,3 +465,43 @@ func TestBatchSession_ThresholdEdgeCases(t *testing.T) {
 func floatPtr(f float64) *float64 {
 	return &f
 }
+
+func TestBatchSession_CheckModel(t *testing.T) {
+	manager := NewBatchSessionManager(NewMemoryBatchStore())
+	bound, err := manager.CreateModelBatchSession("batch-bound", "incident", "2024-01", nil, 0)
+	if err != nil {
+		t.Fatalf("CreateModelBatchSession failed: %v", err)
+	}
+	unbound, err := manager.CreateBatchSession("batch-unbound", nil)
+	if err != nil {
+		t.Fatalf("CreateBatchSession failed: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		session       *BatchSession
+		modelType     string
+		schemaVersion string
+		wantErr       bool
+	}{
+		{"same model", bound, "incident", "", false},
+		{"same model and version", bound, "incident", "2024-01", false},
+		{"other model", bound, "deployment", "", true},
+		{"other version", bound, "incident", "2023-06", true},
+		{"unbound session", unbound, "deployment", "v9", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.session.CheckModel(tt.modelType, tt.schemaVersion)
+			if tt.wantErr != errors.Is(err, ErrBatchModelMismatch) {
+				t.Errorf("CheckModel(%q, %q) = %v, wantErr %v", tt.modelType, tt.schemaVersion, err, tt.wantErr)
+			}
+		})
+	}
+
+	status := bound.GetStatus()
+	if status["model_type"] != "incident" || status["schema_version"] != "2024-01" {
+		t.Errorf("GetStatus does not report the binding: %v", status)
+	}
+}
@@ -0,0 +1,123 @@
+// Package problem writes every error response of the server as an RFC 7807
+// problem details object of type application/problem+json
+package problem
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"goplayground-data-validator/jsonlimit"
+	"goplayground-data-validator/logging"
+	"goplayground-data-validator/tracing"
+)
+
+// ContentType is the media type of problem responses
+const ContentType = "application/problem+json"
+
+// TypePrefix starts the type URI of every coded problem, followed by its code
+// with hyphens for underscores. Types are relative to the server.
+const TypePrefix = "/problems/"
+
+// Codes of problems that say more than their status. Problems without one are
+// coded by their status, such as "bad_request", and have type about:blank.
+// Exceeded input limits are coded by the limit's name, such as
+// "max_body_bytes", and admission rejections by their reason.
+const (
+	CodeInvalidPayload       = "invalid_payload"        // The body is malformed or does not fit the model
+	CodeInvalidParameter     = "invalid_parameter"      // A query parameter, header or option is out of range
+	CodeModelNotRegistered   = "model_not_registered"   // No model of the requested type
+	CodeNotAcceptable        = "not_acceptable"         // Accept allows no format of the response
+	CodeUnsupportedMediaType = "unsupported_media_type" // The endpoint does not read the body's Content-Type
+	CodeValidationFailed     = "validation_failed"      // Validation could not run to completion
+	CodeValidationTimeout    = "validation_timeout"     // Array validation ran out of time
+	CodeBatchNotFound        = "batch_not_found"
+	CodeBatchModelMismatch   = "batch_model_mismatch"
+	CodeBatchExpired         = "batch_expired"
+	CodeBatchCompleted       = "batch_completed"
+	CodeBatchTooManyRecords  = "batch_too_many_records"
+	CodeBatchLimitExceeded   = "batch_limit_exceeded"
+	CodeInvalidBatchTTL      = "invalid_batch_ttl"
+)
+
+// titles are the summaries of the coded problems; the summary of a problem
+// coded by its status is the status text
+var titles = map[string]string{
+	CodeInvalidPayload:          "Invalid payload",
+	CodeInvalidParameter:        "Invalid parameter",
+	CodeModelNotRegistered:      "Model type not registered",
+	CodeNotAcceptable:           "No acceptable response format",
+	CodeUnsupportedMediaType:    "Unsupported request format",
+	CodeValidationFailed:        "Validation failed",
+	CodeValidationTimeout:       "Validation timed out",
+	CodeBatchNotFound:           "Batch session not found",
+	CodeBatchModelMismatch:      "Batch session bound to another model",
+	CodeBatchExpired:            "Batch session expired",
+	CodeBatchCompleted:          "Batch session completed",
+	CodeBatchTooManyRecords:     "Batch session full",
+	CodeBatchLimitExceeded:      "Too many open batch sessions",
+	CodeInvalidBatchTTL:         "Invalid batch session TTL",
+	jsonlimit.LimitBodyBytes:    "Request body too large",
+	jsonlimit.LimitDepth:        "Request nested too deeply",
+	jsonlimit.LimitArrayLength:  "Request array too long",
+	jsonlimit.LimitStringLength: "Request string too long",
+	jsonlimit.LimitObjectKeys:   "Request object has too many keys",
+	// The reasons of admission.RejectedError
+	"rate_limit": "Rate limit exceeded",
+	"overload":   "Server overloaded",
+}
+
+// Problem is a problem details object. RequestID, ModelType and Code are
+// extension members.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	ModelType string `json:"model_type,omitempty"`
+	Code      string `json:"code"`
+}
+
+// New returns the problem with a status, code and detail; an empty code is
+// replaced by the code of the status
+func New(status int, code, detail string) *Problem {
+	p := &Problem{Type: "about:blank", Title: http.StatusText(status), Status: status, Detail: detail, Code: code}
+	if code == "" {
+		p.Code = strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+		return p
+	}
+	p.Type = TypePrefix + strings.ReplaceAll(code, "_", "-")
+	if title, ok := titles[code]; ok {
+		p.Title = title
+	}
+	return p
+}
+
+// Write answers r with p. The instance is the request path, and the request
+// ID and model type are those of r's context unless p has its own.
+func (p *Problem) Write(w http.ResponseWriter, r *http.Request) {
+	if p.Instance == "" {
+		p.Instance = r.URL.Path
+	}
+	if p.RequestID == "" {
+		p.RequestID = tracing.RequestID(r.Context())
+	}
+	if p.ModelType == "" {
+		if model, ok := logging.Field(r.Context(), "model"); ok {
+			p.ModelType = model.String()
+		}
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to encode error response", "error", err)
+	}
+}
+
+// Write answers r with the problem with a status, code and detail
+func Write(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	New(status, code, detail).Write(w, r)
+}
@@ -0,0 +1,68 @@
+package problem
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"goplayground-data-validator/logging"
+	"goplayground-data-validator/tracing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		code      string
+		wantType  string
+		wantTitle string
+		wantCode  string
+	}{
+		{"status only", http.StatusNotFound, "", "about:blank", "Not Found", "not_found"},
+		{"coded", http.StatusNotFound, CodeBatchNotFound, "/problems/batch-not-found", "Batch session not found", "batch_not_found"},
+		{"limit", http.StatusRequestEntityTooLarge, "max_body_bytes", "/problems/max-body-bytes", "Request body too large", "max_body_bytes"},
+		{"unknown code", http.StatusConflict, "schema_changed", "/problems/schema-changed", "Conflict", "schema_changed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(tt.status, tt.code, "detail")
+			if p.Type != tt.wantType || p.Title != tt.wantTitle || p.Code != tt.wantCode || p.Status != tt.status || p.Detail != "detail" {
+				t.Errorf("New(%d, %q) = %+v", tt.status, tt.code, p)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/validate?model_type=incident", nil)
+	ctx, _ := logging.WithFields(tracing.WithRequestID(req.Context(), "req-1"))
+	logging.AddFields(ctx, slog.String("model", "incident"))
+	w := httptest.NewRecorder()
+
+	Write(w, req.WithContext(ctx), http.StatusBadRequest, CodeInvalidParameter, "threshold must be a number between 0 and 100")
+
+	if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != ContentType {
+		t.Fatalf("Expected 400 %s, got %d %s", ContentType, w.Code, w.Header().Get("Content-Type"))
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := map[string]interface{}{
+		"type":       "/problems/invalid-parameter",
+		"title":      "Invalid parameter",
+		"status":     float64(http.StatusBadRequest),
+		"detail":     "threshold must be a number between 0 and 100",
+		"instance":   "/validate",
+		"request_id": "req-1",
+		"model_type": "incident",
+		"code":       CodeInvalidParameter,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v", key, got[key], value)
+		}
+	}
+}
@@ -4,7 +4,6 @@ package registry
 
 import (
 	"context"
-	"log"
 	"net/http"
 )
 
@@ -44,6 +43,5 @@ func GetGlobalDynamicRegistry() *DynamicModelRegistry {
 
 // StartDynamicRegistration starts the unified registration system
 func StartDynamicRegistration(ctx context.Context, mux *http.ServeMux) error {
-	log.Println("🔄 Starting unified automatic registration system...")
 	return StartRegistration(ctx, mux)
 }
\ No newline at end of file
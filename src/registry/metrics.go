@@ -0,0 +1,103 @@
+package registry
+
+import (
+	"time"
+
+	"goplayground-data-validator/metrics"
+	"goplayground-data-validator/models"
+)
+
+// Validation modes used as the "mode" label
+const (
+	modeSingle = "single"
+	modeArray  = "array"
+	modeStream = "stream"
+)
+
+// arraySizeBuckets bound the number of records in one array or stream validation
+var arraySizeBuckets = []float64{1, 10, 50, 100, 500, 1000, 5000, 10000, 50000, 100000, 1000000}
+
+// Validation traffic metrics, served from the default metrics registry
+var (
+	validationRequests = metrics.Default().NewCounterVec(
+		"validator_validation_requests_total",
+		"Validation operations by model and mode (single, array, stream).",
+		"model", "mode")
+	validationRecords = metrics.Default().NewCounterVec(
+		"validator_records_total",
+		"Validated records by model and result (valid, invalid).",
+		"model", "result")
+	validationWarningRecords = metrics.Default().NewCounterVec(
+		"validator_warning_records_total",
+		"Valid records that carried at least one warning, by model.",
+		"model")
+	validationErrorCodes = metrics.Default().NewCounterVec(
+		"validator_validation_errors_total",
+		"Validation errors by model and error code.",
+		"model", "code")
+	validationWarningCodes = metrics.Default().NewCounterVec(
+		"validator_validation_warnings_total",
+		"Validation warnings by model and warning code.",
+		"model", "code")
+	validationDuration = metrics.Default().NewHistogramVec(
+		"validator_validation_duration_seconds",
+		"Time spent validating one payload, array or stream, by model and mode.",
+		metrics.DefaultDurationBuckets,
+		"model", "mode")
+	validationArraySize = metrics.Default().NewHistogramVec(
+		"validator_array_records",
+		"Records per array or stream validation, by model and mode.",
+		arraySizeBuckets,
+		"model", "mode")
+	thresholdResults = metrics.Default().NewCounterVec(
+		"validator_threshold_checks_total",
+		"Array and stream validations with a threshold, by model and result (pass, fail).",
+		"model", "result")
+)
+
+// observeCodes counts the error and warning codes of one record
+func observeCodes(model string, errors []models.ValidationError, warnings []models.ValidationWarning) {
+	for _, validationError := range errors {
+		validationErrorCodes.Inc(model, validationError.Code)
+	}
+	for _, warning := range warnings {
+		validationWarningCodes.Inc(model, warning.Code)
+	}
+}
+
+// observeRecord counts one validated record and its codes
+func observeRecord(model string, isValid bool, errors []models.ValidationError, warnings []models.ValidationWarning) {
+	if isValid {
+		validationRecords.Inc(model, "valid")
+		if len(warnings) > 0 {
+			validationWarningRecords.Inc(model)
+		}
+	} else {
+		validationRecords.Inc(model, "invalid")
+	}
+	observeCodes(model, errors, warnings)
+}
+
+// observePayload records a single payload validation
+func observePayload(modelType ModelType, result models.ValidationResult, elapsed time.Duration) {
+	model := string(modelType)
+	validationRequests.Inc(model, modeSingle)
+	validationDuration.Observe(elapsed.Seconds(), model, modeSingle)
+	observeRecord(model, result.IsValid, result.Errors, result.Warnings)
+}
+
+// observeBatch records an array or stream validation once its status is known.
+// Per-record counts are recorded by the caller as rows are validated.
+func observeBatch(modelType ModelType, mode string, records int, threshold *float64, status string, elapsed time.Duration) {
+	model := string(modelType)
+	validationRequests.Inc(model, mode)
+	validationDuration.Observe(elapsed.Seconds(), model, mode)
+	validationArraySize.Observe(float64(records), model, mode)
+	if threshold != nil {
+		result := "pass"
+		if status != "success" {
+			result = "fail"
+		}
+		thresholdResults.Inc(model, result)
+	}
+}
@@ -0,0 +1,70 @@
+package registry
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"goplayground-data-validator/models"
+)
+
+// TestUnifiedRegistry_ValidationMetrics tests that single, array and stream
+// validations are counted once per record and once per operation
+func TestUnifiedRegistry_ValidationMetrics(t *testing.T) {
+	registry := NewUnifiedRegistry()
+	const model = "metricstest"
+	if err := registry.RegisterModel(&ModelInfo{
+		Type:        model,
+		ModelStruct: reflect.TypeOf(models.GenericPayload{}),
+		Validator:   Adapt[models.GenericPayload](&mockValidatorWithMixedResults{}),
+	}); err != nil {
+		t.Fatalf("RegisterModel failed: %v", err)
+	}
+
+	if _, err := registry.ValidatePayload(model, models.GenericPayload{ID: "invalid-1"}); err != nil {
+		t.Fatalf("ValidatePayload failed: %v", err)
+	}
+
+	threshold := 80.0
+	records := []map[string]interface{}{{"id": "valid-1"}, {"id": "invalid-1"}, {"id": "valid-2"}}
+	if _, err := registry.ValidateArray(model, records, &threshold); err != nil {
+		t.Fatalf("ValidateArray failed: %v", err)
+	}
+
+	stream := strings.NewReader(`{"id":"valid-1"}` + "\n" + `not json` + "\n")
+	if _, err := registry.ValidateStream(context.Background(), model, stream, nil, models.BatchOptions{}, func(models.RowValidationResult) error { return nil }); err != nil {
+		t.Fatalf("ValidateStream failed: %v", err)
+	}
+
+	counters := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"single requests", validationRequests.Value(model, modeSingle), 1},
+		{"array requests", validationRequests.Value(model, modeArray), 1},
+		{"stream requests", validationRequests.Value(model, modeStream), 1},
+		{"valid records", validationRecords.Value(model, "valid"), 3},
+		{"invalid records", validationRecords.Value(model, "invalid"), 3},
+		{"VALIDATION_FAILED errors", validationErrorCodes.Value(model, "VALIDATION_FAILED"), 2},
+		{"INVALID_JSON errors", validationErrorCodes.Value(model, "INVALID_JSON"), 1},
+		{"threshold failures", thresholdResults.Value(model, "fail"), 1},
+		{"threshold passes", thresholdResults.Value(model, "pass"), 0},
+	}
+	for _, c := range counters {
+		if c.got != c.expected {
+			t.Errorf("Expected %s to be %v, got %v", c.name, c.expected, c.got)
+		}
+	}
+
+	if count := validationDuration.Count(model, modeSingle); count != 1 {
+		t.Errorf("Expected 1 single duration observation, got %d", count)
+	}
+	if count := validationArraySize.Count(model, modeArray); count != 1 {
+		t.Errorf("Expected 1 array size observation, got %d", count)
+	}
+	if count := validationArraySize.Count(model, modeSingle); count != 0 {
+		t.Errorf("Expected no array size observation for single payloads, got %d", count)
+	}
+}
@@ -3,15 +3,52 @@
 package registry
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"goplayground-data-validator/models"
 )
 
 // ModelType represents different types of models that can be registered.
+// A type is either a single name ("incident") or a family and variant joined
+// by a dot ("api.response"), which is served under /validate/{family}/{variant}.
 type ModelType string
 
-// ValidatorInterface represents the interface that all validators must implement.
+// Family returns the part of the model type before the dot ("api" for "api.response")
+func (mt ModelType) Family() string {
+	family, _, _ := strings.Cut(string(mt), ".")
+	return family
+}
+
+// Variant returns the part of the model type after the dot, or "" for a top-level type
+func (mt ModelType) Variant() string {
+	_, variant, _ := strings.Cut(string(mt), ".")
+	return variant
+}
+
+// Endpoint returns the HTTP path that validates this model type
+func (mt ModelType) Endpoint() string {
+	if variant := mt.Variant(); variant != "" {
+		return "/validate/" + mt.Family() + "/" + variant
+	}
+	return "/validate/" + string(mt)
+}
+
+// ValidatorInterface is the type-erased validator stored in ModelInfo.
+// Model validators implement Validator[T] and are adapted to it with Adapt.
 type ValidatorInterface interface {
-	ValidatePayload(payload interface{}) interface{} // Return type is now interface{} for flexibility
+	ValidatePayload(payload interface{}) models.ValidationResult
+}
+
+// ContextValidatorInterface is implemented by validators that accept the request
+// context, which lets them trace their individual checks. TypedValidator implements it.
+type ContextValidatorInterface interface {
+	ValidatePayloadContext(ctx context.Context, payload interface{}) models.ValidationResult
 }
 
 // ModelInfo contains information about a registered model.
@@ -28,57 +65,180 @@ type ModelInfo struct {
 	Tags        []string
 }
 
-// UniversalValidatorWrapper - A universal wrapper that works with any validator using reflection
-type UniversalValidatorWrapper struct {
-	modelType         string
-	validatorInstance interface{}
-	modelStructType   reflect.Type
+// Validator is implemented by every model validator. T is the model struct it
+// validates, so handing a validator the wrong model is a compile error.
+type Validator[T any] interface {
+	ValidatePayload(payload T) models.ValidationResult
 }
 
-// ValidatePayload implements ValidatorInterface using reflection to call any validator
-func (uvw *UniversalValidatorWrapper) ValidatePayload(payload interface{}) interface{} {
-	// Use reflection to call the validator's ValidatePayload method
-	validatorValue := reflect.ValueOf(uvw.validatorInstance)
+// ContextValidator is optionally implemented by a Validator[T] whose checks use
+// the request context; the registry prefers it over ValidatePayload.
+type ContextValidator[T any] interface {
+	ValidatePayloadContext(ctx context.Context, payload T) models.ValidationResult
+}
 
-	// Try different method names that validators might use
-	methodNames := []string{"ValidatePayload", "Validate", "ValidateRequest", "ValidateModel"}
+// ValidatorFunc adapts a function or method value to Validator[T].
+type ValidatorFunc[T any] func(payload T) models.ValidationResult
 
-	for _, methodName := range methodNames {
-		validateMethod := validatorValue.MethodByName(methodName)
-		if !validateMethod.IsValid() {
-			continue
-		}
+// ValidatePayload calls f(payload)
+func (f ValidatorFunc[T]) ValidatePayload(payload T) models.ValidationResult {
+	return f(payload)
+}
 
-		// Call the method with the payload
-		results := validateMethod.Call([]reflect.Value{reflect.ValueOf(payload)})
+// ModelValidator is a ValidatorInterface that also reports the model struct it
+// accepts. Adapt builds one from any Validator[T].
+type ModelValidator interface {
+	ValidatorInterface
+	ModelStruct() reflect.Type
+}
 
-		// Return the first result (should be ValidationResult)
-		if len(results) > 0 {
-			result := results[0].Interface()
+// TypedValidator adapts a Validator[T] to ModelValidator
+type TypedValidator[T any] struct {
+	validator Validator[T]
+}
 
-			// Ensure model type is set for validation results
-			if validationResult, ok := result.(map[string]interface{}); ok {
-				if validationResult["model_type"] == "" || validationResult["model_type"] == nil {
-					validationResult["model_type"] = uvw.modelType
-				}
-				if validationResult["provider"] == "" || validationResult["provider"] == nil {
-					validationResult["provider"] = "universal-wrapper"
-				}
-			}
+// Adapt wraps a typed validator so it can be stored in the registry
+func Adapt[T any](validator Validator[T]) *TypedValidator[T] {
+	return &TypedValidator[T]{validator: validator}
+}
 
-			return result
+// Constructor turns a validator constructor into the NewValidator function of a
+// ModelSpec, e.g. Constructor[models.IncidentPayload](NewIncidentValidator).
+func Constructor[T any, V Validator[T]](newValidator func() V) func() ModelValidator {
+	return func() ModelValidator {
+		return Adapt[T](newValidator())
+	}
+}
+
+// Method builds the NewValidator function of a ModelSpec from a validator constructor
+// and one of its methods, for validators that handle several models of a family,
+// e.g. Method(NewAPIValidator, (*APIValidator).ValidateResponse).
+func Method[V any, T any](newValidator func() V, method func(V, T) models.ValidationResult) func() ModelValidator {
+	return func() ModelValidator {
+		validator := newValidator()
+		return Adapt[T](ValidatorFunc[T](func(payload T) models.ValidationResult {
+			return method(validator, payload)
+		}))
+	}
+}
+
+// ValidatePayload validates a T or *T and reports a TYPE_MISMATCH error for anything else
+func (tv *TypedValidator[T]) ValidatePayload(payload interface{}) models.ValidationResult {
+	return tv.ValidatePayloadContext(context.Background(), payload)
+}
+
+// ValidatePayloadContext is ValidatePayload with the request context, passed on
+// to validators that implement ContextValidator[T]
+func (tv *TypedValidator[T]) ValidatePayloadContext(ctx context.Context, payload interface{}) models.ValidationResult {
+	switch typed := payload.(type) {
+	case T:
+		return tv.validate(ctx, typed)
+	case *T:
+		if typed != nil {
+			return tv.validate(ctx, *typed)
 		}
 	}
 
-	// Fallback: create a basic validation result
-	return map[string]interface{}{
-		"is_valid":   false,
-		"model_type": uvw.modelType,
-		"provider":   "universal-wrapper-fallback",
-		"errors": []map[string]interface{}{{
-			"field":   "validator",
-			"message": "No suitable validation method found for " + uvw.modelType,
-			"code":    "METHOD_NOT_FOUND",
+	return models.ValidationResult{
+		IsValid:   false,
+		ModelType: tv.ModelStruct().Name(),
+		Provider:  "registry",
+		Timestamp: time.Now(),
+		Errors: []models.ValidationError{{
+			Field:    "payload",
+			Message:  fmt.Sprintf("payload must be %s, got %T", tv.ModelStruct(), payload),
+			Code:     "TYPE_MISMATCH",
+			Expected: tv.ModelStruct().String(),
 		}},
+		Warnings: []models.ValidationWarning{},
+	}
+}
+
+// validate calls the context-aware method when the validator has one
+func (tv *TypedValidator[T]) validate(ctx context.Context, payload T) models.ValidationResult {
+	if contextValidator, ok := tv.validator.(ContextValidator[T]); ok {
+		return contextValidator.ValidatePayloadContext(ctx, payload)
+	}
+	return tv.validator.ValidatePayload(payload)
+}
+
+// ModelStruct returns the reflect type of T
+func (tv *TypedValidator[T]) ModelStruct() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// ModelSpec describes a model that is compiled into the binary and registered
+// through Register, typically from an init() function next to its validator.
+// The model struct is taken from the validator, so the two cannot disagree.
+type ModelSpec struct {
+	NewValidator func() ModelValidator // Usually Constructor[Model](NewXxxValidator)
+	Name         string
+	Description  string
+	Version      string
+	Author       string
+	Tags         []string
+}
+
+var (
+	specs      = make(map[ModelType]ModelSpec)
+	specsMutex sync.RWMutex
+)
+
+// Register makes a model available to every UnifiedRegistry started afterwards.
+// It is meant to be called from init() and panics if the model type is empty,
+// the spec is incomplete, or the type was already registered.
+func Register(modelType ModelType, spec ModelSpec) {
+	if err := spec.check(modelType); err != nil {
+		panic("registry: " + err.Error())
+	}
+
+	specsMutex.Lock()
+	defer specsMutex.Unlock()
+
+	if _, exists := specs[modelType]; exists {
+		panic(fmt.Sprintf("registry: Register called twice for model type '%s'", modelType))
+	}
+	specs[modelType] = spec
+}
+
+// RegisteredSpecs returns the model types passed to Register, sorted by name.
+func RegisteredSpecs() []ModelType {
+	specsMutex.RLock()
+	defer specsMutex.RUnlock()
+
+	types := make([]ModelType, 0, len(specs))
+	for modelType := range specs {
+		types = append(types, modelType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// lookupSpec returns the spec registered for a model type
+func lookupSpec(modelType ModelType) (ModelSpec, bool) {
+	specsMutex.RLock()
+	defer specsMutex.RUnlock()
+
+	spec, exists := specs[modelType]
+	return spec, exists
+}
+
+// check reports whether the spec carries everything needed to build a ModelInfo
+func (spec ModelSpec) check(modelType ModelType) error {
+	if modelType == "" {
+		return fmt.Errorf("model type cannot be empty")
+	}
+	family, variant, hierarchical := strings.Cut(string(modelType), ".")
+	if family == "" || (hierarchical && (variant == "" || strings.Contains(variant, "."))) ||
+		strings.ContainsAny(string(modelType), "/{} ") {
+		return fmt.Errorf("model type '%s' must be 'name' or 'family.variant'", modelType)
+	}
+	if variant == "stream" {
+		// Would collide with the family's own /validate/{family}/stream endpoint
+		return fmt.Errorf("model type '%s' uses the reserved variant 'stream'", modelType)
+	}
+	if spec.NewValidator == nil {
+		return fmt.Errorf("model type '%s' has no validator constructor", modelType)
 	}
+	return nil
 }
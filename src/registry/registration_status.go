@@ -0,0 +1,78 @@
+package registry
+
+import "sort"
+
+// Registration states reported by RegistrationStatus
+const (
+	ModelStatusRegistered = "registered"
+	ModelStatusFailed     = "failed"
+	ModelStatusMissing    = "missing" // Passed to Register but not registered (yet)
+)
+
+// ModelRegistrationStatus is the registration outcome of one model
+type ModelRegistrationStatus struct {
+	Type   ModelType `json:"type"`
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// RegistrationStatus summarizes StartAutoRegistration for health checks
+type RegistrationStatus struct {
+	Complete   bool                      `json:"complete"`   // StartAutoRegistration has run
+	Expected   int                       `json:"expected"`   // Models passed to Register
+	Registered int                       `json:"registered"` // Models available for validation
+	Failed     int                       `json:"failed"`
+	Models     []ModelRegistrationStatus `json:"models"`
+}
+
+// Ready reports whether registration finished and every compiled-in model is available
+func (rs RegistrationStatus) Ready() bool {
+	return rs.Complete && rs.Registered > 0 && rs.Failed == 0 && rs.missing() == 0
+}
+
+// missing counts compiled-in models that are neither registered nor failed
+func (rs RegistrationStatus) missing() int {
+	count := 0
+	for _, model := range rs.Models {
+		if model.Status == ModelStatusMissing {
+			count++
+		}
+	}
+	return count
+}
+
+// RegistrationStatus returns the registration state of every compiled-in model
+// and of models registered directly with RegisterModel
+func (ur *UnifiedRegistry) RegistrationStatus() RegistrationStatus {
+	specTypes := RegisteredSpecs()
+
+	ur.mutex.RLock()
+	defer ur.mutex.RUnlock()
+
+	status := RegistrationStatus{
+		Complete:   ur.registrationComplete,
+		Expected:   len(specTypes),
+		Registered: len(ur.models),
+		Failed:     len(ur.registrationFailures),
+	}
+
+	seen := make(map[ModelType]bool, len(specTypes)+len(ur.models))
+	for _, modelType := range specTypes {
+		seen[modelType] = true
+		model := ModelRegistrationStatus{Type: modelType, Status: ModelStatusMissing}
+		if reason, failed := ur.registrationFailures[modelType]; failed {
+			model.Status, model.Error = ModelStatusFailed, reason
+		} else if _, registered := ur.models[modelType]; registered {
+			model.Status = ModelStatusRegistered
+		}
+		status.Models = append(status.Models, model)
+	}
+	for modelType := range ur.models {
+		if !seen[modelType] {
+			status.Models = append(status.Models, ModelRegistrationStatus{Type: modelType, Status: ModelStatusRegistered})
+		}
+	}
+
+	sort.Slice(status.Models, func(i, j int) bool { return status.Models[i].Type < status.Models[j].Type })
+	return status
+}
@@ -0,0 +1,316 @@
+package registry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"goplayground-data-validator/admission"
+	"goplayground-data-validator/jsonlimit"
+	"goplayground-data-validator/logging"
+	"goplayground-data-validator/models"
+	"goplayground-data-validator/problem"
+	"goplayground-data-validator/tracing"
+)
+
+const (
+	// streamMaxLineBytes is the longest NDJSON line the stream accepts
+	streamMaxLineBytes = 1 << 20
+	// streamFlushEvery is how many row lines are buffered before a flush
+	streamFlushEvery = 100
+	// streamIdleTimeout bounds the wait for the next line and for the client to read
+	streamIdleTimeout = 30 * time.Second
+)
+
+// ndjsonMediaTypes are the Content-Types accepted by the stream endpoint
+var ndjsonMediaTypes = map[string]bool{
+	"application/x-ndjson":    true,
+	"application/jsonl":       true,
+	"application/x-jsonlines": true,
+}
+
+// ValidateStream validates newline-delimited JSON records read from src one at a time,
+// passing each row result to emit as soon as it is known. Blank lines are skipped and
+// a line that is not a JSON object or exceeds a jsonlimit limit becomes an invalid row.
+// The stream stops early when emit fails, ctx is done, a line exceeds the size limit,
+// or opts asks to stop on the first invalid row; the returned summary then carries
+// the reason in Error.
+func (ur *UnifiedRegistry) ValidateStream(ctx context.Context, modelType ModelType, src io.Reader, threshold *float64, opts models.BatchOptions, emit func(models.RowValidationResult) error) (*models.StreamValidationSummary, error) {
+	batchID := models.GenerateBatchID("stream")
+	startTime := time.Now()
+
+	ctx, span := tracing.Start(ctx, "registry.ValidateStream",
+		attribute.String("validator.model", string(modelType)),
+		attribute.String("validator.batch_id", batchID))
+	defer span.End()
+
+	modelInfo, err := ur.GetModel(modelType)
+	if err != nil {
+		err = fmt.Errorf("model type not found: %w", err)
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(make([]byte, 0, 64*1024), streamMaxLineBytes)
+
+	stopOnError := opts.StopOnFirstError || opts.FailFast
+	limits := jsonlimit.Current()
+	builder := models.NewSummaryBuilder()
+	validCount, invalidCount, warningCount := 0, 0, 0
+	var streamErr error
+
+	for rowIndex := 0; ; {
+		if err := ctx.Err(); err != nil {
+			streamErr = context.Cause(ctx)
+			break
+		}
+		if !scanner.Scan() {
+			streamErr = scanner.Err()
+			break
+		}
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var rowResult models.RowValidationResult
+		var limitErr *jsonlimit.LimitError
+		if err := jsonlimit.Check(line, limits); errors.As(err, &limitErr) {
+			rowResult = invalidLineResult(modelType, rowIndex, err)
+		} else if !json.Valid(line) {
+			rowResult = invalidLineResult(modelType, rowIndex, json.Unmarshal(line, new(json.RawMessage)))
+		} else if bytes.TrimSpace(line)[0] != '{' {
+			rowResult = invalidLineResult(modelType, rowIndex, nil)
+		} else {
+			// The line is only read until the next Scan, which waits for the row
+			row := func(i int) (json.RawMessage, string, error) {
+				return line, models.DetectRawRecordIdentifier(line, i), nil
+			}
+			rowResult = ur.validateRow(ctx, modelType, modelInfo, row, rowIndex)
+		}
+		rowIndex++
+
+		builder.Add(rowResult)
+		observeRecord(string(modelType), rowResult.IsValid, rowResult.Errors, rowResult.Warnings)
+		if rowResult.IsValid {
+			validCount++
+			if len(rowResult.Warnings) > 0 {
+				warningCount++
+			}
+		} else {
+			invalidCount++
+		}
+
+		if err := emit(rowResult); err != nil {
+			streamErr = err
+			break
+		}
+		if stopOnError && !rowResult.IsValid {
+			break
+		}
+	}
+
+	summary := builder.Summary()
+	totalRecords := summary.TotalRecordsProcessed
+
+	result := &models.StreamValidationSummary{
+		Type:           "summary",
+		BatchID:        batchID,
+		RequestID:      tracing.RequestID(ctx),
+		TraceID:        tracing.TraceID(ctx),
+		Status:         batchStatus(totalRecords, invalidCount, summary.SuccessRate, threshold, stopOnError),
+		TotalRecords:   totalRecords,
+		ValidRecords:   validCount,
+		InvalidRecords: invalidCount,
+		WarningRecords: warningCount,
+		Threshold:      threshold,
+		ProcessingTime: time.Since(startTime).Milliseconds(),
+		CompletedAt:    time.Now(),
+		Summary:        summary,
+	}
+	if streamErr != nil {
+		// A stream that did not reach its end cannot pass, whatever the rows so far say
+		result.Status = "failed"
+		if errors.Is(streamErr, bufio.ErrTooLong) {
+			streamErr = fmt.Errorf("line %d exceeds %d bytes", totalRecords+1, streamMaxLineBytes)
+		}
+		result.Error = fmt.Sprintf("stream stopped after %d records: %v", totalRecords, streamErr)
+		tracing.Fail(span, streamErr)
+	}
+	span.SetAttributes(
+		attribute.String("validator.status", result.Status),
+		attribute.Int("validator.records", totalRecords),
+		attribute.Int("validator.invalid_records", invalidCount),
+	)
+	observeBatch(modelType, modeStream, totalRecords, threshold, result.Status, time.Since(startTime))
+	logging.AddFields(ctx,
+		slog.String("model", string(modelType)),
+		slog.Int("records", totalRecords),
+		slog.Int("invalid_records", invalidCount),
+		slog.String("validation_status", result.Status),
+	)
+
+	return result, nil
+}
+
+// invalidLineResult reports a stream line that could not be decoded as a JSON object
+func invalidLineResult(modelType ModelType, rowIndex int, err error) models.RowValidationResult {
+	message, code := "Line is not a JSON object", "INVALID_JSON"
+	var limitErr *jsonlimit.LimitError
+	if errors.As(err, &limitErr) {
+		message, code = "Line exceeds a JSON input limit: "+limitErr.Error(), "JSON_LIMIT_EXCEEDED"
+	} else if err != nil {
+		message = fmt.Sprintf("Line is not valid JSON: %v", err)
+	}
+	return models.RowValidationResult{
+		RowIndex:         rowIndex,
+		RecordIdentifier: fmt.Sprintf("row_%d", rowIndex),
+		IsValid:          false,
+		TestName:         fmt.Sprintf("%sValidator:%s", toTitleCase(string(modelType)), code),
+		Errors: []models.ValidationError{{
+			Field:   "record",
+			Message: message,
+			Code:    code,
+		}},
+		Warnings: []models.ValidationWarning{},
+	}
+}
+
+// createStreamHandler creates the NDJSON stream handler for a specific model.
+// Row results are written as they are produced, followed by a summary trailer.
+func (ur *UnifiedRegistry) createStreamHandler(modelType ModelType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		logging.AddFields(r.Context(), slog.String("model", string(modelType)))
+
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || !ndjsonMediaTypes[mediaType] {
+			problem.Write(w, r, http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType, "Content-Type must be application/x-ndjson")
+			return
+		}
+
+		query := r.URL.Query()
+		threshold := ur.DefaultThreshold(modelType)
+		if raw := query.Get("threshold"); raw != "" {
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil || value < 0 || value > 100 {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "threshold must be a number between 0 and 100")
+				return
+			}
+			threshold = &value
+		}
+
+		var opts models.BatchOptions
+		for name, flag := range map[string]*bool{"fail_fast": &opts.FailFast, "stop_on_first_error": &opts.StopOnFirstError} {
+			if raw := query.Get(name); raw != "" {
+				if *flag, err = strconv.ParseBool(raw); err != nil {
+					problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, name+" must be true or false")
+					return
+				}
+			}
+		}
+		if raw := query.Get("timeout"); raw != "" {
+			if opts.Timeout, err = time.ParseDuration(raw); err != nil || opts.Timeout <= 0 {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "timeout must be a positive duration such as 30s")
+				return
+			}
+		}
+		// Rows are validated in order as they arrive, so there are no workers to size
+		if query.Has("max_concurrency") {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "max_concurrency is not supported by streams, which validate one row at a time")
+			return
+		}
+
+		// Rows are validated one at a time, so a stream holds one record of capacity
+		release, err := admission.Default().Acquire(r.Context(), string(modelType), 1)
+		if err != nil {
+			admission.WriteError(w, r, err)
+			return
+		}
+		defer release()
+
+		// The server's read and write timeouts cover the whole request, which a
+		// multi-GB stream will outlive; keep pushing the deadlines out instead, and
+		// let HTTP/1.1 keep reading the body after the first row is written
+		rc := http.NewResponseController(w)
+		if err := rc.EnableFullDuplex(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			slog.WarnContext(r.Context(), "Cannot enable full duplex for stream", "model", modelType, "error", err)
+		}
+		body := &deadlineReader{reader: r.Body, rc: rc}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+		if err := flushStream(rc); err != nil {
+			return
+		}
+
+		encoder := json.NewEncoder(w)
+		written := 0
+		emit := func(row models.RowValidationResult) error {
+			_ = rc.SetWriteDeadline(time.Now().Add(streamIdleTimeout))
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+			written++
+			if written%streamFlushEvery == 0 {
+				return flushStream(rc)
+			}
+			return nil
+		}
+
+		summary, err := ur.ValidateStream(r.Context(), modelType, body, threshold, opts, emit)
+		if err != nil {
+			// Headers are already sent; the trailer is the only place left to report it
+			summary = &models.StreamValidationSummary{Type: "summary", Status: "failed", CompletedAt: time.Now(), Error: err.Error()}
+		}
+
+		_ = rc.SetWriteDeadline(time.Now().Add(streamIdleTimeout))
+		if err := encoder.Encode(summary); err != nil {
+			slog.WarnContext(r.Context(), "Failed to write stream summary", "error", err)
+			return
+		}
+		if err := flushStream(rc); err != nil {
+			slog.WarnContext(r.Context(), "Failed to flush stream summary", "error", err)
+		}
+	}
+}
+
+// flushStream flushes buffered rows to the client; writers that cannot flush are left to buffer
+func flushStream(rc *http.ResponseController) error {
+	if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// deadlineReader extends the connection read deadline before every read, so a
+// long stream is bounded by how long it idles rather than by its total length
+type deadlineReader struct {
+	reader io.Reader
+	rc     *http.ResponseController
+}
+
+// Read extends the read deadline and reads from the request body
+func (dr *deadlineReader) Read(p []byte) (int, error) {
+	_ = dr.rc.SetReadDeadline(time.Now().Add(streamIdleTimeout))
+	return dr.reader.Read(p)
+}
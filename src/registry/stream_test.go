@@ -0,0 +1,348 @@
+package registry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"goplayground-data-validator/jsonlimit"
+	"goplayground-data-validator/models"
+)
+
+// newStreamTestRegistry returns a registry with a "mixed" model served over HTTP
+func newStreamTestRegistry(t *testing.T) (*UnifiedRegistry, *http.ServeMux) {
+	t.Helper()
+	registry := NewUnifiedRegistry()
+	registry.mux = http.NewServeMux()
+	if err := registry.RegisterModel(&ModelInfo{
+		Type:        "mixed",
+		Name:        "Mixed",
+		ModelStruct: reflect.TypeOf(models.GenericPayload{}),
+		Validator:   Adapt[models.GenericPayload](&mockValidatorWithMixedResults{}),
+	}); err != nil {
+		t.Fatalf("RegisterModel failed: %v", err)
+	}
+	registry.registerAllHTTPEndpoints()
+	return registry, registry.mux
+}
+
+// readStream splits an NDJSON response into row results and the summary trailer
+func readStream(t *testing.T, body io.Reader) ([]models.RowValidationResult, models.StreamValidationSummary) {
+	t.Helper()
+	var lines [][]byte
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	if len(lines) == 0 {
+		t.Fatal("Expected at least a summary line")
+	}
+
+	rows := make([]models.RowValidationResult, len(lines)-1)
+	for i, line := range lines[:len(lines)-1] {
+		if err := json.Unmarshal(line, &rows[i]); err != nil {
+			t.Fatalf("Failed to decode row line %q: %v", line, err)
+		}
+	}
+	var summary models.StreamValidationSummary
+	if err := json.Unmarshal(lines[len(lines)-1], &summary); err != nil {
+		t.Fatalf("Failed to decode summary line: %v", err)
+	}
+	if summary.Type != "summary" {
+		t.Fatalf("Last line is not a summary: %s", lines[len(lines)-1])
+	}
+	return rows, summary
+}
+
+// TestUnifiedRegistry_StreamHandler tests the NDJSON stream endpoint
+func TestUnifiedRegistry_StreamHandler(t *testing.T) {
+	_, mux := newStreamTestRegistry(t)
+
+	body := strings.Join([]string{
+		`{"id":"valid-1"}`,
+		``,
+		`{"id":"invalid-2"}`,
+		`{"id":`,
+		`{"id":"valid-4"}`,
+	}, "\n")
+
+	tests := []struct {
+		name        string
+		query       string
+		wantRows    int
+		wantStatus  string
+		wantInvalid int
+	}{
+		{"no threshold", "", 4, "success", 2},
+		{"threshold met", "?threshold=50", 4, "success", 2},
+		{"threshold missed", "?threshold=75", 4, "failed", 2},
+		{"fail fast", "?fail_fast=true", 2, "failed", 1},
+		{"stop on first error", "?stop_on_first_error=true", 2, "failed", 1},
+		{"timeout not reached", "?timeout=1m", 4, "success", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/validate/mixed/stream"+tt.query, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/x-ndjson")
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+				t.Errorf("Content-Type = %s, want application/x-ndjson", ct)
+			}
+
+			rows, summary := readStream(t, w.Body)
+			if len(rows) != tt.wantRows {
+				t.Fatalf("Got %d rows, want %d", len(rows), tt.wantRows)
+			}
+			for i, row := range rows {
+				if row.RowIndex != i {
+					t.Errorf("Row %d has index %d", i, row.RowIndex)
+				}
+			}
+			if summary.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", summary.Status, tt.wantStatus)
+			}
+			if summary.TotalRecords != tt.wantRows || summary.InvalidRecords != tt.wantInvalid {
+				t.Errorf("total/invalid = %d/%d, want %d/%d", summary.TotalRecords, summary.InvalidRecords, tt.wantRows, tt.wantInvalid)
+			}
+			if summary.Summary.TotalRecordsProcessed != tt.wantRows {
+				t.Errorf("Summary processed %d records, want %d", summary.Summary.TotalRecordsProcessed, tt.wantRows)
+			}
+		})
+	}
+
+	t.Run("timeout ends the stream", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/validate/mixed/stream?timeout=1ns", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		_, summary := readStream(t, w.Body)
+		if summary.Status != "failed" || summary.Error == "" {
+			t.Errorf("Expected a failed summary with an error, got %+v", summary)
+		}
+	})
+
+	t.Run("malformed line becomes an invalid row", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/validate/mixed/stream", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/jsonl; charset=utf-8")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		rows, _ := readStream(t, w.Body)
+		if rows[2].IsValid || len(rows[2].Errors) != 1 || rows[2].Errors[0].Code != "INVALID_JSON" {
+			t.Errorf("Expected INVALID_JSON for row 2, got %+v", rows[2])
+		}
+	})
+
+	t.Run("line over a JSON limit becomes an invalid row", func(t *testing.T) {
+		defer jsonlimit.Configure(jsonlimit.DefaultLimits())
+		jsonlimit.Configure(jsonlimit.Limits{MaxDepth: 2})
+
+		req := httptest.NewRequest("POST", "/validate/mixed/stream", strings.NewReader(`{"id":"valid-1","tags":[{"a":1}]}`))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		rows, _ := readStream(t, w.Body)
+		if len(rows) != 1 || rows[0].IsValid || rows[0].Errors[0].Code != "JSON_LIMIT_EXCEEDED" {
+			t.Fatalf("Expected JSON_LIMIT_EXCEEDED for row 0, got %+v", rows)
+		}
+		if !strings.Contains(rows[0].Errors[0].Message, "$.tags[0]") {
+			t.Errorf("Expected the message to name the path, got %q", rows[0].Errors[0].Message)
+		}
+	})
+}
+
+// TestUnifiedRegistry_StreamHandler_BadRequests tests requests rejected before streaming
+func TestUnifiedRegistry_StreamHandler_BadRequests(t *testing.T) {
+	_, mux := newStreamTestRegistry(t)
+
+	tests := []struct {
+		name        string
+		contentType string
+		query       string
+		wantStatus  int
+	}{
+		{"json content type", "application/json", "", http.StatusUnsupportedMediaType},
+		{"missing content type", "", "", http.StatusUnsupportedMediaType},
+		{"bad threshold", "application/x-ndjson", "?threshold=abc", http.StatusBadRequest},
+		{"threshold out of range", "application/x-ndjson", "?threshold=150", http.StatusBadRequest},
+		{"bad fail_fast", "application/x-ndjson", "?fail_fast=maybe", http.StatusBadRequest},
+		{"bad stop_on_first_error", "application/x-ndjson", "?stop_on_first_error=maybe", http.StatusBadRequest},
+		{"bad timeout", "application/x-ndjson", "?timeout=soon", http.StatusBadRequest},
+		{"negative timeout", "application/x-ndjson", "?timeout=-1s", http.StatusBadRequest},
+		{"max_concurrency", "application/x-ndjson", "?max_concurrency=4", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/validate/mixed/stream"+tt.query, strings.NewReader(`{"id":"valid-1"}`))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+// TestUnifiedRegistry_ValidateStream tests stream validation outside HTTP
+func TestUnifiedRegistry_ValidateStream(t *testing.T) {
+	registry, _ := newStreamTestRegistry(t)
+	collect := func(rows *[]models.RowValidationResult) func(models.RowValidationResult) error {
+		return func(row models.RowValidationResult) error {
+			*rows = append(*rows, row)
+			return nil
+		}
+	}
+
+	t.Run("unknown model", func(t *testing.T) {
+		var rows []models.RowValidationResult
+		if _, err := registry.ValidateStream(context.Background(), "missing", strings.NewReader(""), nil, models.BatchOptions{}, collect(&rows)); err == nil {
+			t.Error("Expected error for unknown model")
+		}
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		var rows []models.RowValidationResult
+		summary, err := registry.ValidateStream(context.Background(), "mixed", strings.NewReader("\n\n"), nil, models.BatchOptions{}, collect(&rows))
+		if err != nil {
+			t.Fatalf("ValidateStream failed: %v", err)
+		}
+		if len(rows) != 0 || summary.TotalRecords != 0 || summary.Status != "success" || summary.Error != "" {
+			t.Errorf("Unexpected result for empty stream: rows=%d summary=%+v", len(rows), summary)
+		}
+	})
+
+	t.Run("line too long", func(t *testing.T) {
+		input := `{"id":"valid-1"}` + "\n" + `{"id":"` + strings.Repeat("x", streamMaxLineBytes) + `"}` + "\n"
+		var rows []models.RowValidationResult
+		summary, err := registry.ValidateStream(context.Background(), "mixed", strings.NewReader(input), nil, models.BatchOptions{}, collect(&rows))
+		if err != nil {
+			t.Fatalf("ValidateStream failed: %v", err)
+		}
+		if len(rows) != 1 || summary.Status != "failed" || !strings.Contains(summary.Error, "exceeds") {
+			t.Errorf("Expected failure after 1 row, got rows=%d summary=%+v", len(rows), summary)
+		}
+	})
+
+	t.Run("emit error stops the stream", func(t *testing.T) {
+		input := strings.Repeat(`{"id":"valid"}`+"\n", 10)
+		emitted := 0
+		summary, err := registry.ValidateStream(context.Background(), "mixed", strings.NewReader(input), nil, models.BatchOptions{}, func(models.RowValidationResult) error {
+			emitted++
+			if emitted == 3 {
+				return errors.New("client went away")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ValidateStream failed: %v", err)
+		}
+		if emitted != 3 || summary.TotalRecords != 3 || !strings.Contains(summary.Error, "client went away") {
+			t.Errorf("Expected stop after 3 rows, got emitted=%d summary=%+v", emitted, summary)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var rows []models.RowValidationResult
+		summary, err := registry.ValidateStream(ctx, "mixed", strings.NewReader(`{"id":"valid-1"}`), nil, models.BatchOptions{}, collect(&rows))
+		if err != nil {
+			t.Fatalf("ValidateStream failed: %v", err)
+		}
+		if len(rows) != 0 || summary.Status != "failed" || !strings.Contains(summary.Error, context.Canceled.Error()) {
+			t.Errorf("Expected cancelled stream, got rows=%d summary=%+v", len(rows), summary)
+		}
+	})
+}
+
+// TestUnifiedRegistry_StreamHandler_Incremental tests that rows are returned
+// while the client is still sending the request body
+func TestUnifiedRegistry_StreamHandler_Incremental(t *testing.T) {
+	_, mux := newStreamTestRegistry(t)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	bodyReader, bodyWriter := io.Pipe()
+	req, err := http.NewRequest("POST", server.URL+"/validate/mixed/stream", bodyReader)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	writeRows := func(from, to int) {
+		var buf bytes.Buffer
+		for i := from; i < to; i++ {
+			fmt.Fprintf(&buf, `{"id":"valid-%d"}`+"\n", i)
+		}
+		if _, err := bodyWriter.Write(buf.Bytes()); err != nil {
+			t.Errorf("Failed to write rows: %v", err)
+		}
+	}
+
+	responses := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("Request failed: %v", err)
+			close(responses)
+			return
+		}
+		responses <- resp
+	}()
+
+	writeRows(0, streamFlushEvery)
+	var resp *http.Response
+	select {
+	case resp = <-responses:
+		if resp == nil {
+			return
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for response headers")
+	}
+	defer resp.Body.Close()
+
+	// The first flushed batch must arrive before the body is finished
+	reader := bufio.NewReader(resp.Body)
+	for i := 0; i < streamFlushEvery; i++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("Failed to read row %d before the body was closed: %v", i, err)
+		}
+		var row models.RowValidationResult
+		if err := json.Unmarshal(line, &row); err != nil || row.RowIndex != i {
+			t.Fatalf("Unexpected row %d: %s", i, line)
+		}
+	}
+
+	writeRows(streamFlushEvery, streamFlushEvery+5)
+	bodyWriter.Close()
+
+	rows, summary := readStream(t, reader)
+	if len(rows) != 5 || summary.TotalRecords != streamFlushEvery+5 || summary.Status != "success" {
+		t.Errorf("Unexpected tail: rows=%d summary=%+v", len(rows), summary)
+	}
+}
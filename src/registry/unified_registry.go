@@ -1,340 +1,172 @@
 // Package registry provides a unified, fully automatic model registration system.
-// This system combines auto-discovery, file monitoring, and HTTP endpoint management
-// into a single, efficient implementation with zero configuration required.
+// Models register themselves at compile time through Register, and the registry
+// turns them into validators and HTTP endpoints with zero runtime configuration.
 package registry
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"go/ast"
-	"go/parser"
-	"go/token"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
-	"path/filepath"
 	"reflect"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	"goplayground-data-validator/admission"
+	"goplayground-data-validator/codec"
+	"goplayground-data-validator/jsonlimit"
+	"goplayground-data-validator/logging"
 	"goplayground-data-validator/models"
-	"goplayground-data-validator/validations"
+	"goplayground-data-validator/problem"
+	"goplayground-data-validator/tracing"
 )
 
-// Removed FileSystemWatcher - keeping the system simple with startup-only registration
-
 // UnifiedRegistry is the single, consolidated registry system that handles:
-// - Automatic model discovery and registration
-// - File system monitoring for dynamic updates
+// - Registration of models compiled into the binary via Register
 // - HTTP endpoint creation and management
-// - Universal validation with any validator type
+// - Type-safe validation through validators adapted from Validator[T]
 type UnifiedRegistry struct {
-	models          map[ModelType]*ModelInfo
-	modelsPath      string
-	validationsPath string
-	mux             *http.ServeMux
-	mutex           sync.RWMutex
+	models map[ModelType]*ModelInfo
+	mux    *http.ServeMux
+	mutex  sync.RWMutex
+
+	registrationComplete bool                 // Set when StartAutoRegistration has run
+	registrationFailures map[ModelType]string // Specs that failed to register, with the reason
+
+	defaultThresholds map[ModelType]float64 // Thresholds for requests that name none; "" is the fallback for every model
 }
 
 // NewUnifiedRegistry creates a new unified registry instance
-func NewUnifiedRegistry(modelsPath, validationsPath string) *UnifiedRegistry {
+func NewUnifiedRegistry() *UnifiedRegistry {
 	return &UnifiedRegistry{
-		models:          make(map[ModelType]*ModelInfo),
-		modelsPath:      modelsPath,
-		validationsPath: validationsPath,
-		mutex:           sync.RWMutex{},
+		models:            make(map[ModelType]*ModelInfo),
+		mutex:             sync.RWMutex{},
+		defaultThresholds: make(map[ModelType]float64),
 	}
 }
 
-// StartAutoRegistration performs initial discovery and starts file system monitoring
-func (ur *UnifiedRegistry) StartAutoRegistration(_ context.Context, mux *http.ServeMux) error {
+// StartAutoRegistration registers every compiled-in model and its HTTP endpoints.
+// It returns once registration is complete; models that fail to register are
+// reported in the error, after the endpoints of the others have been added.
+func (ur *UnifiedRegistry) StartAutoRegistration(ctx context.Context, mux *http.ServeMux) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	ur.mux = mux
 
-	log.Println("🚀 Starting unified automatic model registration system...")
+	slog.Info("Starting model registration")
 
-	// Phase 1: Initial discovery and registration
-	if err := ur.discoverAndRegisterAll(); err != nil {
-		log.Printf("⚠️ Initial discovery had issues: %v", err)
+	// Phase 1: Register all models passed to Register
+	registrationErr := ur.registerCompiledModels()
+	if registrationErr != nil {
+		slog.Warn("Model registration had issues", "error", registrationErr)
 	}
 
-	// Phase 2: Register HTTP endpoints for discovered models (only once)
+	// Phase 2: Register HTTP endpoints for registered models (only once)
 	ur.registerAllHTTPEndpoints()
 
-	// Phase 3: File system monitoring removed - keeping it simple with startup-only registration
-	log.Println("✅ Pure auto-registration completed - models will be discovered on each startup")
+	ur.mutex.Lock()
+	ur.registrationComplete = true
+	ur.mutex.Unlock()
+
+	if registrationErr != nil {
+		return registrationErr
+	}
+	slog.Info("Model registration completed")
 
 	return nil
 }
 
-// discoverAndRegisterAll scans directories and registers all found models
-func (ur *UnifiedRegistry) discoverAndRegisterAll() error {
-	log.Println("🔍 Starting comprehensive model discovery...")
-
-	modelFiles, err := filepath.Glob(filepath.Join(ur.modelsPath, "*.go"))
-	if err != nil {
-		return fmt.Errorf("scanning models directory: %w", err)
-	}
+// registerCompiledModels registers every model spec passed to Register
+func (ur *UnifiedRegistry) registerCompiledModels() error {
+	slog.Debug("Registering compiled-in models")
 
 	registered := 0
 	var errors []string
-
-	for _, modelFile := range modelFiles {
-		baseName := strings.TrimSuffix(filepath.Base(modelFile), ".go")
-
-		// Skip test files and package files
-		if strings.HasSuffix(baseName, "_test") || baseName == "models" {
+	failures := make(map[ModelType]string)
+
+	for _, modelType := range RegisteredSpecs() {
+		spec, _ := lookupSpec(modelType)
+		if err := ur.RegisterSpec(modelType, spec); err != nil {
+			slog.Error("Failed to register model", "model", modelType, "error", err)
+			errors = append(errors, fmt.Sprintf("%s: %v", modelType, err))
+			failures[modelType] = err.Error()
 			continue
 		}
-
-		// Check if corresponding validator exists
-		validatorFile := filepath.Join(ur.validationsPath, baseName+".go")
-		if _, err := os.Stat(validatorFile); os.IsNotExist(err) {
-			log.Printf("⚠️ No validator found for model: %s (skipping)", baseName)
-			continue
-		}
-
-		// Attempt automatic registration
-		if err := ur.registerModelAutomatically(baseName); err != nil {
-			log.Printf("❌ Failed to register model %s: %v", baseName, err)
-			errors = append(errors, fmt.Sprintf("%s: %v", baseName, err))
-			continue
-		}
-
 		registered++
-		log.Printf("✅ Auto-registered model: %s", baseName)
 	}
 
-	log.Printf("🎉 Discovery completed: %d models registered", registered)
+	ur.mutex.Lock()
+	ur.registrationFailures = failures
+	ur.mutex.Unlock()
+
+	slog.Info("Registered models", "count", registered)
 
 	if len(errors) > 0 {
-		log.Printf("⚠️ %d models had registration issues:", len(errors))
-		for _, errMsg := range errors {
-			log.Printf("   - %s", errMsg)
-		}
+		return fmt.Errorf("%d models had registration issues: %s", len(errors), strings.Join(errors, "; "))
 	}
 
 	return nil
 }
 
-// registerModelAutomatically discovers and registers a single model
-func (ur *UnifiedRegistry) registerModelAutomatically(baseName string) error {
-	log.Printf("🔍 Auto-registering model: %s", baseName)
-
-	// Step 1: Find model struct type
-	modelStruct, structName, err := ur.discoverModelStruct(baseName)
-	if err != nil {
-		return fmt.Errorf("discovering model struct: %w", err)
+// RegisterSpec builds model information from a spec and registers it
+func (ur *UnifiedRegistry) RegisterSpec(modelType ModelType, spec ModelSpec) error {
+	if err := spec.check(modelType); err != nil {
+		return err
 	}
 
-	// Step 2: Create validator instance
-	validatorInstance, err := ur.createValidatorInstance(baseName)
-	if err != nil {
-		return fmt.Errorf("creating validator: %w", err)
+	// Step 1: Create the validator; it knows which model struct it accepts
+	validator := spec.NewValidator()
+	if validator == nil {
+		return fmt.Errorf("model type '%s' validator constructor returned nil", modelType)
 	}
-
-	// Step 3: Create universal wrapper
-	wrapper := &UniversalValidatorWrapper{
-		modelType:         baseName,
-		validatorInstance: validatorInstance,
-		modelStructType:   modelStruct,
+	modelStruct := validator.ModelStruct()
+	if modelStruct.Kind() != reflect.Struct {
+		return fmt.Errorf("model type '%s' must validate a struct, got %s", modelType, modelStruct.Kind())
 	}
 
-	// Step 4: Create model info with metadata
-	modelInfo := &ModelInfo{
-		Type:        ModelType(baseName),
-		Name:        ur.generateModelName(baseName, structName),
-		Description: ur.generateModelDescription(baseName),
-		ModelStruct: modelStruct,
-		Validator:   wrapper,
-		Version:     "1.0.0",
-		CreatedAt:   time.Now().Format(time.RFC3339),
-		Author:      "Unified Auto-Registry",
-		Tags:        ur.generateModelTags(baseName),
+	// Step 2: Fill in metadata defaults for specs that leave them out
+	name := spec.Name
+	if name == "" {
+		name = toTitleCase(string(modelType)) + " Data"
 	}
-
-	// Step 5: Register the model
-	return ur.RegisterModel(modelInfo)
-}
-
-// discoverModelStruct finds the correct struct type for a model
-func (ur *UnifiedRegistry) discoverModelStruct(baseName string) (reflect.Type, string, error) {
-	// Parse the Go file to find struct names
-	modelFile := filepath.Join(ur.modelsPath, baseName+".go")
-	discoveredStructs, err := ur.parseGoFileForStructs(modelFile)
-	if err != nil {
-		log.Printf("⚠️ Could not parse %s: %v", modelFile, err)
-	}
-
-	// Try different naming patterns
-	possibleNames := discoveredStructs
-	titleCase := toTitleCase(baseName)
-	possibleNames = append(possibleNames,
-		titleCase+"Payload",
-		titleCase+"Model",
-		titleCase+"Request",
-		titleCase+"Data",
-		titleCase,
-	)
-
-	// Get known model types
-	knownTypes := ur.getKnownModelTypes()
-
-	for _, name := range possibleNames {
-		if structType, exists := knownTypes[name]; exists {
-			log.Printf("✅ Found model struct: %s -> %s", baseName, name)
-			return structType, name, nil
-		}
+	description := spec.Description
+	if description == "" {
+		description = fmt.Sprintf("%s validation with comprehensive business rules", toTitleCase(string(modelType)))
 	}
-
-	return nil, "", fmt.Errorf("no model struct found for %s (tried: %v)", baseName, possibleNames)
-}
-
-// parseGoFileForStructs extracts struct names from a Go file
-func (ur *UnifiedRegistry) parseGoFileForStructs(filename string) ([]string, error) {
-	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
-	if err != nil {
-		return nil, err
+	version := spec.Version
+	if version == "" {
+		version = "1.0.0"
 	}
-
-	var structNames []string
-	ast.Inspect(node, func(n ast.Node) bool {
-		if typeSpec, ok := n.(*ast.TypeSpec); ok {
-			if _, ok := typeSpec.Type.(*ast.StructType); ok {
-				structNames = append(structNames, typeSpec.Name.Name)
-			}
-		}
-		return true
-	})
-
-	return structNames, nil
-}
-
-// createValidatorInstance creates validator using naming conventions
-func (ur *UnifiedRegistry) createValidatorInstance(baseName string) (interface{}, error) {
-	// Try multiple naming patterns
-	// Handle special cases first
-	specialCases := map[string]string{
-		"github": "GitHub",
-		"api":    "API",
-		"db":     "DB",
-		"http":   "HTTP",
-		"json":   "JSON",
-		"xml":    "XML",
-		"url":    "URL",
+	author := spec.Author
+	if author == "" {
+		author = "Unified Registry"
 	}
-
-	var titleCase string
-	if special, exists := specialCases[baseName]; exists {
-		titleCase = special
-	} else {
-		titleCase = toTitleCase(baseName)
+	tags := spec.Tags
+	if len(tags) == 0 {
+		tags = []string{strings.ToLower(string(modelType))}
 	}
 
-	possibleNames := []string{
-		"New" + titleCase + "Validator",                 // NewGitHubValidator, NewAPIValidator
-		"New" + toTitleCase(baseName) + "Validator",     // NewGithubValidator
-		"New" + strings.ToUpper(baseName) + "Validator", // NewGITHUBValidator
-	}
-
-	knownValidators := ur.getKnownValidatorConstructors()
-
-	for _, constructorName := range possibleNames {
-		log.Printf("🔍 Looking for: %s", constructorName)
-		if constructor, exists := knownValidators[constructorName]; exists {
-			log.Printf("✅ Found validator constructor: %s", constructorName)
-			return constructor(), nil
-		}
-	}
-
-	return nil, fmt.Errorf("no validator constructor found (tried: %v)", possibleNames)
-}
-
-// getKnownModelTypes returns available model types via reflection
-func (ur *UnifiedRegistry) getKnownModelTypes() map[string]reflect.Type {
-	return map[string]reflect.Type{
-		"IncidentPayload":   reflect.TypeOf(models.IncidentPayload{}),
-		"GitHubPayload":     reflect.TypeOf(models.GitHubPayload{}),
-		"APIRequest":        reflect.TypeOf(models.APIRequest{}),
-		"DatabaseQuery":     reflect.TypeOf(models.DatabaseQuery{}),
-		"GenericPayload":    reflect.TypeOf(models.GenericPayload{}),
-		"DeploymentPayload": reflect.TypeOf(models.DeploymentPayload{}),
-	}
-}
-
-// getKnownValidatorConstructors returns available validator constructors
-func (ur *UnifiedRegistry) getKnownValidatorConstructors() map[string]func() interface{} {
-	return map[string]func() interface{}{
-		"NewIncidentValidator":   func() interface{} { return validations.NewIncidentValidator() },
-		"NewGitHubValidator":     func() interface{} { return validations.NewGitHubValidator() },
-		"NewAPIValidator":        func() interface{} { return validations.NewAPIValidator() },
-		"NewDatabaseValidator":   func() interface{} { return validations.NewDatabaseValidator() },
-		"NewGenericValidator":    func() interface{} { return validations.NewGenericValidator() },
-		"NewDeploymentValidator": func() interface{} { return validations.NewDeploymentValidator() },
-	}
-}
-
-// generateModelName creates human-readable model names
-func (ur *UnifiedRegistry) generateModelName(baseName, structName string) string {
-	contextualNames := map[string]string{
-		"github":     "GitHub Webhook",
-		"api":        "API Request/Response",
-		"database":   "Database Operations",
-		"generic":    "Generic Payload",
-		"deployment": "Deployment Webhook",
-		"incident":   "Incident Report",
-	}
-
-	if name, exists := contextualNames[strings.ToLower(baseName)]; exists {
-		return name
-	}
-
-	if strings.Contains(strings.ToLower(structName), "payload") {
-		return toTitleCase(baseName) + " Payload"
-	}
-
-	return toTitleCase(baseName) + " Data"
-}
-
-// generateModelDescription creates model descriptions
-func (ur *UnifiedRegistry) generateModelDescription(baseName string) string {
-	contextualDescriptions := map[string]string{
-		"github":     "GitHub webhook payload validation with comprehensive business rules",
-		"api":        "API request and response validation with comprehensive business rules",
-		"database":   "Database query and transaction validation with comprehensive business rules",
-		"generic":    "Generic payload validation with flexible business rules",
-		"deployment": "Deployment webhook payload validation with semantic versioning and business rules",
-		"incident":   "Incident report validation with operational context and business rules",
-	}
-
-	if desc, exists := contextualDescriptions[strings.ToLower(baseName)]; exists {
-		return desc
-	}
-
-	return fmt.Sprintf("Automatically discovered %s validation with comprehensive business rules", toTitleCase(baseName))
-}
-
-// generateModelTags creates appropriate tags
-func (ur *UnifiedRegistry) generateModelTags(baseName string) []string {
-	baseTags := []string{"auto-discovered", strings.ToLower(baseName)}
-
-	contextualTags := map[string][]string{
-		"github":     {"webhook", "github", "git", "collaboration"},
-		"api":        {"api", "http", "rest", "web"},
-		"database":   {"database", "sql", "transaction", "query"},
-		"generic":    {"generic", "flexible", "json", "general"},
-		"deployment": {"deployment", "webhook", "devops", "ci/cd"},
-		"incident":   {"incident", "monitoring", "alert", "operations"},
-	}
-
-	if tags, exists := contextualTags[strings.ToLower(baseName)]; exists {
-		return append(baseTags, tags...)
-	}
-
-	return append(baseTags, "custom", "flexible")
+	// Step 3: Register the model
+	return ur.RegisterModel(&ModelInfo{
+		Type:        modelType,
+		Name:        name,
+		Description: description,
+		ModelStruct: modelStruct,
+		Validator:   validator,
+		Version:     version,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+		Author:      author,
+		Tags:        tags,
+	})
 }
 
 // RegisterModel registers a model with the unified registry
@@ -347,7 +179,7 @@ func (ur *UnifiedRegistry) RegisterModel(info *ModelInfo) error {
 	}
 
 	ur.models[info.Type] = info
-	log.Printf("✅ Registered model: %s -> %s", info.Type, info.Name)
+	slog.Debug("Registered model", "model", info.Type, "name", info.Name)
 	return nil
 }
 
@@ -361,7 +193,7 @@ func (ur *UnifiedRegistry) UnregisterModel(modelType ModelType) error {
 	}
 
 	delete(ur.models, modelType)
-	log.Printf("🗑️ Unregistered model: %s", modelType)
+	slog.Info("Unregistered model", "model", modelType)
 	return nil
 }
 
@@ -411,6 +243,35 @@ func (ur *UnifiedRegistry) IsRegistered(modelType ModelType) bool {
 	return exists
 }
 
+// SetDefaultThreshold sets the threshold for arrays, streams and batch sessions of
+// modelType that name none; an empty modelType sets it for every model without
+// its own, and nil removes it
+func (ur *UnifiedRegistry) SetDefaultThreshold(modelType ModelType, threshold *float64) {
+	ur.mutex.Lock()
+	defer ur.mutex.Unlock()
+
+	if threshold == nil {
+		delete(ur.defaultThresholds, modelType)
+		return
+	}
+	ur.defaultThresholds[modelType] = *threshold
+}
+
+// DefaultThreshold returns the threshold for a request of modelType that names
+// none, or nil when no default is configured
+func (ur *UnifiedRegistry) DefaultThreshold(modelType ModelType) *float64 {
+	ur.mutex.RLock()
+	defer ur.mutex.RUnlock()
+
+	threshold, ok := ur.defaultThresholds[modelType]
+	if !ok {
+		if threshold, ok = ur.defaultThresholds[""]; !ok {
+			return nil
+		}
+	}
+	return &threshold
+}
+
 // GetValidator retrieves validator for a model type
 func (ur *UnifiedRegistry) GetValidator(modelType ModelType) (ValidatorInterface, error) {
 	model, err := ur.GetModel(modelType)
@@ -421,10 +282,54 @@ func (ur *UnifiedRegistry) GetValidator(modelType ModelType) (ValidatorInterface
 }
 
 // ValidatePayload validates payload using appropriate validator
-func (ur *UnifiedRegistry) ValidatePayload(modelType ModelType, payload interface{}) (interface{}, error) {
+func (ur *UnifiedRegistry) ValidatePayload(modelType ModelType, payload interface{}) (models.ValidationResult, error) {
+	return ur.ValidatePayloadContext(context.Background(), modelType, payload)
+}
+
+// ValidatePayloadContext validates payload in a span of the trace carried by ctx
+// and stamps the result with the trace and request IDs
+func (ur *UnifiedRegistry) ValidatePayloadContext(ctx context.Context, modelType ModelType, payload interface{}) (models.ValidationResult, error) {
+	ctx, span := tracing.Start(ctx, "registry.ValidatePayload", attribute.String("validator.model", string(modelType)))
+	defer span.End()
+
+	startTime := time.Now()
+	result, err := ur.validatePayload(ctx, modelType, payload)
+	if err != nil {
+		tracing.Fail(span, err)
+		return result, err
+	}
+	observePayload(modelType, result, time.Since(startTime))
+	invalidRecords := 0
+	if !result.IsValid {
+		invalidRecords = 1
+	}
+	logging.AddFields(ctx,
+		slog.String("model", string(modelType)),
+		slog.Int("records", 1),
+		slog.Int("invalid_records", invalidRecords),
+	)
+	span.SetAttributes(
+		attribute.Bool("validator.valid", result.IsValid),
+		attribute.Int("validator.errors", len(result.Errors)),
+		attribute.Int("validator.warnings", len(result.Warnings)),
+	)
+
+	if result.RequestID == "" {
+		result.RequestID = tracing.RequestID(ctx)
+	}
+	result.TraceID = tracing.TraceID(ctx)
+	return result, nil
+}
+
+// validatePayload validates payload without recording metrics; array and stream
+// rows are counted by their batch instead
+func (ur *UnifiedRegistry) validatePayload(ctx context.Context, modelType ModelType, payload interface{}) (models.ValidationResult, error) {
 	validator, err := ur.GetValidator(modelType)
 	if err != nil {
-		return nil, err
+		return models.ValidationResult{}, err
+	}
+	if contextValidator, ok := validator.(ContextValidatorInterface); ok {
+		return contextValidator.ValidatePayloadContext(ctx, payload), nil
 	}
 	return validator.ValidatePayload(payload), nil
 }
@@ -434,26 +339,129 @@ func (ur *UnifiedRegistry) ValidatePayload(modelType ModelType, payload interfac
 // Status is determined by threshold: if no threshold provided, status is "success" for single records
 // For multiple records with threshold, status is "success" if success_rate >= threshold, otherwise "failed"
 func (ur *UnifiedRegistry) ValidateArray(modelType ModelType, records []map[string]interface{}, threshold *float64) (*models.ArrayValidationResult, error) {
+	return ur.ValidateArrayWithOptions(context.Background(), modelType, records, threshold, models.BatchOptions{})
+}
+
+// ValidateArrayWithOptions validates records on a bounded worker pool.
+// MaxConcurrency caps the number of workers (default GOMAXPROCS, 1 means sequential).
+// StopOnFirstError/FailFast stop at the lowest-indexed invalid row: rows after it are
+// reported as skipped, so the outcome does not depend on worker scheduling.
+// Timeout (or ctx) aborts the batch with an error once the deadline passes.
+func (ur *UnifiedRegistry) ValidateArrayWithOptions(ctx context.Context, modelType ModelType, records []map[string]interface{}, threshold *float64, opts models.BatchOptions) (*models.ArrayValidationResult, error) {
+	return ur.validateArray(ctx, modelType, len(records), mapRows(records), threshold, opts)
+}
+
+// encodeRecord encodes a record decoded into a map again for the model's
+// decoder, and returns it with its record identifier
+func encodeRecord(record map[string]interface{}, rowIndex int) (json.RawMessage, string, error) {
+	data, err := json.Marshal(record)
+	return data, models.DetectRecordIdentifier(record, rowIndex), err
+}
+
+// ValidateRawArrayWithOptions is ValidateArrayWithOptions for records that are
+// still JSON, such as the elements of a request's data array. Each record is
+// decoded once, straight into the model.
+func (ur *UnifiedRegistry) ValidateRawArrayWithOptions(ctx context.Context, modelType ModelType, records []json.RawMessage, threshold *float64, opts models.BatchOptions) (*models.ArrayValidationResult, error) {
+	return ur.validateArray(ctx, modelType, len(records), rawRows(records), threshold, opts)
+}
+
+// arrayRow returns the JSON of record i of an array and its record identifier
+type arrayRow func(i int) (json.RawMessage, string, error)
+
+// mapRows reads the rows of an array of decoded records
+func mapRows(records []map[string]interface{}) arrayRow {
+	return func(i int) (json.RawMessage, string, error) {
+		return encodeRecord(records[i], i)
+	}
+}
+
+// rawRows reads the rows of an array of records that are still JSON
+func rawRows(records []json.RawMessage) arrayRow {
+	return func(i int) (json.RawMessage, string, error) {
+		return records[i], models.DetectRawRecordIdentifier(records[i], i), nil
+	}
+}
+
+// validateArray validates the count records of an array on the worker pool
+func (ur *UnifiedRegistry) validateArray(ctx context.Context, modelType ModelType, count int, row arrayRow, threshold *float64, opts models.BatchOptions) (*models.ArrayValidationResult, error) {
 	// Generate batch_id for tracking
 	batchID := models.GenerateBatchID("auto")
 	startTime := time.Now()
 
-	allResults := make([]models.RowValidationResult, len(records))
-	validCount := 0
-	invalidCount := 0
-	warningCount := 0
+	ctx, span := tracing.Start(ctx, "registry.ValidateArray",
+		attribute.String("validator.model", string(modelType)),
+		attribute.String("validator.batch_id", batchID),
+		attribute.Int("validator.records", count))
+	defer span.End()
 
 	// Get model info for struct creation
 	modelInfo, err := ur.GetModel(modelType)
 	if err != nil {
-		return nil, fmt.Errorf("model type not found: %w", err)
+		err = fmt.Errorf("model type not found: %w", err)
+		tracing.Fail(span, err)
+		return nil, err
 	}
 
-	// Sequential validation (can be optimized later with worker pool)
-	for i, record := range records {
-		rowResult := ur.validateSingleRow(modelType, modelInfo, record, i)
-		allResults[i] = rowResult
+	if opts.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, opts.Timeout)
+		defer cancelTimeout()
+	}
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	// Run rows through the worker pool; each row writes only its own slot
+	allResults := make([]models.RowValidationResult, count)
+	stopOnError := opts.StopOnFirstError || opts.FailFast
+	var firstInvalid atomic.Int64
+	firstInvalid.Store(int64(count))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < batchWorkers(opts.MaxConcurrency, count); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				allResults[i] = ur.validateRow(ctx, modelType, modelInfo, row, i)
+				if stopOnError && !allResults[i].IsValid {
+					lowerTo(&firstInvalid, int64(i))
+					stop()
+				}
+			}
+		}()
+	}
+
+	// Rows are dispatched in order, so every row before the last dispatched one
+	// has been validated once the workers drain
+	dispatched := 0
+dispatch:
+	for dispatched < count && ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case indexes <- dispatched:
+			dispatched++
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	processed := dispatched
+	if stopOnError && firstInvalid.Load() < int64(count) {
+		processed = int(firstInvalid.Load()) + 1
+	} else if processed < count {
+		err := fmt.Errorf("array validation stopped after %d of %d records: %w", processed, count, context.Cause(ctx))
+		tracing.Fail(span, err)
+		return nil, err
+	}
+	allResults = allResults[:processed]
 
+	validCount := 0
+	invalidCount := 0
+	warningCount := 0
+	for _, rowResult := range allResults {
+		observeRecord(string(modelType), rowResult.IsValid, rowResult.Errors, rowResult.Warnings)
 		if rowResult.IsValid {
 			validCount++
 			// Check if it has warnings only (valid but with warnings)
@@ -476,16 +484,56 @@ func (ur *UnifiedRegistry) ValidateArray(modelType ModelType, records []map[stri
 	}
 
 	// Calculate success rate
-	totalRecords := len(records)
+	totalRecords := count
 	successRate := 0.0
 	if totalRecords > 0 {
 		successRate = (float64(validCount) / float64(totalRecords)) * 100.0
 	}
 
+	status := batchStatus(totalRecords, invalidCount, successRate, threshold, stopOnError)
+
+	arrayResult := &models.ArrayValidationResult{
+		BatchID:        batchID,
+		RequestID:      tracing.RequestID(ctx),
+		TraceID:        tracing.TraceID(ctx),
+		Status:         status,
+		TotalRecords:   totalRecords,
+		ValidRecords:   validCount,
+		InvalidRecords: invalidCount,
+		WarningRecords: warningCount,
+		SkippedRecords: totalRecords - processed,
+		Threshold:      threshold,
+		ProcessingTime: time.Since(startTime).Milliseconds(),
+		CompletedAt:    time.Now(),
+		Results:        filteredResults,                 // Only invalid rows (successful validations excluded)
+		Summary:        models.BuildSummary(allResults), // Summary includes all validated rows
+		Validated:      allResults,
+	}
+	observeBatch(modelType, modeArray, totalRecords, threshold, status, time.Since(startTime))
+	logging.AddFields(ctx,
+		slog.String("model", string(modelType)),
+		slog.Int("records", totalRecords),
+		slog.Int("invalid_records", invalidCount),
+		slog.String("validation_status", status),
+	)
+	span.SetAttributes(
+		attribute.String("validator.status", status),
+		attribute.Int("validator.valid_records", validCount),
+		attribute.Int("validator.invalid_records", invalidCount),
+	)
+
+	return arrayResult, nil
+}
+
+// batchStatus applies the threshold decision to a validated batch
+func batchStatus(totalRecords, invalidCount int, successRate float64, threshold *float64, stopOnError bool) string {
 	// Determine status based on threshold logic
 	status := "success" // default status
 
-	if threshold != nil {
+	if stopOnError && invalidCount > 0 {
+		// Fail-fast batches fail on their first invalid row
+		status = "failed"
+	} else if threshold != nil {
 		// Threshold is provided - apply strict comparison
 		// success_rate >= threshold means success, otherwise failed
 		if successRate < *threshold {
@@ -500,28 +548,40 @@ func (ur *UnifiedRegistry) ValidateArray(modelType ModelType, records []map[stri
 		}
 		// For multiple records without threshold, status remains "success"
 	}
+	return status
+}
 
-	arrayResult := &models.ArrayValidationResult{
-		BatchID:        batchID,
-		Status:         status,
-		TotalRecords:   totalRecords,
-		ValidRecords:   validCount,
-		InvalidRecords: invalidCount,
-		WarningRecords: warningCount,
-		Threshold:      threshold,
-		ProcessingTime: time.Since(startTime).Milliseconds(),
-		CompletedAt:    time.Now(),
-		Results:        filteredResults,                 // Only invalid rows (successful validations excluded)
-		Summary:        models.BuildSummary(allResults), // Summary includes all rows
+// batchWorkers returns the worker pool size for a batch of the given length
+func batchWorkers(maxConcurrency, records int) int {
+	workers := maxConcurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > records {
+		workers = records
 	}
+	return workers
+}
 
-	return arrayResult, nil
+// lowerTo atomically stores value in target if it is smaller than the current value
+func lowerTo(target *atomic.Int64, value int64) {
+	for {
+		current := target.Load()
+		if value >= current || target.CompareAndSwap(current, value) {
+			return
+		}
+	}
 }
 
-// validateSingleRow validates a single row from an array
-func (ur *UnifiedRegistry) validateSingleRow(modelType ModelType, modelInfo *ModelInfo, record map[string]interface{}, rowIndex int) models.RowValidationResult {
+// validateRow decodes row rowIndex of an array into the model and validates it
+func (ur *UnifiedRegistry) validateRow(ctx context.Context, modelType ModelType, modelInfo *ModelInfo, row arrayRow, rowIndex int) models.RowValidationResult {
 	rowStartTime := time.Now()
-	recordID := models.DetectRecordIdentifier(record, rowIndex)
+	record, recordID, encodeErr := row(rowIndex)
+
+	ctx, span := tracing.Start(ctx, "registry.validateRecord",
+		attribute.Int("validator.row_index", rowIndex),
+		attribute.String("validator.record_id", recordID))
+	defer span.End()
 
 	// Generate test name from model type (e.g., "incident" -> "IncidentValidator")
 	testName := fmt.Sprintf("%sValidator", toTitleCase(string(modelType)))
@@ -546,18 +606,23 @@ func (ur *UnifiedRegistry) validateSingleRow(modelType ModelType, modelInfo *Mod
 	// Create and populate model instance
 	modelInstance := reflect.New(modelInfo.ModelStruct).Interface()
 
-	jsonBytes, err := json.Marshal(record)
-	if err != nil {
-		return createErrorResult("JSON_MARSHAL_ERROR", fmt.Sprintf("Failed to marshal record: %v", err))
+	_, decodeSpan := tracing.Start(ctx, "decode")
+	if encodeErr != nil {
+		tracing.Fail(decodeSpan, encodeErr)
+		decodeSpan.End()
+		return createErrorResult("JSON_MARSHAL_ERROR", fmt.Sprintf("Failed to marshal record: %v", encodeErr))
 	}
 
-	if err := json.Unmarshal(jsonBytes, modelInstance); err != nil {
+	if err := json.Unmarshal(record, modelInstance); err != nil {
+		tracing.Fail(decodeSpan, err)
+		decodeSpan.End()
 		return createErrorResult("JSON_UNMARSHAL_ERROR", fmt.Sprintf("Failed to unmarshal record: %v", err))
 	}
+	decodeSpan.End()
 
 	// Validate using existing validator
 	modelValue := reflect.ValueOf(modelInstance).Elem().Interface()
-	result, err := ur.ValidatePayload(modelType, modelValue)
+	result, err := ur.validatePayload(ctx, modelType, modelValue)
 	if err != nil {
 		return createErrorResult("VALIDATION_ERROR", fmt.Sprintf("Validation failed: %v", err))
 	}
@@ -566,38 +631,17 @@ func (ur *UnifiedRegistry) validateSingleRow(modelType ModelType, modelInfo *Mod
 	rowResult := models.RowValidationResult{
 		RowIndex:         rowIndex,
 		RecordIdentifier: recordID,
+		IsValid:          result.IsValid,
 		ValidationTime:   time.Since(rowStartTime).Milliseconds(),
 		TestName:         testName,
-		Errors:           []models.ValidationError{},
-		Warnings:         []models.ValidationWarning{},
-	}
-
-	// Extract validation result fields
-	if validationResult, ok := result.(models.ValidationResult); ok {
-		rowResult.IsValid = validationResult.IsValid
-		rowResult.Errors = validationResult.Errors
-		rowResult.Warnings = validationResult.Warnings
-	} else if resultMap, ok := result.(map[string]interface{}); ok {
-		// Handle map-based validation result
-		if isValid, exists := resultMap["is_valid"]; exists {
-			if valid, ok := isValid.(bool); ok {
-				rowResult.IsValid = valid
-			}
-		}
-
-		// Extract errors
-		if errors, exists := resultMap["errors"]; exists {
-			if errSlice, ok := errors.([]models.ValidationError); ok {
-				rowResult.Errors = errSlice
-			}
-		}
-
-		// Extract warnings
-		if warnings, exists := resultMap["warnings"]; exists {
-			if warnSlice, ok := warnings.([]models.ValidationWarning); ok {
-				rowResult.Warnings = warnSlice
-			}
-		}
+		Errors:           result.Errors,
+		Warnings:         result.Warnings,
+	}
+	if rowResult.Errors == nil {
+		rowResult.Errors = []models.ValidationError{}
+	}
+	if rowResult.Warnings == nil {
+		rowResult.Warnings = []models.ValidationWarning{}
 	}
 
 	// Add sub-test categorization based on error/warning codes
@@ -628,26 +672,28 @@ func (ur *UnifiedRegistry) CreateModelInstance(modelType ModelType) (interface{}
 // registerAllHTTPEndpoints creates HTTP endpoints for all registered models
 func (ur *UnifiedRegistry) registerAllHTTPEndpoints() {
 	if ur.mux == nil {
-		log.Println("⚠️ No HTTP mux provided, skipping endpoint registration")
+		slog.Warn("No HTTP mux provided, skipping endpoint registration")
 		return
 	}
 
 	ur.mutex.RLock()
 	defer ur.mutex.RUnlock()
 
-	log.Println("🔄 Registering HTTP endpoints for all models...")
+	slog.Debug("Registering HTTP endpoints for all models")
 
 	for modelType, modelInfo := range ur.models {
-		endpointPath := "/validate/" + string(modelType)
+		endpointPath := modelType.Endpoint()
 
 		// Create closure to capture variables properly
 		func(mt ModelType, mi *ModelInfo, path string) {
 			ur.mux.HandleFunc("POST "+path, ur.createDynamicHandler(mt, mi))
-			log.Printf("✅ Registered endpoint: POST %s -> %s", path, mi.Name)
+			slog.Debug("Registered endpoint", "method", "POST", "path", path, "model", mi.Name)
+			ur.mux.HandleFunc("POST "+path+"/stream", ur.createStreamHandler(mt))
+			slog.Debug("Registered endpoint", "method", "POST", "path", path+"/stream", "model", mi.Name, "format", "ndjson")
 		}(modelType, modelInfo, endpointPath)
 	}
 
-	log.Printf("🎉 Successfully registered %d HTTP endpoints", len(ur.models))
+	slog.Info("Registered HTTP endpoints", "count", 2*len(ur.models))
 }
 
 // createDynamicHandler creates HTTP handler for a specific model
@@ -655,48 +701,62 @@ func (ur *UnifiedRegistry) createDynamicHandler(modelType ModelType, modelInfo *
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Ensure request body is closed and cleaned up
 		defer r.Body.Close()
+		// Problems and the access log name the model from the start
+		logging.AddFields(r.Context(), slog.String("model", string(modelType)))
 
-		w.Header().Set("Content-Type", "application/json")
+		respond, ok := codec.ForAccept(r.Header.Get("Accept"))
+		if !ok {
+			problem.Write(w, r, http.StatusNotAcceptable, problem.CodeNotAcceptable, codec.NotAcceptableMessage)
+			return
+		}
 
 		// Create model instance
 		modelInstance := reflect.New(modelInfo.ModelStruct).Interface()
 
-		// Parse JSON into model
-		if err := json.NewDecoder(r.Body).Decode(modelInstance); err != nil {
-			ur.sendJSONError(w, "Invalid JSON payload", http.StatusBadRequest)
+		// Parse the body into the model in the format of its Content-Type
+		_, decodeSpan := tracing.Start(r.Context(), "decode")
+		if format, err := codec.DecodeRequest(w, r, modelInstance); err != nil {
+			tracing.Fail(decodeSpan, err)
+			decodeSpan.End()
+			var limitErr *jsonlimit.LimitError
+			if errors.As(err, &limitErr) {
+				problem.Write(w, r, limitErr.Status(), limitErr.Limit, limitErr.Error())
+				return
+			}
+			var formatErr *codec.Error
+			if errors.As(err, &formatErr) {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, formatErr.Error())
+				return
+			}
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, fmt.Sprintf("Invalid %s payload", format.Name))
 			return
 		}
+		decodeSpan.End()
 
 		// Get actual struct value
 		modelValue := reflect.ValueOf(modelInstance).Elem().Interface()
 
+		release, err := admission.Default().Acquire(r.Context(), string(modelType), 1)
+		if err != nil {
+			admission.WriteError(w, r, err)
+			return
+		}
+		defer release()
+
 		// Validate
-		result, err := ur.ValidatePayload(modelType, modelValue)
+		result, err := ur.ValidatePayloadContext(r.Context(), modelType, modelValue)
 		if err != nil {
-			ur.sendJSONError(w, "Validation failed", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeValidationFailed, "Validation failed: "+err.Error())
 			return
 		}
 
 		// Send response
-		if err := json.NewEncoder(w).Encode(result); err != nil {
-			log.Printf("Error encoding response: %v", err)
+		if err := respond.Write(w, http.StatusOK, result); err != nil {
+			slog.ErrorContext(r.Context(), "Failed to encode response", "error", err)
 		}
 	}
 }
 
-// sendJSONError sends standardized JSON error
-func (ur *UnifiedRegistry) sendJSONError(w http.ResponseWriter, message string, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"error":     message,
-		"status":    statusCode,
-		"timestamp": time.Now().Format(time.RFC3339),
-	}); err != nil {
-		log.Printf("Error encoding error response: %v", err)
-	}
-}
-
 // GetModelStats returns registry statistics
 func (ur *UnifiedRegistry) GetModelStats() map[string]interface{} {
 	ur.mutex.RLock()
@@ -728,7 +788,9 @@ func (ur *UnifiedRegistry) GetRegisteredModelsWithDetails() map[string]interface
 			"author":      modelInfo.Author,
 			"tags":        modelInfo.Tags,
 			"created_at":  modelInfo.CreatedAt,
-			"endpoint":    "/validate/" + string(modelType),
+			"endpoint":    modelType.Endpoint(),
+			"family":      modelType.Family(),
+			"variant":     modelType.Variant(),
 		}
 	}
 
@@ -745,7 +807,7 @@ var globalUnifiedRegistry *UnifiedRegistry
 // GetGlobalRegistry returns the global unified registry
 func GetGlobalRegistry() *UnifiedRegistry {
 	if globalUnifiedRegistry == nil {
-		globalUnifiedRegistry = NewUnifiedRegistry("src/models", "src/validations")
+		globalUnifiedRegistry = NewUnifiedRegistry()
 	}
 	return globalUnifiedRegistry
 }
@@ -761,7 +823,7 @@ func toTitleCase(s string) string {
 
 	makeUpper := true
 	for i, r := range runes {
-		if unicode.IsSpace(r) || r == '_' || r == '-' {
+		if unicode.IsSpace(r) || r == '_' || r == '-' || r == '.' {
 			result[i] = r
 			makeUpper = true
 		} else if makeUpper {
@@ -775,8 +837,6 @@ func toTitleCase(s string) string {
 	return string(result)
 }
 
-// FileSystemWatcher methods removed - using simple startup-only registration
-
 // StartRegistration starts the unified registration system
 func StartRegistration(ctx context.Context, mux *http.ServeMux) error {
 	return GetGlobalRegistry().StartAutoRegistration(ctx, mux)
@@ -0,0 +1,109 @@
+package registry_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"goplayground-data-validator/models"
+	"goplayground-data-validator/registry"
+	_ "goplayground-data-validator/validations"
+)
+
+// incidentRecords builds n incident rows, every tenth one invalid
+func incidentRecords(n int) []map[string]interface{} {
+	records := make([]map[string]interface{}, n)
+	for i := range records {
+		severity := "high"
+		if i%10 == 0 {
+			severity = "unknown"
+		}
+		records[i] = map[string]interface{}{
+			"id":          fmt.Sprintf("INC-%06d", i),
+			"title":       "Database connection pool exhausted",
+			"description": "Connection pool reached its limit during peak traffic",
+			"severity":    severity,
+			"status":      "open",
+			"priority":    2,
+			"category":    "performance",
+			"environment": "production",
+			"reported_by": "monitoring",
+			"reported_at": time.Now().Format(time.RFC3339),
+		}
+	}
+	return records
+}
+
+// BenchmarkValidateArray compares the sequential path with the worker pool
+func BenchmarkValidateArray(b *testing.B) {
+	reg := registry.NewUnifiedRegistry()
+	if err := reg.StartAutoRegistration(context.Background(), nil); err != nil {
+		b.Fatalf("StartAutoRegistration failed: %v", err)
+	}
+
+	for _, size := range []int{1000, 10000} {
+		records := incidentRecords(size)
+
+		for _, workers := range []int{1, 4, 0} {
+			name := fmt.Sprintf("records=%d/workers=%d", size, workers)
+			if workers == 0 {
+				name = fmt.Sprintf("records=%d/workers=gomaxprocs", size)
+			}
+
+			b.Run(name, func(b *testing.B) {
+				opts := models.BatchOptions{MaxConcurrency: workers}
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := reg.ValidateArrayWithOptions(context.Background(), "incident", records, nil, opts); err != nil {
+						b.Fatalf("ValidateArrayWithOptions failed: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkValidateArrayDecode compares decoding a request's data array into
+// maps, which are encoded again for each record's model, with passing its
+// records on as raw JSON that is decoded once, straight into the model
+func BenchmarkValidateArrayDecode(b *testing.B) {
+	reg := registry.NewUnifiedRegistry()
+	if err := reg.StartAutoRegistration(context.Background(), nil); err != nil {
+		b.Fatalf("StartAutoRegistration failed: %v", err)
+	}
+	body, err := json.Marshal(map[string]interface{}{"data": incidentRecords(1000)})
+	if err != nil {
+		b.Fatalf("Marshal failed: %v", err)
+	}
+
+	b.Run("records=1000/data=maps", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var request struct {
+				Data []map[string]interface{} `json:"data"`
+			}
+			if err := json.Unmarshal(body, &request); err != nil {
+				b.Fatalf("Unmarshal failed: %v", err)
+			}
+			if _, err := reg.ValidateArrayWithOptions(context.Background(), "incident", request.Data, nil, models.BatchOptions{}); err != nil {
+				b.Fatalf("ValidateArrayWithOptions failed: %v", err)
+			}
+		}
+	})
+	b.Run("records=1000/data=raw", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var request struct {
+				Data []json.RawMessage `json:"data"`
+			}
+			if err := json.Unmarshal(body, &request); err != nil {
+				b.Fatalf("Unmarshal failed: %v", err)
+			}
+			if _, err := reg.ValidateRawArrayWithOptions(context.Background(), "incident", request.Data, nil, models.BatchOptions{}); err != nil {
+				b.Fatalf("ValidateRawArrayWithOptions failed: %v", err)
+			}
+		}
+	})
+}
@@ -3,30 +3,28 @@ package registry
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"os"
-	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+
+	"goplayground-data-validator/logging"
 	"goplayground-data-validator/models"
+	"goplayground-data-validator/tracing"
 )
 
 func TestNewUnifiedRegistry(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	if registry == nil {
 		t.Fatal("NewUnifiedRegistry returned nil")
 	}
-	if registry.modelsPath != "test/models" {
-		t.Errorf("modelsPath = %s, want test/models", registry.modelsPath)
-	}
-	if registry.validationsPath != "test/validations" {
-		t.Errorf("validationsPath = %s, want test/validations", registry.validationsPath)
-	}
 	if registry.models == nil {
 		t.Error("models map should be initialized")
 	}
@@ -36,7 +34,7 @@ func TestNewUnifiedRegistry(t *testing.T) {
 }
 
 func TestUnifiedRegistry_RegisterModel(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	modelInfo := &ModelInfo{
 		Type:        "test",
@@ -70,7 +68,7 @@ func TestUnifiedRegistry_RegisterModel(t *testing.T) {
 }
 
 func TestUnifiedRegistry_RegisterModel_EmptyType(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	modelInfo := &ModelInfo{
 		Type: "", // Empty type should fail
@@ -84,7 +82,7 @@ func TestUnifiedRegistry_RegisterModel_EmptyType(t *testing.T) {
 }
 
 func TestUnifiedRegistry_UnregisterModel(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	// Register a model first
 	modelInfo := &ModelInfo{
@@ -107,7 +105,7 @@ func TestUnifiedRegistry_UnregisterModel(t *testing.T) {
 }
 
 func TestUnifiedRegistry_UnregisterModel_NotFound(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	err := registry.UnregisterModel("nonexistent")
 	if err == nil {
@@ -116,7 +114,7 @@ func TestUnifiedRegistry_UnregisterModel_NotFound(t *testing.T) {
 }
 
 func TestUnifiedRegistry_GetModel_NotFound(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	_, err := registry.GetModel("nonexistent")
 	if err == nil {
@@ -125,7 +123,7 @@ func TestUnifiedRegistry_GetModel_NotFound(t *testing.T) {
 }
 
 func TestUnifiedRegistry_GetAllModels(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	// Register multiple models
 	models := []ModelInfo{
@@ -152,7 +150,7 @@ func TestUnifiedRegistry_GetAllModels(t *testing.T) {
 }
 
 func TestUnifiedRegistry_ListModels(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	// Register multiple models
 	registry.RegisterModel(&ModelInfo{Type: "test1", ModelStruct: reflect.TypeOf(models.IncidentPayload{})})
@@ -179,19 +177,15 @@ func TestUnifiedRegistry_ListModels(t *testing.T) {
 }
 
 func TestUnifiedRegistry_GetValidator(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	// Create a mock validator wrapper
-	mockValidator := &UniversalValidatorWrapper{
-		modelType:         "test",
-		validatorInstance: &mockValidatorInstance{},
-		modelStructType:   reflect.TypeOf(models.IncidentPayload{}),
-	}
+	mockValidator := Adapt[models.GenericPayload](&mockValidatorInstance{})
 
 	modelInfo := &ModelInfo{
 		Type:        "test",
 		Name:        "Test Model",
-		ModelStruct: reflect.TypeOf(models.IncidentPayload{}),
+		ModelStruct: mockValidator.ModelStruct(),
 		Validator:   mockValidator,
 	}
 
@@ -207,7 +201,7 @@ func TestUnifiedRegistry_GetValidator(t *testing.T) {
 }
 
 func TestUnifiedRegistry_CreateModelInstance(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	modelInfo := &ModelInfo{
 		Type:        "test",
@@ -232,7 +226,7 @@ func TestUnifiedRegistry_CreateModelInstance(t *testing.T) {
 }
 
 func TestUnifiedRegistry_GetModelStats(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	// Initially should have 0 models
 	stats := registry.GetModelStats()
@@ -258,7 +252,7 @@ func TestUnifiedRegistry_GetModelStats(t *testing.T) {
 }
 
 func TestUnifiedRegistry_GetRegisteredModelsWithDetails(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	modelInfo := &ModelInfo{
 		Type:        "test",
@@ -302,7 +296,7 @@ func TestUnifiedRegistry_GetRegisteredModelsWithDetails(t *testing.T) {
 }
 
 func TestUnifiedRegistry_ConcurrentAccess(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	// Test concurrent registration and access
 	done := make(chan bool, 10)
@@ -341,6 +335,30 @@ func TestUnifiedRegistry_ConcurrentAccess(t *testing.T) {
 	}
 }
 
+func TestUnifiedRegistry_DefaultThreshold(t *testing.T) {
+	registry := NewUnifiedRegistry()
+	if registry.DefaultThreshold("incident") != nil {
+		t.Fatal("Expected no default threshold")
+	}
+
+	global, incident := 90.0, 99.0
+	registry.SetDefaultThreshold("", &global)
+	registry.SetDefaultThreshold("incident", &incident)
+	if got := registry.DefaultThreshold("incident"); got == nil || *got != 99 {
+		t.Errorf("Expected the model's own threshold, got %v", got)
+	}
+	if got := registry.DefaultThreshold("github"); got == nil || *got != 90 {
+		t.Errorf("Expected the fallback threshold, got %v", got)
+	}
+
+	// Changing the returned value must not change the registry
+	*registry.DefaultThreshold("github") = 10
+	registry.SetDefaultThreshold("", nil)
+	if got := registry.DefaultThreshold("github"); got != nil {
+		t.Errorf("Expected the fallback to be removed, got %v", *got)
+	}
+}
+
 func TestUnifiedRegistry_toTitleCase(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -364,70 +382,17 @@ func TestUnifiedRegistry_toTitleCase(t *testing.T) {
 	}
 }
 
-func TestUnifiedRegistry_generateModelName(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
-
-	tests := []struct {
-		baseName   string
-		structName string
-		expected   string
-	}{
-		{"github", "GitHubPayload", "GitHub Webhook"},
-		{"api", "APIRequest", "API Request/Response"},
-		{"custom", "CustomPayload", "Custom Payload"},
-		{"unknown", "UnknownStruct", "Unknown Data"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.baseName, func(t *testing.T) {
-			result := registry.generateModelName(tt.baseName, tt.structName)
-			if result != tt.expected {
-				t.Errorf("generateModelName(%s, %s) = %s, want %s",
-					tt.baseName, tt.structName, result, tt.expected)
-			}
-		})
-	}
-}
-
-func TestUnifiedRegistry_generateModelDescription(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
-
-	tests := []struct {
-		baseName string
-		contains string
-	}{
-		{"github", "GitHub webhook"},
-		{"incident", "Incident report"},
-		{"api", "API request"},
-		{"unknown", "automatically discovered"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.baseName, func(t *testing.T) {
-			result := registry.generateModelDescription(tt.baseName)
-			if !strings.Contains(strings.ToLower(result), strings.ToLower(tt.contains)) {
-				t.Errorf("generateModelDescription(%s) = %s, should contain %s",
-					tt.baseName, result, tt.contains)
-			}
-		})
-	}
-}
-
 func TestUnifiedRegistry_HTTPHandlers(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 	mux := http.NewServeMux()
 
 	// Register a test model with mock validator
-	mockValidator := &UniversalValidatorWrapper{
-		modelType:         "test",
-		validatorInstance: &mockValidatorInstance{},
-		modelStructType:   reflect.TypeOf(models.IncidentPayload{}),
-	}
+	mockValidator := Adapt[models.GenericPayload](&mockValidatorInstance{})
 
 	modelInfo := &ModelInfo{
 		Type:        "test",
 		Name:        "Test Model",
-		ModelStruct: reflect.TypeOf(models.IncidentPayload{}),
+		ModelStruct: mockValidator.ModelStruct(),
 		Validator:   mockValidator,
 	}
 
@@ -449,7 +414,7 @@ func TestUnifiedRegistry_HTTPHandlers(t *testing.T) {
 }
 
 func BenchmarkUnifiedRegistry_RegisterModel(b *testing.B) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -463,7 +428,7 @@ func BenchmarkUnifiedRegistry_RegisterModel(b *testing.B) {
 }
 
 func BenchmarkUnifiedRegistry_GetModel(b *testing.B) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	// Setup
 	registry.RegisterModel(&ModelInfo{
@@ -479,14 +444,10 @@ func BenchmarkUnifiedRegistry_GetModel(b *testing.B) {
 }
 
 func TestUnifiedRegistry_ValidateArray(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	// Register a test model with mock validator
-	mockValidator := &UniversalValidatorWrapper{
-		modelType:         "test",
-		validatorInstance: &mockValidatorInstance{},
-		modelStructType:   reflect.TypeOf(models.GenericPayload{}),
-	}
+	mockValidator := Adapt[models.GenericPayload](&mockValidatorInstance{})
 
 	modelInfo := &ModelInfo{
 		Type:        "test",
@@ -562,14 +523,10 @@ func TestUnifiedRegistry_ValidateArray(t *testing.T) {
 }
 
 func TestUnifiedRegistry_ValidatePayload(t *testing.T) {
-	registry := NewUnifiedRegistry("test/models", "test/validations")
+	registry := NewUnifiedRegistry()
 
 	// Register a test model with mock validator
-	mockValidator := &UniversalValidatorWrapper{
-		modelType:         "test",
-		validatorInstance: &mockValidatorInstance{},
-		modelStructType:   reflect.TypeOf(models.GenericPayload{}),
-	}
+	mockValidator := Adapt[models.GenericPayload](&mockValidatorInstance{})
 
 	modelInfo := &ModelInfo{
 		Type:        "test",
@@ -581,23 +538,15 @@ func TestUnifiedRegistry_ValidatePayload(t *testing.T) {
 	registry.RegisterModel(modelInfo)
 
 	t.Run("validate single payload", func(t *testing.T) {
-		payload := map[string]interface{}{
-			"id":   "test-123",
-			"name": "Test Item",
-		}
+		payload := models.GenericPayload{ID: "test-123", Type: "test-item"}
 
 		result, err := registry.ValidatePayload("test", payload)
 		if err != nil {
 			t.Fatalf("ValidatePayload failed: %v", err)
 		}
 
-		resultMap, ok := result.(map[string]interface{})
-		if !ok {
-			t.Fatal("Result should be map[string]interface{}")
-		}
-
-		if resultMap["is_valid"] != true {
-			t.Errorf("Expected is_valid=true, got %v", resultMap["is_valid"])
+		if !result.IsValid {
+			t.Errorf("Expected is_valid=true, got %+v", result)
 		}
 	})
 
@@ -616,403 +565,250 @@ func TestUnifiedRegistry_ValidatePayload(t *testing.T) {
 // Mock validator for testing
 type mockValidatorInstance struct{}
 
-func (m *mockValidatorInstance) ValidatePayload(payload interface{}) interface{} {
-	return map[string]interface{}{
-		"is_valid":   true,
-		"model_type": "test",
-		"errors":     []interface{}{},
-		"warnings":   []interface{}{},
-	}
+func newMockValidator() *mockValidatorInstance {
+	return &mockValidatorInstance{}
 }
 
-// TestUnifiedRegistry_StartAutoRegistration tests the auto-registration system
-func TestUnifiedRegistry_StartAutoRegistration(t *testing.T) {
-	// Create a temporary directory structure for testing
-	tempDir := t.TempDir()
-	modelsDir := filepath.Join(tempDir, "models")
-	validationsDir := filepath.Join(tempDir, "validations")
-
-	// Create directories
-	os.MkdirAll(modelsDir, 0755)
-	os.MkdirAll(validationsDir, 0755)
-
-	// Create a test model file
-	modelContent := `package models
-
-type TestPayload struct {
-	ID   string ` + "`json:\"id\"`" + `
-	Name string ` + "`json:\"name\"`" + `
-}
-`
-	os.WriteFile(filepath.Join(modelsDir, "test.go"), []byte(modelContent), 0644)
-
-	// Create a test validator file
-	validatorContent := `package validations
-
-type TestValidator struct{}
-
-func NewTestValidator() *TestValidator {
-	return &TestValidator{}
+// validateStrict is a second validation method, used to test Method
+func (m *mockValidatorInstance) validateStrict(payload models.GenericPayload) models.ValidationResult {
+	return (&mockValidatorWithErrors{}).ValidatePayload(payload)
 }
 
-func (v *TestValidator) ValidatePayload(payload interface{}) interface{} {
-	return map[string]interface{}{
-		"is_valid": true,
+func (m *mockValidatorInstance) ValidatePayload(payload models.GenericPayload) models.ValidationResult {
+	return models.ValidationResult{
+		IsValid:   true,
+		ModelType: "test",
+		Errors:    []models.ValidationError{},
+		Warnings:  []models.ValidationWarning{},
 	}
 }
-`
-	os.WriteFile(filepath.Join(validationsDir, "test.go"), []byte(validatorContent), 0644)
 
-	registry := NewUnifiedRegistry(modelsDir, validationsDir)
-	mux := http.NewServeMux()
+// TestUnifiedRegistry_StartAutoRegistration tests registration of compiled-in models
+func TestUnifiedRegistry_StartAutoRegistration(t *testing.T) {
+	Register("autoreg_test", ModelSpec{
+		NewValidator: Constructor[models.GenericPayload](newMockValidator),
+		Name:         "Auto Registration Test",
+		Tags:         []string{"test"},
+	})
 
-	// Test with context
-	ctx := context.Background()
-	err := registry.StartAutoRegistration(ctx, mux)
+	registry := NewUnifiedRegistry()
+	mux := http.NewServeMux()
 
-	// Should complete without critical errors (some models might not be found, that's OK)
-	if err != nil {
-		t.Logf("StartAutoRegistration returned error (expected for missing models): %v", err)
+	if err := registry.StartAutoRegistration(context.Background(), mux); err != nil {
+		t.Fatalf("StartAutoRegistration failed: %v", err)
 	}
 
 	// Verify mux was set
 	if registry.mux == nil {
 		t.Error("mux should be set after StartAutoRegistration")
 	}
-}
-
-// TestUnifiedRegistry_DiscoverAndRegisterAll tests the discovery process
-func TestUnifiedRegistry_DiscoverAndRegisterAll(t *testing.T) {
-	tempDir := t.TempDir()
-	modelsDir := filepath.Join(tempDir, "models")
-	validationsDir := filepath.Join(tempDir, "validations")
-
-	os.MkdirAll(modelsDir, 0755)
-	os.MkdirAll(validationsDir, 0755)
-
-	// Create a model file without validator (should be skipped)
-	modelContent := `package models
-type OrphanPayload struct {
-	ID string
-}
-`
-	os.WriteFile(filepath.Join(modelsDir, "orphan.go"), []byte(modelContent), 0644)
-
-	registry := NewUnifiedRegistry(modelsDir, validationsDir)
-
-	// Run discovery
-	err := registry.discoverAndRegisterAll()
 
-	// Should not return error even if no models are registered
+	modelInfo, err := registry.GetModel("autoreg_test")
 	if err != nil {
-		t.Logf("discoverAndRegisterAll completed with warnings: %v", err)
+		t.Fatalf("Registered spec should be available: %v", err)
 	}
-
-	// Should have 0 models since there's no validator for orphan
-	if len(registry.models) != 0 {
-		t.Logf("Expected 0 models, got %d (orphan should be skipped)", len(registry.models))
+	if modelInfo.Name != "Auto Registration Test" {
+		t.Errorf("Name = %s, want Auto Registration Test", modelInfo.Name)
+	}
+	if modelInfo.ModelStruct != reflect.TypeOf(models.GenericPayload{}) {
+		t.Errorf("ModelStruct = %v, want models.GenericPayload", modelInfo.ModelStruct)
 	}
-}
-
-// TestUnifiedRegistry_ParseGoFileForStructs tests struct parsing
-func TestUnifiedRegistry_ParseGoFileForStructs(t *testing.T) {
-	tempDir := t.TempDir()
-
-	t.Run("valid go file", func(t *testing.T) {
-		goFile := filepath.Join(tempDir, "test_valid.go")
-		content := `package test
-
-type FirstStruct struct {
-	Field1 string
-}
 
-type SecondStruct struct {
-	Field2 int
-}
+	// Endpoint should be served without any source files on disk
+	req := httptest.NewRequest("POST", "/validate/autoreg_test", strings.NewReader(`{"id":"test"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
 
-type NotAStruct interface {
-	Method()
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
 }
-`
-		os.WriteFile(goFile, []byte(content), 0644)
 
-		registry := NewUnifiedRegistry("", "")
-		structs, err := registry.parseGoFileForStructs(goFile)
+// TestUnifiedRegistry_RegisterSpec tests metadata defaults and spec checks
+func TestUnifiedRegistry_RegisterSpec(t *testing.T) {
+	t.Run("defaults for omitted metadata", func(t *testing.T) {
+		registry := NewUnifiedRegistry()
 
+		err := registry.RegisterSpec("custom_model", ModelSpec{
+			NewValidator: Constructor[models.GenericPayload](newMockValidator),
+		})
 		if err != nil {
-			t.Fatalf("parseGoFileForStructs failed: %v", err)
+			t.Fatalf("RegisterSpec failed: %v", err)
 		}
 
-		if len(structs) != 2 {
-			t.Errorf("Expected 2 structs, got %d", len(structs))
+		modelInfo, _ := registry.GetModel("custom_model")
+		if modelInfo.Name != "Custom_Model Data" {
+			t.Errorf("Name = %s, want Custom_Model Data", modelInfo.Name)
 		}
-
-		if !contains(structs, "FirstStruct") {
-			t.Error("Should find FirstStruct")
+		if modelInfo.Version != "1.0.0" {
+			t.Errorf("Version = %s, want 1.0.0", modelInfo.Version)
 		}
-		if !contains(structs, "SecondStruct") {
-			t.Error("Should find SecondStruct")
+		if !reflect.DeepEqual(modelInfo.Tags, []string{"custom_model"}) {
+			t.Errorf("Tags = %v, want [custom_model]", modelInfo.Tags)
 		}
 	})
 
-	t.Run("invalid go file", func(t *testing.T) {
-		goFile := filepath.Join(tempDir, "test_invalid.go")
-		os.WriteFile(goFile, []byte("this is not valid go code {{{"), 0644)
-
-		registry := NewUnifiedRegistry("", "")
-		_, err := registry.parseGoFileForStructs(goFile)
-
-		if err == nil {
-			t.Error("Expected error for invalid go file")
-		}
-	})
-
-	t.Run("nonexistent file", func(t *testing.T) {
-		registry := NewUnifiedRegistry("", "")
-		_, err := registry.parseGoFileForStructs("/nonexistent/file.go")
+	invalidSpecs := []struct {
+		name      string
+		modelType ModelType
+		spec      ModelSpec
+	}{
+		{"empty model type", "", ModelSpec{NewValidator: Constructor[models.GenericPayload](newMockValidator)}},
+		{"missing validator", "no_validator", ModelSpec{}},
+		{"nil validator", "nil_validator", ModelSpec{NewValidator: func() ModelValidator { return nil }}},
+		{"non-struct model", "map_model", ModelSpec{NewValidator: func() ModelValidator {
+			return Adapt[map[string]interface{}](ValidatorFunc[map[string]interface{}](func(map[string]interface{}) models.ValidationResult {
+				return models.ValidationResult{IsValid: true}
+			}))
+		}}},
+	}
 
-		if err == nil {
-			t.Error("Expected error for nonexistent file")
-		}
-	})
+	for _, tt := range invalidSpecs {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewUnifiedRegistry()
+			if err := registry.RegisterSpec(tt.modelType, tt.spec); err == nil {
+				t.Error("Expected error for invalid spec")
+			}
+		})
+	}
 }
 
-// TestUnifiedRegistry_GetKnownModelTypes tests model type retrieval
-func TestUnifiedRegistry_GetKnownModelTypes(t *testing.T) {
-	registry := NewUnifiedRegistry("", "")
-	knownTypes := registry.getKnownModelTypes()
-
-	if len(knownTypes) == 0 {
-		t.Error("Should have known model types")
+// TestRegister tests the package-level compile-time registration API
+func TestRegister(t *testing.T) {
+	spec := ModelSpec{
+		NewValidator: Constructor[models.GenericPayload](newMockValidator),
 	}
 
-	// Check for expected types
-	expectedTypes := []string{
-		"IncidentPayload",
-		"GitHubPayload",
-		"APIRequest",
-		"DatabaseQuery",
-		"GenericPayload",
-		"DeploymentPayload",
-	}
+	Register("register_test", spec)
 
-	for _, typeName := range expectedTypes {
-		if _, exists := knownTypes[typeName]; !exists {
-			t.Errorf("Expected to find %s in known types", typeName)
+	found := false
+	for _, modelType := range RegisteredSpecs() {
+		if modelType == "register_test" {
+			found = true
 		}
 	}
-}
-
-// TestUnifiedRegistry_GetKnownValidatorConstructors tests validator constructor retrieval
-func TestUnifiedRegistry_GetKnownValidatorConstructors(t *testing.T) {
-	registry := NewUnifiedRegistry("", "")
-	constructors := registry.getKnownValidatorConstructors()
-
-	if len(constructors) == 0 {
-		t.Error("Should have known validator constructors")
+	if !found {
+		t.Error("register_test should be listed by RegisteredSpecs")
 	}
 
-	// Check for expected constructors
-	expectedConstructors := []string{
-		"NewIncidentValidator",
-		"NewGitHubValidator",
-		"NewAPIValidator",
-		"NewDatabaseValidator",
-		"NewGenericValidator",
-		"NewDeploymentValidator",
-	}
+	t.Run("duplicate registration panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic for duplicate registration")
+			}
+		}()
+		Register("register_test", spec)
+	})
 
-	for _, constructorName := range expectedConstructors {
-		if _, exists := constructors[constructorName]; !exists {
-			t.Errorf("Expected to find %s in known constructors", constructorName)
-		}
-		// Test that constructor can be called
-		if constructor, exists := constructors[constructorName]; exists {
-			result := constructor()
-			if result == nil {
-				t.Errorf("Constructor %s returned nil", constructorName)
+	t.Run("incomplete spec panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic for spec without validator")
 			}
-		}
-	}
+		}()
+		Register("register_incomplete", ModelSpec{Name: "Incomplete"})
+	})
 }
 
-// TestUnifiedRegistry_GenerateModelTags tests tag generation
-func TestUnifiedRegistry_GenerateModelTags(t *testing.T) {
-	registry := NewUnifiedRegistry("", "")
-
+// TestModelType_Hierarchy tests family/variant parsing and endpoint paths
+func TestModelType_Hierarchy(t *testing.T) {
 	tests := []struct {
-		baseName     string
-		expectedTags []string
+		modelType ModelType
+		family    string
+		variant   string
+		endpoint  string
 	}{
-		{
-			baseName:     "github",
-			expectedTags: []string{"auto-discovered", "github", "webhook", "git", "collaboration"},
-		},
-		{
-			baseName:     "api",
-			expectedTags: []string{"auto-discovered", "api", "http", "rest", "web"},
-		},
-		{
-			baseName:     "database",
-			expectedTags: []string{"auto-discovered", "database", "sql", "transaction", "query"},
-		},
-		{
-			baseName:     "unknown",
-			expectedTags: []string{"auto-discovered", "unknown", "custom", "flexible"},
-		},
+		{"incident", "incident", "", "/validate/incident"},
+		{"api.response", "api", "response", "/validate/api/response"},
+		{"database.migration", "database", "migration", "/validate/database/migration"},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.baseName, func(t *testing.T) {
-			tags := registry.generateModelTags(tt.baseName)
-
-			for _, expectedTag := range tt.expectedTags {
-				if !contains(tags, expectedTag) {
-					t.Errorf("Expected tag %s not found in %v", expectedTag, tags)
-				}
+		t.Run(string(tt.modelType), func(t *testing.T) {
+			if got := tt.modelType.Family(); got != tt.family {
+				t.Errorf("Family() = %s, want %s", got, tt.family)
+			}
+			if got := tt.modelType.Variant(); got != tt.variant {
+				t.Errorf("Variant() = %s, want %s", got, tt.variant)
+			}
+			if got := tt.modelType.Endpoint(); got != tt.endpoint {
+				t.Errorf("Endpoint() = %s, want %s", got, tt.endpoint)
 			}
 		})
 	}
-}
-
-// TestUnifiedRegistry_CreateValidatorInstance tests validator instance creation
-func TestUnifiedRegistry_CreateValidatorInstance(t *testing.T) {
-	registry := NewUnifiedRegistry("", "")
 
-	t.Run("known validator", func(t *testing.T) {
-		instance, err := registry.createValidatorInstance("incident")
-		if err != nil {
-			t.Fatalf("createValidatorInstance failed: %v", err)
-		}
-		if instance == nil {
-			t.Error("Validator instance should not be nil")
-		}
-	})
-
-	t.Run("special case - github", func(t *testing.T) {
-		instance, err := registry.createValidatorInstance("github")
-		if err != nil {
-			t.Fatalf("createValidatorInstance for github failed: %v", err)
-		}
-		if instance == nil {
-			t.Error("Validator instance should not be nil")
-		}
-	})
-
-	t.Run("unknown validator", func(t *testing.T) {
-		_, err := registry.createValidatorInstance("nonexistent")
-		if err == nil {
-			t.Error("Expected error for unknown validator")
-		}
-	})
-}
-
-// TestUnifiedRegistry_DiscoverModelStruct tests struct discovery
-func TestUnifiedRegistry_DiscoverModelStruct(t *testing.T) {
-	tempDir := t.TempDir()
-	modelsDir := filepath.Join(tempDir, "models")
-	os.MkdirAll(modelsDir, 0755)
-
-	registry := NewUnifiedRegistry(modelsDir, "")
-
-	t.Run("known model type", func(t *testing.T) {
-		// Test with a known model type
-		structType, structName, err := registry.discoverModelStruct("incident")
-
-		if err != nil {
-			t.Fatalf("discoverModelStruct failed: %v", err)
-		}
-
-		if structType == nil {
-			t.Error("Struct type should not be nil")
-		}
-
-		if structName != "IncidentPayload" {
-			t.Errorf("Expected IncidentPayload, got %s", structName)
-		}
-	})
-
-	t.Run("unknown model type", func(t *testing.T) {
-		_, _, err := registry.discoverModelStruct("totallyfakemodel")
-
-		if err == nil {
-			t.Error("Expected error for unknown model type")
-		}
-	})
+	invalid := []ModelType{".response", "api.", "api.response.v2", "api/response", "api.{variant}", "api.stream"}
+	for _, modelType := range invalid {
+		t.Run("invalid "+string(modelType), func(t *testing.T) {
+			spec := ModelSpec{NewValidator: Constructor[models.GenericPayload](newMockValidator)}
+			if err := spec.check(modelType); err == nil {
+				t.Errorf("Expected error for model type %q", modelType)
+			}
+		})
+	}
 }
 
-// TestUnifiedRegistry_RegisterModelAutomatically tests automatic registration
-func TestUnifiedRegistry_RegisterModelAutomatically(t *testing.T) {
-	registry := NewUnifiedRegistry("", "")
-
-	t.Run("successful registration", func(t *testing.T) {
-		// This will likely fail due to missing files, but we're testing the flow
-		err := registry.registerModelAutomatically("incident")
+// TestUnifiedRegistry_SubModelEndpoints tests that family.variant models get nested endpoints
+func TestUnifiedRegistry_SubModelEndpoints(t *testing.T) {
+	registry := NewUnifiedRegistry()
+	mux := http.NewServeMux()
+	registry.mux = mux
 
-		if err != nil {
-			// Expected to fail in test environment
-			t.Logf("registerModelAutomatically failed as expected: %v", err)
-		}
+	if err := registry.RegisterSpec("family", ModelSpec{NewValidator: Constructor[models.GenericPayload](newMockValidator)}); err != nil {
+		t.Fatalf("RegisterSpec failed: %v", err)
+	}
+	err := registry.RegisterSpec("family.variant", ModelSpec{
+		NewValidator: Method(newMockValidator, (*mockValidatorInstance).validateStrict),
 	})
-}
-
-// TestUnifiedRegistry_SendJSONError tests JSON error responses
-func TestUnifiedRegistry_SendJSONError(t *testing.T) {
-	registry := NewUnifiedRegistry("", "")
+	if err != nil {
+		t.Fatalf("RegisterSpec failed: %v", err)
+	}
+	registry.registerAllHTTPEndpoints()
 
 	tests := []struct {
-		name       string
-		message    string
-		statusCode int
+		path      string
+		wantValid bool
 	}{
-		{"bad request", "Invalid input", http.StatusBadRequest},
-		{"not found", "Model not found", http.StatusNotFound},
-		{"internal error", "Server error", http.StatusInternalServerError},
+		{"/validate/family", true},
+		{"/validate/family/variant", false},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(`{"id":"test"}`))
 			w := httptest.NewRecorder()
-			registry.sendJSONError(w, tt.message, tt.statusCode)
+			mux.ServeHTTP(w, req)
 
-			if w.Code != tt.statusCode {
-				t.Errorf("Expected status %d, got %d", tt.statusCode, w.Code)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
 			}
-
-			var response map[string]interface{}
-			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
-				t.Fatalf("Failed to parse JSON response: %v", err)
+			var result models.ValidationResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
 			}
-
-			if response["error"] != tt.message {
-				t.Errorf("Expected error message %s, got %v", tt.message, response["error"])
-			}
-
-			if status, ok := response["status"].(float64); !ok || int(status) != tt.statusCode {
-				t.Errorf("Expected status %d in body, got %v", tt.statusCode, response["status"])
-			}
-
-			if _, exists := response["timestamp"]; !exists {
-				t.Error("Expected timestamp in response")
+			if result.IsValid != tt.wantValid {
+				t.Errorf("is_valid = %v, want %v", result.IsValid, tt.wantValid)
 			}
 		})
 	}
+
+	details := registry.GetRegisteredModelsWithDetails()["models"].(map[string]interface{})
+	variant := details["family.variant"].(map[string]interface{})
+	if variant["endpoint"] != "/validate/family/variant" || variant["family"] != "family" || variant["variant"] != "variant" {
+		t.Errorf("Unexpected details for family.variant: %v", variant)
+	}
 }
 
 // TestUnifiedRegistry_CreateDynamicHandler_ErrorPaths tests error handling in dynamic handler
 func TestUnifiedRegistry_CreateDynamicHandler_ErrorPaths(t *testing.T) {
-	registry := NewUnifiedRegistry("", "")
+	registry := NewUnifiedRegistry()
 
-	mockValidator := &UniversalValidatorWrapper{
-		modelType:         "test",
-		validatorInstance: &mockValidatorInstance{},
-		modelStructType:   reflect.TypeOf(models.IncidentPayload{}),
-	}
+	mockValidator := Adapt[models.GenericPayload](&mockValidatorInstance{})
 
 	modelInfo := &ModelInfo{
 		Type:        "test",
 		Name:        "Test Model",
-		ModelStruct: reflect.TypeOf(models.IncidentPayload{}),
+		ModelStruct: mockValidator.ModelStruct(),
 		Validator:   mockValidator,
 	}
 
@@ -1023,6 +819,8 @@ func TestUnifiedRegistry_CreateDynamicHandler_ErrorPaths(t *testing.T) {
 	t.Run("invalid JSON payload", func(t *testing.T) {
 		req := httptest.NewRequest("POST", "/validate/test", strings.NewReader(`{invalid json`))
 		req.Header.Set("Content-Type", "application/json")
+		ctx, _ := logging.WithFields(req.Context()) // As the access log middleware does
+		req = req.WithContext(ctx)
 		w := httptest.NewRecorder()
 
 		handler(w, req)
@@ -1031,10 +829,16 @@ func TestUnifiedRegistry_CreateDynamicHandler_ErrorPaths(t *testing.T) {
 			t.Errorf("Expected status 400, got %d", w.Code)
 		}
 
+		if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+			t.Errorf("Expected application/problem+json, got %s", contentType)
+		}
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		if !strings.Contains(response["error"].(string), "Invalid JSON") {
-			t.Errorf("Expected 'Invalid JSON' error, got %v", response["error"])
+		if !strings.Contains(response["detail"].(string), "Invalid JSON") {
+			t.Errorf("Expected 'Invalid JSON' detail, got %v", response["detail"])
+		}
+		if response["code"] != "invalid_payload" || response["model_type"] != "test" || response["instance"] != "/validate/test" {
+			t.Errorf("Expected an invalid_payload problem for model test, got %v", response)
 		}
 	})
 
@@ -1049,11 +853,67 @@ func TestUnifiedRegistry_CreateDynamicHandler_ErrorPaths(t *testing.T) {
 			t.Errorf("Expected status 400, got %d", w.Code)
 		}
 	})
+
+	t.Run("invalid YAML payload", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/validate/test", strings.NewReader("id: test-123\n  type: x\n"))
+		req.Header.Set("Content-Type", "application/yaml")
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "Invalid YAML payload at line 2") {
+			t.Errorf("Expected 400 with the YAML error line, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("unsupported Accept", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/validate/test", strings.NewReader(`{"id":"test-123"}`))
+		req.Header.Set("Accept", "text/html")
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+
+		if w.Code != http.StatusNotAcceptable {
+			t.Errorf("Expected status 406, got %d", w.Code)
+		}
+	})
+}
+
+// TestUnifiedRegistry_CreateDynamicHandler_Formats tests XML requests and YAML responses
+func TestUnifiedRegistry_CreateDynamicHandler_Formats(t *testing.T) {
+	registry := NewUnifiedRegistry()
+	mockValidator := Adapt[models.GenericPayload](&mockValidatorInstance{})
+	modelInfo := &ModelInfo{
+		Type:        "test",
+		Name:        "Test Model",
+		ModelStruct: mockValidator.ModelStruct(),
+		Validator:   mockValidator,
+	}
+	registry.RegisterModel(modelInfo)
+	handler := registry.createDynamicHandler("test", modelInfo)
+
+	body := `<event id="test-123"><type>deploy</type><timestamp>2025-01-04T10:00:00Z</timestamp><tags>a</tags></event>`
+	req := httptest.NewRequest("POST", "/validate/test", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Accept", "application/yaml")
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/yaml" {
+		t.Errorf("Expected Content-Type application/yaml, got %s", contentType)
+	}
+	if !strings.Contains(w.Body.String(), "is_valid: true") {
+		t.Errorf("Expected a YAML validation result, got %s", w.Body.String())
+	}
 }
 
-// TestUnifiedRegistry_ValidateSingleRow_EdgeCases tests edge cases in row validation
-func TestUnifiedRegistry_ValidateSingleRow_EdgeCases(t *testing.T) {
-	registry := NewUnifiedRegistry("", "")
+// TestUnifiedRegistry_ValidateRow_EdgeCases tests edge cases in row validation
+func TestUnifiedRegistry_ValidateRow_EdgeCases(t *testing.T) {
+	registry := NewUnifiedRegistry()
 
 	t.Run("JSON marshal error - circular reference", func(t *testing.T) {
 		modelInfo := &ModelInfo{
@@ -1067,7 +927,7 @@ func TestUnifiedRegistry_ValidateSingleRow_EdgeCases(t *testing.T) {
 			"id": make(chan int), // channels can't be marshaled
 		}
 
-		result := registry.validateSingleRow("test", modelInfo, record, 0)
+		result := registry.validateRow(context.Background(), "test", modelInfo, mapRows([]map[string]interface{}{record}), 0)
 
 		if result.IsValid {
 			t.Error("Expected validation to fail for unmarshalable data")
@@ -1080,11 +940,7 @@ func TestUnifiedRegistry_ValidateSingleRow_EdgeCases(t *testing.T) {
 
 	t.Run("validation error handling", func(t *testing.T) {
 		// Create a validator that returns ValidationResult struct
-		mockValidator := &UniversalValidatorWrapper{
-			modelType:         "test",
-			validatorInstance: &mockValidatorWithErrors{},
-			modelStructType:   reflect.TypeOf(models.GenericPayload{}),
-		}
+		mockValidator := Adapt[models.GenericPayload](&mockValidatorWithErrors{})
 
 		modelInfo := &ModelInfo{
 			Type:        "test",
@@ -1094,12 +950,10 @@ func TestUnifiedRegistry_ValidateSingleRow_EdgeCases(t *testing.T) {
 
 		registry.RegisterModel(modelInfo)
 
-		record := map[string]interface{}{
-			"id":   "test-123",
-			"data": "test",
-		}
+		records := make([]json.RawMessage, 6)
+		records[5] = json.RawMessage(`{"id": "test-123", "data": "test"}`)
 
-		result := registry.validateSingleRow("test", modelInfo, record, 5)
+		result := registry.validateRow(context.Background(), "test", modelInfo, rawRows(records), 5)
 
 		if result.RowIndex != 5 {
 			t.Errorf("Expected row index 5, got %d", result.RowIndex)
@@ -1113,13 +967,9 @@ func TestUnifiedRegistry_ValidateSingleRow_EdgeCases(t *testing.T) {
 
 // TestUnifiedRegistry_ValidateArray_EdgeCases tests edge cases in array validation
 func TestUnifiedRegistry_ValidateArray_EdgeCases(t *testing.T) {
-	registry := NewUnifiedRegistry("", "")
+	registry := NewUnifiedRegistry()
 
-	mockValidator := &UniversalValidatorWrapper{
-		modelType:         "test",
-		validatorInstance: &mockValidatorInstance{},
-		modelStructType:   reflect.TypeOf(models.GenericPayload{}),
-	}
+	mockValidator := Adapt[models.GenericPayload](&mockValidatorInstance{})
 
 	modelInfo := &ModelInfo{
 		Type:        "test",
@@ -1130,11 +980,7 @@ func TestUnifiedRegistry_ValidateArray_EdgeCases(t *testing.T) {
 	registry.RegisterModel(modelInfo)
 
 	t.Run("single invalid record without threshold", func(t *testing.T) {
-		mockFailValidator := &UniversalValidatorWrapper{
-			modelType:         "failtest",
-			validatorInstance: &mockValidatorWithErrors{},
-			modelStructType:   reflect.TypeOf(models.GenericPayload{}),
-		}
+		mockFailValidator := Adapt[models.GenericPayload](&mockValidatorWithErrors{})
 
 		failModelInfo := &ModelInfo{
 			Type:        "failtest",
@@ -1180,11 +1026,7 @@ func TestUnifiedRegistry_ValidateArray_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("records with warnings", func(t *testing.T) {
-		mockWarnValidator := &UniversalValidatorWrapper{
-			modelType:         "warntest",
-			validatorInstance: &mockValidatorWithWarnings{},
-			modelStructType:   reflect.TypeOf(models.GenericPayload{}),
-		}
+		mockWarnValidator := Adapt[models.GenericPayload](&mockValidatorWithWarnings{})
 
 		warnModelInfo := &ModelInfo{
 			Type:        "warntest",
@@ -1248,11 +1090,7 @@ func TestUnifiedRegistry_ValidateArray_EdgeCases(t *testing.T) {
 
 	t.Run("threshold failure when below threshold", func(t *testing.T) {
 		// Use mock validator that returns errors for specific records
-		mockMixedValidator := &UniversalValidatorWrapper{
-			modelType:         "mixedtest",
-			validatorInstance: &mockValidatorWithMixedResults{},
-			modelStructType:   reflect.TypeOf(models.GenericPayload{}),
-		}
+		mockMixedValidator := Adapt[models.GenericPayload](&mockValidatorWithMixedResults{})
 
 		mixedModelInfo := &ModelInfo{
 			Type:        "mixedtest",
@@ -1290,11 +1128,7 @@ func TestUnifiedRegistry_ValidateArray_EdgeCases(t *testing.T) {
 
 	t.Run("threshold exact match", func(t *testing.T) {
 		// Create mock validator for exact threshold test
-		mockExactValidator := &UniversalValidatorWrapper{
-			modelType:         "exacttest",
-			validatorInstance: &mockValidatorExactThreshold{},
-			modelStructType:   reflect.TypeOf(models.GenericPayload{}),
-		}
+		mockExactValidator := Adapt[models.GenericPayload](&mockValidatorExactThreshold{})
 
 		exactModelInfo := &ModelInfo{
 			Type:        "exacttest",
@@ -1326,11 +1160,7 @@ func TestUnifiedRegistry_ValidateArray_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("no threshold with mixed results", func(t *testing.T) {
-		mockMixedValidator := &UniversalValidatorWrapper{
-			modelType:         "mixedtest2",
-			validatorInstance: &mockValidatorWithMixedResults{},
-			modelStructType:   reflect.TypeOf(models.GenericPayload{}),
-		}
+		mockMixedValidator := Adapt[models.GenericPayload](&mockValidatorWithMixedResults{})
 
 		mixedModelInfo := &ModelInfo{
 			Type:        "mixedtest2",
@@ -1363,6 +1193,96 @@ func TestUnifiedRegistry_ValidateArray_EdgeCases(t *testing.T) {
 	})
 }
 
+// TestUnifiedRegistry_ValidateArrayWithOptions tests the worker pool options
+func TestUnifiedRegistry_ValidateArrayWithOptions(t *testing.T) {
+	registry := NewUnifiedRegistry()
+	registry.RegisterModel(&ModelInfo{
+		Type:        "mixed",
+		ModelStruct: reflect.TypeOf(models.GenericPayload{}),
+		Validator:   Adapt[models.GenericPayload](&mockValidatorWithMixedResults{}),
+	})
+
+	records := make([]map[string]interface{}, 200)
+	for i := range records {
+		prefix := "valid-"
+		if i%3 == 1 {
+			prefix = "invalid-"
+		}
+		records[i] = map[string]interface{}{"id": fmt.Sprintf("%s%d", prefix, i)}
+	}
+
+	t.Run("results are identical for any concurrency", func(t *testing.T) {
+		sequential, err := registry.ValidateArrayWithOptions(context.Background(), "mixed", records, nil, models.BatchOptions{MaxConcurrency: 1})
+		if err != nil {
+			t.Fatalf("sequential ValidateArrayWithOptions failed: %v", err)
+		}
+
+		for _, workers := range []int{0, 4, 16, 500} {
+			pooled, err := registry.ValidateArrayWithOptions(context.Background(), "mixed", records, nil, models.BatchOptions{MaxConcurrency: workers})
+			if err != nil {
+				t.Fatalf("ValidateArrayWithOptions(%d workers) failed: %v", workers, err)
+			}
+			if pooled.ValidRecords != sequential.ValidRecords || pooled.InvalidRecords != sequential.InvalidRecords {
+				t.Errorf("%d workers: counts %d/%d, want %d/%d", workers, pooled.ValidRecords, pooled.InvalidRecords, sequential.ValidRecords, sequential.InvalidRecords)
+			}
+			if len(pooled.Results) != len(sequential.Results) {
+				t.Fatalf("%d workers: %d results, want %d", workers, len(pooled.Results), len(sequential.Results))
+			}
+			for i := range pooled.Results {
+				if pooled.Results[i].RowIndex != sequential.Results[i].RowIndex {
+					t.Fatalf("%d workers: result %d is row %d, want row %d", workers, i, pooled.Results[i].RowIndex, sequential.Results[i].RowIndex)
+				}
+			}
+		}
+	})
+
+	t.Run("fail fast stops at the first invalid row", func(t *testing.T) {
+		for _, opts := range []models.BatchOptions{
+			{FailFast: true, MaxConcurrency: 8},
+			{StopOnFirstError: true, MaxConcurrency: 1},
+		} {
+			result, err := registry.ValidateArrayWithOptions(context.Background(), "mixed", records, nil, opts)
+			if err != nil {
+				t.Fatalf("ValidateArrayWithOptions failed: %v", err)
+			}
+			if result.Status != "failed" {
+				t.Errorf("Status = %s, want failed", result.Status)
+			}
+			if result.ValidRecords != 1 || result.InvalidRecords != 1 || result.SkippedRecords != len(records)-2 {
+				t.Errorf("valid/invalid/skipped = %d/%d/%d, want 1/1/%d", result.ValidRecords, result.InvalidRecords, result.SkippedRecords, len(records)-2)
+			}
+			if len(result.Results) != 1 || result.Results[0].RowIndex != 1 {
+				t.Errorf("Expected only row 1 in results, got %+v", result.Results)
+			}
+		}
+	})
+
+	t.Run("timeout aborts the batch", func(t *testing.T) {
+		registry.RegisterModel(&ModelInfo{
+			Type:        "slow",
+			ModelStruct: reflect.TypeOf(models.GenericPayload{}),
+			Validator: Adapt[models.GenericPayload](ValidatorFunc[models.GenericPayload](func(models.GenericPayload) models.ValidationResult {
+				time.Sleep(5 * time.Millisecond)
+				return models.ValidationResult{IsValid: true}
+			})),
+		})
+
+		_, err := registry.ValidateArrayWithOptions(context.Background(), "slow", records, nil, models.BatchOptions{MaxConcurrency: 2, Timeout: 20 * time.Millisecond})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := registry.ValidateArrayWithOptions(ctx, "mixed", records, nil, models.BatchOptions{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context canceled, got %v", err)
+		}
+	})
+}
+
 // TestUnifiedRegistry_GlobalRegistry tests global registry functions
 func TestUnifiedRegistry_GetGlobalRegistry(t *testing.T) {
 	registry1 := GetGlobalRegistry()
@@ -1377,6 +1297,52 @@ func TestUnifiedRegistry_GetGlobalRegistry(t *testing.T) {
 	}
 }
 
+// TestUnifiedRegistry_StartAutoRegistration_Cancelled tests that a cancelled
+// startup registers nothing
+func TestUnifiedRegistry_StartAutoRegistration_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	registry := NewUnifiedRegistry()
+	if err := registry.StartAutoRegistration(ctx, http.NewServeMux()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if len(registry.ListModels()) != 0 {
+		t.Error("No models should be registered after a cancelled start")
+	}
+}
+
+// TestUnifiedRegistry_RegistrationStatus tests the status reported for health checks
+func TestUnifiedRegistry_RegistrationStatus(t *testing.T) {
+	registry := NewUnifiedRegistry()
+	if status := registry.RegistrationStatus(); status.Complete || status.Ready() {
+		t.Errorf("Fresh registry should not be ready: %+v", status)
+	}
+
+	if err := registry.StartAutoRegistration(context.Background(), http.NewServeMux()); err != nil {
+		t.Fatalf("StartAutoRegistration failed: %v", err)
+	}
+	status := registry.RegistrationStatus()
+	if !status.Complete || !status.Ready() || status.Expected != len(RegisteredSpecs()) {
+		t.Errorf("Expected ready status, got %+v", status)
+	}
+	for _, model := range status.Models {
+		if model.Status != ModelStatusRegistered {
+			t.Errorf("Model %s has status %s", model.Type, model.Status)
+		}
+	}
+
+	// A failed spec keeps the registry from being ready
+	registry.mutex.Lock()
+	registry.registrationFailures = map[ModelType]string{status.Models[0].Type: "broken"}
+	delete(registry.models, status.Models[0].Type)
+	registry.mutex.Unlock()
+	status = registry.RegistrationStatus()
+	if status.Ready() || status.Failed != 1 || status.Models[0].Status != ModelStatusFailed || status.Models[0].Error != "broken" {
+		t.Errorf("Expected failed model to be reported, got %+v", status)
+	}
+}
+
 // TestUnifiedRegistry_StartRegistration tests the global start function
 func TestUnifiedRegistry_StartRegistration(t *testing.T) {
 	ctx := context.Background()
@@ -1396,7 +1362,7 @@ func TestUnifiedRegistry_StartRegistration(t *testing.T) {
 
 // TestUnifiedRegistry_CreateModelInstance_ErrorPath tests error handling
 func TestUnifiedRegistry_CreateModelInstance_ErrorPath(t *testing.T) {
-	registry := NewUnifiedRegistry("", "")
+	registry := NewUnifiedRegistry()
 
 	_, err := registry.CreateModelInstance("nonexistent")
 	if err == nil {
@@ -1406,7 +1372,7 @@ func TestUnifiedRegistry_CreateModelInstance_ErrorPath(t *testing.T) {
 
 // TestUnifiedRegistry_RegisterAllHTTPEndpoints tests endpoint registration
 func TestUnifiedRegistry_RegisterAllHTTPEndpoints(t *testing.T) {
-	registry := NewUnifiedRegistry("", "")
+	registry := NewUnifiedRegistry()
 
 	t.Run("with nil mux", func(t *testing.T) {
 		registry.mux = nil
@@ -1418,11 +1384,7 @@ func TestUnifiedRegistry_RegisterAllHTTPEndpoints(t *testing.T) {
 		mux := http.NewServeMux()
 		registry.mux = mux
 
-		mockValidator := &UniversalValidatorWrapper{
-			modelType:         "endpoint_test",
-			validatorInstance: &mockValidatorInstance{},
-			modelStructType:   reflect.TypeOf(models.GenericPayload{}),
-		}
+		mockValidator := Adapt[models.GenericPayload](&mockValidatorInstance{})
 
 		modelInfo := &ModelInfo{
 			Type:        "endpoint_test",
@@ -1449,7 +1411,7 @@ func TestUnifiedRegistry_RegisterAllHTTPEndpoints(t *testing.T) {
 
 // TestDynamicRegistry_Functions tests dynamic registry wrapper functions
 func TestDynamicRegistry_Functions(t *testing.T) {
-	unified := NewUnifiedRegistry("", "")
+	unified := NewUnifiedRegistry()
 	dynamic := NewDynamicModelRegistry(unified, "models", "validations")
 
 	if dynamic.UnifiedRegistry != unified {
@@ -1492,61 +1454,64 @@ func TestStartDynamicRegistration(t *testing.T) {
 	}
 }
 
-// TestUniversalValidatorWrapper_EdgeCases tests validator wrapper edge cases
-func TestUniversalValidatorWrapper_EdgeCases(t *testing.T) {
-	t.Run("validator without ValidatePayload method", func(t *testing.T) {
-		wrapper := &UniversalValidatorWrapper{
-			modelType:         "test",
-			validatorInstance: &struct{}{}, // No methods
-			modelStructType:   reflect.TypeOf(models.GenericPayload{}),
-		}
-
-		result := wrapper.ValidatePayload(map[string]interface{}{})
+// TestAdapt tests the typed validator adapter
+func TestAdapt(t *testing.T) {
+	validator := Adapt[models.GenericPayload](&mockValidatorInstance{})
 
-		resultMap, ok := result.(map[string]interface{})
-		if !ok {
-			t.Fatal("Expected map result")
-		}
-
-		if resultMap["is_valid"] != false {
-			t.Error("Expected is_valid=false for missing method")
-		}
+	if validator.ModelStruct() != reflect.TypeOf(models.GenericPayload{}) {
+		t.Errorf("ModelStruct = %v, want models.GenericPayload", validator.ModelStruct())
+	}
 
-		if resultMap["provider"] != "universal-wrapper-fallback" {
-			t.Error("Expected fallback provider")
+	t.Run("struct value", func(t *testing.T) {
+		if result := validator.ValidatePayload(models.GenericPayload{ID: "1"}); !result.IsValid {
+			t.Errorf("Expected valid result, got %+v", result)
 		}
 	})
 
-	t.Run("validator with alternative method names", func(t *testing.T) {
-		wrapper := &UniversalValidatorWrapper{
-			modelType:         "test",
-			validatorInstance: &mockValidatorWithAlternateMethod{},
-			modelStructType:   reflect.TypeOf(models.GenericPayload{}),
+	t.Run("struct pointer", func(t *testing.T) {
+		if result := validator.ValidatePayload(&models.GenericPayload{ID: "1"}); !result.IsValid {
+			t.Errorf("Expected valid result, got %+v", result)
 		}
+	})
 
-		result := wrapper.ValidatePayload(map[string]interface{}{})
+	mismatches := []struct {
+		name    string
+		payload interface{}
+	}{
+		{"map payload", map[string]interface{}{"id": "1"}},
+		{"other struct", models.IncidentPayload{}},
+		{"nil pointer", (*models.GenericPayload)(nil)},
+		{"nil", nil},
+	}
 
-		if result == nil {
-			t.Error("Expected non-nil result")
-		}
-	})
+	for _, tt := range mismatches {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validator.ValidatePayload(tt.payload)
+			if result.IsValid {
+				t.Fatal("Expected invalid result for mismatched payload")
+			}
+			if len(result.Errors) != 1 || result.Errors[0].Code != "TYPE_MISMATCH" {
+				t.Errorf("Expected TYPE_MISMATCH error, got %+v", result.Errors)
+			}
+		})
+	}
 }
 
-// Helper function
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
+// TestValidatorFunc tests adapting a method value with ValidatorFunc
+func TestValidatorFunc(t *testing.T) {
+	validator := Adapt[models.GenericPayload](ValidatorFunc[models.GenericPayload]((&mockValidatorWithErrors{}).ValidatePayload))
+
+	result := validator.ValidatePayload(models.GenericPayload{})
+	if result.IsValid || result.Errors[0].Code != "INVALID_ID" {
+		t.Errorf("Expected INVALID_ID from wrapped method, got %+v", result)
 	}
-	return false
 }
 
 // Additional mock validators for testing
 
 type mockValidatorWithErrors struct{}
 
-func (m *mockValidatorWithErrors) ValidatePayload(payload interface{}) interface{} {
+func (m *mockValidatorWithErrors) ValidatePayload(payload models.GenericPayload) models.ValidationResult {
 	return models.ValidationResult{
 		IsValid:   false,
 		ModelType: "test",
@@ -1559,7 +1524,7 @@ func (m *mockValidatorWithErrors) ValidatePayload(payload interface{}) interface
 
 type mockValidatorWithWarnings struct{}
 
-func (m *mockValidatorWithWarnings) ValidatePayload(payload interface{}) interface{} {
+func (m *mockValidatorWithWarnings) ValidatePayload(payload models.GenericPayload) models.ValidationResult {
 	return models.ValidationResult{
 		IsValid:   true,
 		ModelType: "test",
@@ -1570,33 +1535,18 @@ func (m *mockValidatorWithWarnings) ValidatePayload(payload interface{}) interfa
 	}
 }
 
-type mockValidatorWithAlternateMethod struct{}
-
-func (m *mockValidatorWithAlternateMethod) Validate(payload interface{}) interface{} {
-	return map[string]interface{}{
-		"is_valid": true,
-	}
-}
-
 // mockValidatorWithMixedResults returns success for IDs starting with "valid-", errors for others
 type mockValidatorWithMixedResults struct{}
 
-func (m *mockValidatorWithMixedResults) ValidatePayload(payload interface{}) interface{} {
-	// Extract ID from payload if it's a map
-	if payloadMap, ok := payload.(map[string]interface{}); ok {
-		if id, exists := payloadMap["id"]; exists {
-			if idStr, ok := id.(string); ok {
-				// Return valid for IDs starting with "valid-"
-				if len(idStr) >= 6 && idStr[:6] == "valid-" {
-					return models.ValidationResult{
-						IsValid:   true,
-						ModelType: "mixedtest",
-						Provider:  "go-playground",
-						Errors:    []models.ValidationError{},
-						Warnings:  []models.ValidationWarning{},
-					}
-				}
-			}
+func (m *mockValidatorWithMixedResults) ValidatePayload(payload models.GenericPayload) models.ValidationResult {
+	// Return valid for IDs starting with "valid-"
+	if strings.HasPrefix(payload.ID, "valid-") {
+		return models.ValidationResult{
+			IsValid:   true,
+			ModelType: "mixedtest",
+			Provider:  "go-playground",
+			Errors:    []models.ValidationError{},
+			Warnings:  []models.ValidationWarning{},
 		}
 	}
 
@@ -1619,28 +1569,21 @@ func (m *mockValidatorWithMixedResults) ValidatePayload(payload interface{}) int
 // mockValidatorExactThreshold returns errors only for IDs starting with "invalid-"
 type mockValidatorExactThreshold struct{}
 
-func (m *mockValidatorExactThreshold) ValidatePayload(payload interface{}) interface{} {
-	// Extract ID from payload if it's a map
-	if payloadMap, ok := payload.(map[string]interface{}); ok {
-		if id, exists := payloadMap["id"]; exists {
-			if idStr, ok := id.(string); ok {
-				// Return invalid for IDs starting with "invalid-"
-				if len(idStr) >= 8 && idStr[:8] == "invalid-" {
-					return models.ValidationResult{
-						IsValid:   false,
-						ModelType: "exacttest",
-						Provider:  "go-playground",
-						Errors: []models.ValidationError{
-							{
-								Field:   "id",
-								Message: "Invalid record",
-								Code:    "VALIDATION_FAILED",
-							},
-						},
-						Warnings: []models.ValidationWarning{},
-					}
-				}
-			}
+func (m *mockValidatorExactThreshold) ValidatePayload(payload models.GenericPayload) models.ValidationResult {
+	// Return invalid for IDs starting with "invalid-"
+	if strings.HasPrefix(payload.ID, "invalid-") {
+		return models.ValidationResult{
+			IsValid:   false,
+			ModelType: "exacttest",
+			Provider:  "go-playground",
+			Errors: []models.ValidationError{
+				{
+					Field:   "id",
+					Message: "Invalid record",
+					Code:    "VALIDATION_FAILED",
+				},
+			},
+			Warnings: []models.ValidationWarning{},
 		}
 	}
 
@@ -1653,3 +1596,67 @@ func (m *mockValidatorExactThreshold) ValidatePayload(payload interface{}) inter
 		Warnings:  []models.ValidationWarning{},
 	}
 }
+
+// contextRecordingValidator records the trace ID of the context it validates with
+type contextRecordingValidator struct {
+	traceIDs []string
+}
+
+func (v *contextRecordingValidator) ValidatePayload(payload models.GenericPayload) models.ValidationResult {
+	return v.ValidatePayloadContext(context.Background(), payload)
+}
+
+func (v *contextRecordingValidator) ValidatePayloadContext(ctx context.Context, payload models.GenericPayload) models.ValidationResult {
+	v.traceIDs = append(v.traceIDs, tracing.TraceID(ctx))
+	return models.ValidationResult{IsValid: true}
+}
+
+// TestUnifiedRegistry_TraceContext tests that the request context reaches the
+// validator and that results carry the trace and request IDs
+func TestUnifiedRegistry_TraceContext(t *testing.T) {
+	registry := NewUnifiedRegistry()
+	validator := &contextRecordingValidator{}
+	if err := registry.RegisterModel(&ModelInfo{
+		Type:        "traced",
+		ModelStruct: reflect.TypeOf(models.GenericPayload{}),
+		Validator:   Adapt[models.GenericPayload](validator),
+	}); err != nil {
+		t.Fatalf("RegisterModel failed: %v", err)
+	}
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	parent, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		t.Fatalf("TraceIDFromHex failed: %v", err)
+	}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: parent,
+		SpanID:  trace.SpanID{1},
+	}))
+	ctx = tracing.WithRequestID(ctx, "req-7")
+
+	result, err := registry.ValidatePayloadContext(ctx, "traced", models.GenericPayload{ID: "1"})
+	if err != nil {
+		t.Fatalf("ValidatePayloadContext failed: %v", err)
+	}
+	if result.TraceID != traceID || result.RequestID != "req-7" {
+		t.Errorf("Expected trace and request IDs on result, got %q and %q", result.TraceID, result.RequestID)
+	}
+
+	arrayResult, err := registry.ValidateArrayWithOptions(ctx, "traced", []map[string]interface{}{{"id": "1"}, {"id": "2"}}, nil, models.BatchOptions{MaxConcurrency: 1})
+	if err != nil {
+		t.Fatalf("ValidateArrayWithOptions failed: %v", err)
+	}
+	if arrayResult.TraceID != traceID || arrayResult.RequestID != "req-7" {
+		t.Errorf("Expected trace and request IDs on array result, got %q and %q", arrayResult.TraceID, arrayResult.RequestID)
+	}
+
+	if len(validator.traceIDs) != 3 {
+		t.Fatalf("Expected 3 validator calls, got %d", len(validator.traceIDs))
+	}
+	for i, got := range validator.traceIDs {
+		if got != traceID {
+			t.Errorf("Call %d: expected validator to see trace %s, got %q", i, traceID, got)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Package tracing sets up OpenTelemetry tracing and carries trace and request IDs through a request
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans created by this service
+const instrumentationName = "goplayground-data-validator"
+
+// propagator reads and writes W3C traceparent/tracestate and baggage headers
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Exporters accepted in Config.Exporter
+const (
+	ExporterNone   = "none"   // IDs are generated and propagated, nothing is exported
+	ExporterOTLP   = "otlp"   // OTLP over HTTP, configured by the standard OTEL_EXPORTER_OTLP_* variables
+	ExporterStdout = "stdout" // One JSON document per span on standard output
+	ExporterFile   = "file"   // Like stdout, appended to Config.File
+)
+
+// Config selects where finished spans are sent
+type Config struct {
+	Exporter       string // One of the Exporter constants; empty means none
+	File           string // Output path for the file exporter
+	ServiceName    string // service.name resource attribute; OTEL_SERVICE_NAME overrides it
+	ServiceVersion string // service.version resource attribute
+
+	OTLPEndpoint string            // Collector URL for the OTLP exporter; overrides OTEL_EXPORTER_OTLP_ENDPOINT
+	OTLPHeaders  map[string]string // Headers sent to the collector; overrides OTEL_EXPORTER_OTLP_HEADERS
+}
+
+// Setup installs the global tracer provider and the W3C trace context propagator.
+// The returned function flushes pending spans and must be called before exit.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagator)
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", cfg.ServiceName),
+			attribute.String("service.version", cfg.ServiceVersion),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	options := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	var closeOutput io.Closer
+
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		// Spans still get IDs so results can be correlated, but none are recorded
+		options = append(options, sdktrace.WithSampler(sdktrace.NeverSample()))
+	case ExporterOTLP:
+		var otlpOptions []otlptracehttp.Option
+		if cfg.OTLPEndpoint != "" {
+			otlpOptions = append(otlpOptions, otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+		}
+		if len(cfg.OTLPHeaders) > 0 {
+			otlpOptions = append(otlpOptions, otlptracehttp.WithHeaders(cfg.OTLPHeaders))
+		}
+		exporter, err := otlptracehttp.New(ctx, otlpOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case ExporterStdout, ExporterFile:
+		var out io.Writer = os.Stdout
+		if cfg.Exporter == ExporterFile {
+			if cfg.File == "" {
+				return nil, fmt.Errorf("trace exporter %q needs a file path", ExporterFile)
+			}
+			file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open trace file: %w", err)
+			}
+			out, closeOutput = file, file
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(out))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Exporter, err)
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q (want none, otlp, stdout or file)", cfg.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeOutput != nil {
+			if closeErr := closeOutput.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
+
+// Tracer returns the tracer used for every span in the service
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span named name as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Fail marks span as failed with err
+func Fail(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// TraceID returns the hex trace ID of the span in ctx, or "" if there is none
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or ""
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
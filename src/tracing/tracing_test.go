@@ -0,0 +1,185 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// useRecorder installs a tracer provider that keeps finished spans in memory
+func useRecorder(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previous)
+	})
+	return exporter
+}
+
+func TestSetup(t *testing.T) {
+	t.Run("none still generates trace IDs", func(t *testing.T) {
+		shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+		if err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+		defer shutdown(context.Background())
+
+		ctx, span := Start(context.Background(), "test")
+		defer span.End()
+		if TraceID(ctx) == "" {
+			t.Error("Expected a trace ID without an exporter")
+		}
+		if span.IsRecording() {
+			t.Error("Expected spans not to be recorded without an exporter")
+		}
+	})
+
+	t.Run("file exporter writes spans", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "traces.jsonl")
+		shutdown, err := Setup(context.Background(), Config{Exporter: ExporterFile, File: path, ServiceName: "test"})
+		if err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+		_, span := Start(context.Background(), "written-span")
+		span.End()
+		if err := shutdown(context.Background()); err != nil {
+			t.Fatalf("shutdown failed: %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		if !strings.Contains(string(data), "written-span") {
+			t.Errorf("Expected span in trace file, got %s", data)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		if _, err := Setup(context.Background(), Config{Exporter: "zipkin"}); err == nil {
+			t.Error("Expected error for unknown exporter")
+		}
+		if _, err := Setup(context.Background(), Config{Exporter: ExporterFile}); err == nil {
+			t.Error("Expected error for file exporter without a path")
+		}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	exporter := useRecorder(t)
+
+	var traceID, requestID string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /validate/{model}", func(w http.ResponseWriter, r *http.Request) {
+		traceID, requestID = TraceID(r.Context()), RequestID(r.Context())
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req := httptest.NewRequest("POST", "/validate/incident", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set(RequestIDHeader, "req-42")
+	Middleware(mux).ServeHTTP(httptest.NewRecorder(), req)
+
+	if traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected the incoming trace to continue, got trace ID %q", traceID)
+	}
+	if requestID != "req-42" {
+		t.Errorf("Expected request ID from header, got %q", requestID)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "POST /validate/{model}" {
+		t.Errorf("Expected span named after the route, got %q", span.Name)
+	}
+	if span.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("Expected remote parent span, got %s", span.Parent.SpanID())
+	}
+	if span.Status.Code != codes.Error {
+		t.Errorf("Expected error status for a 503, got %v", span.Status.Code)
+	}
+	found := false
+	for _, attr := range span.Attributes {
+		if attr == attribute.Int("http.response.status_code", http.StatusServiceUnavailable) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected status code attribute, got %v", span.Attributes)
+	}
+}
+
+func TestMiddleware_RequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		generate bool
+	}{
+		{"caller ID is kept", "req-42", false},
+		{"missing ID is generated", "", true},
+		{"ID with spaces is replaced", "req 42", true},
+		{"overlong ID is replaced", strings.Repeat("a", maxRequestIDLength+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requestID string
+			handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestID = RequestID(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if tt.generate {
+				if len(requestID) != 32 || requestID == tt.header {
+					t.Errorf("Expected a generated 32-character ID, got %q", requestID)
+				}
+			} else if requestID != tt.header {
+				t.Errorf("Expected request ID %q, got %q", tt.header, requestID)
+			}
+			if got := w.Header().Get(RequestIDHeader); got != requestID {
+				t.Errorf("Expected response header %q, got %q", requestID, got)
+			}
+		})
+	}
+}
+
+func TestMiddleware_KeepsResponseController(t *testing.T) {
+	useRecorder(t)
+
+	var flushErr error
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("row\n"))
+		flushErr = http.NewResponseController(w).Flush()
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if flushErr != nil {
+		t.Errorf("Expected Flush to reach the underlying writer, got %v", flushErr)
+	}
+	if !w.Flushed {
+		t.Error("Expected the recorder to be flushed")
+	}
+}
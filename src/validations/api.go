@@ -3,6 +3,7 @@
 package validations
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"goplayground-data-validator/models"
+	"goplayground-data-validator/registry"
 )
 
 // APIValidator provides API-specific validation functionality.
@@ -28,8 +30,46 @@ func NewAPIValidator() *APIValidator {
 	return &APIValidator{validator: v}
 }
 
+// init registers the api model family with the unified registry at compile time
+func init() {
+	registry.Register("api", registry.ModelSpec{
+		NewValidator: registry.Constructor[models.APIRequest](NewAPIValidator),
+		Name:         "API Request",
+		Description:  "API request validation with comprehensive business rules",
+		Tags:         []string{"api", "http", "rest", "web"},
+	})
+	registry.Register("api.response", registry.ModelSpec{
+		NewValidator: registry.Method(NewAPIValidator, (*APIValidator).ValidateResponse),
+		Name:         "API Response",
+		Description:  "API response log validation with status, header and content rules",
+		Tags:         []string{"api", "http", "response"},
+	})
+	registry.Register("api.webhook", registry.ModelSpec{
+		NewValidator: registry.Method(NewAPIValidator, (*APIValidator).ValidateWebhook),
+		Name:         "API Webhook",
+		Description:  "Webhook configuration validation with delivery and security rules",
+		Tags:         []string{"api", "webhook", "integration"},
+	})
+	registry.Register("api.endpoint", registry.ModelSpec{
+		NewValidator: registry.Method(NewAPIValidator, (*APIValidator).ValidateEndpoint),
+		Name:         "API Endpoint",
+		Description:  "API endpoint definition validation with auth, rate limit and documentation rules",
+		Tags:         []string{"api", "endpoint", "openapi"},
+	})
+}
+
+// ValidatePayload implements registry.Validator for API requests
+func (av *APIValidator) ValidatePayload(request models.APIRequest) models.ValidationResult {
+	return av.ValidatePayloadContext(context.Background(), request)
+}
+
 // ValidateRequest validates an API request with comprehensive rules.
 func (av *APIValidator) ValidateRequest(request models.APIRequest) models.ValidationResult {
+	return av.ValidatePayloadContext(context.Background(), request)
+}
+
+// ValidatePayloadContext validates an API request, tracing each check as a span of ctx
+func (av *APIValidator) ValidatePayloadContext(ctx context.Context, request models.APIRequest) models.ValidationResult {
 	start := time.Now()
 
 	result := models.ValidationResult{
@@ -42,7 +82,7 @@ func (av *APIValidator) ValidateRequest(request models.APIRequest) models.Valida
 	}
 
 	// Perform struct validation
-	if err := av.validator.Struct(request); err != nil {
+	if err := traceStruct(ctx, av.validator, request); err != nil {
 		result.IsValid = false
 
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
@@ -63,7 +103,7 @@ func (av *APIValidator) ValidateRequest(request models.APIRequest) models.Valida
 
 	// Perform business logic validation
 	if result.IsValid {
-		warnings := ValidateAPIRequestBusinessLogic(request)
+		warnings := validateAPIRequestBusinessLogic(ctx, request)
 		result.Warnings = append(result.Warnings, warnings...)
 	}
 
@@ -130,6 +170,108 @@ func (av *APIValidator) ValidateResponse(response models.APIResponse) models.Val
 	return result
 }
 
+// ValidateWebhook validates an API webhook configuration with comprehensive rules.
+func (av *APIValidator) ValidateWebhook(webhook models.APIWebhook) models.ValidationResult {
+	start := time.Now()
+
+	result := models.ValidationResult{
+		IsValid:   true,
+		ModelType: "APIWebhook",
+		Provider:  "api_validator",
+		Timestamp: time.Now(),
+		Errors:    []models.ValidationError{},
+		Warnings:  []models.ValidationWarning{},
+	}
+
+	// Perform struct validation
+	if err := av.validator.Struct(webhook); err != nil {
+		result.IsValid = false
+
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			for _, fieldError := range validationErrors {
+				result.Errors = append(result.Errors, models.ValidationError{
+					Field:      fieldError.Field(),
+					Message:    formatAPIValidationError(fieldError),
+					Code:       fieldError.Tag(),
+					Value:      fieldError.Value(),
+					Expected:   fieldError.Param(),
+					Constraint: fieldError.Tag(),
+					Path:       fieldError.Namespace(),
+					Severity:   "error",
+				})
+			}
+		}
+	}
+
+	// Perform business logic validation
+	if result.IsValid {
+		warnings := ValidateAPIWebhookBusinessLogic(webhook)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+
+	// Add performance metrics
+	result.ProcessingDuration = time.Since(start)
+	result.PerformanceMetrics = &models.PerformanceMetrics{
+		ValidationDuration: time.Since(start),
+		Provider:           "api_validator",
+		FieldCount:         countAPIWebhookFields(webhook),
+		RuleCount:          av.getRuleCount(),
+	}
+
+	return result
+}
+
+// ValidateEndpoint validates an API endpoint definition with comprehensive rules.
+func (av *APIValidator) ValidateEndpoint(endpoint models.APIEndpoint) models.ValidationResult {
+	start := time.Now()
+
+	result := models.ValidationResult{
+		IsValid:   true,
+		ModelType: "APIEndpoint",
+		Provider:  "api_validator",
+		Timestamp: time.Now(),
+		Errors:    []models.ValidationError{},
+		Warnings:  []models.ValidationWarning{},
+	}
+
+	// Perform struct validation
+	if err := av.validator.Struct(endpoint); err != nil {
+		result.IsValid = false
+
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			for _, fieldError := range validationErrors {
+				result.Errors = append(result.Errors, models.ValidationError{
+					Field:      fieldError.Field(),
+					Message:    formatAPIValidationError(fieldError),
+					Code:       fieldError.Tag(),
+					Value:      fieldError.Value(),
+					Expected:   fieldError.Param(),
+					Constraint: fieldError.Tag(),
+					Path:       fieldError.Namespace(),
+					Severity:   "error",
+				})
+			}
+		}
+	}
+
+	// Perform business logic validation
+	if result.IsValid {
+		warnings := ValidateAPIEndpointBusinessLogic(endpoint)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+
+	// Add performance metrics
+	result.ProcessingDuration = time.Since(start)
+	result.PerformanceMetrics = &models.PerformanceMetrics{
+		ValidationDuration: time.Since(start),
+		Provider:           "api_validator",
+		FieldCount:         countAPIEndpointFields(endpoint),
+		RuleCount:          av.getRuleCount(),
+	}
+
+	return result
+}
+
 // validateAPIContentType validates API content type format.
 func validateAPIContentType(fl validator.FieldLevel) bool {
 	contentType := fl.Field().String()
@@ -212,19 +354,24 @@ func validateAPIVersion(fl validator.FieldLevel) bool {
 
 // ValidateAPIRequestBusinessLogic performs API request-specific business logic validation.
 func ValidateAPIRequestBusinessLogic(request models.APIRequest) []models.ValidationWarning {
+	return validateAPIRequestBusinessLogic(context.Background(), request)
+}
+
+// validateAPIRequestBusinessLogic runs each API request rule in its own span
+func validateAPIRequestBusinessLogic(ctx context.Context, request models.APIRequest) []models.ValidationWarning {
 	var warnings []models.ValidationWarning
 
 	// Check for security concerns
-	warnings = append(warnings, checkAPIRequestSecurity(request)...)
+	warnings = append(warnings, traceWarnings(ctx, "api_request_security", checkAPIRequestSecurity, request)...)
 
 	// Check for performance concerns
-	warnings = append(warnings, checkAPIRequestPerformance(request)...)
+	warnings = append(warnings, traceWarnings(ctx, "api_request_performance", checkAPIRequestPerformance, request)...)
 
 	// Check for best practices
-	warnings = append(warnings, checkAPIRequestBestPractices(request)...)
+	warnings = append(warnings, traceWarnings(ctx, "api_request_best_practices", checkAPIRequestBestPractices, request)...)
 
 	// Check for rate limiting concerns
-	warnings = append(warnings, checkAPIRequestRateLimit(request)...)
+	warnings = append(warnings, traceWarnings(ctx, "api_request_rate_limit", checkAPIRequestRateLimit, request)...)
 
 	return warnings
 }
@@ -248,6 +395,121 @@ func ValidateAPIResponseBusinessLogic(response models.APIResponse) []models.Vali
 	return warnings
 }
 
+// ValidateAPIWebhookBusinessLogic performs API webhook-specific business logic validation.
+func ValidateAPIWebhookBusinessLogic(webhook models.APIWebhook) []models.ValidationWarning {
+	var warnings []models.ValidationWarning
+
+	// Check for delivery security
+	if !webhook.SSL || strings.HasPrefix(strings.ToLower(webhook.URL), "http://") {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "URL",
+			Message:    "Webhook deliveries are not protected by TLS",
+			Code:       "WEBHOOK_INSECURE_DELIVERY",
+			Value:      webhook.URL,
+			Suggestion: "Use an HTTPS URL and enable SSL verification",
+			Category:   "security",
+		})
+	}
+
+	if webhook.Secret == "" {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Secret",
+			Message:    "Webhook has no signing secret",
+			Code:       "WEBHOOK_UNSIGNED",
+			Suggestion: "Configure a secret so receivers can verify payload signatures",
+			Category:   "security",
+		})
+	}
+
+	// Check for delivery health
+	if webhook.LastResponse != nil && !webhook.LastResponse.Success {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "LastResponse.Success",
+			Message:    fmt.Sprintf("Last webhook delivery failed with status %d", webhook.LastResponse.StatusCode),
+			Code:       "WEBHOOK_DELIVERY_FAILED",
+			Value:      webhook.LastResponse.StatusCode,
+			Suggestion: "Check the receiving endpoint and retry failed deliveries",
+			Category:   "reliability",
+		})
+	}
+
+	if !webhook.Active {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Active",
+			Message:    "Webhook is configured but inactive",
+			Code:       "WEBHOOK_INACTIVE",
+			Suggestion: "Activate the webhook or remove unused configuration",
+			Category:   "configuration",
+		})
+	}
+
+	return warnings
+}
+
+// ValidateAPIEndpointBusinessLogic performs API endpoint-specific business logic validation.
+func ValidateAPIEndpointBusinessLogic(endpoint models.APIEndpoint) []models.ValidationWarning {
+	var warnings []models.ValidationWarning
+
+	// Check for unauthenticated write operations
+	isWrite := endpoint.Method == "POST" || endpoint.Method == "PUT" || endpoint.Method == "PATCH" || endpoint.Method == "DELETE"
+	if isWrite && (endpoint.Auth == nil || !endpoint.Auth.Required) {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Auth",
+			Message:    fmt.Sprintf("%s endpoint does not require authentication", endpoint.Method),
+			Code:       "ENDPOINT_UNAUTHENTICATED_WRITE",
+			Value:      endpoint.Path,
+			Suggestion: "Require authentication for endpoints that modify data",
+			Category:   "security",
+		})
+	}
+
+	// Check for public endpoints without rate limits
+	if endpoint.Public && endpoint.RateLimit == nil {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "RateLimit",
+			Message:    "Public endpoint has no rate limit",
+			Code:       "ENDPOINT_NO_RATE_LIMIT",
+			Suggestion: "Configure rate limiting to protect public endpoints",
+			Category:   "performance",
+		})
+	}
+
+	// Check for deprecated public endpoints
+	if endpoint.Deprecated && endpoint.Public {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Deprecated",
+			Message:    "Public endpoint is deprecated",
+			Code:       "ENDPOINT_DEPRECATED",
+			Value:      endpoint.Path,
+			Suggestion: "Announce a sunset date and point clients to the replacement",
+			Category:   "maintainability",
+		})
+	}
+
+	// Check for documentation completeness
+	if len(endpoint.Responses) == 0 {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Responses",
+			Message:    "Endpoint documents no responses",
+			Code:       "ENDPOINT_UNDOCUMENTED_RESPONSES",
+			Suggestion: "Document at least the success and error responses",
+			Category:   "documentation",
+		})
+	}
+
+	if endpoint.Version == "" {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Version",
+			Message:    "Endpoint is not versioned",
+			Code:       "ENDPOINT_UNVERSIONED",
+			Suggestion: "Version endpoints so breaking changes can be rolled out safely",
+			Category:   "best-practices",
+		})
+	}
+
+	return warnings
+}
+
 // checkAPIRequestSecurity checks for security-related concerns in API requests.
 func checkAPIRequestSecurity(request models.APIRequest) []models.ValidationWarning {
 	var warnings []models.ValidationWarning
@@ -677,6 +939,25 @@ func countAPIResponseFields(response models.APIResponse) int {
 	return count
 }
 
+// countAPIWebhookFields counts the number of fields in an API webhook for metrics.
+func countAPIWebhookFields(webhook models.APIWebhook) int {
+	count := 15 // Base fields
+	count += len(webhook.Events)
+	count += len(webhook.Headers)
+	count += len(webhook.Config)
+	return count
+}
+
+// countAPIEndpointFields counts the number of fields in an API endpoint for metrics.
+func countAPIEndpointFields(endpoint models.APIEndpoint) int {
+	count := 15 // Base fields
+	count += len(endpoint.Tags)
+	count += len(endpoint.Parameters)
+	count += len(endpoint.Responses)
+	count += len(endpoint.Examples)
+	return count
+}
+
 // getRuleCount returns the number of validation rules applied.
 func (av *APIValidator) getRuleCount() int {
 	// Return approximate number of validation rules
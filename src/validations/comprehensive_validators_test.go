@@ -2,11 +2,16 @@
 package validations
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"goplayground-data-validator/models"
+	"goplayground-data-validator/registry"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -276,6 +281,93 @@ func TestAPIValidator_ValidateResponse_InvalidDuration(t *testing.T) {
 	assert.False(t, result.IsValid)
 }
 
+func TestAPIValidator_ValidateWebhook_Valid(t *testing.T) {
+	validator := NewAPIValidator()
+
+	webhook := models.APIWebhook{
+		ID:          "hook-1",
+		URL:         "https://hooks.example.com/deliver",
+		Events:      []string{"push"},
+		Secret:      "s3cr3t-value",
+		ContentType: "application/json",
+		Active:      true,
+		SSL:         true,
+		CreatedAt:   time.Now().Add(-time.Hour),
+		UpdatedAt:   time.Now(),
+	}
+
+	result := validator.ValidateWebhook(webhook)
+	assert.True(t, result.IsValid)
+	assert.Equal(t, "APIWebhook", result.ModelType)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestAPIValidator_ValidateWebhook_InsecureDelivery(t *testing.T) {
+	validator := NewAPIValidator()
+
+	webhook := models.APIWebhook{
+		ID:          "hook-1",
+		URL:         "http://hooks.example.com/deliver",
+		Events:      []string{"push"},
+		ContentType: "application/json",
+		Active:      true,
+		CreatedAt:   time.Now().Add(-time.Hour),
+		UpdatedAt:   time.Now(),
+	}
+
+	result := validator.ValidateWebhook(webhook)
+	assert.True(t, result.IsValid)
+	assert.True(t, hasWarning(result, "WEBHOOK_INSECURE_DELIVERY"))
+	assert.True(t, hasWarning(result, "WEBHOOK_UNSIGNED"))
+}
+
+func TestAPIValidator_ValidateWebhook_MissingEvents(t *testing.T) {
+	validator := NewAPIValidator()
+
+	webhook := models.APIWebhook{
+		ID:          "hook-1",
+		URL:         "https://hooks.example.com/deliver",
+		ContentType: "application/json",
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	result := validator.ValidateWebhook(webhook)
+	assert.False(t, result.IsValid)
+}
+
+func TestAPIValidator_ValidateEndpoint_UnauthenticatedWrite(t *testing.T) {
+	validator := NewAPIValidator()
+
+	endpoint := models.APIEndpoint{
+		Path:      "/orders",
+		Method:    "POST",
+		Public:    true,
+		CreatedAt: time.Now().Add(-time.Hour),
+		UpdatedAt: time.Now(),
+	}
+
+	result := validator.ValidateEndpoint(endpoint)
+	assert.True(t, result.IsValid)
+	assert.Equal(t, "APIEndpoint", result.ModelType)
+	assert.True(t, hasWarning(result, "ENDPOINT_UNAUTHENTICATED_WRITE"))
+	assert.True(t, hasWarning(result, "ENDPOINT_NO_RATE_LIMIT"))
+}
+
+func TestAPIValidator_ValidateEndpoint_InvalidMethod(t *testing.T) {
+	validator := NewAPIValidator()
+
+	endpoint := models.APIEndpoint{
+		Path:      "/orders",
+		Method:    "FETCH",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	result := validator.ValidateEndpoint(endpoint)
+	assert.False(t, result.IsValid)
+}
+
 // ========================================
 // GitHubValidator Tests
 // ========================================
@@ -692,6 +784,84 @@ func TestDatabaseValidator_ValidateTransaction_InvalidIsolationLevel(t *testing.
 	assert.False(t, result.IsValid)
 }
 
+func TestDatabaseValidator_ValidateMigration_DestructiveWithoutRollback(t *testing.T) {
+	validator := NewDatabaseValidator()
+
+	migration := models.DatabaseMigration{
+		ID:          "mig-42",
+		Version:     "1.4.0",
+		Name:        "drop legacy orders",
+		Type:        "schema",
+		Direction:   "up",
+		SQL:         "DROP TABLE legacy_orders;",
+		Checksum:    strings.Repeat("a", 64),
+		Environment: "production",
+	}
+
+	result := validator.ValidateMigration(migration)
+	assert.True(t, result.IsValid)
+	assert.Equal(t, "DatabaseMigration", result.ModelType)
+	assert.True(t, hasWarning(result, "DESTRUCTIVE_MIGRATION"))
+	assert.True(t, hasWarning(result, "MIGRATION_NO_ROLLBACK"))
+}
+
+func TestDatabaseValidator_ValidateMigration_InvalidChecksum(t *testing.T) {
+	validator := NewDatabaseValidator()
+
+	migration := models.DatabaseMigration{
+		ID:        "mig-42",
+		Version:   "1.4.0",
+		Name:      "add index",
+		Type:      "schema",
+		Direction: "up",
+		SQL:       "CREATE INDEX idx ON orders(id);",
+		Checksum:  "not-a-sha256",
+	}
+
+	result := validator.ValidateMigration(migration)
+	assert.False(t, result.IsValid)
+}
+
+func TestDatabaseValidator_ValidateBackup_Unencrypted(t *testing.T) {
+	validator := NewDatabaseValidator()
+
+	backup := models.DatabaseBackup{
+		ID:        "bkp-1",
+		Type:      "full",
+		Status:    "completed",
+		StartTime: time.Now().Add(-time.Hour),
+		Location:  "s3://backups/bkp-1",
+		ConnectionInfo: models.DatabaseConnectionInfo{
+			Host:     "localhost",
+			Port:     5432,
+			Database: "testdb",
+			Username: "dbuser",
+			Driver:   "postgres",
+		},
+	}
+
+	result := validator.ValidateBackup(backup)
+	assert.True(t, result.IsValid)
+	assert.Equal(t, "DatabaseBackup", result.ModelType)
+	assert.True(t, hasWarning(result, "BACKUP_UNENCRYPTED"))
+	assert.True(t, hasWarning(result, "BACKUP_NO_CHECKSUM"))
+}
+
+func TestDatabaseValidator_ValidateBackup_InvalidType(t *testing.T) {
+	validator := NewDatabaseValidator()
+
+	backup := models.DatabaseBackup{
+		ID:        "bkp-1",
+		Type:      "snapshot",
+		Status:    "completed",
+		StartTime: time.Now(),
+		Location:  "s3://backups/bkp-1",
+	}
+
+	result := validator.ValidateBackup(backup)
+	assert.False(t, result.IsValid)
+}
+
 // ========================================
 // DeploymentValidator Tests
 // ========================================
@@ -3281,6 +3451,60 @@ func TestIncidentValidator_AllStatuses(t *testing.T) {
 	}
 }
 
+// ========================================
+// Registration Tests
+// ========================================
+
+func TestRegisteredModels_TypedValidators(t *testing.T) {
+	expected := map[registry.ModelType]reflect.Type{
+		"api":        reflect.TypeOf(models.APIRequest{}),
+		"database":   reflect.TypeOf(models.DatabaseQuery{}),
+		"deployment": reflect.TypeOf(models.DeploymentPayload{}),
+		"generic":    reflect.TypeOf(models.GenericPayload{}),
+		"github":     reflect.TypeOf(models.GitHubPayload{}),
+		"incident":   reflect.TypeOf(models.IncidentPayload{}),
+
+		"api.response":         reflect.TypeOf(models.APIResponse{}),
+		"api.webhook":          reflect.TypeOf(models.APIWebhook{}),
+		"api.endpoint":         reflect.TypeOf(models.APIEndpoint{}),
+		"api.model":            reflect.TypeOf(models.APIModel{}),
+		"database.transaction": reflect.TypeOf(models.DatabaseTransaction{}),
+		"database.migration":   reflect.TypeOf(models.DatabaseMigration{}),
+		"database.backup":      reflect.TypeOf(models.DatabaseBackup{}),
+	}
+
+	reg := registry.NewUnifiedRegistry()
+	assert.NoError(t, reg.StartAutoRegistration(context.Background(), http.NewServeMux()))
+
+	for modelType, modelStruct := range expected {
+		info, err := reg.GetModel(modelType)
+		if assert.NoError(t, err, modelType) {
+			assert.Equal(t, modelStruct, info.ModelStruct, modelType)
+		}
+	}
+
+	// Sub-models reach their own validator method
+	result, err := reg.ValidatePayload("database.migration", models.DatabaseMigration{})
+	assert.NoError(t, err)
+	assert.Equal(t, "DatabaseMigration", result.ModelType)
+
+	// A payload of the wrong model type is rejected by the adapter, not the validator
+	result, err = reg.ValidatePayload("deployment", models.IncidentPayload{})
+	assert.NoError(t, err)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, "TYPE_MISMATCH", result.Errors[0].Code)
+}
+
+// hasWarning reports whether the result carries a warning with the given code
+func hasWarning(result models.ValidationResult, code string) bool {
+	for _, warning := range result.Warnings {
+		if warning.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
 func TestCountNewTests(t *testing.T) {
 	t.Log("Enhanced test suite with comprehensive edge case and enum coverage")
 }
@@ -3,6 +3,7 @@
 package validations
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"goplayground-data-validator/models"
+	"goplayground-data-validator/registry"
 )
 
 // DatabaseValidator provides database-specific validation functionality.
@@ -28,8 +30,46 @@ func NewDatabaseValidator() *DatabaseValidator {
 	return &DatabaseValidator{validator: v}
 }
 
+// init registers the database model family with the unified registry at compile time
+func init() {
+	registry.Register("database", registry.ModelSpec{
+		NewValidator: registry.Constructor[models.DatabaseQuery](NewDatabaseValidator),
+		Name:         "Database Query",
+		Description:  "Database query validation with comprehensive business rules",
+		Tags:         []string{"database", "sql", "query"},
+	})
+	registry.Register("database.transaction", registry.ModelSpec{
+		NewValidator: registry.Method(NewDatabaseValidator, (*DatabaseValidator).ValidateTransaction),
+		Name:         "Database Transaction",
+		Description:  "Database transaction validation with lock and isolation level rules",
+		Tags:         []string{"database", "sql", "transaction"},
+	})
+	registry.Register("database.migration", registry.ModelSpec{
+		NewValidator: registry.Method(NewDatabaseValidator, (*DatabaseValidator).ValidateMigration),
+		Name:         "Database Migration",
+		Description:  "Migration manifest validation with rollback and destructive change rules",
+		Tags:         []string{"database", "migration", "schema"},
+	})
+	registry.Register("database.backup", registry.ModelSpec{
+		NewValidator: registry.Method(NewDatabaseValidator, (*DatabaseValidator).ValidateBackup),
+		Name:         "Database Backup",
+		Description:  "Backup record validation with encryption, integrity and retention rules",
+		Tags:         []string{"database", "backup", "operations"},
+	})
+}
+
+// ValidatePayload implements registry.Validator for database queries
+func (dv *DatabaseValidator) ValidatePayload(query models.DatabaseQuery) models.ValidationResult {
+	return dv.ValidatePayloadContext(context.Background(), query)
+}
+
 // ValidateQuery validates a database query with comprehensive rules.
 func (dv *DatabaseValidator) ValidateQuery(query models.DatabaseQuery) models.ValidationResult {
+	return dv.ValidatePayloadContext(context.Background(), query)
+}
+
+// ValidatePayloadContext validates a database query, tracing each check as a span of ctx
+func (dv *DatabaseValidator) ValidatePayloadContext(ctx context.Context, query models.DatabaseQuery) models.ValidationResult {
 	start := time.Now()
 
 	result := models.ValidationResult{
@@ -42,7 +82,7 @@ func (dv *DatabaseValidator) ValidateQuery(query models.DatabaseQuery) models.Va
 	}
 
 	// Perform struct validation
-	if err := dv.validator.Struct(query); err != nil {
+	if err := traceStruct(ctx, dv.validator, query); err != nil {
 		result.IsValid = false
 
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
@@ -63,7 +103,7 @@ func (dv *DatabaseValidator) ValidateQuery(query models.DatabaseQuery) models.Va
 
 	// Perform business logic validation
 	if result.IsValid {
-		warnings := ValidateDatabaseQueryBusinessLogic(query)
+		warnings := validateDatabaseQueryBusinessLogic(ctx, query)
 		result.Warnings = append(result.Warnings, warnings...)
 	}
 
@@ -130,6 +170,108 @@ func (dv *DatabaseValidator) ValidateTransaction(transaction models.DatabaseTran
 	return result
 }
 
+// ValidateMigration validates a database migration manifest with comprehensive rules.
+func (dv *DatabaseValidator) ValidateMigration(migration models.DatabaseMigration) models.ValidationResult {
+	start := time.Now()
+
+	result := models.ValidationResult{
+		IsValid:   true,
+		ModelType: "DatabaseMigration",
+		Provider:  "database_validator",
+		Timestamp: time.Now(),
+		Errors:    []models.ValidationError{},
+		Warnings:  []models.ValidationWarning{},
+	}
+
+	// Perform struct validation
+	if err := dv.validator.Struct(migration); err != nil {
+		result.IsValid = false
+
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			for _, fieldError := range validationErrors {
+				result.Errors = append(result.Errors, models.ValidationError{
+					Field:      fieldError.Field(),
+					Message:    formatDatabaseValidationError(fieldError),
+					Code:       fieldError.Tag(),
+					Value:      fieldError.Value(),
+					Expected:   fieldError.Param(),
+					Constraint: fieldError.Tag(),
+					Path:       fieldError.Namespace(),
+					Severity:   "error",
+				})
+			}
+		}
+	}
+
+	// Perform business logic validation
+	if result.IsValid {
+		warnings := ValidateDatabaseMigrationBusinessLogic(migration)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+
+	// Add performance metrics
+	result.ProcessingDuration = time.Since(start)
+	result.PerformanceMetrics = &models.PerformanceMetrics{
+		ValidationDuration: time.Since(start),
+		Provider:           "database_validator",
+		FieldCount:         countDatabaseMigrationFields(migration),
+		RuleCount:          dv.getRuleCount(),
+	}
+
+	return result
+}
+
+// ValidateBackup validates a database backup record with comprehensive rules.
+func (dv *DatabaseValidator) ValidateBackup(backup models.DatabaseBackup) models.ValidationResult {
+	start := time.Now()
+
+	result := models.ValidationResult{
+		IsValid:   true,
+		ModelType: "DatabaseBackup",
+		Provider:  "database_validator",
+		Timestamp: time.Now(),
+		Errors:    []models.ValidationError{},
+		Warnings:  []models.ValidationWarning{},
+	}
+
+	// Perform struct validation
+	if err := dv.validator.Struct(backup); err != nil {
+		result.IsValid = false
+
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			for _, fieldError := range validationErrors {
+				result.Errors = append(result.Errors, models.ValidationError{
+					Field:      fieldError.Field(),
+					Message:    formatDatabaseValidationError(fieldError),
+					Code:       fieldError.Tag(),
+					Value:      fieldError.Value(),
+					Expected:   fieldError.Param(),
+					Constraint: fieldError.Tag(),
+					Path:       fieldError.Namespace(),
+					Severity:   "error",
+				})
+			}
+		}
+	}
+
+	// Perform business logic validation
+	if result.IsValid {
+		warnings := ValidateDatabaseBackupBusinessLogic(backup)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+
+	// Add performance metrics
+	result.ProcessingDuration = time.Since(start)
+	result.PerformanceMetrics = &models.PerformanceMetrics{
+		ValidationDuration: time.Since(start),
+		Provider:           "database_validator",
+		FieldCount:         countDatabaseBackupFields(backup),
+		RuleCount:          dv.getRuleCount(),
+	}
+
+	return result
+}
+
 // validateHostnameRFC1123 validates hostname according to RFC 1123.
 func validateHostnameRFC1123(fl validator.FieldLevel) bool {
 	hostname := fl.Field().String()
@@ -159,23 +301,28 @@ func validateSemVer(fl validator.FieldLevel) bool {
 
 // ValidateDatabaseQueryBusinessLogic performs database query-specific business logic validation.
 func ValidateDatabaseQueryBusinessLogic(query models.DatabaseQuery) []models.ValidationWarning {
+	return validateDatabaseQueryBusinessLogic(context.Background(), query)
+}
+
+// validateDatabaseQueryBusinessLogic runs each database query rule in its own span
+func validateDatabaseQueryBusinessLogic(ctx context.Context, query models.DatabaseQuery) []models.ValidationWarning {
 	var warnings []models.ValidationWarning
 
 	// Check for SQL injection patterns
-	warnings = append(warnings, checkSQLInjectionPatterns(query)...)
+	warnings = append(warnings, traceWarnings(ctx, "sql_injection_patterns", checkSQLInjectionPatterns, query)...)
 
 	// Check for performance concerns
-	warnings = append(warnings, checkDatabasePerformanceConcerns(query)...)
+	warnings = append(warnings, traceWarnings(ctx, "database_performance_concerns", checkDatabasePerformanceConcerns, query)...)
 
 	// Check for security concerns
-	warnings = append(warnings, checkDatabaseSecurityConcerns(query)...)
+	warnings = append(warnings, traceWarnings(ctx, "database_security_concerns", checkDatabaseSecurityConcerns, query)...)
 
 	// Check for best practices
-	warnings = append(warnings, checkDatabaseBestPractices(query)...)
+	warnings = append(warnings, traceWarnings(ctx, "database_best_practices", checkDatabaseBestPractices, query)...)
 
 	// Check execution plan concerns
 	if query.ExecutionPlan != nil {
-		warnings = append(warnings, checkExecutionPlanConcerns(*query.ExecutionPlan)...)
+		warnings = append(warnings, traceWarnings(ctx, "execution_plan_concerns", checkExecutionPlanConcerns, *query.ExecutionPlan)...)
 	}
 
 	return warnings
@@ -200,6 +347,123 @@ func ValidateDatabaseTransactionBusinessLogic(transaction models.DatabaseTransac
 	return warnings
 }
 
+// ValidateDatabaseMigrationBusinessLogic performs database migration-specific business logic validation.
+func ValidateDatabaseMigrationBusinessLogic(migration models.DatabaseMigration) []models.ValidationWarning {
+	var warnings []models.ValidationWarning
+
+	sqlLower := strings.ToLower(migration.SQL)
+
+	// Check for destructive statements
+	for _, operation := range []string{"drop table", "drop column", "truncate table", "drop database"} {
+		if strings.Contains(sqlLower, operation) {
+			warnings = append(warnings, models.ValidationWarning{
+				Field:      "SQL",
+				Message:    fmt.Sprintf("Destructive migration statement detected: %s", operation),
+				Code:       "DESTRUCTIVE_MIGRATION",
+				Suggestion: "Back up affected data and stage destructive changes separately",
+				Category:   "data-safety",
+			})
+			break
+		}
+	}
+
+	// Check for rollback coverage in production
+	if migration.Environment == "production" && (migration.Rollback == nil || !migration.Rollback.Available) {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Rollback",
+			Message:    "Production migration has no rollback available",
+			Code:       "MIGRATION_NO_ROLLBACK",
+			Suggestion: "Provide rollback SQL before running in production",
+			Category:   "reliability",
+		})
+	}
+
+	if migration.Environment == "production" && migration.Direction == "down" {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Direction",
+			Message:    "Down migration targeted at production",
+			Code:       "MIGRATION_DOWN_IN_PRODUCTION",
+			Suggestion: "Confirm the rollback is intentional and data loss is acceptable",
+			Category:   "data-safety",
+		})
+	}
+
+	// Check execution outcome
+	if migration.ExecutedAt != nil && !migration.Success {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Success",
+			Message:    fmt.Sprintf("Migration execution failed: %s", migration.Error),
+			Code:       "MIGRATION_FAILED",
+			Value:      migration.Error,
+			Suggestion: "Resolve the failure and re-run or roll back the migration",
+			Category:   "reliability",
+		})
+	}
+
+	return warnings
+}
+
+// ValidateDatabaseBackupBusinessLogic performs database backup-specific business logic validation.
+func ValidateDatabaseBackupBusinessLogic(backup models.DatabaseBackup) []models.ValidationWarning {
+	var warnings []models.ValidationWarning
+
+	// Check for encryption at rest
+	if backup.Encryption == nil || !backup.Encryption.Enabled {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Encryption",
+			Message:    "Backup is not encrypted",
+			Code:       "BACKUP_UNENCRYPTED",
+			Suggestion: "Enable backup encryption to protect data at rest",
+			Category:   "security",
+		})
+	}
+
+	// Check backup outcome and integrity
+	if backup.Status == "failed" {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Status",
+			Message:    fmt.Sprintf("Backup failed: %s", backup.Error),
+			Code:       "BACKUP_FAILED",
+			Value:      backup.Error,
+			Suggestion: "Investigate the failure and schedule a new backup",
+			Category:   "reliability",
+		})
+	}
+
+	if backup.Status == "completed" && backup.Checksum == "" {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "Checksum",
+			Message:    "Completed backup has no checksum",
+			Code:       "BACKUP_NO_CHECKSUM",
+			Suggestion: "Record a checksum so restores can be verified",
+			Category:   "integrity",
+		})
+	}
+
+	if backup.RetentionPolicy == nil {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "RetentionPolicy",
+			Message:    "Backup has no retention policy",
+			Code:       "BACKUP_NO_RETENTION_POLICY",
+			Suggestion: "Define a retention policy to control storage costs and compliance",
+			Category:   "best-practices",
+		})
+	}
+
+	if backup.Size > 0 && backup.CompressedSize > backup.Size {
+		warnings = append(warnings, models.ValidationWarning{
+			Field:      "CompressedSize",
+			Message:    fmt.Sprintf("Compressed size %d exceeds original size %d", backup.CompressedSize, backup.Size),
+			Code:       "BACKUP_COMPRESSION_INEFFECTIVE",
+			Value:      backup.CompressedSize,
+			Suggestion: "Disable compression or choose a different algorithm for this data",
+			Category:   "performance",
+		})
+	}
+
+	return warnings
+}
+
 // checkSQLInjectionPatterns checks for potential SQL injection patterns.
 func checkSQLInjectionPatterns(query models.DatabaseQuery) []models.ValidationWarning {
 	var warnings []models.ValidationWarning
@@ -743,6 +1007,22 @@ func countDatabaseTransactionFields(transaction models.DatabaseTransaction) int
 	return count
 }
 
+// countDatabaseMigrationFields counts the number of fields in a database migration for metrics.
+func countDatabaseMigrationFields(migration models.DatabaseMigration) int {
+	count := 15 // Base fields
+	count += len(migration.Dependencies)
+	count += len(migration.Tags)
+	return count
+}
+
+// countDatabaseBackupFields counts the number of fields in a database backup for metrics.
+func countDatabaseBackupFields(backup models.DatabaseBackup) int {
+	count := 15 // Base fields
+	count += len(backup.Tables)
+	count += len(backup.ExcludedTables)
+	return count
+}
+
 // getRuleCount returns the number of validation rules applied.
 func (dv *DatabaseValidator) getRuleCount() int {
 	// Return approximate number of validation rules
@@ -2,12 +2,14 @@
 package validations
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"goplayground-data-validator/models"
+	"goplayground-data-validator/registry"
 )
 
 // DeploymentValidator handles validation for Deployment payloads
@@ -28,23 +30,23 @@ func NewDeploymentValidator() *DeploymentValidator {
 	}
 }
 
+// init registers the deployment model with the unified registry at compile time
+func init() {
+	registry.Register("deployment", registry.ModelSpec{
+		NewValidator: registry.Constructor[models.DeploymentPayload](NewDeploymentValidator),
+		Name:         "Deployment Webhook",
+		Description:  "Deployment webhook payload validation with semantic versioning and business rules",
+		Tags:         []string{"deployment", "webhook", "devops", "ci/cd"},
+	})
+}
+
 // ValidatePayload validates a Deployment payload and returns structured results
-func (dv *DeploymentValidator) ValidatePayload(payload interface{}) models.ValidationResult {
-	deploymentPayload, ok := payload.(models.DeploymentPayload)
-	if !ok {
-		return models.ValidationResult{
-			IsValid:   false,
-			ModelType: "deployment",
-			Provider:  "go-playground",
-			Errors: []models.ValidationError{{
-				Field:   "payload",
-				Message: "payload is not a Deployment payload",
-				Code:    "TYPE_MISMATCH",
-				Value:   fmt.Sprintf("%T", payload),
-			}},
-		}
-	}
+func (dv *DeploymentValidator) ValidatePayload(payload models.DeploymentPayload) models.ValidationResult {
+	return dv.ValidatePayloadContext(context.Background(), payload)
+}
 
+// ValidatePayloadContext validates a Deployment payload, tracing each check as a span of ctx
+func (dv *DeploymentValidator) ValidatePayloadContext(ctx context.Context, payload models.DeploymentPayload) models.ValidationResult {
 	// Perform struct validation
 	result := models.ValidationResult{
 		IsValid:   true,
@@ -54,7 +56,7 @@ func (dv *DeploymentValidator) ValidatePayload(payload interface{}) models.Valid
 		Warnings:  []models.ValidationWarning{},
 	}
 
-	if err := dv.validator.Struct(deploymentPayload); err != nil {
+	if err := traceStruct(ctx, dv.validator, payload); err != nil {
 		result.IsValid = false
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
 			for _, ve := range validationErrors {
@@ -70,7 +72,7 @@ func (dv *DeploymentValidator) ValidatePayload(payload interface{}) models.Valid
 
 	// Add business logic validation warnings
 	if result.IsValid {
-		result.Warnings = dv.validateBusinessLogic(deploymentPayload)
+		result.Warnings = traceWarnings(ctx, "deployment_business_rules", dv.validateBusinessLogic, payload)
 	}
 
 	return result
@@ -3,6 +3,7 @@
 package validations
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"goplayground-data-validator/models"
+	"goplayground-data-validator/registry"
 )
 
 // GenericValidator provides generic validation functionality.
@@ -28,8 +30,29 @@ func NewGenericValidator() *GenericValidator {
 	return &GenericValidator{validator: v}
 }
 
+// init registers the generic models with the unified registry at compile time
+func init() {
+	registry.Register("generic", registry.ModelSpec{
+		NewValidator: registry.Constructor[models.GenericPayload](NewGenericValidator),
+		Name:         "Generic Payload",
+		Description:  "Generic payload validation with flexible business rules",
+		Tags:         []string{"generic", "flexible", "json", "general"},
+	})
+	registry.Register("api.model", registry.ModelSpec{
+		NewValidator: registry.Method(NewGenericValidator, (*GenericValidator).ValidateAPIModel),
+		Name:         "API Call",
+		Description:  "Generic API call validation covering method, URL, status code and timing",
+		Tags:         []string{"api", "http", "generic"},
+	})
+}
+
 // ValidatePayload validates a generic payload with comprehensive rules.
 func (gv *GenericValidator) ValidatePayload(payload models.GenericPayload) models.ValidationResult {
+	return gv.ValidatePayloadContext(context.Background(), payload)
+}
+
+// ValidatePayloadContext validates a generic payload, tracing each check as a span of ctx
+func (gv *GenericValidator) ValidatePayloadContext(ctx context.Context, payload models.GenericPayload) models.ValidationResult {
 	start := time.Now()
 
 	result := models.ValidationResult{
@@ -42,7 +65,7 @@ func (gv *GenericValidator) ValidatePayload(payload models.GenericPayload) model
 	}
 
 	// Perform struct validation
-	if err := gv.validator.Struct(payload); err != nil {
+	if err := traceStruct(ctx, gv.validator, payload); err != nil {
 		result.IsValid = false
 
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
@@ -63,7 +86,7 @@ func (gv *GenericValidator) ValidatePayload(payload models.GenericPayload) model
 
 	// Perform business logic validation
 	if result.IsValid {
-		warnings := ValidateGenericBusinessLogic(payload)
+		warnings := validateGenericBusinessLogic(ctx, payload)
 		result.Warnings = append(result.Warnings, warnings...)
 	}
 
@@ -152,22 +175,27 @@ func validatePriorityLevel(fl validator.FieldLevel) bool {
 
 // ValidateGenericBusinessLogic performs generic payload business logic validation.
 func ValidateGenericBusinessLogic(payload models.GenericPayload) []models.ValidationWarning {
+	return validateGenericBusinessLogic(context.Background(), payload)
+}
+
+// validateGenericBusinessLogic runs each generic payload rule in its own span
+func validateGenericBusinessLogic(ctx context.Context, payload models.GenericPayload) []models.ValidationWarning {
 	var warnings []models.ValidationWarning
 
 	// Check timestamp consistency
-	warnings = append(warnings, checkTimestampConsistency(payload)...)
+	warnings = append(warnings, traceWarnings(ctx, "timestamp_consistency", checkTimestampConsistency, payload)...)
 
 	// Check data integrity
-	warnings = append(warnings, checkDataIntegrity(payload)...)
+	warnings = append(warnings, traceWarnings(ctx, "data_integrity", checkDataIntegrity, payload)...)
 
 	// Check metadata patterns
-	warnings = append(warnings, checkMetadataPatterns(payload)...)
+	warnings = append(warnings, traceWarnings(ctx, "metadata_patterns", checkMetadataPatterns, payload)...)
 
 	// Check priority and status consistency
-	warnings = append(warnings, checkPriorityStatusConsistency(payload)...)
+	warnings = append(warnings, traceWarnings(ctx, "priority_status_consistency", checkPriorityStatusConsistency, payload)...)
 
 	// Check tag patterns
-	warnings = append(warnings, checkTagPatterns(payload)...)
+	warnings = append(warnings, traceWarnings(ctx, "tag_patterns", checkTagPatterns, payload)...)
 
 	return warnings
 }
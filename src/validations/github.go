@@ -3,6 +3,7 @@
 package validations
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"goplayground-data-validator/models"
+	"goplayground-data-validator/registry"
 )
 
 // GitHubValidator provides GitHub-specific validation functionality.
@@ -28,8 +30,23 @@ func NewGitHubValidator() *GitHubValidator {
 	return &GitHubValidator{validator: v}
 }
 
+// init registers the github model with the unified registry at compile time
+func init() {
+	registry.Register("github", registry.ModelSpec{
+		NewValidator: registry.Constructor[models.GitHubPayload](NewGitHubValidator),
+		Name:         "GitHub Webhook",
+		Description:  "GitHub webhook payload validation with comprehensive business rules",
+		Tags:         []string{"github", "webhook", "git", "collaboration"},
+	})
+}
+
 // ValidatePayload validates a GitHub webhook payload with comprehensive rules.
 func (gv *GitHubValidator) ValidatePayload(payload models.GitHubPayload) models.ValidationResult {
+	return gv.ValidatePayloadContext(context.Background(), payload)
+}
+
+// ValidatePayloadContext validates a GitHub payload, tracing each check as a span of ctx
+func (gv *GitHubValidator) ValidatePayloadContext(ctx context.Context, payload models.GitHubPayload) models.ValidationResult {
 	start := time.Now()
 
 	result := models.ValidationResult{
@@ -42,7 +59,7 @@ func (gv *GitHubValidator) ValidatePayload(payload models.GitHubPayload) models.
 	}
 
 	// Perform struct validation
-	if err := gv.validator.Struct(payload); err != nil {
+	if err := traceStruct(ctx, gv.validator, payload); err != nil {
 		result.IsValid = false
 
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
@@ -63,7 +80,7 @@ func (gv *GitHubValidator) ValidatePayload(payload models.GitHubPayload) models.
 
 	// Perform business logic validation
 	if result.IsValid {
-		warnings := ValidateGitHubBusinessLogic(payload)
+		warnings := validateGitHubBusinessLogic(ctx, payload)
 		result.Warnings = append(result.Warnings, warnings...)
 	}
 
@@ -117,25 +134,30 @@ func validateHexColor(fl validator.FieldLevel) bool {
 
 // ValidateGitHubBusinessLogic performs GitHub-specific business logic validation.
 func ValidateGitHubBusinessLogic(payload models.GitHubPayload) []models.ValidationWarning {
+	return validateGitHubBusinessLogic(context.Background(), payload)
+}
+
+// validateGitHubBusinessLogic runs each GitHub rule in its own span
+func validateGitHubBusinessLogic(ctx context.Context, payload models.GitHubPayload) []models.ValidationWarning {
 	var warnings []models.ValidationWarning
 
 	// Check for WIP (Work in Progress) indicators
-	warnings = append(warnings, checkWIPIndicators(payload)...)
+	warnings = append(warnings, traceWarnings(ctx, "wip_indicators", checkWIPIndicators, payload)...)
 
 	// Check for large changesets
-	warnings = append(warnings, checkLargeChangeset(payload)...)
+	warnings = append(warnings, traceWarnings(ctx, "large_changeset", checkLargeChangeset, payload)...)
 
 	// Check for missing description
-	warnings = append(warnings, checkMissingDescription(payload)...)
+	warnings = append(warnings, traceWarnings(ctx, "missing_description", checkMissingDescription, payload)...)
 
 	// Check for security-related concerns
-	warnings = append(warnings, checkSecurityConcerns(payload)...)
+	warnings = append(warnings, traceWarnings(ctx, "security_concerns", checkSecurityConcerns, payload)...)
 
 	// Check for repository health indicators
-	warnings = append(warnings, checkRepositoryHealth(payload)...)
+	warnings = append(warnings, traceWarnings(ctx, "repository_health", checkRepositoryHealth, payload)...)
 
 	// Check for collaboration patterns
-	warnings = append(warnings, checkCollaborationPatterns(payload)...)
+	warnings = append(warnings, traceWarnings(ctx, "collaboration_patterns", checkCollaborationPatterns, payload)...)
 
 	return warnings
 }
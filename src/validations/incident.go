@@ -2,6 +2,7 @@
 package validations
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"goplayground-data-validator/models"
+	"goplayground-data-validator/registry"
 )
 
 // IncidentValidator handles validation for Incident payloads with 2 custom validations
@@ -22,8 +24,23 @@ func NewIncidentValidator() *IncidentValidator {
 	return &IncidentValidator{validator: v}
 }
 
+// init registers the incident model with the unified registry at compile time
+func init() {
+	registry.Register("incident", registry.ModelSpec{
+		NewValidator: registry.Constructor[models.IncidentPayload](NewIncidentValidator),
+		Name:         "Incident Report",
+		Description:  "Incident report validation with operational context and business rules",
+		Tags:         []string{"incident", "monitoring", "alert", "operations"},
+	})
+}
+
 // ValidatePayload validates an Incident payload and returns structured results
 func (iv *IncidentValidator) ValidatePayload(payload models.IncidentPayload) models.ValidationResult {
+	return iv.ValidatePayloadContext(context.Background(), payload)
+}
+
+// ValidatePayloadContext validates an Incident payload, tracing each check as a span of ctx
+func (iv *IncidentValidator) ValidatePayloadContext(ctx context.Context, payload models.IncidentPayload) models.ValidationResult {
 	// Perform struct validation
 	result := models.ValidationResult{
 		IsValid:   true,
@@ -33,7 +50,7 @@ func (iv *IncidentValidator) ValidatePayload(payload models.IncidentPayload) mod
 		Warnings:  []models.ValidationWarning{},
 	}
 
-	if err := iv.validator.Struct(payload); err != nil {
+	if err := traceStruct(ctx, iv.validator, payload); err != nil {
 		result.IsValid = false
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
 			for _, ve := range validationErrors {
@@ -50,7 +67,9 @@ func (iv *IncidentValidator) ValidatePayload(payload models.IncidentPayload) mod
 	// Apply 2 custom validations only if basic validation passed
 	if result.IsValid {
 		// Custom Validation 1: ID format validation
-		if err := iv.validateIncidentIDFormat(payload.ID); err != nil {
+		if err := traceCheck(ctx, "incident_id_format", func() error {
+			return iv.validateIncidentIDFormat(payload.ID)
+		}); err != nil {
 			result.IsValid = false
 			result.Errors = append(result.Errors, models.ValidationError{
 				Field:   "id",
@@ -61,7 +80,9 @@ func (iv *IncidentValidator) ValidatePayload(payload models.IncidentPayload) mod
 		}
 
 		// Custom Validation 2: Priority vs Severity consistency
-		if err := iv.validatePrioritySeverityConsistency(payload.Priority, payload.Severity); err != nil {
+		if err := traceCheck(ctx, "priority_severity_consistency", func() error {
+			return iv.validatePrioritySeverityConsistency(payload.Priority, payload.Severity)
+		}); err != nil {
 			result.IsValid = false
 			result.Errors = append(result.Errors, models.ValidationError{
 				Field:   "priority",
@@ -73,7 +94,7 @@ func (iv *IncidentValidator) ValidatePayload(payload models.IncidentPayload) mod
 	}
 
 	// Add business logic warnings even if validation failed
-	result.Warnings = iv.validateBusinessLogic(payload)
+	result.Warnings = traceWarnings(ctx, "incident_business_rules", iv.validateBusinessLogic, payload)
 
 	return result
 }
@@ -1,10 +1,15 @@
 package validations
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
 	"goplayground-data-validator/models"
 )
 
@@ -393,3 +398,33 @@ func containsSubstring(str, substr string) bool {
 	}
 	return false
 }
+
+func TestIncidentValidator_ValidatePayloadContext_Spans(t *testing.T) {
+	// Validators start spans from the global tracer provider
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	result := NewIncidentValidator().ValidatePayloadContext(ctx, getValidIncidentPayload())
+	parent.End()
+	if !result.IsValid {
+		t.Fatalf("Expected valid incident, got errors %v", result.Errors)
+	}
+
+	names := make(map[string]bool)
+	for _, span := range exporter.GetSpans() {
+		names[span.Name] = true
+		if span.Name != "request" && span.Parent.SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("Span %s is not a child of the request span", span.Name)
+		}
+	}
+	for _, expected := range []string{"validate.struct", "check.incident_id_format", "check.priority_severity_consistency", "check.incident_business_rules"} {
+		if !names[expected] {
+			t.Errorf("Expected span %s, got %v", expected, names)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package validations
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/attribute"
+
+	"goplayground-data-validator/models"
+	"goplayground-data-validator/tracing"
+)
+
+// traceStruct runs go-playground struct validation in a "validate.struct" span
+func traceStruct(ctx context.Context, v *validator.Validate, payload interface{}) error {
+	_, span := tracing.Start(ctx, "validate.struct")
+	defer span.End()
+
+	err := v.Struct(payload)
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		span.SetAttributes(attribute.Int("validator.errors", len(validationErrors)))
+	}
+	return err
+}
+
+// traceCheck runs a business rule that rejects the payload in a "check.<name>" span
+func traceCheck(ctx context.Context, name string, check func() error) error {
+	_, span := tracing.Start(ctx, "check."+name)
+	defer span.End()
+
+	err := check()
+	span.SetAttributes(attribute.Bool("validator.passed", err == nil))
+	return err
+}
+
+// traceWarnings runs a business rule that only warns in a "check.<name>" span
+func traceWarnings[T any](ctx context.Context, name string, check func(T) []models.ValidationWarning, payload T) []models.ValidationWarning {
+	_, span := tracing.Start(ctx, "check."+name)
+	defer span.End()
+
+	warnings := check(payload)
+	span.SetAttributes(attribute.Int("validator.warnings", len(warnings)))
+	return warnings
+}
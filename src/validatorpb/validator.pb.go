@@ -0,0 +1,1840 @@
+// gRPC interface to the validation server. It validates the same models, with
+// the same registry and batch sessions, as the HTTP API; records are passed as
+// google.protobuf.Struct in the shape of their JSON payloads.
+//
+// Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: validator.proto
+
+package validatorpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ValidationOptions mirrors the options object of POST /validate
+type ValidationOptions struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	StopOnFirstError bool                   `protobuf:"varint,1,opt,name=stop_on_first_error,json=stopOnFirstError,proto3" json:"stop_on_first_error,omitempty"`
+	MaxConcurrency   int32                  `protobuf:"varint,2,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"` // 1 to 100; 0 uses the server default
+	TimeoutMs        int64                  `protobuf:"varint,3,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`                // 0 means no timeout
+	FailFast         bool                   `protobuf:"varint,4,opt,name=fail_fast,json=failFast,proto3" json:"fail_fast,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ValidationOptions) Reset() {
+	*x = ValidationOptions{}
+	mi := &file_validator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationOptions) ProtoMessage() {}
+
+func (x *ValidationOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationOptions.ProtoReflect.Descriptor instead.
+func (*ValidationOptions) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ValidationOptions) GetStopOnFirstError() bool {
+	if x != nil {
+		return x.StopOnFirstError
+	}
+	return false
+}
+
+func (x *ValidationOptions) GetMaxConcurrency() int32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+func (x *ValidationOptions) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *ValidationOptions) GetFailFast() bool {
+	if x != nil {
+		return x.FailFast
+	}
+	return false
+}
+
+type ValidateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ModelType     string                 `protobuf:"bytes,1,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"` // Such as "incident" or "api.response"
+	Record        *structpb.Struct       `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateRequest) Reset() {
+	*x = ValidateRequest{}
+	mi := &file_validator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateRequest) ProtoMessage() {}
+
+func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateRequest.ProtoReflect.Descriptor instead.
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ValidateRequest) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetRecord() *structpb.Struct {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type ValidateArrayRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ModelType     string                 `protobuf:"bytes,1,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"`
+	Records       []*structpb.Struct     `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
+	Threshold     *float64               `protobuf:"fixed64,3,opt,name=threshold,proto3,oneof" json:"threshold,omitempty"` // Success rate in percent; unset uses the configured default
+	Options       *ValidationOptions     `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateArrayRequest) Reset() {
+	*x = ValidateArrayRequest{}
+	mi := &file_validator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateArrayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateArrayRequest) ProtoMessage() {}
+
+func (x *ValidateArrayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateArrayRequest.ProtoReflect.Descriptor instead.
+func (*ValidateArrayRequest) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidateArrayRequest) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+func (x *ValidateArrayRequest) GetRecords() []*structpb.Struct {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ValidateArrayRequest) GetThreshold() float64 {
+	if x != nil && x.Threshold != nil {
+		return *x.Threshold
+	}
+	return 0
+}
+
+func (x *ValidateArrayRequest) GetOptions() *ValidationOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type ValidateStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ModelType     string                 `protobuf:"bytes,1,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"` // Read from the first message only
+	Threshold     *float64               `protobuf:"fixed64,2,opt,name=threshold,proto3,oneof" json:"threshold,omitempty"`          // Read from the first message only
+	Options       *ValidationOptions     `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`                      // Read from the first message only
+	Record        *structpb.Struct       `protobuf:"bytes,4,opt,name=record,proto3" json:"record,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateStreamRequest) Reset() {
+	*x = ValidateStreamRequest{}
+	mi := &file_validator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateStreamRequest) ProtoMessage() {}
+
+func (x *ValidateStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateStreamRequest.ProtoReflect.Descriptor instead.
+func (*ValidateStreamRequest) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidateStreamRequest) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+func (x *ValidateStreamRequest) GetThreshold() float64 {
+	if x != nil && x.Threshold != nil {
+		return *x.Threshold
+	}
+	return 0
+}
+
+func (x *ValidateStreamRequest) GetOptions() *ValidationOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ValidateStreamRequest) GetRecord() *structpb.Struct {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type FieldIssue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Code          string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	Value         *structpb.Value        `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	Path          string                 `protobuf:"bytes,5,opt,name=path,proto3" json:"path,omitempty"`
+	Severity      string                 `protobuf:"bytes,6,opt,name=severity,proto3" json:"severity,omitempty"`     // Errors only
+	Suggestion    string                 `protobuf:"bytes,7,opt,name=suggestion,proto3" json:"suggestion,omitempty"` // Warnings only
+	Category      string                 `protobuf:"bytes,8,opt,name=category,proto3" json:"category,omitempty"`     // Warnings only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldIssue) Reset() {
+	*x = FieldIssue{}
+	mi := &file_validator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldIssue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldIssue) ProtoMessage() {}
+
+func (x *FieldIssue) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldIssue.ProtoReflect.Descriptor instead.
+func (*FieldIssue) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FieldIssue) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldIssue) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *FieldIssue) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *FieldIssue) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *FieldIssue) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FieldIssue) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *FieldIssue) GetSuggestion() string {
+	if x != nil {
+		return x.Suggestion
+	}
+	return ""
+}
+
+func (x *FieldIssue) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+type ValidationResult struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	IsValid              bool                   `protobuf:"varint,1,opt,name=is_valid,json=isValid,proto3" json:"is_valid,omitempty"`
+	ModelType            string                 `protobuf:"bytes,2,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"`
+	Provider             string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Errors               []*FieldIssue          `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+	Warnings             []*FieldIssue          `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	Timestamp            *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ProcessingDurationNs int64                  `protobuf:"varint,7,opt,name=processing_duration_ns,json=processingDurationNs,proto3" json:"processing_duration_ns,omitempty"`
+	RequestId            string                 `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	TraceId              string                 `protobuf:"bytes,9,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ValidationResult) Reset() {
+	*x = ValidationResult{}
+	mi := &file_validator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationResult) ProtoMessage() {}
+
+func (x *ValidationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationResult.ProtoReflect.Descriptor instead.
+func (*ValidationResult) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ValidationResult) GetIsValid() bool {
+	if x != nil {
+		return x.IsValid
+	}
+	return false
+}
+
+func (x *ValidationResult) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetErrors() []*FieldIssue {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ValidationResult) GetWarnings() []*FieldIssue {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *ValidationResult) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ValidationResult) GetProcessingDurationNs() int64 {
+	if x != nil {
+		return x.ProcessingDurationNs
+	}
+	return 0
+}
+
+func (x *ValidationResult) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+type RowValidationResult struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RowIndex         int32                  `protobuf:"varint,1,opt,name=row_index,json=rowIndex,proto3" json:"row_index,omitempty"`
+	RecordIdentifier string                 `protobuf:"bytes,2,opt,name=record_identifier,json=recordIdentifier,proto3" json:"record_identifier,omitempty"`
+	IsValid          bool                   `protobuf:"varint,3,opt,name=is_valid,json=isValid,proto3" json:"is_valid,omitempty"`
+	ValidationTimeMs int64                  `protobuf:"varint,4,opt,name=validation_time_ms,json=validationTimeMs,proto3" json:"validation_time_ms,omitempty"`
+	TestName         string                 `protobuf:"bytes,5,opt,name=test_name,json=testName,proto3" json:"test_name,omitempty"`
+	Errors           []*FieldIssue          `protobuf:"bytes,6,rep,name=errors,proto3" json:"errors,omitempty"`
+	Warnings         []*FieldIssue          `protobuf:"bytes,7,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RowValidationResult) Reset() {
+	*x = RowValidationResult{}
+	mi := &file_validator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RowValidationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RowValidationResult) ProtoMessage() {}
+
+func (x *RowValidationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RowValidationResult.ProtoReflect.Descriptor instead.
+func (*RowValidationResult) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RowValidationResult) GetRowIndex() int32 {
+	if x != nil {
+		return x.RowIndex
+	}
+	return 0
+}
+
+func (x *RowValidationResult) GetRecordIdentifier() string {
+	if x != nil {
+		return x.RecordIdentifier
+	}
+	return ""
+}
+
+func (x *RowValidationResult) GetIsValid() bool {
+	if x != nil {
+		return x.IsValid
+	}
+	return false
+}
+
+func (x *RowValidationResult) GetValidationTimeMs() int64 {
+	if x != nil {
+		return x.ValidationTimeMs
+	}
+	return 0
+}
+
+func (x *RowValidationResult) GetTestName() string {
+	if x != nil {
+		return x.TestName
+	}
+	return ""
+}
+
+func (x *RowValidationResult) GetErrors() []*FieldIssue {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *RowValidationResult) GetWarnings() []*FieldIssue {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type ValidationSummary struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	SuccessRate           float64                `protobuf:"fixed64,1,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`
+	ValidationErrors      int32                  `protobuf:"varint,2,opt,name=validation_errors,json=validationErrors,proto3" json:"validation_errors,omitempty"`
+	ValidationWarnings    int32                  `protobuf:"varint,3,opt,name=validation_warnings,json=validationWarnings,proto3" json:"validation_warnings,omitempty"`
+	TotalRecordsProcessed int32                  `protobuf:"varint,4,opt,name=total_records_processed,json=totalRecordsProcessed,proto3" json:"total_records_processed,omitempty"`
+	TotalTestsRan         int32                  `protobuf:"varint,5,opt,name=total_tests_ran,json=totalTestsRan,proto3" json:"total_tests_ran,omitempty"`
+	SuccessfulTestNames   []string               `protobuf:"bytes,6,rep,name=successful_test_names,json=successfulTestNames,proto3" json:"successful_test_names,omitempty"`
+	FailedTestNames       []string               `protobuf:"bytes,7,rep,name=failed_test_names,json=failedTestNames,proto3" json:"failed_test_names,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ValidationSummary) Reset() {
+	*x = ValidationSummary{}
+	mi := &file_validator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationSummary) ProtoMessage() {}
+
+func (x *ValidationSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationSummary.ProtoReflect.Descriptor instead.
+func (*ValidationSummary) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ValidationSummary) GetSuccessRate() float64 {
+	if x != nil {
+		return x.SuccessRate
+	}
+	return 0
+}
+
+func (x *ValidationSummary) GetValidationErrors() int32 {
+	if x != nil {
+		return x.ValidationErrors
+	}
+	return 0
+}
+
+func (x *ValidationSummary) GetValidationWarnings() int32 {
+	if x != nil {
+		return x.ValidationWarnings
+	}
+	return 0
+}
+
+func (x *ValidationSummary) GetTotalRecordsProcessed() int32 {
+	if x != nil {
+		return x.TotalRecordsProcessed
+	}
+	return 0
+}
+
+func (x *ValidationSummary) GetTotalTestsRan() int32 {
+	if x != nil {
+		return x.TotalTestsRan
+	}
+	return 0
+}
+
+func (x *ValidationSummary) GetSuccessfulTestNames() []string {
+	if x != nil {
+		return x.SuccessfulTestNames
+	}
+	return nil
+}
+
+func (x *ValidationSummary) GetFailedTestNames() []string {
+	if x != nil {
+		return x.FailedTestNames
+	}
+	return nil
+}
+
+type ArrayValidationResult struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	BatchId          string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	RequestId        string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	TraceId          string                 `protobuf:"bytes,3,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	Status           string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // "success" or "failed"
+	TotalRecords     int32                  `protobuf:"varint,5,opt,name=total_records,json=totalRecords,proto3" json:"total_records,omitempty"`
+	ValidRecords     int32                  `protobuf:"varint,6,opt,name=valid_records,json=validRecords,proto3" json:"valid_records,omitempty"`
+	InvalidRecords   int32                  `protobuf:"varint,7,opt,name=invalid_records,json=invalidRecords,proto3" json:"invalid_records,omitempty"`
+	WarningRecords   int32                  `protobuf:"varint,8,opt,name=warning_records,json=warningRecords,proto3" json:"warning_records,omitempty"`
+	SkippedRecords   int32                  `protobuf:"varint,9,opt,name=skipped_records,json=skippedRecords,proto3" json:"skipped_records,omitempty"`
+	Threshold        *float64               `protobuf:"fixed64,10,opt,name=threshold,proto3,oneof" json:"threshold,omitempty"`
+	ProcessingTimeMs int64                  `protobuf:"varint,11,opt,name=processing_time_ms,json=processingTimeMs,proto3" json:"processing_time_ms,omitempty"`
+	CompletedAt      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Summary          *ValidationSummary     `protobuf:"bytes,13,opt,name=summary,proto3" json:"summary,omitempty"`
+	Results          []*RowValidationResult `protobuf:"bytes,14,rep,name=results,proto3" json:"results,omitempty"` // Invalid rows and rows with warnings
+	Error            string                 `protobuf:"bytes,15,opt,name=error,proto3" json:"error,omitempty"`     // Why a stream stopped early, if it did
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ArrayValidationResult) Reset() {
+	*x = ArrayValidationResult{}
+	mi := &file_validator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArrayValidationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArrayValidationResult) ProtoMessage() {}
+
+func (x *ArrayValidationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArrayValidationResult.ProtoReflect.Descriptor instead.
+func (*ArrayValidationResult) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ArrayValidationResult) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *ArrayValidationResult) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ArrayValidationResult) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *ArrayValidationResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ArrayValidationResult) GetTotalRecords() int32 {
+	if x != nil {
+		return x.TotalRecords
+	}
+	return 0
+}
+
+func (x *ArrayValidationResult) GetValidRecords() int32 {
+	if x != nil {
+		return x.ValidRecords
+	}
+	return 0
+}
+
+func (x *ArrayValidationResult) GetInvalidRecords() int32 {
+	if x != nil {
+		return x.InvalidRecords
+	}
+	return 0
+}
+
+func (x *ArrayValidationResult) GetWarningRecords() int32 {
+	if x != nil {
+		return x.WarningRecords
+	}
+	return 0
+}
+
+func (x *ArrayValidationResult) GetSkippedRecords() int32 {
+	if x != nil {
+		return x.SkippedRecords
+	}
+	return 0
+}
+
+func (x *ArrayValidationResult) GetThreshold() float64 {
+	if x != nil && x.Threshold != nil {
+		return *x.Threshold
+	}
+	return 0
+}
+
+func (x *ArrayValidationResult) GetProcessingTimeMs() int64 {
+	if x != nil {
+		return x.ProcessingTimeMs
+	}
+	return 0
+}
+
+func (x *ArrayValidationResult) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *ArrayValidationResult) GetSummary() *ValidationSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *ArrayValidationResult) GetResults() []*RowValidationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ArrayValidationResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type StartBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ModelType     string                 `protobuf:"bytes,1,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"`
+	SchemaVersion string                 `protobuf:"bytes,2,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	JobId         string                 `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Prefix for the batch ID
+	Threshold     *float64               `protobuf:"fixed64,4,opt,name=threshold,proto3,oneof" json:"threshold,omitempty"`
+	TtlSeconds    int64                  `protobuf:"varint,5,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // Idle expiry; 0 uses the server default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartBatchRequest) Reset() {
+	*x = StartBatchRequest{}
+	mi := &file_validator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartBatchRequest) ProtoMessage() {}
+
+func (x *StartBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartBatchRequest.ProtoReflect.Descriptor instead.
+func (*StartBatchRequest) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StartBatchRequest) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+func (x *StartBatchRequest) GetSchemaVersion() string {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return ""
+}
+
+func (x *StartBatchRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *StartBatchRequest) GetThreshold() float64 {
+	if x != nil && x.Threshold != nil {
+		return *x.Threshold
+	}
+	return 0
+}
+
+func (x *StartBatchRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type BatchChunkReport struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Received              int32                  `protobuf:"varint,1,opt,name=received,proto3" json:"received,omitempty"`
+	MissingSequences      []int32                `protobuf:"varint,2,rep,packed,name=missing_sequences,json=missingSequences,proto3" json:"missing_sequences,omitempty"`
+	OutOfOrderSequences   []int32                `protobuf:"varint,3,rep,packed,name=out_of_order_sequences,json=outOfOrderSequences,proto3" json:"out_of_order_sequences,omitempty"`
+	NonSequentialChunkIds int32                  `protobuf:"varint,4,opt,name=non_sequential_chunk_ids,json=nonSequentialChunkIds,proto3" json:"non_sequential_chunk_ids,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *BatchChunkReport) Reset() {
+	*x = BatchChunkReport{}
+	mi := &file_validator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchChunkReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchChunkReport) ProtoMessage() {}
+
+func (x *BatchChunkReport) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchChunkReport.ProtoReflect.Descriptor instead.
+func (*BatchChunkReport) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchChunkReport) GetReceived() int32 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *BatchChunkReport) GetMissingSequences() []int32 {
+	if x != nil {
+		return x.MissingSequences
+	}
+	return nil
+}
+
+func (x *BatchChunkReport) GetOutOfOrderSequences() []int32 {
+	if x != nil {
+		return x.OutOfOrderSequences
+	}
+	return nil
+}
+
+func (x *BatchChunkReport) GetNonSequentialChunkIds() int32 {
+	if x != nil {
+		return x.NonSequentialChunkIds
+	}
+	return 0
+}
+
+type BatchSession struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BatchId        string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	ModelType      string                 `protobuf:"bytes,2,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"`
+	SchemaVersion  string                 `protobuf:"bytes,3,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Status         string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // "in_progress", "success", "failed" or "expired"
+	TotalRecords   int32                  `protobuf:"varint,5,opt,name=total_records,json=totalRecords,proto3" json:"total_records,omitempty"`
+	ValidRecords   int32                  `protobuf:"varint,6,opt,name=valid_records,json=validRecords,proto3" json:"valid_records,omitempty"`
+	InvalidRecords int32                  `protobuf:"varint,7,opt,name=invalid_records,json=invalidRecords,proto3" json:"invalid_records,omitempty"`
+	WarningRecords int32                  `protobuf:"varint,8,opt,name=warning_records,json=warningRecords,proto3" json:"warning_records,omitempty"`
+	SuccessRate    float64                `protobuf:"fixed64,9,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`
+	Threshold      *float64               `protobuf:"fixed64,10,opt,name=threshold,proto3,oneof" json:"threshold,omitempty"`
+	StartedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	LastUpdated    *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	IsFinal        bool                   `protobuf:"varint,14,opt,name=is_final,json=isFinal,proto3" json:"is_final,omitempty"`
+	Chunks         *BatchChunkReport      `protobuf:"bytes,15,opt,name=chunks,proto3" json:"chunks,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BatchSession) Reset() {
+	*x = BatchSession{}
+	mi := &file_validator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSession) ProtoMessage() {}
+
+func (x *BatchSession) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSession.ProtoReflect.Descriptor instead.
+func (*BatchSession) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchSession) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *BatchSession) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+func (x *BatchSession) GetSchemaVersion() string {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return ""
+}
+
+func (x *BatchSession) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BatchSession) GetTotalRecords() int32 {
+	if x != nil {
+		return x.TotalRecords
+	}
+	return 0
+}
+
+func (x *BatchSession) GetValidRecords() int32 {
+	if x != nil {
+		return x.ValidRecords
+	}
+	return 0
+}
+
+func (x *BatchSession) GetInvalidRecords() int32 {
+	if x != nil {
+		return x.InvalidRecords
+	}
+	return 0
+}
+
+func (x *BatchSession) GetWarningRecords() int32 {
+	if x != nil {
+		return x.WarningRecords
+	}
+	return 0
+}
+
+func (x *BatchSession) GetSuccessRate() float64 {
+	if x != nil {
+		return x.SuccessRate
+	}
+	return 0
+}
+
+func (x *BatchSession) GetThreshold() float64 {
+	if x != nil && x.Threshold != nil {
+		return *x.Threshold
+	}
+	return 0
+}
+
+func (x *BatchSession) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *BatchSession) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+func (x *BatchSession) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *BatchSession) GetIsFinal() bool {
+	if x != nil {
+		return x.IsFinal
+	}
+	return false
+}
+
+func (x *BatchSession) GetChunks() *BatchChunkReport {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+type AddBatchRecordsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchId       string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	ChunkId       string                 `protobuf:"bytes,2,opt,name=chunk_id,json=chunkId,proto3" json:"chunk_id,omitempty"` // Optional; a number is also checked for gaps and order
+	SchemaVersion string                 `protobuf:"bytes,3,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Records       []*structpb.Struct     `protobuf:"bytes,4,rep,name=records,proto3" json:"records,omitempty"`
+	Options       *ValidationOptions     `protobuf:"bytes,5,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddBatchRecordsRequest) Reset() {
+	*x = AddBatchRecordsRequest{}
+	mi := &file_validator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddBatchRecordsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddBatchRecordsRequest) ProtoMessage() {}
+
+func (x *AddBatchRecordsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddBatchRecordsRequest.ProtoReflect.Descriptor instead.
+func (*AddBatchRecordsRequest) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AddBatchRecordsRequest) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *AddBatchRecordsRequest) GetChunkId() string {
+	if x != nil {
+		return x.ChunkId
+	}
+	return ""
+}
+
+func (x *AddBatchRecordsRequest) GetSchemaVersion() string {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return ""
+}
+
+func (x *AddBatchRecordsRequest) GetRecords() []*structpb.Struct {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *AddBatchRecordsRequest) GetOptions() *ValidationOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type AddBatchRecordsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BatchId        string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	Records        int32                  `protobuf:"varint,2,opt,name=records,proto3" json:"records,omitempty"` // Records validated in this chunk
+	ValidRecords   int32                  `protobuf:"varint,3,opt,name=valid_records,json=validRecords,proto3" json:"valid_records,omitempty"`
+	InvalidRecords int32                  `protobuf:"varint,4,opt,name=invalid_records,json=invalidRecords,proto3" json:"invalid_records,omitempty"`
+	WarningRecords int32                  `protobuf:"varint,5,opt,name=warning_records,json=warningRecords,proto3" json:"warning_records,omitempty"`
+	SessionTotal   int32                  `protobuf:"varint,6,opt,name=session_total,json=sessionTotal,proto3" json:"session_total,omitempty"` // Records in the session after this chunk
+	Replayed       bool                   `protobuf:"varint,7,opt,name=replayed,proto3" json:"replayed,omitempty"`                             // The chunk_id was seen before and nothing was validated
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AddBatchRecordsResponse) Reset() {
+	*x = AddBatchRecordsResponse{}
+	mi := &file_validator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddBatchRecordsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddBatchRecordsResponse) ProtoMessage() {}
+
+func (x *AddBatchRecordsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddBatchRecordsResponse.ProtoReflect.Descriptor instead.
+func (*AddBatchRecordsResponse) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AddBatchRecordsResponse) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *AddBatchRecordsResponse) GetRecords() int32 {
+	if x != nil {
+		return x.Records
+	}
+	return 0
+}
+
+func (x *AddBatchRecordsResponse) GetValidRecords() int32 {
+	if x != nil {
+		return x.ValidRecords
+	}
+	return 0
+}
+
+func (x *AddBatchRecordsResponse) GetInvalidRecords() int32 {
+	if x != nil {
+		return x.InvalidRecords
+	}
+	return 0
+}
+
+func (x *AddBatchRecordsResponse) GetWarningRecords() int32 {
+	if x != nil {
+		return x.WarningRecords
+	}
+	return 0
+}
+
+func (x *AddBatchRecordsResponse) GetSessionTotal() int32 {
+	if x != nil {
+		return x.SessionTotal
+	}
+	return 0
+}
+
+func (x *AddBatchRecordsResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+type GetBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchId       string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBatchRequest) Reset() {
+	*x = GetBatchRequest{}
+	mi := &file_validator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBatchRequest) ProtoMessage() {}
+
+func (x *GetBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetBatchRequest) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetBatchRequest) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+type CompleteBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchId       string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteBatchRequest) Reset() {
+	*x = CompleteBatchRequest{}
+	mi := &file_validator_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteBatchRequest) ProtoMessage() {}
+
+func (x *CompleteBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteBatchRequest.ProtoReflect.Descriptor instead.
+func (*CompleteBatchRequest) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CompleteBatchRequest) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+type AbortBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchId       string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AbortBatchRequest) Reset() {
+	*x = AbortBatchRequest{}
+	mi := &file_validator_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AbortBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AbortBatchRequest) ProtoMessage() {}
+
+func (x *AbortBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AbortBatchRequest.ProtoReflect.Descriptor instead.
+func (*AbortBatchRequest) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AbortBatchRequest) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+type AbortBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchId       string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AbortBatchResponse) Reset() {
+	*x = AbortBatchResponse{}
+	mi := &file_validator_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AbortBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AbortBatchResponse) ProtoMessage() {}
+
+func (x *AbortBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AbortBatchResponse.ProtoReflect.Descriptor instead.
+func (*AbortBatchResponse) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AbortBatchResponse) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+type ListModelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModelsRequest) Reset() {
+	*x = ListModelsRequest{}
+	mi := &file_validator_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModelsRequest) ProtoMessage() {}
+
+func (x *ListModelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModelsRequest.ProtoReflect.Descriptor instead.
+func (*ListModelsRequest) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{18}
+}
+
+type ListModelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ModelTypes    []string               `protobuf:"bytes,1,rep,name=model_types,json=modelTypes,proto3" json:"model_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModelsResponse) Reset() {
+	*x = ListModelsResponse{}
+	mi := &file_validator_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModelsResponse) ProtoMessage() {}
+
+func (x *ListModelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_validator_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModelsResponse.ProtoReflect.Descriptor instead.
+func (*ListModelsResponse) Descriptor() ([]byte, []int) {
+	return file_validator_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListModelsResponse) GetModelTypes() []string {
+	if x != nil {
+		return x.ModelTypes
+	}
+	return nil
+}
+
+var File_validator_proto protoreflect.FileDescriptor
+
+const file_validator_proto_rawDesc = "" +
+	"\n" +
+	"\x0fvalidator.proto\x12\fvalidator.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa7\x01\n" +
+	"\x11ValidationOptions\x12-\n" +
+	"\x13stop_on_first_error\x18\x01 \x01(\bR\x10stopOnFirstError\x12'\n" +
+	"\x0fmax_concurrency\x18\x02 \x01(\x05R\x0emaxConcurrency\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x03 \x01(\x03R\ttimeoutMs\x12\x1b\n" +
+	"\tfail_fast\x18\x04 \x01(\bR\bfailFast\"a\n" +
+	"\x0fValidateRequest\x12\x1d\n" +
+	"\n" +
+	"model_type\x18\x01 \x01(\tR\tmodelType\x12/\n" +
+	"\x06record\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x06record\"\xd4\x01\n" +
+	"\x14ValidateArrayRequest\x12\x1d\n" +
+	"\n" +
+	"model_type\x18\x01 \x01(\tR\tmodelType\x121\n" +
+	"\arecords\x18\x02 \x03(\v2\x17.google.protobuf.StructR\arecords\x12!\n" +
+	"\tthreshold\x18\x03 \x01(\x01H\x00R\tthreshold\x88\x01\x01\x129\n" +
+	"\aoptions\x18\x04 \x01(\v2\x1f.validator.v1.ValidationOptionsR\aoptionsB\f\n" +
+	"\n" +
+	"_threshold\"\xd3\x01\n" +
+	"\x15ValidateStreamRequest\x12\x1d\n" +
+	"\n" +
+	"model_type\x18\x01 \x01(\tR\tmodelType\x12!\n" +
+	"\tthreshold\x18\x02 \x01(\x01H\x00R\tthreshold\x88\x01\x01\x129\n" +
+	"\aoptions\x18\x03 \x01(\v2\x1f.validator.v1.ValidationOptionsR\aoptions\x12/\n" +
+	"\x06record\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x06recordB\f\n" +
+	"\n" +
+	"_threshold\"\xea\x01\n" +
+	"\n" +
+	"FieldIssue\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12,\n" +
+	"\x05value\x18\x04 \x01(\v2\x16.google.protobuf.ValueR\x05value\x12\x12\n" +
+	"\x04path\x18\x05 \x01(\tR\x04path\x12\x1a\n" +
+	"\bseverity\x18\x06 \x01(\tR\bseverity\x12\x1e\n" +
+	"\n" +
+	"suggestion\x18\a \x01(\tR\n" +
+	"suggestion\x12\x1a\n" +
+	"\bcategory\x18\b \x01(\tR\bcategory\"\xfa\x02\n" +
+	"\x10ValidationResult\x12\x19\n" +
+	"\bis_valid\x18\x01 \x01(\bR\aisValid\x12\x1d\n" +
+	"\n" +
+	"model_type\x18\x02 \x01(\tR\tmodelType\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x120\n" +
+	"\x06errors\x18\x04 \x03(\v2\x18.validator.v1.FieldIssueR\x06errors\x124\n" +
+	"\bwarnings\x18\x05 \x03(\v2\x18.validator.v1.FieldIssueR\bwarnings\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x124\n" +
+	"\x16processing_duration_ns\x18\a \x01(\x03R\x14processingDurationNs\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\b \x01(\tR\trequestId\x12\x19\n" +
+	"\btrace_id\x18\t \x01(\tR\atraceId\"\xad\x02\n" +
+	"\x13RowValidationResult\x12\x1b\n" +
+	"\trow_index\x18\x01 \x01(\x05R\browIndex\x12+\n" +
+	"\x11record_identifier\x18\x02 \x01(\tR\x10recordIdentifier\x12\x19\n" +
+	"\bis_valid\x18\x03 \x01(\bR\aisValid\x12,\n" +
+	"\x12validation_time_ms\x18\x04 \x01(\x03R\x10validationTimeMs\x12\x1b\n" +
+	"\ttest_name\x18\x05 \x01(\tR\btestName\x120\n" +
+	"\x06errors\x18\x06 \x03(\v2\x18.validator.v1.FieldIssueR\x06errors\x124\n" +
+	"\bwarnings\x18\a \x03(\v2\x18.validator.v1.FieldIssueR\bwarnings\"\xd4\x02\n" +
+	"\x11ValidationSummary\x12!\n" +
+	"\fsuccess_rate\x18\x01 \x01(\x01R\vsuccessRate\x12+\n" +
+	"\x11validation_errors\x18\x02 \x01(\x05R\x10validationErrors\x12/\n" +
+	"\x13validation_warnings\x18\x03 \x01(\x05R\x12validationWarnings\x126\n" +
+	"\x17total_records_processed\x18\x04 \x01(\x05R\x15totalRecordsProcessed\x12&\n" +
+	"\x0ftotal_tests_ran\x18\x05 \x01(\x05R\rtotalTestsRan\x122\n" +
+	"\x15successful_test_names\x18\x06 \x03(\tR\x13successfulTestNames\x12*\n" +
+	"\x11failed_test_names\x18\a \x03(\tR\x0ffailedTestNames\"\xf5\x04\n" +
+	"\x15ArrayValidationResult\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\x12\x19\n" +
+	"\btrace_id\x18\x03 \x01(\tR\atraceId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12#\n" +
+	"\rtotal_records\x18\x05 \x01(\x05R\ftotalRecords\x12#\n" +
+	"\rvalid_records\x18\x06 \x01(\x05R\fvalidRecords\x12'\n" +
+	"\x0finvalid_records\x18\a \x01(\x05R\x0einvalidRecords\x12'\n" +
+	"\x0fwarning_records\x18\b \x01(\x05R\x0ewarningRecords\x12'\n" +
+	"\x0fskipped_records\x18\t \x01(\x05R\x0eskippedRecords\x12!\n" +
+	"\tthreshold\x18\n" +
+	" \x01(\x01H\x00R\tthreshold\x88\x01\x01\x12,\n" +
+	"\x12processing_time_ms\x18\v \x01(\x03R\x10processingTimeMs\x12=\n" +
+	"\fcompleted_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x129\n" +
+	"\asummary\x18\r \x01(\v2\x1f.validator.v1.ValidationSummaryR\asummary\x12;\n" +
+	"\aresults\x18\x0e \x03(\v2!.validator.v1.RowValidationResultR\aresults\x12\x14\n" +
+	"\x05error\x18\x0f \x01(\tR\x05errorB\f\n" +
+	"\n" +
+	"_threshold\"\xc2\x01\n" +
+	"\x11StartBatchRequest\x12\x1d\n" +
+	"\n" +
+	"model_type\x18\x01 \x01(\tR\tmodelType\x12%\n" +
+	"\x0eschema_version\x18\x02 \x01(\tR\rschemaVersion\x12\x15\n" +
+	"\x06job_id\x18\x03 \x01(\tR\x05jobId\x12!\n" +
+	"\tthreshold\x18\x04 \x01(\x01H\x00R\tthreshold\x88\x01\x01\x12\x1f\n" +
+	"\vttl_seconds\x18\x05 \x01(\x03R\n" +
+	"ttlSecondsB\f\n" +
+	"\n" +
+	"_threshold\"\xc9\x01\n" +
+	"\x10BatchChunkReport\x12\x1a\n" +
+	"\breceived\x18\x01 \x01(\x05R\breceived\x12+\n" +
+	"\x11missing_sequences\x18\x02 \x03(\x05R\x10missingSequences\x123\n" +
+	"\x16out_of_order_sequences\x18\x03 \x03(\x05R\x13outOfOrderSequences\x127\n" +
+	"\x18non_sequential_chunk_ids\x18\x04 \x01(\x05R\x15nonSequentialChunkIds\"\xff\x04\n" +
+	"\fBatchSession\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\x12\x1d\n" +
+	"\n" +
+	"model_type\x18\x02 \x01(\tR\tmodelType\x12%\n" +
+	"\x0eschema_version\x18\x03 \x01(\tR\rschemaVersion\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12#\n" +
+	"\rtotal_records\x18\x05 \x01(\x05R\ftotalRecords\x12#\n" +
+	"\rvalid_records\x18\x06 \x01(\x05R\fvalidRecords\x12'\n" +
+	"\x0finvalid_records\x18\a \x01(\x05R\x0einvalidRecords\x12'\n" +
+	"\x0fwarning_records\x18\b \x01(\x05R\x0ewarningRecords\x12!\n" +
+	"\fsuccess_rate\x18\t \x01(\x01R\vsuccessRate\x12!\n" +
+	"\tthreshold\x18\n" +
+	" \x01(\x01H\x00R\tthreshold\x88\x01\x01\x129\n" +
+	"\n" +
+	"started_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\flast_updated\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vlastUpdated\x129\n" +
+	"\n" +
+	"expires_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x19\n" +
+	"\bis_final\x18\x0e \x01(\bR\aisFinal\x126\n" +
+	"\x06chunks\x18\x0f \x01(\v2\x1e.validator.v1.BatchChunkReportR\x06chunksB\f\n" +
+	"\n" +
+	"_threshold\"\xe3\x01\n" +
+	"\x16AddBatchRecordsRequest\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\x12\x19\n" +
+	"\bchunk_id\x18\x02 \x01(\tR\achunkId\x12%\n" +
+	"\x0eschema_version\x18\x03 \x01(\tR\rschemaVersion\x121\n" +
+	"\arecords\x18\x04 \x03(\v2\x17.google.protobuf.StructR\arecords\x129\n" +
+	"\aoptions\x18\x05 \x01(\v2\x1f.validator.v1.ValidationOptionsR\aoptions\"\x86\x02\n" +
+	"\x17AddBatchRecordsResponse\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\x12\x18\n" +
+	"\arecords\x18\x02 \x01(\x05R\arecords\x12#\n" +
+	"\rvalid_records\x18\x03 \x01(\x05R\fvalidRecords\x12'\n" +
+	"\x0finvalid_records\x18\x04 \x01(\x05R\x0einvalidRecords\x12'\n" +
+	"\x0fwarning_records\x18\x05 \x01(\x05R\x0ewarningRecords\x12#\n" +
+	"\rsession_total\x18\x06 \x01(\x05R\fsessionTotal\x12\x1a\n" +
+	"\breplayed\x18\a \x01(\bR\breplayed\",\n" +
+	"\x0fGetBatchRequest\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\"1\n" +
+	"\x14CompleteBatchRequest\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\".\n" +
+	"\x11AbortBatchRequest\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\"/\n" +
+	"\x12AbortBatchResponse\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\"\x13\n" +
+	"\x11ListModelsRequest\"5\n" +
+	"\x12ListModelsResponse\x12\x1f\n" +
+	"\vmodel_types\x18\x01 \x03(\tR\n" +
+	"modelTypes2\xfa\x05\n" +
+	"\x10ValidatorService\x12I\n" +
+	"\bValidate\x12\x1d.validator.v1.ValidateRequest\x1a\x1e.validator.v1.ValidationResult\x12X\n" +
+	"\rValidateArray\x12\".validator.v1.ValidateArrayRequest\x1a#.validator.v1.ArrayValidationResult\x12\\\n" +
+	"\x0eValidateStream\x12#.validator.v1.ValidateStreamRequest\x1a#.validator.v1.ArrayValidationResult(\x01\x12I\n" +
+	"\n" +
+	"StartBatch\x12\x1f.validator.v1.StartBatchRequest\x1a\x1a.validator.v1.BatchSession\x12^\n" +
+	"\x0fAddBatchRecords\x12$.validator.v1.AddBatchRecordsRequest\x1a%.validator.v1.AddBatchRecordsResponse\x12E\n" +
+	"\bGetBatch\x12\x1d.validator.v1.GetBatchRequest\x1a\x1a.validator.v1.BatchSession\x12O\n" +
+	"\rCompleteBatch\x12\".validator.v1.CompleteBatchRequest\x1a\x1a.validator.v1.BatchSession\x12O\n" +
+	"\n" +
+	"AbortBatch\x12\x1f.validator.v1.AbortBatchRequest\x1a .validator.v1.AbortBatchResponse\x12O\n" +
+	"\n" +
+	"ListModels\x12\x1f.validator.v1.ListModelsRequest\x1a .validator.v1.ListModelsResponseB)Z'goplayground-data-validator/validatorpbb\x06proto3"
+
+var (
+	file_validator_proto_rawDescOnce sync.Once
+	file_validator_proto_rawDescData []byte
+)
+
+func file_validator_proto_rawDescGZIP() []byte {
+	file_validator_proto_rawDescOnce.Do(func() {
+		file_validator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_validator_proto_rawDesc), len(file_validator_proto_rawDesc)))
+	})
+	return file_validator_proto_rawDescData
+}
+
+var file_validator_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_validator_proto_goTypes = []any{
+	(*ValidationOptions)(nil),       // 0: validator.v1.ValidationOptions
+	(*ValidateRequest)(nil),         // 1: validator.v1.ValidateRequest
+	(*ValidateArrayRequest)(nil),    // 2: validator.v1.ValidateArrayRequest
+	(*ValidateStreamRequest)(nil),   // 3: validator.v1.ValidateStreamRequest
+	(*FieldIssue)(nil),              // 4: validator.v1.FieldIssue
+	(*ValidationResult)(nil),        // 5: validator.v1.ValidationResult
+	(*RowValidationResult)(nil),     // 6: validator.v1.RowValidationResult
+	(*ValidationSummary)(nil),       // 7: validator.v1.ValidationSummary
+	(*ArrayValidationResult)(nil),   // 8: validator.v1.ArrayValidationResult
+	(*StartBatchRequest)(nil),       // 9: validator.v1.StartBatchRequest
+	(*BatchChunkReport)(nil),        // 10: validator.v1.BatchChunkReport
+	(*BatchSession)(nil),            // 11: validator.v1.BatchSession
+	(*AddBatchRecordsRequest)(nil),  // 12: validator.v1.AddBatchRecordsRequest
+	(*AddBatchRecordsResponse)(nil), // 13: validator.v1.AddBatchRecordsResponse
+	(*GetBatchRequest)(nil),         // 14: validator.v1.GetBatchRequest
+	(*CompleteBatchRequest)(nil),    // 15: validator.v1.CompleteBatchRequest
+	(*AbortBatchRequest)(nil),       // 16: validator.v1.AbortBatchRequest
+	(*AbortBatchResponse)(nil),      // 17: validator.v1.AbortBatchResponse
+	(*ListModelsRequest)(nil),       // 18: validator.v1.ListModelsRequest
+	(*ListModelsResponse)(nil),      // 19: validator.v1.ListModelsResponse
+	(*structpb.Struct)(nil),         // 20: google.protobuf.Struct
+	(*structpb.Value)(nil),          // 21: google.protobuf.Value
+	(*timestamppb.Timestamp)(nil),   // 22: google.protobuf.Timestamp
+}
+var file_validator_proto_depIdxs = []int32{
+	20, // 0: validator.v1.ValidateRequest.record:type_name -> google.protobuf.Struct
+	20, // 1: validator.v1.ValidateArrayRequest.records:type_name -> google.protobuf.Struct
+	0,  // 2: validator.v1.ValidateArrayRequest.options:type_name -> validator.v1.ValidationOptions
+	0,  // 3: validator.v1.ValidateStreamRequest.options:type_name -> validator.v1.ValidationOptions
+	20, // 4: validator.v1.ValidateStreamRequest.record:type_name -> google.protobuf.Struct
+	21, // 5: validator.v1.FieldIssue.value:type_name -> google.protobuf.Value
+	4,  // 6: validator.v1.ValidationResult.errors:type_name -> validator.v1.FieldIssue
+	4,  // 7: validator.v1.ValidationResult.warnings:type_name -> validator.v1.FieldIssue
+	22, // 8: validator.v1.ValidationResult.timestamp:type_name -> google.protobuf.Timestamp
+	4,  // 9: validator.v1.RowValidationResult.errors:type_name -> validator.v1.FieldIssue
+	4,  // 10: validator.v1.RowValidationResult.warnings:type_name -> validator.v1.FieldIssue
+	22, // 11: validator.v1.ArrayValidationResult.completed_at:type_name -> google.protobuf.Timestamp
+	7,  // 12: validator.v1.ArrayValidationResult.summary:type_name -> validator.v1.ValidationSummary
+	6,  // 13: validator.v1.ArrayValidationResult.results:type_name -> validator.v1.RowValidationResult
+	22, // 14: validator.v1.BatchSession.started_at:type_name -> google.protobuf.Timestamp
+	22, // 15: validator.v1.BatchSession.last_updated:type_name -> google.protobuf.Timestamp
+	22, // 16: validator.v1.BatchSession.expires_at:type_name -> google.protobuf.Timestamp
+	10, // 17: validator.v1.BatchSession.chunks:type_name -> validator.v1.BatchChunkReport
+	20, // 18: validator.v1.AddBatchRecordsRequest.records:type_name -> google.protobuf.Struct
+	0,  // 19: validator.v1.AddBatchRecordsRequest.options:type_name -> validator.v1.ValidationOptions
+	1,  // 20: validator.v1.ValidatorService.Validate:input_type -> validator.v1.ValidateRequest
+	2,  // 21: validator.v1.ValidatorService.ValidateArray:input_type -> validator.v1.ValidateArrayRequest
+	3,  // 22: validator.v1.ValidatorService.ValidateStream:input_type -> validator.v1.ValidateStreamRequest
+	9,  // 23: validator.v1.ValidatorService.StartBatch:input_type -> validator.v1.StartBatchRequest
+	12, // 24: validator.v1.ValidatorService.AddBatchRecords:input_type -> validator.v1.AddBatchRecordsRequest
+	14, // 25: validator.v1.ValidatorService.GetBatch:input_type -> validator.v1.GetBatchRequest
+	15, // 26: validator.v1.ValidatorService.CompleteBatch:input_type -> validator.v1.CompleteBatchRequest
+	16, // 27: validator.v1.ValidatorService.AbortBatch:input_type -> validator.v1.AbortBatchRequest
+	18, // 28: validator.v1.ValidatorService.ListModels:input_type -> validator.v1.ListModelsRequest
+	5,  // 29: validator.v1.ValidatorService.Validate:output_type -> validator.v1.ValidationResult
+	8,  // 30: validator.v1.ValidatorService.ValidateArray:output_type -> validator.v1.ArrayValidationResult
+	8,  // 31: validator.v1.ValidatorService.ValidateStream:output_type -> validator.v1.ArrayValidationResult
+	11, // 32: validator.v1.ValidatorService.StartBatch:output_type -> validator.v1.BatchSession
+	13, // 33: validator.v1.ValidatorService.AddBatchRecords:output_type -> validator.v1.AddBatchRecordsResponse
+	11, // 34: validator.v1.ValidatorService.GetBatch:output_type -> validator.v1.BatchSession
+	11, // 35: validator.v1.ValidatorService.CompleteBatch:output_type -> validator.v1.BatchSession
+	17, // 36: validator.v1.ValidatorService.AbortBatch:output_type -> validator.v1.AbortBatchResponse
+	19, // 37: validator.v1.ValidatorService.ListModels:output_type -> validator.v1.ListModelsResponse
+	29, // [29:38] is the sub-list for method output_type
+	20, // [20:29] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_validator_proto_init() }
+func file_validator_proto_init() {
+	if File_validator_proto != nil {
+		return
+	}
+	file_validator_proto_msgTypes[2].OneofWrappers = []any{}
+	file_validator_proto_msgTypes[3].OneofWrappers = []any{}
+	file_validator_proto_msgTypes[8].OneofWrappers = []any{}
+	file_validator_proto_msgTypes[9].OneofWrappers = []any{}
+	file_validator_proto_msgTypes[11].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_validator_proto_rawDesc), len(file_validator_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_validator_proto_goTypes,
+		DependencyIndexes: file_validator_proto_depIdxs,
+		MessageInfos:      file_validator_proto_msgTypes,
+	}.Build()
+	File_validator_proto = out.File
+	file_validator_proto_goTypes = nil
+	file_validator_proto_depIdxs = nil
+}
@@ -0,0 +1,453 @@
+// gRPC interface to the validation server. It validates the same models, with
+// the same registry and batch sessions, as the HTTP API; records are passed as
+// google.protobuf.Struct in the shape of their JSON payloads.
+//
+// Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: validator.proto
+
+package validatorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ValidatorService_Validate_FullMethodName        = "/validator.v1.ValidatorService/Validate"
+	ValidatorService_ValidateArray_FullMethodName   = "/validator.v1.ValidatorService/ValidateArray"
+	ValidatorService_ValidateStream_FullMethodName  = "/validator.v1.ValidatorService/ValidateStream"
+	ValidatorService_StartBatch_FullMethodName      = "/validator.v1.ValidatorService/StartBatch"
+	ValidatorService_AddBatchRecords_FullMethodName = "/validator.v1.ValidatorService/AddBatchRecords"
+	ValidatorService_GetBatch_FullMethodName        = "/validator.v1.ValidatorService/GetBatch"
+	ValidatorService_CompleteBatch_FullMethodName   = "/validator.v1.ValidatorService/CompleteBatch"
+	ValidatorService_AbortBatch_FullMethodName      = "/validator.v1.ValidatorService/AbortBatch"
+	ValidatorService_ListModels_FullMethodName      = "/validator.v1.ValidatorService/ListModels"
+)
+
+// ValidatorServiceClient is the client API for ValidatorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ValidatorServiceClient interface {
+	// Validate checks one record. An invalid record is a normal response with
+	// is_valid false, not an error.
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidationResult, error)
+	// ValidateArray checks a list of records and applies the threshold, like
+	// POST /validate with a data array
+	ValidateArray(ctx context.Context, in *ValidateArrayRequest, opts ...grpc.CallOption) (*ArrayValidationResult, error)
+	// ValidateStream checks records as the client sends them and answers with
+	// the totals once the client closes the stream. The first message names the
+	// model; any message may carry a record.
+	ValidateStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ValidateStreamRequest, ArrayValidationResult], error)
+	// StartBatch opens a batch session bound to one model
+	StartBatch(ctx context.Context, in *StartBatchRequest, opts ...grpc.CallOption) (*BatchSession, error)
+	// AddBatchRecords validates a chunk of records into a batch session. A chunk
+	// resent with the same chunk_id is answered without counting it twice.
+	AddBatchRecords(ctx context.Context, in *AddBatchRecordsRequest, opts ...grpc.CallOption) (*AddBatchRecordsResponse, error)
+	// GetBatch returns a batch session's running totals
+	GetBatch(ctx context.Context, in *GetBatchRequest, opts ...grpc.CallOption) (*BatchSession, error)
+	// CompleteBatch finalizes a batch session and applies its threshold
+	CompleteBatch(ctx context.Context, in *CompleteBatchRequest, opts ...grpc.CallOption) (*BatchSession, error)
+	// AbortBatch discards a batch session and its retained rows
+	AbortBatch(ctx context.Context, in *AbortBatchRequest, opts ...grpc.CallOption) (*AbortBatchResponse, error)
+	// ListModels returns the registered model types
+	ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error)
+}
+
+type validatorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewValidatorServiceClient(cc grpc.ClientConnInterface) ValidatorServiceClient {
+	return &validatorServiceClient{cc}
+}
+
+func (c *validatorServiceClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidationResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidationResult)
+	err := c.cc.Invoke(ctx, ValidatorService_Validate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validatorServiceClient) ValidateArray(ctx context.Context, in *ValidateArrayRequest, opts ...grpc.CallOption) (*ArrayValidationResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArrayValidationResult)
+	err := c.cc.Invoke(ctx, ValidatorService_ValidateArray_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validatorServiceClient) ValidateStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ValidateStreamRequest, ArrayValidationResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ValidatorService_ServiceDesc.Streams[0], ValidatorService_ValidateStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ValidateStreamRequest, ArrayValidationResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ValidatorService_ValidateStreamClient = grpc.ClientStreamingClient[ValidateStreamRequest, ArrayValidationResult]
+
+func (c *validatorServiceClient) StartBatch(ctx context.Context, in *StartBatchRequest, opts ...grpc.CallOption) (*BatchSession, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchSession)
+	err := c.cc.Invoke(ctx, ValidatorService_StartBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validatorServiceClient) AddBatchRecords(ctx context.Context, in *AddBatchRecordsRequest, opts ...grpc.CallOption) (*AddBatchRecordsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddBatchRecordsResponse)
+	err := c.cc.Invoke(ctx, ValidatorService_AddBatchRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validatorServiceClient) GetBatch(ctx context.Context, in *GetBatchRequest, opts ...grpc.CallOption) (*BatchSession, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchSession)
+	err := c.cc.Invoke(ctx, ValidatorService_GetBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validatorServiceClient) CompleteBatch(ctx context.Context, in *CompleteBatchRequest, opts ...grpc.CallOption) (*BatchSession, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchSession)
+	err := c.cc.Invoke(ctx, ValidatorService_CompleteBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validatorServiceClient) AbortBatch(ctx context.Context, in *AbortBatchRequest, opts ...grpc.CallOption) (*AbortBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AbortBatchResponse)
+	err := c.cc.Invoke(ctx, ValidatorService_AbortBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validatorServiceClient) ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListModelsResponse)
+	err := c.cc.Invoke(ctx, ValidatorService_ListModels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ValidatorServiceServer is the server API for ValidatorService service.
+// All implementations must embed UnimplementedValidatorServiceServer
+// for forward compatibility.
+type ValidatorServiceServer interface {
+	// Validate checks one record. An invalid record is a normal response with
+	// is_valid false, not an error.
+	Validate(context.Context, *ValidateRequest) (*ValidationResult, error)
+	// ValidateArray checks a list of records and applies the threshold, like
+	// POST /validate with a data array
+	ValidateArray(context.Context, *ValidateArrayRequest) (*ArrayValidationResult, error)
+	// ValidateStream checks records as the client sends them and answers with
+	// the totals once the client closes the stream. The first message names the
+	// model; any message may carry a record.
+	ValidateStream(grpc.ClientStreamingServer[ValidateStreamRequest, ArrayValidationResult]) error
+	// StartBatch opens a batch session bound to one model
+	StartBatch(context.Context, *StartBatchRequest) (*BatchSession, error)
+	// AddBatchRecords validates a chunk of records into a batch session. A chunk
+	// resent with the same chunk_id is answered without counting it twice.
+	AddBatchRecords(context.Context, *AddBatchRecordsRequest) (*AddBatchRecordsResponse, error)
+	// GetBatch returns a batch session's running totals
+	GetBatch(context.Context, *GetBatchRequest) (*BatchSession, error)
+	// CompleteBatch finalizes a batch session and applies its threshold
+	CompleteBatch(context.Context, *CompleteBatchRequest) (*BatchSession, error)
+	// AbortBatch discards a batch session and its retained rows
+	AbortBatch(context.Context, *AbortBatchRequest) (*AbortBatchResponse, error)
+	// ListModels returns the registered model types
+	ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error)
+	mustEmbedUnimplementedValidatorServiceServer()
+}
+
+// UnimplementedValidatorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedValidatorServiceServer struct{}
+
+func (UnimplementedValidatorServiceServer) Validate(context.Context, *ValidateRequest) (*ValidationResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedValidatorServiceServer) ValidateArray(context.Context, *ValidateArrayRequest) (*ArrayValidationResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateArray not implemented")
+}
+func (UnimplementedValidatorServiceServer) ValidateStream(grpc.ClientStreamingServer[ValidateStreamRequest, ArrayValidationResult]) error {
+	return status.Errorf(codes.Unimplemented, "method ValidateStream not implemented")
+}
+func (UnimplementedValidatorServiceServer) StartBatch(context.Context, *StartBatchRequest) (*BatchSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartBatch not implemented")
+}
+func (UnimplementedValidatorServiceServer) AddBatchRecords(context.Context, *AddBatchRecordsRequest) (*AddBatchRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBatchRecords not implemented")
+}
+func (UnimplementedValidatorServiceServer) GetBatch(context.Context, *GetBatchRequest) (*BatchSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBatch not implemented")
+}
+func (UnimplementedValidatorServiceServer) CompleteBatch(context.Context, *CompleteBatchRequest) (*BatchSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteBatch not implemented")
+}
+func (UnimplementedValidatorServiceServer) AbortBatch(context.Context, *AbortBatchRequest) (*AbortBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbortBatch not implemented")
+}
+func (UnimplementedValidatorServiceServer) ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModels not implemented")
+}
+func (UnimplementedValidatorServiceServer) mustEmbedUnimplementedValidatorServiceServer() {}
+func (UnimplementedValidatorServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeValidatorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ValidatorServiceServer will
+// result in compilation errors.
+type UnsafeValidatorServiceServer interface {
+	mustEmbedUnimplementedValidatorServiceServer()
+}
+
+func RegisterValidatorServiceServer(s grpc.ServiceRegistrar, srv ValidatorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedValidatorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ValidatorService_ServiceDesc, srv)
+}
+
+func _ValidatorService_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorServiceServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidatorService_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorServiceServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidatorService_ValidateArray_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateArrayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorServiceServer).ValidateArray(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidatorService_ValidateArray_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorServiceServer).ValidateArray(ctx, req.(*ValidateArrayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidatorService_ValidateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ValidatorServiceServer).ValidateStream(&grpc.GenericServerStream[ValidateStreamRequest, ArrayValidationResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ValidatorService_ValidateStreamServer = grpc.ClientStreamingServer[ValidateStreamRequest, ArrayValidationResult]
+
+func _ValidatorService_StartBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorServiceServer).StartBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidatorService_StartBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorServiceServer).StartBatch(ctx, req.(*StartBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidatorService_AddBatchRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddBatchRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorServiceServer).AddBatchRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidatorService_AddBatchRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorServiceServer).AddBatchRecords(ctx, req.(*AddBatchRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidatorService_GetBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorServiceServer).GetBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidatorService_GetBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorServiceServer).GetBatch(ctx, req.(*GetBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidatorService_CompleteBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorServiceServer).CompleteBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidatorService_CompleteBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorServiceServer).CompleteBatch(ctx, req.(*CompleteBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidatorService_AbortBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorServiceServer).AbortBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidatorService_AbortBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorServiceServer).AbortBatch(ctx, req.(*AbortBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidatorService_ListModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListModelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorServiceServer).ListModels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidatorService_ListModels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorServiceServer).ListModels(ctx, req.(*ListModelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ValidatorService_ServiceDesc is the grpc.ServiceDesc for ValidatorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ValidatorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "validator.v1.ValidatorService",
+	HandlerType: (*ValidatorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Validate",
+			Handler:    _ValidatorService_Validate_Handler,
+		},
+		{
+			MethodName: "ValidateArray",
+			Handler:    _ValidatorService_ValidateArray_Handler,
+		},
+		{
+			MethodName: "StartBatch",
+			Handler:    _ValidatorService_StartBatch_Handler,
+		},
+		{
+			MethodName: "AddBatchRecords",
+			Handler:    _ValidatorService_AddBatchRecords_Handler,
+		},
+		{
+			MethodName: "GetBatch",
+			Handler:    _ValidatorService_GetBatch_Handler,
+		},
+		{
+			MethodName: "CompleteBatch",
+			Handler:    _ValidatorService_CompleteBatch_Handler,
+		},
+		{
+			MethodName: "AbortBatch",
+			Handler:    _ValidatorService_AbortBatch_Handler,
+		},
+		{
+			MethodName: "ListModels",
+			Handler:    _ValidatorService_ListModels_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ValidateStream",
+			Handler:       _ValidatorService_ValidateStream_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "validator.proto",
+}